@@ -0,0 +1,94 @@
+// Package agent describes the AI coding agents gwq can start in a worktree
+// and builds the command line for each of them.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Task is what an agent is asked to do.
+type Task struct {
+	Prompt   string // Instructions for the agent
+	Worktree string // Worktree the agent runs in
+}
+
+// Agent is a coding agent that can be started on a task.
+type Agent interface {
+	// Name identifies the agent, e.g. in --agent.
+	Name() string
+	// Command returns the program and arguments that start the agent on
+	// task.
+	Command(task Task) ([]string, error)
+	// HealthCheck reports whether the agent can be started.
+	HealthCheck(ctx context.Context) error
+	// CheckCompletion reports whether the captured pane output shows a
+	// finished run and its exit code. Agents without completion patterns
+	// never report completion.
+	CheckCompletion(pane string) (completed bool, exitCode int, err error)
+}
+
+// CommandAgent starts a CLI agent from an executable and argument
+// templates, so simple CLIs need no code of their own. Args are Go
+// templates over Task, e.g. "{{.Prompt}}".
+type CommandAgent struct {
+	AgentName  string
+	Executable string
+	Args       []string
+	Completion CompletionPatterns
+
+	compileOnce sync.Once
+	matcher     *CompletionMatcher
+	matcherErr  error
+}
+
+// Name implements Agent.
+func (a *CommandAgent) Name() string { return a.AgentName }
+
+// Command implements Agent. Arguments rendering to an empty string are
+// dropped, so optional flags can be written as "{{if .Prompt}}...{{end}}".
+func (a *CommandAgent) Command(task Task) ([]string, error) {
+	if a.Executable == "" {
+		return nil, fmt.Errorf("agent %s: no executable configured", a.AgentName)
+	}
+
+	argv := []string{a.Executable}
+	for _, src := range a.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: parse argument %q: %w", a.AgentName, src, err)
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, task); err != nil {
+			return nil, fmt.Errorf("agent %s: render argument %q: %w", a.AgentName, src, err)
+		}
+		if buf.Len() > 0 {
+			argv = append(argv, buf.String())
+		}
+	}
+	return argv, nil
+}
+
+// HealthCheck implements Agent by looking the executable up in PATH.
+func (a *CommandAgent) HealthCheck(context.Context) error {
+	if _, err := exec.LookPath(a.Executable); err != nil {
+		return fmt.Errorf("agent %s: %w", a.AgentName, err)
+	}
+	return nil
+}
+
+// CheckCompletion implements Agent. The patterns are compiled on first use.
+func (a *CommandAgent) CheckCompletion(pane string) (bool, int, error) {
+	a.compileOnce.Do(func() {
+		a.matcher, a.matcherErr = NewCompletionMatcher(a.Completion)
+	})
+	if a.matcherErr != nil {
+		return false, 0, fmt.Errorf("agent %s: %w", a.AgentName, a.matcherErr)
+	}
+	completed, exitCode := a.matcher.Check(pane)
+	return completed, exitCode, nil
+}
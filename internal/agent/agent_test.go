@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestCommandAgent_Command(t *testing.T) {
+	tests := []struct {
+		name    string
+		agent   *CommandAgent
+		task    Task
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "prompt argument",
+			agent: &CommandAgent{AgentName: "claude", Executable: "claude", Args: []string{"{{.Prompt}}"}},
+			task:  Task{Prompt: "fix the tests"},
+			want:  []string{"claude", "fix the tests"},
+		},
+		{
+			name:  "flags and worktree",
+			agent: &CommandAgent{AgentName: "aider", Executable: "aider", Args: []string{"--message", "{{.Prompt}}", "--root={{.Worktree}}"}},
+			task:  Task{Prompt: "add docs", Worktree: "/tmp/wt"},
+			want:  []string{"aider", "--message", "add docs", "--root=/tmp/wt"},
+		},
+		{
+			name:  "empty arguments are dropped",
+			agent: &CommandAgent{AgentName: "codex", Executable: "codex", Args: []string{"{{if .Prompt}}{{.Prompt}}{{end}}"}},
+			want:  []string{"codex"},
+		},
+		{
+			name:    "unknown field",
+			agent:   &CommandAgent{AgentName: "bad", Executable: "bad", Args: []string{"{{.Nope}}"}},
+			wantErr: true,
+		},
+		{
+			name:    "no executable",
+			agent:   &CommandAgent{AgentName: "empty"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.agent.Command(tt.task)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Command() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Command() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	cfg := &models.Config{Agents: map[string]models.AgentConfig{
+		"aider":  {Executable: "aider", Args: []string{"--message", "{{.Prompt}}"}},
+		"claude": {Executable: "/opt/claude", Args: []string{"-p", "{{.Prompt}}"}},
+	}}
+	r := NewDefaultRegistry(cfg)
+
+	if got, want := r.Names(), []string{"aider", "claude"}; !slices.Equal(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+
+	a, err := r.Get("Claude")
+	if err != nil {
+		t.Fatalf("Get(claude) error = %v", err)
+	}
+	argv, err := a.Command(Task{Prompt: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/opt/claude", "-p", "hi"}; !slices.Equal(argv, want) {
+		t.Errorf("configured claude = %q, want %q", argv, want)
+	}
+
+	if _, err := r.Get("cursor"); !errors.Is(err, ErrUnknownAgent) {
+		t.Errorf("Get(cursor) error = %v, want ErrUnknownAgent", err)
+	}
+
+	r.Register("echo", func(*models.Config) Agent {
+		return &CommandAgent{AgentName: "echo", Executable: "echo"}
+	})
+	if a, err := r.Get("echo"); err != nil || a.Name() != "echo" {
+		t.Errorf("Get(echo) = %v, %v", a, err)
+	}
+}
+
+func TestDefaultRegistry_BuiltinClaude(t *testing.T) {
+	a, err := NewDefaultRegistry(nil).Get("claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argv, err := a.Command(Task{Prompt: "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"claude", "review"}; !slices.Equal(argv, want) {
+		t.Errorf("Command() = %q, want %q", argv, want)
+	}
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// completionTailLines is how many trailing pane lines are searched for
+// completion markers, so markers printed by earlier runs in the same pane
+// scroll out of reach.
+const completionTailLines = 10
+
+// Claude's markers for 'claude -p --output-format stream-json', used when
+// the claude agent configures none of its own.
+var (
+	claudeCompletionPatterns = []string{regexp.QuoteMeta(`"type":"result"`)}
+	claudeSuccessPatterns    = []string{regexp.QuoteMeta(`"subtype":"success"`)}
+)
+
+// CompletionPatterns are regular expressions that recognise the end of an
+// agent run in its captured pane output.
+type CompletionPatterns struct {
+	Completion []string // Any match means the run finished
+	Success    []string // Any match means it succeeded
+	Error      []string // Any match means it failed, even if a success pattern matched
+}
+
+// CompletionMatcher checks captured pane output against compiled
+// CompletionPatterns.
+type CompletionMatcher struct {
+	completion, success, errors []*regexp.Regexp
+}
+
+// NewCompletionMatcher compiles p.
+func NewCompletionMatcher(p CompletionPatterns) (*CompletionMatcher, error) {
+	var m CompletionMatcher
+	var err error
+	if m.completion, err = compilePatterns("completion", p.Completion); err != nil {
+		return nil, err
+	}
+	if m.success, err = compilePatterns("success", p.Success); err != nil {
+		return nil, err
+	}
+	if m.errors, err = compilePatterns("error", p.Error); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func compilePatterns(kind string, patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", kind, p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Check reports whether the last lines of pane show a finished run and, if
+// so, its exit code: 0 when a success pattern matched and no error pattern
+// did, 1 otherwise. A matching error pattern also counts as finished.
+func (m *CompletionMatcher) Check(pane string) (completed bool, exitCode int) {
+	lines := strings.Split(strings.TrimRight(pane, "\n"), "\n")
+	tail := strings.Join(lines[max(0, len(lines)-completionTailLines):], "\n")
+
+	failed := matchAny(m.errors, tail)
+	if !failed && !matchAny(m.completion, tail) {
+		return false, 0
+	}
+	if !failed && matchAny(m.success, tail) {
+		return true, 0
+	}
+	return true, 1
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestCompletionMatcher_Check(t *testing.T) {
+	claude := claudeCompletion()
+	custom := CompletionPatterns{
+		Completion: []string{`(?m)^Done\.$`},
+		Success:    []string{`(?m)^Tests: \d+ passed$`},
+		Error:      []string{`(?m)^ERROR:`},
+	}
+
+	tests := []struct {
+		name          string
+		patterns      CompletionPatterns
+		pane          string
+		wantCompleted bool
+		wantExitCode  int
+	}{
+		{
+			name:          "claude success",
+			patterns:      claude,
+			pane:          `{"type":"assistant"}` + "\n" + `{"type":"result","subtype":"success","result":"ok"}` + "\n",
+			wantCompleted: true,
+		},
+		{
+			name:          "claude error result",
+			patterns:      claude,
+			pane:          `{"type":"result","subtype":"error_max_turns"}`,
+			wantCompleted: true,
+			wantExitCode:  1,
+		},
+		{
+			name:     "claude still running",
+			patterns: claude,
+			pane:     `{"type":"assistant","message":"working"}`,
+		},
+		{
+			name:     "marker scrolled out of the tail",
+			patterns: claude,
+			pane:     `{"type":"result","subtype":"success"}` + strings.Repeat("\nmore output", completionTailLines),
+		},
+		{
+			name:          "custom success",
+			patterns:      custom,
+			pane:          "Tests: 12 passed\nDone.\n",
+			wantCompleted: true,
+		},
+		{
+			name:          "custom completion without success",
+			patterns:      custom,
+			pane:          "Tests: 3 failed\nDone.",
+			wantCompleted: true,
+			wantExitCode:  1,
+		},
+		{
+			name:          "error pattern finishes the run",
+			patterns:      custom,
+			pane:          "Tests: 12 passed\nERROR: disk full",
+			wantCompleted: true,
+			wantExitCode:  1,
+		},
+		{
+			name: "no patterns never complete",
+			pane: `{"type":"result","subtype":"success"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewCompletionMatcher(tt.patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			completed, exitCode := m.Check(tt.pane)
+			if completed != tt.wantCompleted || exitCode != tt.wantExitCode {
+				t.Errorf("Check() = %t, %d, want %t, %d", completed, exitCode, tt.wantCompleted, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestNewCompletionMatcher_InvalidPattern(t *testing.T) {
+	if _, err := NewCompletionMatcher(CompletionPatterns{Success: []string{"("}}); err == nil {
+		t.Error("NewCompletionMatcher() error = nil, want error")
+	}
+}
+
+func TestDefaultRegistry_CompletionPatterns(t *testing.T) {
+	cfg := &models.Config{Agents: map[string]models.AgentConfig{
+		"claude": {Executable: "claude", Args: []string{"-p", "{{.Prompt}}"}},
+		"aider":  {Executable: "aider", CompletionPatterns: []string{`Tokens: .* sent`}},
+	}}
+	r := NewDefaultRegistry(cfg)
+
+	tests := []struct {
+		agent, pane   string
+		wantCompleted bool
+	}{
+		{"claude", `{"type":"result","subtype":"success"}`, true},
+		{"aider", "Tokens: 2.1k sent, 300 received.", true},
+		{"aider", `{"type":"result","subtype":"success"}`, false},
+	}
+	for _, tt := range tests {
+		a, err := r.Get(tt.agent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		completed, _, err := a.CheckCompletion(tt.pane)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if completed != tt.wantCompleted {
+			t.Errorf("%s CheckCompletion(%q) = %t, want %t", tt.agent, tt.pane, completed, tt.wantCompleted)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// ErrUnknownAgent is returned by Registry.Get for names nothing was
+// registered under.
+var ErrUnknownAgent = errors.New("unknown agent")
+
+// Factory creates an agent from the configuration.
+type Factory func(cfg *models.Config) Agent
+
+// Registry maps agent names to their factories.
+type Registry struct {
+	cfg       *models.Config
+	factories map[string]Factory
+}
+
+// NewRegistry returns an empty registry whose factories receive cfg.
+func NewRegistry(cfg *models.Config) *Registry {
+	return &Registry{cfg: cfg, factories: make(map[string]Factory)}
+}
+
+// NewDefaultRegistry returns a registry with the built-in agents and the
+// agents configured in cfg.Agents, which take precedence.
+func NewDefaultRegistry(cfg *models.Config) *Registry {
+	r := NewRegistry(cfg)
+	r.Register("claude", func(*models.Config) Agent {
+		return &CommandAgent{
+			AgentName:  "claude",
+			Executable: "claude",
+			Args:       []string{"{{.Prompt}}"},
+			Completion: claudeCompletion(),
+		}
+	})
+	if cfg != nil {
+		for name, ac := range cfg.Agents {
+			completion := CompletionPatterns{
+				Completion: ac.CompletionPatterns,
+				Success:    ac.SuccessPatterns,
+				Error:      ac.ErrorPatterns,
+			}
+			if strings.EqualFold(name, "claude") && len(completion.Completion)+len(completion.Success)+len(completion.Error) == 0 {
+				completion = claudeCompletion()
+			}
+			r.Register(name, func(*models.Config) Agent {
+				return &CommandAgent{AgentName: name, Executable: ac.Executable, Args: ac.Args, Completion: completion}
+			})
+		}
+	}
+	return r
+}
+
+// claudeCompletion returns Claude's built-in completion markers.
+func claudeCompletion() CompletionPatterns {
+	return CompletionPatterns{Completion: claudeCompletionPatterns, Success: claudeSuccessPatterns}
+}
+
+// Register makes factory available as name, replacing any earlier
+// registration. Names are case-insensitive.
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[strings.ToLower(name)] = factory
+}
+
+// Get returns a new instance of the agent registered as name.
+func (r *Registry) Get(name string) (Agent, error) {
+	factory, ok := r.factories[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownAgent, name, strings.Join(r.Names(), ", "))
+	}
+	return factory(r.cfg), nil
+}
+
+// Names returns the registered agent names in sorted order.
+func (r *Registry) Names() []string {
+	return slices.Sorted(maps.Keys(r.factories))
+}
@@ -1,22 +1,34 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/duration"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/github"
 	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
 var (
-	addBranch      bool
-	addInteractive bool
-	addForce       bool
-	addStay        bool
-	addExpires     string
+	addBranch         bool
+	addInteractive    bool
+	addForce          bool
+	addAllowProtected bool
+	addStay           bool
+	addExpires        string
+	addFromStash      string
+	addFromPR         int
+	addBase           string
+	addPull           bool
+	addNoCheckout     bool
+	addTrackRemote    bool
 )
 
 // addCmd represents the add command.
@@ -26,7 +38,43 @@ var addCmd = &cobra.Command{
 	Long: `Create a new worktree for the specified branch.
 
 If no path is provided, it will be generated based on the configuration template.
-Use -i flag to interactively select a branch using fuzzy finder.`,
+If no branch is given, or with -i, a fuzzy finder lists local and remote
+branches. Choosing a remote branch creates a local branch that tracks it.
+
+Setup commands from repository_settings run in the new worktree with these
+environment variables set:
+  GWQ_WORKTREE_PATH  path of the new worktree
+  GWQ_BRANCH         branch checked out in it
+  GWQ_REPO_ROOT      root of the main repository
+  GWQ_REPO_NAME      directory name of the main repository
+
+With --from-pr, the pull request's head is fetched from origin into a local
+branch named pr/<number>, and the only argument is an optional path. The pull
+request is looked up with the gh CLI when it is installed, otherwise through
+the GitHub API with GH_TOKEN, GITHUB_TOKEN or github.token from the config.
+
+With -b, --base starts the new branch from another ref instead of HEAD. Adding
+--pull first fetches the base from its remote: a remote-tracking base such as
+origin/main is refreshed, and a local branch is replaced by its fetched
+upstream unless it has unpushed commits. If the fetch fails, gwq warns and
+uses the local state of the base.
+
+Branches matching worktree.protected_branches (e.g. main or release/*) are
+not checked out into new worktrees, so they are not edited directly by
+accident; create a feature branch with -b instead. --allow-protected checks
+out a protected branch anyway, with a warning. Creating a branch with -b is
+never refused.
+
+--no-checkout registers the worktree without checking out its files, which
+is useful in very large repositories: set up a sparse checkout in it and
+then run 'git checkout'. Setup from repository_settings is skipped.
+
+A branch given by name that has no local branch is looked up among the
+remote-tracking branches: if a remote (preferably origin) has it, the new
+branch tracks it; otherwise a new branch is created from HEAD, as with -b. Tags and other refs are checked out
+as before.
+This is on by default (worktree.track_remote_if_exists);
+--track-remote-if-exists=false only checks out existing branches.`,
 	Example: `  # Create worktree from existing branch
   gwq add feature/new-ui
 
@@ -36,6 +84,12 @@ Use -i flag to interactively select a branch using fuzzy finder.`,
   # Create new branch and worktree
   gwq add -b feature/api-v2
 
+  # Track origin/feature/login, or create the branch if no remote has it
+  gwq add feature/login
+
+  # Branch off the latest origin/main
+  gwq add -b feature/api-v2 --base origin/main --pull
+
   # Interactive branch selection
   gwq add -i
 
@@ -46,7 +100,19 @@ Use -i flag to interactively select a branch using fuzzy finder.`,
   gwq add --expires 7d feature/experiment
 
   # Create worktree expiring in 1 hour
-  gwq add --expires 1h hotfix/quick-test`,
+  gwq add --expires 1h hotfix/quick-test
+
+  # Move the latest stash into a new branch and worktree
+  gwq add -b feature/rescued --from-stash
+
+  # Apply a specific stash (note the '=')
+  gwq add -b feature/rescued --from-stash='stash@{2}'
+
+  # Check out GitHub pull request #123 into branch pr/123
+  gwq add --from-pr 123
+
+  # Create a worktree for a sparse checkout
+  gwq add --no-checkout -b feature/docs`,
 	RunE:              runAdd,
 	ValidArgsFunction: getBranchCompletions,
 }
@@ -57,45 +123,82 @@ func init() {
 	addCmd.Flags().BoolVarP(&addBranch, "branch", "b", false, "Create new branch")
 	addCmd.Flags().BoolVarP(&addInteractive, "interactive", "i", false, "Select branch using fuzzy finder")
 	addCmd.Flags().BoolVarP(&addForce, "force", "f", false, "Overwrite existing directory")
+	addCmd.Flags().BoolVar(&addAllowProtected, "allow-protected", false, "Check out a branch matching worktree.protected_branches")
 	addCmd.Flags().BoolVarP(&addStay, "stay", "s", false, "Stay in worktree directory after creation")
 	addCmd.Flags().StringVar(&addExpires, "expires", "", "Set expiration (e.g., 1d, 7d, 1h)")
+	addCmd.Flags().StringVar(&addFromStash, "from-stash", "", "Apply a stash to the new worktree (default stash@{0})")
+	addCmd.Flags().Lookup("from-stash").NoOptDefVal = "stash@{0}"
+	addCmd.Flags().IntVar(&addFromPR, "from-pr", 0, "Check out a GitHub pull request into branch pr/<number>")
+	addCmd.MarkFlagsMutuallyExclusive("from-pr", "branch")
+	addCmd.MarkFlagsMutuallyExclusive("from-pr", "interactive")
+	addCmd.Flags().StringVar(&addBase, "base", "", "Start the new branch from this ref (requires -b)")
+	addCmd.Flags().BoolVar(&addPull, "pull", false, "Fetch the latest --base from its remote first")
+	addCmd.Flags().BoolVar(&addNoCheckout, "no-checkout", false, "Register the worktree without checking out files or running setup")
+	addCmd.MarkFlagsMutuallyExclusive("no-checkout", "from-stash")
+	addCmd.Flags().BoolVar(&addTrackRemote, "track-remote-if-exists", false, "Track a remote branch of the name if there is no local one, else create it (default from worktree.track_remote_if_exists)")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
 	return ExecuteWithArgs(true, func(ctx *CommandContext, cmd *cobra.Command, args []string) error {
 		var branch string
 		var path string
+		// byName is set when the branch was typed rather than picked.
+		var byName bool
+
+		if addBase != "" && !addBranch {
+			return &usageError{err: fmt.Errorf("--base requires -b")}
+		}
+		if addPull && addBase == "" {
+			return &usageError{err: fmt.Errorf("--pull requires --base")}
+		}
 
-		if addInteractive {
+		if cmd.Flags().Changed("from-pr") {
+			if addFromPR <= 0 {
+				return &usageError{err: fmt.Errorf("invalid pull request number %d", addFromPR)}
+			}
+			if len(args) > 1 {
+				return &usageError{err: fmt.Errorf("--from-pr accepts at most a path argument")}
+			}
+			branch = github.BranchName(addFromPR)
+			if len(args) > 0 {
+				path = args[0]
+			}
+		} else if addInteractive || (len(args) == 0 && !addBranch) {
 			if len(args) > 0 {
 				return fmt.Errorf("cannot specify branch name with -i flag")
 			}
 
-			branches, err := ctx.Git.ListBranches(true)
+			branches, err := ctx.WorktreeManager.BranchCandidates()
 			if err != nil {
 				return fmt.Errorf("failed to list branches: %w", err)
 			}
 
 			selectedBranch, err := ctx.GetFinder().SelectBranch(branches)
 			if err != nil {
-				return fmt.Errorf("branch selection cancelled")
+				return fmt.Errorf("branch selection cancelled: %w", err)
 			}
 
-			branch = selectedBranch.Name
-			if selectedBranch.IsRemote {
-				branch = selectedBranch.Name[len("origin/"):]
-				addBranch = true
-			}
+			// For a remote branch, git creates a local branch of the same
+			// name that tracks it.
+			branch = worktree.LocalBranchName(*selectedBranch)
 		} else {
 			if len(args) < 1 {
 				return fmt.Errorf("branch name is required")
 			}
 			branch = args[0]
+			byName = true
 			if len(args) > 1 {
 				path = args[1]
 			}
 		}
 
+		// Only checking out an existing branch can edit it directly.
+		if !addBranch && !cmd.Flags().Changed("from-pr") {
+			if err := checkProtectedBranch(os.Stderr, ctx.Config.Worktree.ProtectedBranches, branch, addAllowProtected); err != nil {
+				return err
+			}
+		}
+
 		if path != "" && !addForce {
 			if err := ctx.WorktreeManager.ValidateWorktreePath(path); err != nil {
 				return err
@@ -115,10 +218,59 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			expiresDuration = d
 		}
 
-		worktreePath, err := ctx.WorktreeManager.Add(branch, path, addBranch)
+		// Likewise make sure the stash exists before creating anything.
+		if addFromStash != "" {
+			if _, err := ctx.Git.RunCommand("rev-parse", "--verify", "--quiet", addFromStash); err != nil {
+				return fmt.Errorf("stash %s not found", addFromStash)
+			}
+		}
+
+		base := addBase
+		if addPull {
+			// Best effort: a stale base is better than no worktree.
+			pulled, err := ctx.WorktreeManager.PullBase(base)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not update %s, using its local state: %v\n", base, err)
+			}
+			base = pulled
+		}
+
+		opts := worktree.AddOptions{
+			CreateBranch: addBranch,
+			Base:         base,
+			NoCheckout:   addNoCheckout,
+		}
+		trackRemote := ctx.Config.Worktree.TrackRemoteIfExists
+		if cmd.Flags().Changed("track-remote-if-exists") {
+			trackRemote = addTrackRemote
+		}
+		var source *worktree.BranchSource
+		if byName && !addBranch && trackRemote {
+			resolved, err := ctx.WorktreeManager.ResolveBranchSource(branch)
+			if err != nil {
+				return err
+			}
+			source = &resolved
+			opts = source.AddOptions()
+			opts.NoCheckout = addNoCheckout
+		}
+
+		var worktreePath string
+		var err error
+		if cmd.Flags().Changed("from-pr") {
+			worktreePath, err = addPullRequestWorktree(ctx, addFromPR, path, addNoCheckout)
+		} else {
+			worktreePath, err = ctx.WorktreeManager.AddWithOptions(branch, path, opts)
+		}
 		if err != nil {
 			return err
 		}
+		if source != nil {
+			describeBranchSource(os.Stderr, branch, *source)
+		}
+		if addNoCheckout {
+			fmt.Fprintf(os.Stderr, "Files were not checked out; e.g. run 'git sparse-checkout set <dir>...' and then 'git checkout' in %s\n", worktreePath)
+		}
 
 		var expiresAt *time.Time
 		if addExpires != "" {
@@ -145,15 +297,30 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		var appliedStash string
+		if addFromStash != "" {
+			// A failed apply keeps both the worktree and the stash so the
+			// user can resolve it by hand.
+			var conflictErr *git.StashConflictError
+			if err := ctx.Git.StashApply(worktreePath, addFromStash); errors.As(err, &conflictErr) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\nResolve the conflicts in %s; the stash was kept.\n", conflictErr, worktreePath)
+			} else if err != nil {
+				return fmt.Errorf("created worktree at %s but could not apply stash: %w", worktreePath, err)
+			} else {
+				appliedStash = addFromStash
+			}
+		}
+
 		handleAddPostCreate(
 			os.Stdout, os.Stderr,
 			isCdShimActive(),
 			ctx.Config.Cd.AutoCdOnAdd,
 			addResult{
-				Branch:    branch,
-				Path:      worktreePath,
-				Stay:      addStay,
-				ExpiresAt: expiresAt,
+				Branch:       branch,
+				Path:         worktreePath,
+				Stay:         addStay,
+				ExpiresAt:    expiresAt,
+				AppliedStash: appliedStash,
 			},
 			LaunchShell,
 		)
@@ -161,13 +328,43 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	})(cmd, args)
 }
 
+// describeBranchSource tells w whether a new branch tracks a remote one or
+// is created from scratch; checking out a local branch needs no comment.
+func describeBranchSource(w io.Writer, branch string, source worktree.BranchSource) {
+	switch {
+	case source.Existing:
+	case source.Remote != "":
+		_, _ = fmt.Fprintf(w, "Tracking %s\n", source.Remote)
+	default:
+		_, _ = fmt.Fprintf(w, "Creating new branch %s\n", branch)
+	}
+}
+
+// checkProtectedBranch refuses a worktree checking out an existing branch
+// that matches one of the protected patterns, or only warns on w when allow
+// is set.
+func checkProtectedBranch(w io.Writer, patterns []string, branch string, allow bool) error {
+	for _, pattern := range patterns {
+		if !utils.MatchPath(pattern, branch) {
+			continue
+		}
+		if allow {
+			_, _ = fmt.Fprintf(w, "Warning: branch '%s' is protected (matches %q); avoid editing it directly\n", branch, pattern)
+			return nil
+		}
+		return fmt.Errorf("branch '%s' is protected (matches %q): create a feature branch instead, e.g. 'gwq add -b feature/my-change', or check it out anyway with 'gwq add --allow-protected %s'", branch, pattern, branch)
+	}
+	return nil
+}
+
 // addResult carries the outcome of a successful `gwq add` into the
 // post-create output routing.
 type addResult struct {
-	Branch    string
-	Path      string
-	Stay      bool
-	ExpiresAt *time.Time
+	Branch       string
+	Path         string
+	Stay         bool
+	ExpiresAt    *time.Time
+	AppliedStash string
 }
 
 // handleAddPostCreate routes success messages and the worktree path to the
@@ -193,6 +390,9 @@ func handleAddPostCreate(
 	if r.ExpiresAt != nil {
 		_, _ = fmt.Fprintf(msgDst, "Worktree expires at %s\n", r.ExpiresAt.Format(time.RFC3339))
 	}
+	if r.AppliedStash != "" {
+		_, _ = fmt.Fprintf(msgDst, "Applied %s (the stash was kept)\n", r.AppliedStash)
+	}
 
 	switch {
 	case inShim && wantCd:
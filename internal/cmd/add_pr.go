@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/d-kuro/gwq/internal/github"
+	"github.com/d-kuro/gwq/internal/url"
+	"github.com/d-kuro/gwq/internal/worktree"
+)
+
+// addPullRequestWorktree fetches the head of GitHub pull request number from
+// origin and creates a worktree for it on branch pr/<number>, without
+// checking out its files when noCheckout is set.
+func addPullRequestWorktree(ctx *CommandContext, number int, path string, noCheckout bool) (string, error) {
+	repoURL, err := ctx.Git.GetRepositoryURL()
+	if err != nil {
+		return "", err
+	}
+	info, err := url.ParseRepositoryURL(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse repository URL: %w", err)
+	}
+	if err := github.CheckHost(info); err != nil {
+		return "", err
+	}
+
+	fetcher, err := github.NewFetcher(github.Token(ctx.Config.GitHub.Token), nil)
+	if err != nil {
+		return "", err
+	}
+	pr, err := github.Resolve(context.Background(), fetcher, info, number)
+	if err != nil {
+		return "", err
+	}
+
+	// pull/<n>/head also carries pull requests opened from forks, whose
+	// branches are not on origin.
+	if _, err := ctx.Git.RunCommand("fetch", "origin", github.HeadRefspec(number)); err != nil {
+		return "", fmt.Errorf("failed to fetch pull request #%d: %w", number, err)
+	}
+
+	return ctx.WorktreeManager.AddWithOptions(github.BranchName(number), path, worktree.AddOptions{
+		CreateBranch: true,
+		Base:         pr.HeadSHA,
+		NoCheckout:   noCheckout,
+	})
+}
@@ -113,3 +113,40 @@ func TestHandleAddPostCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckProtectedBranch(t *testing.T) {
+	patterns := []string{"main", "master", "release/*"}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		branch   string
+		allow    bool
+		wantErr  bool
+		wantWarn bool
+	}{
+		{name: "no patterns configured", branch: "main"},
+		{name: "unprotected branch", patterns: patterns, branch: "feature/x"},
+		{name: "exact match refused", patterns: patterns, branch: "main", wantErr: true},
+		{name: "glob match refused", patterns: patterns, branch: "release/1.2", wantErr: true},
+		{name: "glob does not cross slashes", patterns: patterns, branch: "release/1.2/hotfix"},
+		{name: "allow warns", patterns: patterns, branch: "master", allow: true, wantWarn: true},
+		{name: "allow on unprotected branch is silent", patterns: patterns, branch: "feature/x", allow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := checkProtectedBranch(&buf, tt.patterns, tt.branch, tt.allow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkProtectedBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && (!strings.Contains(err.Error(), "gwq add -b") || !strings.Contains(err.Error(), "--allow-protected")) {
+				t.Errorf("error should suggest a feature branch and --allow-protected, got %q", err)
+			}
+			if got := buf.Len() > 0; got != tt.wantWarn {
+				t.Errorf("warning written = %v, want %v (output %q)", got, tt.wantWarn, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// defaultArchiveDir is where 'gwq archive' writes archives by default.
+const defaultArchiveDir = "~/.local/share/gwq/archives"
+
+var (
+	archiveOutput      string
+	archiveRestorePath string
+)
+
+// archiveCmd represents the archive command.
+var archiveCmd = &cobra.Command{
+	Use:   "archive [pattern]",
+	Short: "Save a worktree to a tar.gz archive and remove it",
+	Long: `Archive a worktree instead of deleting it.
+
+The worktree directory, including uncommitted and untracked files, is written
+to <output>/<branch>-<date>.tar.gz. The worktree is then removed from git with
+'git worktree remove --force' and its directory is deleted. The branch is kept.
+
+If no pattern is provided, or several worktrees match, a fuzzy finder is shown.
+The main worktree cannot be archived. Use 'gwq archive restore' to bring an
+archived worktree back.`,
+	Example: `  # Pick a worktree to archive
+  gwq archive
+
+  # Archive a worktree by pattern
+  gwq archive feature/experiment
+
+  # Write the archive somewhere else
+  gwq archive feature/experiment --output ~/backups`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: ExecuteWithArgs(true, runArchive),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getRemoveCompletions(cmd, args, toComplete)
+	},
+}
+
+// archiveRestoreCmd represents the archive restore command.
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Recreate a worktree from an archive",
+	Long: `Recreate a worktree from an archive written by 'gwq archive'.
+
+The branch is inferred from the archive file name. A worktree for that branch
+is added at the generated path (or --path) and the archived files are
+extracted into it, restoring uncommitted and untracked changes. The branch must
+still exist in the repository.`,
+	Example: `  # Restore an archived worktree
+  gwq archive restore ~/.local/share/gwq/archives/feature%2Fexperiment-2026-10-15.tar.gz
+
+  # Restore to a custom path
+  gwq archive restore feature%2Fexperiment-2026-10-15.tar.gz --path ~/src/experiment`,
+	Args: cobra.ExactArgs(1),
+	RunE: ExecuteWithArgs(true, runArchiveRestore),
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveRestoreCmd)
+
+	archiveCmd.Flags().StringVarP(&archiveOutput, "output", "o", defaultArchiveDir, "Directory to write the archive to")
+	archiveRestoreCmd.Flags().StringVarP(&archiveRestorePath, "path", "p", "", "Path for the restored worktree (default: generated path)")
+}
+
+func runArchive(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	target, err := selectArchiveTarget(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if inside, err := cwdInside(target.Path); err != nil {
+		return err
+	} else if inside {
+		return fmt.Errorf("cannot archive the current worktree; run 'gwq archive' from another directory")
+	}
+
+	archivePath, err := ctx.WorktreeManager.Archive(target, archiveOutput, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if reg, err := registry.New(); err == nil {
+		_ = reg.Unregister(target.Path)
+	}
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("Archived worktree %s to %s", target.Branch, archivePath))
+	return nil
+}
+
+// selectArchiveTarget resolves the worktree to archive from an optional
+// pattern, showing the fuzzy finder when there is no pattern.
+func selectArchiveTarget(ctx *CommandContext, args []string) (models.Worktree, error) {
+	if len(args) > 0 {
+		return selectNonMainWorktree(ctx, args[0])
+	}
+
+	worktrees, err := ctx.WorktreeManager.List()
+	if err != nil {
+		return models.Worktree{}, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	worktrees = filterNonMainWorktrees(worktrees)
+	if len(worktrees) == 0 {
+		return models.Worktree{}, fmt.Errorf("no worktrees to archive")
+	}
+
+	selected, err := ctx.GetFinder().SelectWorktree(worktrees)
+	if err != nil {
+		return models.Worktree{}, fmt.Errorf("worktree selection cancelled: %w", err)
+	}
+	return *selected, nil
+}
+
+func runArchiveRestore(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	if archiveRestorePath != "" {
+		if err := ctx.WorktreeManager.ValidateWorktreePath(archiveRestorePath); err != nil {
+			return err
+		}
+	}
+
+	path, branch, err := ctx.WorktreeManager.Restore(args[0], archiveRestorePath)
+	if err != nil {
+		return err
+	}
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("Restored worktree %s at %s", branch, path))
+	return nil
+}
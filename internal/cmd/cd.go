@@ -2,13 +2,21 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
 )
 
-var cdGlobal bool
+var (
+	cdGlobal bool
+	cdNew    bool
+	cdBase   string
+)
 
 var cdCmd = &cobra.Command{
 	Use:   "cd [pattern]",
@@ -22,7 +30,12 @@ With shell integration (cd.launch_shell=false), this command changes the
 current shell's directory instead of launching a new shell.
 
 If multiple worktrees match the pattern, an interactive fuzzy finder will be shown.
-If no pattern is provided, all worktrees will be shown in the fuzzy finder.`,
+If no pattern is provided, all worktrees will be shown in the fuzzy finder.
+
+With --new, the pattern is taken as an exact branch name: gwq changes to the
+worktree of that branch, or creates one first at the path 'gwq add' would use.
+An existing local or remote branch is checked out; otherwise the branch is
+created from HEAD, or from --base.`,
 	Example: `  # Change to a worktree matching 'feature'
   gwq cd feature
 
@@ -30,7 +43,13 @@ If no pattern is provided, all worktrees will be shown in the fuzzy finder.`,
   gwq cd
 
   # Change to global worktree
-  gwq cd -g project:feature`,
+  gwq cd -g project:feature
+
+  # Open the worktree for a branch, creating it if needed
+  gwq cd --new feature/login
+
+  # Create missing branches from origin/main
+  gwq cd --new --base origin/main feature/login`,
 	RunE: runCd,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) > 0 {
@@ -43,6 +62,9 @@ If no pattern is provided, all worktrees will be shown in the fuzzy finder.`,
 func init() {
 	rootCmd.AddCommand(cdCmd)
 	cdCmd.Flags().BoolVarP(&cdGlobal, "global", "g", false, "Change to global worktree")
+	cdCmd.Flags().BoolVarP(&cdNew, "new", "n", false, "Treat the pattern as a branch and create its worktree if there is none")
+	cdCmd.Flags().StringVar(&cdBase, "base", "", "Base for branches created by --new")
+	cdCmd.MarkFlagsMutuallyExclusive("new", "global")
 }
 
 const envCdShim = "__GWQ_CD_SHIM"
@@ -63,36 +85,26 @@ func runCd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// launch_shell=false without shell integration: show setup guidance
-	if !cfg.Cd.LaunchShell && !isCdShimActive() {
-		return fmt.Errorf(`'gwq cd' requires shell integration when cd.launch_shell is false.
-
-To enable shell integration, add this to your shell configuration:
-
-  # bash (~/.bashrc)
-  source <(gwq completion bash)
-
-  # zsh (~/.zshrc)
-  source <(gwq completion zsh)
-
-  # fish (~/.config/fish/config.fish)
-  gwq completion fish | source
-
-Then reload your shell:
-  exec $SHELL
-
-Or, to use the old behavior (launching a new shell), run:
-  gwq config set cd.launch_shell true`)
+	if err := requireCdIntegration(cfg, "cd"); err != nil {
+		return err
 	}
 
 	var pattern string
 	if len(args) > 0 {
 		pattern = args[0]
 	}
+	if cdNew && pattern == "" {
+		return &usageError{err: fmt.Errorf("--new requires a branch name")}
+	}
+	if cdBase != "" && !cdNew {
+		return &usageError{err: fmt.Errorf("--base requires --new")}
+	}
 
 	var worktreePath string
 	if cdGlobal {
 		worktreePath, err = getGlobalWorktreePathForExec(cfg, pattern)
+	} else if cdNew {
+		worktreePath, err = cdNewWorktree(os.Stderr, cfg, pattern, cdBase)
 	} else {
 		worktreePath, err = getLocalWorktreePathForExec(cfg, pattern)
 	}
@@ -101,12 +113,92 @@ Or, to use the old behavior (launching a new shell), run:
 		return err
 	}
 
-	// Called from shell wrapper: print path to stdout
-	if isCdShimActive() {
-		fmt.Println(worktreePath)
+	return changeDirectory(worktreePath)
+}
+
+// cdNewWorktree resolves 'gwq cd --new' to the worktree that has branch
+// checked out, or creates one. The branch is matched exactly, so "feat" never
+// jumps to an existing "feature" worktree. Messages go to w so stdout stays
+// free for the shell wrapper.
+func cdNewWorktree(w io.Writer, cfg *models.Config, branch, base string) (string, error) {
+	g, err := git.NewFromCwd()
+	if err != nil {
+		return "", err
+	}
+	if _, err := g.GetRepositoryPath(); err != nil {
+		return "", fmt.Errorf("--new requires running inside a git repository")
+	}
+	wm := worktree.New(g, cfg)
+
+	wt, found, err := wm.GetWorktreeByExactBranch(branch)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return wt.Path, nil
+	}
+	return createCdWorktree(w, wm, cfg, branch, base)
+}
+
+// createCdWorktree creates the worktree for branch when 'gwq cd --new' finds
+// none.
+func createCdWorktree(w io.Writer, wm *worktree.Manager, cfg *models.Config, branch, base string) (string, error) {
+	var path string
+	var err error
+	if base != "" {
+		path, err = wm.AddFromBase(branch, base, "")
+	} else {
+		exists, existsErr := wm.BranchExists(branch)
+		if existsErr != nil {
+			return "", fmt.Errorf("failed to list branches: %w", existsErr)
+		}
+		if exists {
+			if err := checkProtectedBranch(w, cfg.Worktree.ProtectedBranches, branch, false); err != nil {
+				return "", err
+			}
+		}
+		path, err = wm.Add(branch, "", !exists)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create a worktree for %s: %w\nCreate it explicitly with 'gwq add'", branch, err)
+	}
+
+	_, _ = fmt.Fprintf(w, "Created worktree for branch '%s'\n", branch)
+	return path, nil
+}
+
+// requireCdIntegration returns setup guidance when cd.launch_shell is false
+// but the shell wrapper that performs the cd is not installed.
+func requireCdIntegration(cfg *models.Config, command string) error {
+	if cfg.Cd.LaunchShell || isCdShimActive() {
 		return nil
 	}
+	return fmt.Errorf(`'gwq %s' requires shell integration when cd.launch_shell is false.
+
+To enable shell integration, add this to your shell configuration:
+
+  # bash (~/.bashrc)
+  source <(gwq completion bash)
+
+  # zsh (~/.zshrc)
+  source <(gwq completion zsh)
+
+  # fish (~/.config/fish/config.fish)
+  gwq completion fish | source
+
+Then reload your shell:
+  exec $SHELL
+
+Or, to use the old behavior (launching a new shell), run:
+  gwq config set cd.launch_shell true`, command)
+}
 
-	// Default behavior: launch a new shell
-	return LaunchShell(worktreePath)
+// changeDirectory hands path to the shell wrapper on stdout when running
+// under shell integration, and otherwise launches a new shell in path.
+func changeDirectory(path string) error {
+	if isCdShimActive() {
+		fmt.Println(path)
+		return nil
+	}
+	return LaunchShell(path)
 }
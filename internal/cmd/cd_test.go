@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
 )
 
 func TestCdCmd_Structure(t *testing.T) {
@@ -56,3 +62,92 @@ func TestCdCmd_ShimEnvIsNotSetByDefault(t *testing.T) {
 		t.Skip("__GWQ_CD_SHIM is set in the test environment")
 	}
 }
+
+func TestCreateCdWorktree(t *testing.T) {
+	repo := initTestGitRepo(t)
+	for _, args := range [][]string{
+		{"-C", repo, "branch", "existing"},
+		{"-C", repo, "branch", "release/1"},
+		{"-C", repo, "remote", "add", "origin", "https://github.com/example/repo.git"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	t.Chdir(repo)
+
+	cfg := &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir(), AutoMkdir: true, ProtectedBranches: []string{"release/*"}}}
+	cfg.Naming.Template = "{{.Branch}}"
+	cfg.Naming.SanitizeChars = map[string]string{"/": "-"}
+
+	tests := []struct {
+		name    string
+		branch  string
+		base    string
+		wantErr string
+	}{
+		{name: "NewBranch", branch: "feature/new"},
+		{name: "ExistingBranch", branch: "existing"},
+		{name: "FromBase", branch: "feature/based", base: "main"},
+		{name: "CreateFails", branch: "feature/bad", base: "no-such-ref", wantErr: "Create it explicitly with 'gwq add'"},
+		{name: "ExistingProtectedBranch", branch: "release/1", wantErr: "is protected"},
+		{name: "NewProtectedName", branch: "release/2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			path, err := cdNewWorktree(&out, cfg, tt.branch, tt.base)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("cdNewWorktree() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cdNewWorktree() error = %v", err)
+			}
+			if want := filepath.Join(cfg.Worktree.BaseDir, strings.ReplaceAll(tt.branch, "/", "-")); path != want {
+				t.Errorf("path = %q, want %q", path, want)
+			}
+			head, err := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+			if err != nil || strings.TrimSpace(string(head)) != tt.branch {
+				t.Errorf("worktree is on %q, want %q", head, tt.branch)
+			}
+			if !strings.Contains(out.String(), "Created worktree for branch '"+tt.branch+"'") {
+				t.Errorf("message = %q", out.String())
+			}
+		})
+	}
+}
+
+func TestCdNewWorktree_ExactBranch(t *testing.T) {
+	repo := initTestGitRepo(t)
+	if out, err := exec.Command("git", "-C", repo, "remote", "add", "origin", "https://github.com/example/repo.git").CombinedOutput(); err != nil {
+		t.Fatalf("git remote add failed: %v: %s", err, out)
+	}
+	t.Chdir(repo)
+
+	cfg := &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir(), AutoMkdir: true}}
+	cfg.Naming.Template = "{{.Branch}}"
+
+	var out bytes.Buffer
+	feature, err := cdNewWorktree(&out, cfg, "feature", "")
+	if err != nil {
+		t.Fatalf("cdNewWorktree(feature) error = %v", err)
+	}
+
+	// An existing worktree for the exact branch is reused.
+	out.Reset()
+	if path, err := cdNewWorktree(&out, cfg, "feature", ""); err != nil || path != feature || out.Len() != 0 {
+		t.Errorf("cdNewWorktree(feature) again = %q, %v (output %q); want %q without creating", path, err, out.String(), feature)
+	}
+
+	// A prefix of an existing branch gets its own worktree.
+	feat, err := cdNewWorktree(&out, cfg, "feat", "")
+	if err != nil {
+		t.Fatalf("cdNewWorktree(feat) error = %v", err)
+	}
+	if feat == feature || filepath.Base(feat) != "feat" {
+		t.Errorf("cdNewWorktree(feat) = %q, want a new worktree for feat", feat)
+	}
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// claudeContext is the session context of Claude tasks, as set by
+// 'gwq tmux run --agent claude'.
+const claudeContext = "claude"
+
+var claudeCmd = &cobra.Command{
+	Use:   "claude",
+	Short: "Inspect Claude tasks running in tmux sessions",
+	Long: `Inspect Claude tasks started with 'gwq tmux run --agent claude'.
+
+Tasks are the tmux sessions whose context is "claude".`,
+	Example: `  # Start a task, then list running tasks
+  gwq tmux run --agent claude -w feature/auth "add login tests"
+  gwq claude list`,
+}
+
+func init() {
+	rootCmd.AddCommand(claudeCmd)
+}
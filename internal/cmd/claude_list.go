@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/agent"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/table"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var claudeListOutput string
+
+var claudeListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List Claude tasks and their worktrees",
+	Long: `List the Claude tasks running in tmux sessions with their task ID,
+worktree, duration and state.
+
+The state is "completed" or "failed" once the task's exit code has been
+recorded or Claude's result marker appears in its pane (see the claude
+agent's completion patterns), and "running" otherwise. The task ID is only
+known for tasks started with task_log enabled.`,
+	Example: `  # List Claude tasks
+  gwq claude list
+
+  # JSON output for scripting
+  gwq claude list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runClaudeList,
+}
+
+func init() {
+	claudeCmd.AddCommand(claudeListCmd)
+
+	claudeListCmd.Flags().StringVarP(&claudeListOutput, "output", "o", "table", "Output format (table, json)")
+	_ = claudeListCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// claudeTask is a row of 'gwq claude list'.
+type claudeTask struct {
+	TaskID   string    `json:"task_id,omitempty"`
+	Session  string    `json:"session"`
+	Worktree string    `json:"worktree"`
+	Started  time.Time `json:"started"`
+	Duration string    `json:"duration"`
+	State    string    `json:"state"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+}
+
+func runClaudeList(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(claudeListOutput)
+	if format != "table" && format != "json" {
+		return &usageError{err: fmt.Errorf("invalid output format %q: must be table or json", claudeListOutput)}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	claude, err := agent.NewDefaultRegistry(cfg).Get(claudeContext)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := tmux.NewSessionManager(nil).ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	tmuxCmd := tmux.NewTmuxCommand("")
+	now := time.Now()
+	var tasks []claudeTask
+	for _, session := range filterClaudeSessions(sessions) {
+		capture := func() (string, error) { return tmuxCmd.CapturePane(session.SessionName, 0) }
+		tasks = append(tasks, newClaudeTask(session, capture, claude, now))
+	}
+
+	w := cmd.OutOrStdout()
+	if format == "json" {
+		if tasks == nil {
+			tasks = []claudeTask{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(tasks)
+	}
+	return printClaudeTasks(w, tasks, cfg.UI.TildeHome)
+}
+
+// filterClaudeSessions keeps the sessions of Claude tasks.
+func filterClaudeSessions(sessions []*tmux.Session) []*tmux.Session {
+	var claude []*tmux.Session
+	for _, session := range sessions {
+		if session.Context == claudeContext {
+			claude = append(claude, session)
+		}
+	}
+	return claude
+}
+
+// newClaudeTask describes session as of now. A state that cannot be
+// determined is reported as running.
+func newClaudeTask(session *tmux.Session, capture func() (string, error), claude agent.Agent, now time.Time) claudeTask {
+	task := claudeTask{
+		TaskID:   session.Metadata[sessionTaskKey],
+		Session:  session.SessionName,
+		Worktree: session.Metadata[sessionWorktreeKey],
+		Started:  session.StartTime,
+		Duration: formatDuration(now.Sub(session.StartTime)),
+		State:    "running",
+	}
+	if task.Worktree == "" {
+		task.Worktree = session.WorkingDir
+	}
+
+	status, err := checkExecutionStatus(session, capture, claude)
+	if err == nil && status.Done {
+		task.State = "completed"
+		if status.ExitCode != 0 {
+			task.State = "failed"
+		}
+		task.ExitCode = &status.ExitCode
+	}
+	return task
+}
+
+// formatDuration renders d for the task list, e.g. "45s", "12m" or
+// "3h05m".
+func formatDuration(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+func printClaudeTasks(w io.Writer, tasks []claudeTask, tildeHome bool) error {
+	if len(tasks) == 0 {
+		_, err := fmt.Fprintln(w, "No Claude tasks found")
+		return err
+	}
+
+	t := table.New().SetOutput(w).Headers("TASK", "WORKTREE", "DURATION", "STATE")
+	for _, task := range tasks {
+		id := task.TaskID
+		if id == "" {
+			id = task.Session
+		}
+		worktree := task.Worktree
+		if tildeHome {
+			worktree = utils.TildePath(worktree)
+		}
+		state := task.State
+		if task.State == "failed" {
+			state += " (exit " + strconv.Itoa(*task.ExitCode) + ")"
+		}
+		t.Row(id, worktree, task.Duration, state)
+	}
+	return t.Println()
+}
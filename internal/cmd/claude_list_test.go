@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/agent"
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+func TestFilterClaudeSessions(t *testing.T) {
+	sessions := []*tmux.Session{
+		{SessionName: "gwq-claude-auth-20240101120000", Context: "claude"},
+		{SessionName: "gwq-run-build-20240101120000", Context: "run"},
+		{SessionName: "gwq-aider-docs-20240101120000", Context: "aider"},
+		{SessionName: "gwq-claude-tests-20240101120000", Context: "claude"},
+	}
+
+	var got []string
+	for _, s := range filterClaudeSessions(sessions) {
+		got = append(got, s.SessionName)
+	}
+	want := []string{"gwq-claude-auth-20240101120000", "gwq-claude-tests-20240101120000"}
+	if !slices.Equal(got, want) {
+		t.Errorf("filterClaudeSessions() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "0s"},
+		{0, "0s"},
+		{45*time.Second + 400*time.Millisecond, "45s"},
+		{time.Minute, "1m"},
+		{59*time.Minute + 59*time.Second, "59m"},
+		{time.Hour, "1h00m"},
+		{3*time.Hour + 5*time.Minute, "3h05m"},
+		{49 * time.Hour, "49h00m"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestNewClaudeTask(t *testing.T) {
+	claude, err := agent.NewDefaultRegistry(nil).Get("claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(90 * time.Second)
+	exitFile := filepath.Join(t.TempDir(), "abc.exit")
+	if err := os.WriteFile(exitFile, []byte("2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pane := func(s string) func() (string, error) {
+		return func() (string, error) { return s, nil }
+	}
+
+	tests := []struct {
+		name     string
+		session  *tmux.Session
+		capture  func() (string, error)
+		want     claudeTask
+		wantExit int
+	}{
+		{
+			name: "running task with metadata",
+			session: &tmux.Session{
+				SessionName: "gwq-claude-auth-20240101120000",
+				WorkingDir:  "/wt/auth/sub",
+				StartTime:   start,
+				Metadata:    map[string]string{sessionTaskKey: "t1", sessionWorktreeKey: "/wt/auth"},
+			},
+			capture: pane("thinking..."),
+			want:    claudeTask{TaskID: "t1", Session: "gwq-claude-auth-20240101120000", Worktree: "/wt/auth", Duration: "1m", State: "running"},
+		},
+		{
+			name:    "completed by result marker",
+			session: &tmux.Session{SessionName: "s2", WorkingDir: "/wt/docs", StartTime: start},
+			capture: pane(`{"type":"result","subtype":"success"}`),
+			want:    claudeTask{Session: "s2", Worktree: "/wt/docs", Duration: "1m", State: "completed"},
+		},
+		{
+			name:     "failed by exit sentinel",
+			session:  &tmux.Session{SessionName: "s3", StartTime: start, Metadata: map[string]string{tmux.MetadataExitFile: exitFile}},
+			capture:  pane("thinking..."),
+			want:     claudeTask{Session: "s3", Duration: "1m", State: "failed"},
+			wantExit: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newClaudeTask(tt.session, tt.capture, claude, now)
+			if (got.ExitCode != nil) != (tt.want.State != "running") {
+				t.Fatalf("ExitCode = %v for state %s", got.ExitCode, got.State)
+			}
+			if got.ExitCode != nil && *got.ExitCode != tt.wantExit {
+				t.Errorf("ExitCode = %d, want %d", *got.ExitCode, tt.wantExit)
+			}
+			got.ExitCode = nil
+			tt.want.Started = start
+			if got != tt.want {
+				t.Errorf("newClaudeTask() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintClaudeTasks(t *testing.T) {
+	code := 1
+	tasks := []claudeTask{
+		{TaskID: "t1", Session: "s1", Worktree: "/wt/auth", Duration: "5m", State: "running"},
+		{Session: "gwq-claude-docs-20240101120000", Worktree: "/wt/docs", Duration: "1h00m", State: "failed", ExitCode: &code},
+	}
+
+	var buf bytes.Buffer
+	if err := printClaudeTasks(&buf, tasks, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TASK", "t1", "/wt/auth", "running", "gwq-claude-docs-20240101120000", "failed (exit 1)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := printClaudeTasks(&buf, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No Claude tasks found\n" {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanDryRun bool
+	cleanYes    bool
+	cleanPrune  bool
+)
+
+// cleanCmd represents the clean command.
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove worktrees whose directories were deleted",
+	Long: `Find worktrees that git still tracks but whose directories no longer exist,
+for example because they were deleted with rm instead of 'gwq remove'.
+
+The orphaned worktrees are offered in a multi-select finder and removed after
+confirmation. With --prune, 'git worktree prune' runs afterwards to clean up
+any remaining stale administrative files.`,
+	Example: `  # Pick orphaned worktrees to remove
+  gwq clean
+
+  # List orphaned worktrees without removing anything
+  gwq clean --dry-run
+
+  # Remove without the confirmation prompt and prune afterwards
+  gwq clean --yes --prune`,
+	Args: cobra.NoArgs,
+	RunE: ExecuteWithArgs(true, runClean),
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "List orphaned worktrees without removing them")
+	cleanCmd.Flags().BoolVarP(&cleanYes, "yes", "y", false, "Skip the confirmation prompt")
+	cleanCmd.Flags().BoolVar(&cleanPrune, "prune", false, "Run 'git worktree prune' after removing")
+}
+
+func runClean(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	orphaned, err := ctx.WorktreeManager.FindOrphaned()
+	if err != nil {
+		return fmt.Errorf("failed to find orphaned worktrees: %w", err)
+	}
+
+	if len(orphaned) == 0 {
+		_, _ = fmt.Fprintln(out, "No orphaned worktrees found")
+	} else if cleanDryRun {
+		_, _ = fmt.Fprintln(out, "Orphaned worktrees:")
+		for _, wt := range orphaned {
+			_, _ = fmt.Fprintf(out, "  %s (%s)\n", wt.Branch, wt.Path)
+		}
+		return nil
+	} else {
+		selected, err := ctx.GetFinder().SelectMultipleWorktrees(orphaned)
+		if err != nil {
+			return fmt.Errorf("worktree selection cancelled: %w", err)
+		}
+		if !cleanYes && !confirmClean(os.Stdin, out, selected) {
+			_, _ = fmt.Fprintln(out, "Operation cancelled")
+			return nil
+		}
+
+		for _, wt := range selected {
+			// The directory is already gone, so there is nothing to lose by
+			// letting git drop its administrative files.
+			if err := ctx.WorktreeManager.Remove(wt.Path, true); err != nil {
+				ctx.Printer.PrintError(fmt.Errorf("failed to remove %s: %v", wt.Branch, err))
+				continue
+			}
+			if reg, err := registry.New(); err == nil {
+				_ = reg.Unregister(wt.Path)
+			}
+			ctx.Printer.PrintSuccess(fmt.Sprintf("Removed orphaned worktree: %s", wt.Branch))
+		}
+	}
+
+	if cleanPrune && !cleanDryRun {
+		if err := ctx.WorktreeManager.Prune(); err != nil {
+			return fmt.Errorf("failed to prune worktrees: %w", err)
+		}
+		ctx.Printer.PrintSuccess("Pruned stale worktree information")
+	}
+	return nil
+}
+
+// confirmClean lists the worktrees about to be removed and asks for
+// confirmation.
+func confirmClean(in io.Reader, out io.Writer, worktrees []models.Worktree) bool {
+	_, _ = fmt.Fprintf(out, "This will remove %d orphaned worktree(s):\n", len(worktrees))
+	for _, wt := range worktrees {
+		_, _ = fmt.Fprintf(out, "  %s (%s)\n", wt.Branch, wt.Path)
+	}
+	_, _ = fmt.Fprint(out, "Are you sure? (y/N): ")
+
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
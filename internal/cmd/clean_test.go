@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestConfirmClean(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Branch: "feature/gone", Path: "/tmp/worktrees/feature-gone"},
+		{Branch: "fix/old", Path: "/tmp/worktrees/fix-old"},
+	}
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: "Yes\n", want: true},
+		{input: "n\n"},
+		{input: ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirmClean(strings.NewReader(tt.input), &out, worktrees); got != tt.want {
+			t.Errorf("confirmClean(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		for _, wt := range worktrees {
+			if !strings.Contains(out.String(), wt.Path) {
+				t.Errorf("prompt = %q, want it to list %s", out.String(), wt.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <url> [branch]",
+	Short: "Clone a repository into the worktree base directory",
+	Long: `Clone a repository into the configured base directory, at the same path
+'gwq add' would use for a worktree of the given branch
+(by default <basedir>/<host>/<owner>/<repo>/<branch>).
+
+The branch defaults to the remote's default branch. The clone becomes the
+repository's main worktree, so further worktrees can be added from it with
+'gwq add'.
+
+The path of the new checkout is printed. Under shell integration the current
+shell changes into it instead.`,
+	Example: `  # Clone the default branch
+  gwq clone https://github.com/user/repo.git
+
+  # Clone and check out a specific branch
+  gwq clone git@github.com:user/repo.git develop`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: ExecuteWithArgs(false, runClone),
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+}
+
+func runClone(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	var branch string
+	if len(args) > 1 {
+		branch = args[1]
+	}
+
+	// git's progress goes to stderr so stdout carries only the path.
+	path, err := worktree.Clone(context.Background(), command.NewStandardExecutor(), ctx.Config, args[0], branch, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	printCloneResult(os.Stdout, os.Stderr, isCdShimActive(), path)
+	return nil
+}
+
+// printCloneResult reports a successful clone. Under shell integration
+// (inShim) the message goes to stderr and stdout carries only the path, so
+// the shell wrapper changes into it.
+func printCloneResult(stdout, stderr io.Writer, inShim bool, path string) {
+	if inShim {
+		_, _ = fmt.Fprintf(stderr, "Cloned into %s\n", path)
+		_, _ = fmt.Fprintln(stdout, path)
+		return
+	}
+	_, _ = fmt.Fprintln(stdout, path)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/d-kuro/gwq/internal/git"
 	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/internal/tmux"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -107,16 +108,29 @@ func getConfigKeyCompletions(_ *cobra.Command, args []string, toComplete string)
 	}{
 		{"worktree.basedir", "Base directory for worktrees"},
 		{"worktree.auto_mkdir", "Automatically create directories"},
+		{"worktree.setup_shell", "Shell used to run setup_commands (default: sh)"},
+		{"worktree.open_command", "Program 'gwq open' runs with the worktree path (default: system opener)"},
+		{"worktree.protected_branches", "Branch patterns 'gwq add' refuses without --allow-protected"},
+		{"worktree.track_remote_if_exists", "Track a same-named remote branch, or create the branch, in 'gwq add <branch>'"},
 		{"finder.preview", "Enable preview window"},
 		{"finder.preview_size", "Preview window size"},
+		{"finder.sort_by", "Worktree order in the finder: name, path, activity, commit-date"},
+		{"finder.display_template", "Template for worktree lines in the finder"},
+		{"finder.status_icons", "Symbols emitted by {{.StatusIcon}} per worktree state"},
 		{"finder.keybind_select", "Key binding for selection"},
 		{"finder.keybind_cancel", "Key binding for cancellation"},
 		{"naming.template", "Directory name template"},
+		{"naming.status_template", "Repository column template for status"},
 		{"ui.color", "Enable colored output"},
 		{"ui.icons", "Enable icon display"},
 		{"ui.tilde_home", "Display home directory as ~"},
 		{"cd.launch_shell", "Launch new shell on cd (default: true)"},
 		{"cd.auto_cd_on_add", "Auto-cd after 'gwq add' under shell integration (default: false)"},
+		{"status.activity_sample_size", "Tracked files checked per worktree for the last activity"},
+		{"process_detect.agent_names", "Commands tagged as AI agents by status --show-processes"},
+		{"task_log.enabled", "Log 'gwq tmux run' task lifecycle as JSON lines"},
+		{"task_log.path", "Task log file (default: tasks.jsonl in the config directory)"},
+		{"agents", "Coding agents for 'gwq tmux run --agent', keyed by name"},
 	}
 
 	var completions []string
@@ -160,3 +174,28 @@ func getRemoveCompletions(cmd *cobra.Command, args []string, toComplete string)
 
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
+
+// getTmuxSessionCompletions returns gwq tmux session identifiers for shell
+// completion
+func getTmuxSessionCompletions(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sessions, err := tmux.NewSessionManager(nil).ListSessions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return sessionCompletions(sessions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func sessionCompletions(sessions []*tmux.Session, toComplete string) []string {
+	var completions []string
+	for _, session := range sessions {
+		if strings.HasPrefix(session.Identifier, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\tSession: %s", session.Identifier, session.SessionName))
+		}
+	}
+	return completions
+}
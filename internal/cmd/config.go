@@ -1,10 +1,21 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/template"
 	"github.com/d-kuro/gwq/internal/ui"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
 )
 
@@ -31,7 +42,11 @@ var configSetCmd = &cobra.Command{
 	Short: "Set configuration value",
 	Long: `Set a configuration value.
 
-Configuration keys follow a dot notation format (e.g., worktree.basedir).`,
+Configuration keys follow a dot notation format (e.g., worktree.basedir).
+
+With --dry-run, shows which file would be written and the key's value in
+that file and in the effective configuration before and after, without
+writing anything.`,
 	Example: `  # Set worktree base directory
   gwq config set worktree.basedir ~/worktrees
 
@@ -39,7 +54,10 @@ Configuration keys follow a dot notation format (e.g., worktree.basedir).`,
   gwq config set naming.template "{{.Repository}}-{{.Branch}}"
 
   # Enable/disable colored output
-  gwq config set ui.color true`,
+  gwq config set ui.color true
+
+  # Preview a local override without writing it
+  gwq config set --local --dry-run finder.preview false`,
 	Args:              cobra.ExactArgs(2),
 	RunE:              runConfigSet,
 	ValidArgsFunction: getConfigKeyCompletions,
@@ -60,15 +78,60 @@ var configGetCmd = &cobra.Command{
 	ValidArgsFunction: getConfigKeyCompletions,
 }
 
-var configSetLocal bool
+// configDiffCmd represents the config diff command.
+var configDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show settings overridden by the local config",
+	Long: `Show how the local .gwq.toml in the current directory changes the global
+configuration.
+
+Lines starting with '-' show the global value and lines starting with '+'
+the local value that replaces it. repository_settings entries are matched by
+their repository field; a local entry replaces the whole global entry, so
+fields it leaves out are shown as removed. Only the two files are compared:
+defaults are not included, and the local file is read even if it has not
+been trusted yet.`,
+	Example: `  # Show local overrides
+  gwq config diff
+
+  # JSON output for scripting
+  gwq config diff -o json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDiff,
+}
+
+// configValidateCmd represents the config validate command.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check configuration for errors",
+	Long: `Check the current configuration (global and local) for errors.
+
+Every problem found is reported on its own line, prefixed with the offending
+configuration key. The command exits with a non-zero status if any check
+fails, so it can be used in CI.`,
+	Example: `  # Validate the current configuration
+  gwq config validate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+var (
+	configSetLocal   bool
+	configSetDryRun  bool
+	configDiffOutput string
+)
 
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configDiffCmd)
+	configCmd.AddCommand(configValidateCmd)
 
 	configSetCmd.Flags().BoolVar(&configSetLocal, "local", false, "Write to local config (.gwq.toml) instead of global")
+	configSetCmd.Flags().BoolVar(&configSetDryRun, "dry-run", false, "Show what would change without writing")
+	configDiffCmd.Flags().StringVarP(&configDiffOutput, "output", "o", "text", "Output format (text, json)")
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
@@ -103,6 +166,15 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if configSetDryRun {
+		plan, err := config.PlanSet(key, typedValue, configSetLocal)
+		if err != nil {
+			return fmt.Errorf("failed to plan config change: %w", err)
+		}
+		printSetPlan(cmd.OutOrStdout(), plan)
+		return nil
+	}
+
 	var err error
 	if configSetLocal {
 		err = config.SetLocal(key, typedValue)
@@ -143,3 +215,213 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 	fmt.Println(value)
 	return nil
 }
+
+// configProblem is a single failed configuration check.
+type configProblem struct {
+	Key     string
+	Message string
+}
+
+func (p configProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	rawBaseDir, _ := config.GetValue("worktree.basedir").(string)
+	problems := validateConfig(cfg, rawBaseDir)
+	if len(problems) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid")
+		return nil
+	}
+
+	w := cmd.ErrOrStderr()
+	for _, p := range problems {
+		_, _ = fmt.Fprintln(w, p)
+	}
+	return fmt.Errorf("configuration has %d problem(s)", len(problems))
+}
+
+// validateConfig checks a loaded configuration. rawBaseDir is worktree.basedir
+// as written, before Load resolved it against the current directory.
+func validateConfig(cfg *models.Config, rawBaseDir string) []configProblem {
+	var problems []configProblem
+
+	if err := validateBaseDir(rawBaseDir); err != nil {
+		problems = append(problems, configProblem{Key: "worktree.basedir", Message: err.Error()})
+	}
+
+	for i, pattern := range cfg.Worktree.ProtectedBranches {
+		if err := utils.ValidatePattern(pattern); err != nil {
+			problems = append(problems, configProblem{
+				Key:     fmt.Sprintf("worktree.protected_branches[%d]", i),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if cfg.Status.ActivitySampleSize < 0 {
+		problems = append(problems, configProblem{Key: "status.activity_sample_size", Message: "must not be negative"})
+	}
+
+	for i, rs := range cfg.RepositorySettings {
+		if err := utils.ValidatePattern(rs.Repository); err != nil {
+			problems = append(problems, configProblem{
+				Key:     fmt.Sprintf("repository_settings[%d].repository", i),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if _, err := template.New(cfg.Naming.Template, cfg.Naming.SanitizeChars); err != nil {
+		problems = append(problems, configProblem{Key: "naming.template", Message: err.Error()})
+	}
+	if cfg.Naming.StatusTemplate != "" {
+		if _, err := template.NewDisplay(cfg.Naming.StatusTemplate); err != nil {
+			problems = append(problems, configProblem{Key: "naming.status_template", Message: err.Error()})
+		}
+	}
+
+	if cfg.Finder.DisplayTemplate != "" {
+		if _, err := template.NewDisplay(cfg.Finder.DisplayTemplate); err != nil {
+			problems = append(problems, configProblem{Key: "finder.display_template", Message: err.Error()})
+		}
+	}
+	for state := range cfg.Finder.StatusIcons {
+		if _, err := models.ParseWorktreeState(state); err != nil {
+			problems = append(problems, configProblem{Key: "finder.status_icons." + state, Message: err.Error()})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Agents)) {
+		ac := cfg.Agents[name]
+		for _, list := range []struct {
+			key      string
+			patterns []string
+		}{
+			{"completion_patterns", ac.CompletionPatterns},
+			{"success_patterns", ac.SuccessPatterns},
+			{"error_patterns", ac.ErrorPatterns},
+		} {
+			for i, pattern := range list.patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					problems = append(problems, configProblem{
+						Key:     fmt.Sprintf("agents.%s.%s[%d]", name, list.key, i),
+						Message: err.Error(),
+					})
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateBaseDir requires the base directory to be set and to be absolute
+// once environment variables and a leading ~ are expanded.
+func validateBaseDir(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("is not set")
+	}
+	if _, err := utils.ExpandPath(raw); err != nil {
+		return err
+	}
+	expanded := os.ExpandEnv(raw)
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") {
+		return nil
+	}
+	if !filepath.IsAbs(expanded) {
+		return fmt.Errorf("%q is not an absolute path", raw)
+	}
+	return nil
+}
+
+func runConfigDiff(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(configDiffOutput)
+	if format != "text" && format != "json" {
+		return &usageError{err: fmt.Errorf("invalid output format %q: must be text or json", configDiffOutput)}
+	}
+
+	diff, err := config.DiffLocal()
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+	printConfigDiff(cmd.OutOrStdout(), diff)
+	return nil
+}
+
+// printConfigDiff writes diff in a unified-diff-like form: the global value
+// of each changed key prefixed with '-', then the local value with '+'.
+func printConfigDiff(w io.Writer, diff *config.LocalDiff) {
+	if diff.LocalPath == "" {
+		_, _ = fmt.Fprintln(w, "No local config (.gwq.toml) in the current directory")
+		return
+	}
+	if len(diff.Changes) == 0 {
+		_, _ = fmt.Fprintf(w, "%s does not change any settings\n", diff.LocalPath)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "--- global: %s\n", diff.GlobalPath)
+	_, _ = fmt.Fprintf(w, "+++ local:  %s\n", diff.LocalPath)
+	for _, c := range diff.Changes {
+		if c.Change != config.ChangeAdded {
+			_, _ = fmt.Fprintf(w, "- %s = %s\n", c.Key, formatConfigValue(c.Global))
+		}
+		if c.Change != config.ChangeRemoved {
+			_, _ = fmt.Fprintf(w, "+ %s = %s\n", c.Key, formatConfigValue(c.Local))
+		}
+	}
+}
+
+// printSetPlan describes a 'config set --dry-run'.
+func printSetPlan(w io.Writer, plan *config.SetPlan) {
+	scope := "global"
+	if plan.Local {
+		scope = "local"
+	}
+	action := "update"
+	if !plan.Exists {
+		action = "create"
+	}
+	_, _ = fmt.Fprintf(w, "Would %s %s config: %s\n", action, scope, plan.Path)
+	_, _ = fmt.Fprintf(w, "  %s: %s -> %s\n", plan.Key, formatPlanValue(plan.Current), formatConfigValue(plan.Proposed))
+
+	effective := plan.Proposed
+	if plan.ShadowedBy != "" {
+		effective = plan.Effective
+	}
+	_, _ = fmt.Fprintf(w, "Effective value: %s -> %s\n", formatPlanValue(plan.Effective), formatPlanValue(effective))
+	if plan.ShadowedBy != "" {
+		_, _ = fmt.Fprintf(w, "Note: %s is overridden by %s\n", plan.Key, plan.ShadowedBy)
+	}
+	_, _ = fmt.Fprintln(w, "Dry run: nothing was written")
+}
+
+// formatPlanValue is formatConfigValue with nil shown as unset.
+func formatPlanValue(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	return formatConfigValue(v)
+}
+
+// formatConfigValue renders a setting value with strings quoted, falling
+// back to %v for values JSON cannot encode.
+func formatConfigValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configProfileCmd represents the config profile command.
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named configuration profiles",
+	Long: `Manage named configuration profiles.
+
+Profiles live under [profiles.<name>] in the global config file and contain
+any subset of the regular settings. The active profile is applied on top of
+the global config, merging repository_settings by repository like a local
+.gwq.toml does; a local .gwq.toml is still applied last.`,
+}
+
+var configProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Long:  `List all defined profiles. The active profile is marked with '*'.`,
+	Example: `  # List profiles
+  gwq config profile list`,
+	Args: cobra.NoArgs,
+	RunE: runConfigProfileList,
+}
+
+var configProfileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Activate a profile",
+	Long: `Activate a profile by writing active_profile to the global config.
+
+Pass an empty name to go back to the plain global config.`,
+	Example: `  # Use the work profile
+  gwq config profile use work
+
+  # Deactivate profiles
+  gwq config profile use ""`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runConfigProfileUse,
+	ValidArgsFunction: getProfileCompletions,
+}
+
+var configProfileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Save the current configuration as a profile",
+	Long: `Save the current effective configuration, including the active profile and
+any local .gwq.toml overrides, as a new profile in the global config.`,
+	Example: `  # Snapshot the current settings as "personal"
+  gwq config profile create personal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigProfileCreate,
+}
+
+func init() {
+	configCmd.AddCommand(configProfileCmd)
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileUseCmd)
+	configProfileCmd.AddCommand(configProfileCreateCmd)
+}
+
+func runConfigProfileList(cmd *cobra.Command, args []string) error {
+	printProfiles(cmd.OutOrStdout(), config.ProfileNames(), config.ActiveProfile())
+	return nil
+}
+
+// printProfiles lists profile names, marking the active one with '*'.
+func printProfiles(w io.Writer, names []string, active string) {
+	if len(names) == 0 {
+		_, _ = fmt.Fprintln(w, "No profiles defined")
+		return
+	}
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%s %s\n", marker, name)
+	}
+}
+
+func runConfigProfileUse(cmd *cobra.Command, args []string) error {
+	if err := config.UseProfile(args[0]); err != nil {
+		return fmt.Errorf("failed to use profile: %w", err)
+	}
+	if args[0] == "" {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Deactivated configuration profiles")
+		return nil
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Using profile %s\n", config.ActiveProfile())
+	return nil
+}
+
+func runConfigProfileCreate(cmd *cobra.Command, args []string) error {
+	if err := config.CreateProfile(args[0]); err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created profile %s from the current configuration\n", args[0])
+	return nil
+}
+
+func getProfileCompletions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
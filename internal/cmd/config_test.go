@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestValidateConfig(t *testing.T) {
+	valid := func() *models.Config {
+		return &models.Config{
+			Worktree: models.WorktreeConfig{BaseDir: "/home/user/worktrees"},
+			Naming:   models.NamingConfig{Template: "{{.Host}}/{{.Owner}}/{{.Repository}}/{{.Branch}}"},
+			RepositorySettings: []models.RepositorySetting{
+				{Repository: "/home/user/src/repo"},
+				{Repository: "**/github.com/owner/*"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		rawBaseDir string
+		modify     func(*models.Config)
+		wantKeys   []string
+	}{
+		{name: "valid", rawBaseDir: "~/worktrees"},
+		{name: "env variable", rawBaseDir: "$HOME/worktrees"},
+		{name: "absolute", rawBaseDir: "/srv/worktrees"},
+		{name: "empty basedir", rawBaseDir: "", wantKeys: []string{"worktree.basedir"}},
+		{name: "relative basedir", rawBaseDir: "worktrees", wantKeys: []string{"worktree.basedir"}},
+		{
+			name:       "bad finder display template",
+			rawBaseDir: "~/worktrees",
+			modify: func(cfg *models.Config) {
+				cfg.Finder.DisplayTemplate = "{{.Branch"
+				cfg.Finder.StatusIcons = map[string]string{"modified": "M", "dirty": "D"}
+			},
+			wantKeys: []string{"finder.display_template", "finder.status_icons.dirty"},
+		},
+		{
+			name:       "bad protected branch glob",
+			rawBaseDir: "~/worktrees",
+			modify: func(cfg *models.Config) {
+				cfg.Worktree.ProtectedBranches = []string{"main", "release/[0-9"}
+			},
+			wantKeys: []string{"worktree.protected_branches[1]"},
+		},
+		{
+			name:       "negative activity sample size",
+			rawBaseDir: "~/worktrees",
+			modify:     func(cfg *models.Config) { cfg.Status.ActivitySampleSize = -1 },
+			wantKeys:   []string{"status.activity_sample_size"},
+		},
+		{
+			name:       "bad repository glob",
+			rawBaseDir: "~/worktrees",
+			modify: func(cfg *models.Config) {
+				cfg.RepositorySettings = append(cfg.RepositorySettings, models.RepositorySetting{Repository: "**/owner/[abc"})
+			},
+			wantKeys: []string{"repository_settings[2].repository"},
+		},
+		{
+			name:       "bad template",
+			rawBaseDir: "~/worktrees",
+			modify:     func(cfg *models.Config) { cfg.Naming.Template = "{{.Branch" },
+			wantKeys:   []string{"naming.template"},
+		},
+		{
+			name:       "bad status template",
+			rawBaseDir: "~/worktrees",
+			modify:     func(cfg *models.Config) { cfg.Naming.StatusTemplate = "{{.Owner" },
+			wantKeys:   []string{"naming.status_template"},
+		},
+		{
+			name:       "bad agent pattern",
+			rawBaseDir: "~/worktrees",
+			modify: func(cfg *models.Config) {
+				cfg.Agents = map[string]models.AgentConfig{
+					"aider": {CompletionPatterns: []string{"ok", "(unclosed"}},
+				}
+			},
+			wantKeys: []string{"agents.aider.completion_patterns[1]"},
+		},
+		{
+			name:       "every check fails",
+			rawBaseDir: "relative",
+			modify: func(cfg *models.Config) {
+				cfg.RepositorySettings[0].Repository = "[*"
+				cfg.Naming.Template = "{{end}}"
+			},
+			wantKeys: []string{"worktree.basedir", "repository_settings[0].repository", "naming.template"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			if tt.modify != nil {
+				tt.modify(cfg)
+			}
+
+			var keys []string
+			for _, p := range validateConfig(cfg, tt.rawBaseDir) {
+				keys = append(keys, p.Key)
+			}
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("validateConfig() problem keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestPrintConfigDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff *config.LocalDiff
+		want string
+	}{
+		{
+			name: "no local config",
+			diff: &config.LocalDiff{GlobalPath: "/home/u/.config/gwq/config.toml"},
+			want: "No local config (.gwq.toml) in the current directory\n",
+		},
+		{
+			name: "no changes",
+			diff: &config.LocalDiff{GlobalPath: "/g.toml", LocalPath: "/repo/.gwq.toml"},
+			want: "/repo/.gwq.toml does not change any settings\n",
+		},
+		{
+			name: "changes",
+			diff: &config.LocalDiff{
+				GlobalPath: "/g.toml",
+				LocalPath:  "/repo/.gwq.toml",
+				Changes: []config.SettingChange{
+					{Key: "finder.preview", Change: config.ChangeChanged, Global: true, Local: false},
+					{Key: "naming.template", Change: config.ChangeAdded, Local: "{{.Branch}}"},
+					{Key: "repository_settings[~/src/app].copy_files", Change: config.ChangeRemoved, Global: []any{".env"}},
+				},
+			},
+			want: `--- global: /g.toml
++++ local:  /repo/.gwq.toml
+- finder.preview = true
++ finder.preview = false
++ naming.template = "{{.Branch}}"
+- repository_settings[~/src/app].copy_files = [".env"]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printConfigDiff(&buf, tt.diff)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printConfigDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintProfiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		names  []string
+		active string
+		want   string
+	}{
+		{name: "none", want: "No profiles defined\n"},
+		{name: "no active", names: []string{"personal", "work"}, want: "  personal\n  work\n"},
+		{name: "active", names: []string{"personal", "work"}, active: "work", want: "  personal\n* work\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printProfiles(&buf, tt.names, tt.active)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printProfiles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintSetPlan(t *testing.T) {
+	tests := []struct {
+		name string
+		plan *config.SetPlan
+		want string
+	}{
+		{
+			name: "global",
+			plan: &config.SetPlan{
+				Key: "worktree.basedir", Path: "/home/u/.config/gwq/config.toml", Exists: true,
+				Current: "~/worktrees", Proposed: "~/wt", Effective: "/home/u/worktrees",
+			},
+			want: `Would update global config: /home/u/.config/gwq/config.toml
+  worktree.basedir: "~/worktrees" -> "~/wt"
+Effective value: "/home/u/worktrees" -> "~/wt"
+Dry run: nothing was written
+`,
+		},
+		{
+			name: "new local file",
+			plan: &config.SetPlan{
+				Key: "finder.preview", Path: "/repo/.gwq.toml", Local: true,
+				Proposed: false, Effective: true,
+			},
+			want: `Would create local config: /repo/.gwq.toml
+  finder.preview: (unset) -> false
+Effective value: true -> false
+Dry run: nothing was written
+`,
+		},
+		{
+			name: "global shadowed by local",
+			plan: &config.SetPlan{
+				Key: "finder.preview", Path: "/g.toml", Exists: true,
+				Current: true, Proposed: false, Effective: true, ShadowedBy: "/repo/.gwq.toml",
+			},
+			want: `Would update global config: /g.toml
+  finder.preview: true -> false
+Effective value: true -> true
+Note: finder.preview is overridden by /repo/.gwq.toml
+Dry run: nothing was written
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printSetPlan(&buf, tt.plan)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printSetPlan() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
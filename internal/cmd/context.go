@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/d-kuro/gwq/internal/config"
 	"github.com/d-kuro/gwq/internal/discovery"
@@ -22,6 +26,10 @@ type CommandContext struct {
 	WorktreeManager *worktree.Manager
 	finder          *finder.Finder // Lazy-loaded
 	IsGitRepo       bool
+
+	// NoDiscoveryCache forces global discovery to rescan the base directory
+	// instead of reusing the on-disk discovery cache.
+	NoDiscoveryCache bool
 }
 
 // NewCommandContext creates a new command context for commands that don't require git.
@@ -95,8 +103,16 @@ func CreateGlobalFinder(cfg *models.Config) *finder.Finder {
 }
 
 // DiscoverGlobalWorktrees discovers global worktrees when -g flag is used.
+// Results are served from the discovery cache unless NoDiscoveryCache is set.
+// Ctrl+C stops a scan that hangs, e.g. on a slow network mount.
 func (ctx *CommandContext) DiscoverGlobalWorktrees() ([]*models.Worktree, error) {
-	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir)
+	scanCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	entries, err := discovery.DiscoverGlobalWorktreesCachedContext(scanCtx, ctx.Config.Worktree.BaseDir, &discovery.DiscoverOptions{
+		NoCache: ctx.NoDiscoveryCache,
+		Naming:  &ctx.Config.Naming,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -105,16 +121,34 @@ func (ctx *CommandContext) DiscoverGlobalWorktrees() ([]*models.Worktree, error)
 	var worktrees []*models.Worktree
 	for _, entry := range entries {
 		worktrees = append(worktrees, &models.Worktree{
-			Path:       entry.Path,
-			Branch:     entry.Branch,
-			CommitHash: entry.CommitHash,
-			IsMain:     entry.IsMain,
+			Path:              entry.Path,
+			Branch:            entry.Branch,
+			CommitHash:        entry.CommitHash,
+			IsMain:            entry.IsMain,
+			RepositoryInfo:    entry.RepositoryInfo,
+			BranchDirMismatch: entry.BranchDirMismatch,
 		})
 	}
 
 	return worktrees, nil
 }
 
+// discoverGlobalWorktreesMatching discovers the worktrees in baseDir for a
+// lookup by pattern. Directories whose path cannot match are skipped before
+// their git metadata is read; when that leaves no match, e.g. because a branch
+// no longer matches its directory name, the base directory is scanned in full.
+// Callers still filter the result with pattern.
+func discoverGlobalWorktreesMatching(baseDir, pattern string) ([]*discovery.GlobalWorktreeEntry, error) {
+	if pattern == "" {
+		return discovery.DiscoverGlobalWorktrees(baseDir)
+	}
+	entries, err := discovery.DiscoverGlobalWorktreesWithOptions(baseDir, &discovery.DiscoverOptions{Pattern: pattern})
+	if err != nil || len(discovery.FilterGlobalWorktrees(entries, pattern)) > 0 {
+		return entries, err
+	}
+	return discovery.DiscoverGlobalWorktrees(baseDir)
+}
+
 // GetWorktrees returns worktrees with support for both global and local modes
 func (ctx *CommandContext) GetWorktrees(forceGlobal bool) ([]*models.Worktree, error) {
 	// Use global discovery if forced or not in a git repository
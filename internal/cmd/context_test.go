@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/discovery"
+)
+
+func TestDiscoverGlobalWorktreesMatching(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	baseDir := t.TempDir()
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	loginDir := filepath.Join(baseDir, "github.com", "user", "repo", "feature-login")
+	oldDir := filepath.Join(baseDir, "github.com", "user", "repo", "old-dir")
+	for _, args := range [][]string{
+		{"init", "-b", "main", repoDir},
+		{"-C", repoDir, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "init"},
+		{"-C", repoDir, "remote", "add", "origin", "https://github.com/user/repo.git"},
+		{"-C", repoDir, "worktree", "add", "-b", "feature/login", loginDir},
+		{"-C", repoDir, "worktree", "add", "-b", "renamed/branch", oldDir},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		pattern   string
+		wantPaths []string
+	}{
+		{name: "path pre-filter", pattern: "login", wantPaths: []string{loginDir}},
+		{name: "branch differs from directory", pattern: "renamed", wantPaths: []string{repoDir, loginDir, oldDir}},
+		{name: "no pattern", wantPaths: []string{repoDir, loginDir, oldDir}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := discoverGlobalWorktreesMatching(baseDir, tt.pattern)
+			if err != nil {
+				t.Fatalf("discoverGlobalWorktreesMatching() error = %v", err)
+			}
+			var paths []string
+			for _, e := range entries {
+				paths = append(paths, e.Path)
+			}
+			for _, want := range tt.wantPaths {
+				if !slices.ContainsFunc(paths, func(p string) bool { return sameFile(p, want) }) {
+					t.Errorf("paths = %v, missing %s", paths, want)
+				}
+			}
+			if len(paths) != len(tt.wantPaths) {
+				t.Errorf("paths = %v, want %d entries", paths, len(tt.wantPaths))
+			}
+			if tt.pattern != "" && len(discovery.FilterGlobalWorktrees(entries, tt.pattern)) != 1 {
+				t.Errorf("FilterGlobalWorktrees(%q) did not find exactly one worktree in %v", tt.pattern, paths)
+			}
+		})
+	}
+}
+
+// sameFile reports whether a and b resolve to the same path, allowing for
+// symlinked temp directories.
+func sameFile(a, b string) bool {
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var copyPreserveTimestamps bool
+
+// copyCmd represents the copy command.
+var copyCmd = &cobra.Command{
+	Use:   "copy <src-pattern> <dst-pattern> <file>...",
+	Short: "Copy files from one worktree to another",
+	Long: `Copy files from one worktree of the current repository to another without
+merging, e.g. to try a change from one branch in another.
+
+Both worktrees are resolved like 'gwq switch' does: the first worktree whose
+branch or path matches the pattern. Files are given relative to the worktree
+root and may be glob patterns in copy_files syntax ("*" within a directory,
+"**" across directories, "!" to exclude). Each file is written to the same
+relative path in the destination, creating directories as needed and
+overwriting existing files.`,
+	Example: `  # Copy a file from the feature worktree to main
+  gwq copy feature main internal/cmd/root.go
+
+  # Copy all Go files under internal/, keeping their timestamps
+  gwq copy --preserve-timestamps feature/a feature/b 'internal/**/*.go'`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: ExecuteWithArgs(true, runCopy),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) < 2 {
+			return getWorktreeCompletions(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().BoolVar(&copyPreserveTimestamps, "preserve-timestamps", false, "Keep the modification times of the source files")
+}
+
+func runCopy(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	src, err := ctx.WorktreeManager.GetWorktreePath(args[0])
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	dst, err := ctx.WorktreeManager.GetWorktreePath(args[1])
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	if src == dst {
+		return &usageError{err: fmt.Errorf("source and destination are the same worktree: %s", src)}
+	}
+
+	return copyWorktreeFiles(cmd.OutOrStdout(), filesystem.NewStandardFileSystem(), src, dst, args[2:],
+		filesystem.CopyOptions{PreserveTimestamps: copyPreserveTimestamps})
+}
+
+// copyWorktreeFiles copies the files matching patterns from src to dst,
+// listing each copied file on w and each failure on stderr.
+func copyWorktreeFiles(w io.Writer, fs filesystem.FileSystemInterface, src, dst string, patterns []string, opts filesystem.CopyOptions) error {
+	copied, errs := worktree.CopyBetweenWorktrees(fs, src, dst, patterns, opts)
+	for _, rel := range copied {
+		_, _ = fmt.Fprintf(w, "Copied %s\n", rel)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	switch {
+	case len(errs) > 0:
+		return fmt.Errorf("failed to copy %d file(s)", len(errs))
+	case len(copied) == 0:
+		return fmt.Errorf("no files in %s match the given patterns", src)
+	}
+	return nil
+}
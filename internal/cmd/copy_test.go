@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+)
+
+func TestCopyWorktreeFiles(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fs := filesystem.NewStandardFileSystem()
+
+	tests := []struct {
+		name     string
+		patterns []string
+		wantOut  string
+		wantErr  bool
+	}{
+		{name: "copies and lists files", patterns: []string{"a.txt"}, wantOut: "Copied a.txt\n"},
+		{name: "no match is an error", patterns: []string{"missing.txt"}, wantErr: true},
+		{name: "invalid pattern is an error", patterns: []string{"/abs"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := copyWorktreeFiles(&buf, fs, src, dst, tt.patterns, filesystem.CopyOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("copyWorktreeFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if buf.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", buf.String(), tt.wantOut)
+			}
+		})
+	}
+}
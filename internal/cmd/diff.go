@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffGlobal bool
+	diffStat   bool
+)
+
+// diffCmd represents the diff command.
+var diffCmd = &cobra.Command{
+	Use:   "diff [pattern] [-- git-diff-args...]",
+	Short: "Show uncommitted changes of a worktree",
+	Long: `Show the uncommitted changes of a worktree without changing directory.
+
+The worktree is chosen like in 'gwq exec': by pattern, or with the fuzzy
+finder when no pattern is given or several worktrees match. The output is
+that of 'git diff HEAD' run in the worktree, so staged and unstaged changes
+are shown but untracked files are not. Arguments after -- are passed on to
+git diff.`,
+	Example: `  # Show the changes in the feature worktree
+  gwq diff feature
+
+  # Summarize them
+  gwq diff --stat feature
+
+  # Pick from all worktrees
+  gwq diff -g
+
+  # Ignore whitespace changes
+  gwq diff feature -- -w`,
+	RunE:              runDiff,
+	ValidArgsFunction: getWorktreeCompletions,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVarP(&diffGlobal, "global", "g", false, "Select from all worktrees")
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Show a diffstat instead of the patch")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	pattern, gitArgs, err := splitDiffArgs(args, cmd.ArgsLenAtDash())
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	var path string
+	if diffGlobal {
+		path, err = getGlobalWorktreePathForExec(cfg, pattern)
+	} else {
+		path, err = getLocalWorktreePathForExec(cfg, pattern)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeWorktreeDiff(cmd.OutOrStdout(), worktree.New(git.New(path), cfg), path, diffStat, gitArgs)
+}
+
+// splitDiffArgs splits the arguments into the optional pattern before -- and
+// the git diff arguments after it. dashAt is cobra's ArgsLenAtDash, -1 when
+// there is no --.
+func splitDiffArgs(args []string, dashAt int) (pattern string, gitArgs []string, err error) {
+	before := args
+	if dashAt >= 0 {
+		before, gitArgs = args[:dashAt], args[dashAt:]
+	}
+	if len(before) > 1 {
+		return "", nil, fmt.Errorf("accepts at most one pattern, got %d; pass git diff arguments after --", len(before))
+	}
+	if len(before) == 1 {
+		pattern = before[0]
+	}
+	return pattern, gitArgs, nil
+}
+
+// writeWorktreeDiff writes the diff of the worktree at path, or "No changes"
+// when there is nothing to show.
+func writeWorktreeDiff(w io.Writer, wm *worktree.Manager, path string, stat bool, gitArgs []string) error {
+	out, err := wm.Diff(path, stat, gitArgs...)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(out) == "" {
+		_, err = fmt.Fprintln(w, "No changes")
+		return err
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
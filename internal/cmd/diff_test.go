@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+)
+
+func TestSplitDiffArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		dashAt      int
+		wantPattern string
+		wantGitArgs []string
+		wantErr     bool
+	}{
+		{name: "nothing", dashAt: -1},
+		{name: "pattern", args: []string{"feature"}, dashAt: -1, wantPattern: "feature"},
+		{name: "pattern and git args", args: []string{"feature", "-w", "docs"}, dashAt: 1, wantPattern: "feature", wantGitArgs: []string{"-w", "docs"}},
+		{name: "git args only", args: []string{"--name-only"}, dashAt: 0, wantGitArgs: []string{"--name-only"}},
+		{name: "two patterns", args: []string{"feature", "docs"}, dashAt: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, gitArgs, err := splitDiffArgs(tt.args, tt.dashAt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitDiffArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if pattern != tt.wantPattern {
+				t.Errorf("pattern = %q, want %q", pattern, tt.wantPattern)
+			}
+			if len(gitArgs) != 0 || len(tt.wantGitArgs) != 0 {
+				if !reflect.DeepEqual(gitArgs, tt.wantGitArgs) {
+					t.Errorf("gitArgs = %v, want %v", gitArgs, tt.wantGitArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteWorktreeDiff(t *testing.T) {
+	repo := initTestGitRepo(t)
+	wm := worktree.New(git.New(repo), nil)
+
+	var buf bytes.Buffer
+	if err := writeWorktreeDiff(&buf, wm, repo, false, nil); err != nil {
+		t.Fatalf("writeWorktreeDiff() error = %v", err)
+	}
+	if got := buf.String(); got != "No changes\n" {
+		t.Errorf("clean worktree output = %q, want %q", got, "No changes\n")
+	}
+
+	// Staged changes count as uncommitted too.
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.New(repo).RunCommand("add", "new.txt"); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := writeWorktreeDiff(&buf, wm, repo, true, nil); err != nil {
+		t.Fatalf("writeWorktreeDiff() error = %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "new.txt") || !strings.Contains(got, "1 file changed") {
+		t.Errorf("stat output = %q, want a diffstat of new.txt", got)
+	}
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/d-kuro/gwq/internal/config"
@@ -16,8 +18,12 @@ import (
 )
 
 var (
-	execGlobal bool
-	execStay   bool
+	execGlobal   bool
+	execStay     bool
+	execParallel bool
+	execFailFast bool
+	execWorkers  int
+	execJSON     bool
 )
 
 var execCmd = &cobra.Command{
@@ -30,7 +36,15 @@ The command runs in a subshell with the working directory set to the selected wo
 Use -- to separate gwq arguments from the command to execute.
 
 If multiple worktrees match the pattern, an interactive fuzzy finder will be shown.
-If no pattern is provided, all worktrees will be shown in the fuzzy finder.`,
+If no pattern is provided, all worktrees will be shown in the fuzzy finder.
+
+With --parallel, the command runs in every worktree matching the pattern (or
+every worktree picked in a multi-select finder when no pattern is given) using
+a pool of --workers processes, min(matches, CPUs) by default. Output is
+captured and shown per worktree once all runs finish. --fail-fast stops
+running commands and skips the remaining worktrees after the first failure.
+--json prints the results as a JSON array with one record per worktree
+instead of the table.`,
 	Example: `  # Run tests in a feature branch
   gwq exec feature -- npm test
   
@@ -44,7 +58,16 @@ If no pattern is provided, all worktrees will be shown in the fuzzy finder.`,
   gwq exec --stay feature -- npm install
   
   # Execute in global worktree
-  gwq exec -g project:feature -- make build`,
+  gwq exec -g project:feature -- make build
+
+  # Run tests in every feature worktree, four at a time
+  gwq exec --parallel --workers 4 feature -- make test
+
+  # Stop at the first failing worktree
+  gwq exec -p --fail-fast feature -- go vet ./...
+
+  # Collect results as JSON
+  gwq exec -p --json feature -- make test`,
 	Args: cobra.ArbitraryArgs,
 	RunE: runExec,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -66,6 +89,10 @@ func init() {
 
 	execCmd.Flags().BoolVarP(&execGlobal, "global", "g", false, "Execute in global worktree")
 	execCmd.Flags().BoolVarP(&execStay, "stay", "s", false, "Stay in worktree directory after command execution")
+	execCmd.Flags().BoolVarP(&execParallel, "parallel", "p", false, "Run in all matching worktrees concurrently")
+	execCmd.Flags().BoolVar(&execFailFast, "fail-fast", false, "With --parallel, abort after the first failure")
+	execCmd.Flags().IntVar(&execWorkers, "workers", 0, "With --parallel, number of concurrent commands (default min(matches, CPUs))")
+	execCmd.Flags().BoolVar(&execJSON, "json", false, "With --parallel, output results as JSON")
 }
 
 // execArgs holds parsed execution arguments
@@ -74,6 +101,10 @@ type execArgs struct {
 	commandArgs []string
 	global      bool
 	stay        bool
+	parallel    bool
+	failFast    bool
+	workers     int
+	json        bool
 }
 
 // parseExecArgs manually parses command arguments since DisableFlagParsing is true
@@ -97,9 +128,37 @@ func parseExecArgs(cmd *cobra.Command, args []string) (*execArgs, error) {
 		case "-s", "--stay":
 			result.stay = true
 			i++
+		case "-p", "--parallel":
+			result.parallel = true
+			i++
+		case "--fail-fast":
+			result.failFast = true
+			i++
+		case "--json":
+			result.json = true
+			i++
+		case "--workers":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag needs an argument: --workers")
+			}
+			workers, err := parseExecWorkers(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			result.workers = workers
+			i += 2
 		case "-h", "--help":
 			return nil, cmd.Help()
 		default:
+			if value, ok := strings.CutPrefix(arg, "--workers="); ok {
+				workers, err := parseExecWorkers(value)
+				if err != nil {
+					return nil, err
+				}
+				result.workers = workers
+				i++
+				continue
+			}
 			if strings.HasPrefix(arg, "-") {
 				return nil, fmt.Errorf("unknown flag: %s", arg)
 			}
@@ -121,9 +180,25 @@ func parseExecArgs(cmd *cobra.Command, args []string) (*execArgs, error) {
 	}
 	result.commandArgs = args[dashDashIndex+1:]
 
+	if !result.parallel && (result.failFast || result.workers != 0 || result.json) {
+		return nil, fmt.Errorf("--fail-fast, --workers and --json require --parallel")
+	}
+	if result.parallel && result.stay {
+		return nil, fmt.Errorf("--stay cannot be used with --parallel")
+	}
+
 	return result, nil
 }
 
+// parseExecWorkers parses the --workers value.
+func parseExecWorkers(value string) (int, error) {
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 1 {
+		return 0, fmt.Errorf("invalid --workers value %q: must be a positive integer", value)
+	}
+	return workers, nil
+}
+
 func runExec(cmd *cobra.Command, args []string) error {
 	parsedArgs, err := parseExecArgs(cmd, args)
 	if err != nil {
@@ -138,12 +213,19 @@ func runExec(cmd *cobra.Command, args []string) error {
 	// Set global variables for backward compatibility
 	execGlobal = parsedArgs.global
 	execStay = parsedArgs.stay
+	execParallel = parsedArgs.parallel
+	execFailFast = parsedArgs.failFast
+	execWorkers = parsedArgs.workers
 
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
+	if parsedArgs.parallel {
+		return runExecParallel(cmd, cfg, parsedArgs)
+	}
+
 	var worktreePath string
 	if parsedArgs.global {
 		worktreePath, err = getGlobalWorktreePathForExec(cfg, parsedArgs.pattern)
@@ -159,6 +241,30 @@ func runExec(cmd *cobra.Command, args []string) error {
 	return executeInWorktree(worktreePath, parsedArgs.commandArgs, parsedArgs.stay)
 }
 
+// runExecParallel runs the command in all selected worktrees concurrently.
+func runExecParallel(cmd *cobra.Command, cfg *models.Config, parsedArgs *execArgs) error {
+	var targets []models.Worktree
+	var err error
+	if parsedArgs.global {
+		targets, err = getGlobalWorktreesForParallelExec(cfg, parsedArgs.pattern)
+	} else {
+		targets, err = getLocalWorktreesForParallelExec(cfg, parsedArgs.pattern)
+	}
+	if err != nil {
+		return err
+	}
+
+	results := runParallelExec(context.Background(), targets, parsedArgs.commandArgs, parsedArgs.workers, parsedArgs.failFast)
+	printResults := printExecResults
+	if parsedArgs.json {
+		printResults = printExecResultsJSON
+	}
+	if err := printResults(cmd.OutOrStdout(), results); err != nil {
+		return err
+	}
+	return execResultsError(results)
+}
+
 func getLocalWorktreePathForExec(cfg *models.Config, pattern string) (string, error) {
 	g, err := git.NewFromCwd()
 	if err != nil {
@@ -176,7 +282,7 @@ func getLocalWorktreePathForExec(cfg *models.Config, pattern string) (string, er
 		}
 
 		if len(matches) == 0 {
-			return "", fmt.Errorf("no worktree found matching pattern: %s", pattern)
+			return "", fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
 		} else if len(matches) == 1 {
 			return matches[0].Path, nil
 		} else {
@@ -184,7 +290,7 @@ func getLocalWorktreePathForExec(cfg *models.Config, pattern string) (string, er
 			f := CreateFinder(g, cfg)
 			selected, err := f.SelectWorktree(matches)
 			if err != nil {
-				return "", fmt.Errorf("worktree selection cancelled")
+				return "", fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 			return selected.Path, nil
 		}
@@ -196,7 +302,7 @@ func getLocalWorktreePathForExec(cfg *models.Config, pattern string) (string, er
 		}
 
 		if len(worktrees) == 0 {
-			return "", fmt.Errorf("no worktrees found")
+			return "", worktree.ErrNoWorktreeFound
 		}
 
 		if len(worktrees) == 1 {
@@ -206,20 +312,20 @@ func getLocalWorktreePathForExec(cfg *models.Config, pattern string) (string, er
 		f := CreateFinder(g, cfg)
 		selected, err := f.SelectWorktree(worktrees)
 		if err != nil {
-			return "", fmt.Errorf("worktree selection cancelled")
+			return "", fmt.Errorf("worktree selection cancelled: %w", err)
 		}
 		return selected.Path, nil
 	}
 }
 
 func getGlobalWorktreePathForExec(cfg *models.Config, pattern string) (string, error) {
-	entries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir)
+	entries, err := discoverGlobalWorktreesMatching(cfg.Worktree.BaseDir, pattern)
 	if err != nil {
 		return "", err
 	}
 
 	if len(entries) == 0 {
-		return "", fmt.Errorf("no worktrees found across all repositories")
+		return "", fmt.Errorf("%w across all repositories", worktree.ErrNoWorktreeFound)
 	}
 
 	var selected *discovery.GlobalWorktreeEntry
@@ -229,7 +335,7 @@ func getGlobalWorktreePathForExec(cfg *models.Config, pattern string) (string, e
 		matches := discovery.FilterGlobalWorktrees(entries, pattern)
 
 		if len(matches) == 0 {
-			return "", fmt.Errorf("no worktree matches pattern: %s", pattern)
+			return "", fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
 		} else if len(matches) == 1 {
 			selected = matches[0]
 		} else {
@@ -239,7 +345,7 @@ func getGlobalWorktreePathForExec(cfg *models.Config, pattern string) (string, e
 			f := CreateGlobalFinder(cfg)
 			selectedWT, err := f.SelectWorktree(worktrees)
 			if err != nil {
-				return "", fmt.Errorf("worktree selection cancelled")
+				return "", fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 
 			// Find the corresponding entry
@@ -257,7 +363,7 @@ func getGlobalWorktreePathForExec(cfg *models.Config, pattern string) (string, e
 		f := CreateGlobalFinder(cfg)
 		selectedWT, err := f.SelectWorktree(worktrees)
 		if err != nil {
-			return "", fmt.Errorf("worktree selection cancelled")
+			return "", fmt.Errorf("worktree selection cancelled: %w", err)
 		}
 
 		// Find the corresponding entry
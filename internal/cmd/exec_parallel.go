@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/discovery"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/table"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// execResult is the outcome of running the command in one worktree.
+type execResult struct {
+	Worktree models.Worktree
+	Stdout   string
+	Stderr   string
+	ExitCode int   // -1 if the command could not be started or was killed
+	Err      error // start failure or cancellation; nil for a normal exit
+	Skipped  bool  // not started because --fail-fast aborted the run
+	Duration time.Duration
+}
+
+// failed reports whether the worktree run should count as a failure.
+func (r execResult) failed() bool {
+	return !r.Skipped && (r.Err != nil || r.ExitCode != 0)
+}
+
+// defaultExecWorkers returns the worker count used when --workers is not set.
+func defaultExecWorkers(targets int) int {
+	return max(1, min(targets, runtime.NumCPU()))
+}
+
+// runParallelExec runs commandArgs in every target worktree using at most
+// workers concurrent processes. Results are returned in target order. With
+// failFast, the first failure cancels running commands and skips the rest.
+func runParallelExec(ctx context.Context, targets []models.Worktree, commandArgs []string, workers int, failFast bool) []execResult {
+	if workers <= 0 {
+		workers = defaultExecWorkers(len(targets))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]execResult, len(targets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(workers, len(targets)) {
+		wg.Go(func() {
+			for idx := range jobs {
+				if ctx.Err() != nil {
+					results[idx] = execResult{Worktree: targets[idx], ExitCode: -1, Skipped: true}
+					continue
+				}
+				results[idx] = runExecCommand(ctx, targets[idx], commandArgs)
+				if failFast && results[idx].failed() {
+					cancel()
+				}
+			}
+		})
+	}
+
+	for idx := range targets {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runExecCommand runs commandArgs in wt and captures its output.
+func runExecCommand(ctx context.Context, wt models.Worktree, commandArgs []string) execResult {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, commandArgs[0], commandArgs[1:]...)
+	cmd.Dir = wt.Path
+	cmd.Env = os.Environ()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait indefinitely for grandchildren holding the output pipes
+	// after the command itself was cancelled.
+	cmd.WaitDelay = time.Second
+
+	result := execResult{Worktree: wt}
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil && err != nil:
+		result.ExitCode = -1
+		result.Err = fmt.Errorf("cancelled: %w", ctx.Err())
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+		result.Err = err
+	}
+	return result
+}
+
+// printExecResults renders one labelled row per worktree.
+func printExecResults(w io.Writer, results []execResult) error {
+	t := table.New().SetOutput(w).Headers("BRANCH", "PATH", "EXIT", "STDOUT", "STDERR")
+	for _, r := range results {
+		exitCode := strconv.Itoa(r.ExitCode)
+		stderr := strings.TrimRight(r.Stderr, "\n")
+		switch {
+		case r.Skipped:
+			exitCode = "skipped"
+		case r.Err != nil:
+			exitCode = "error"
+			stderr = strings.TrimSpace(stderr + "\n" + r.Err.Error())
+		}
+		t.Row(r.Worktree.Branch, r.Worktree.Path, exitCode, strings.TrimRight(r.Stdout, "\n"), stderr)
+	}
+	return t.Println()
+}
+
+// execJSONRecord is the --json form of an execResult.
+type execJSONRecord struct {
+	Branch   string  `json:"branch"`
+	Path     string  `json:"path"`
+	ExitCode int     `json:"exit_code"`
+	Duration float64 `json:"duration"` // seconds
+	Stdout   string  `json:"stdout"`
+	Stderr   string  `json:"stderr"`
+	Error    string  `json:"error,omitempty"`
+	Skipped  bool    `json:"skipped,omitempty"`
+}
+
+// printExecResultsJSON writes the results as a JSON array, one record per
+// worktree in target order.
+func printExecResultsJSON(w io.Writer, results []execResult) error {
+	records := make([]execJSONRecord, 0, len(results))
+	for _, r := range results {
+		record := execJSONRecord{
+			Branch:   r.Worktree.Branch,
+			Path:     r.Worktree.Path,
+			ExitCode: r.ExitCode,
+			Duration: r.Duration.Seconds(),
+			Stdout:   r.Stdout,
+			Stderr:   r.Stderr,
+			Skipped:  r.Skipped,
+		}
+		if r.Err != nil {
+			record.Error = r.Err.Error()
+		}
+		records = append(records, record)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// execResultsError summarizes failures as an error, or returns nil.
+func execResultsError(results []execResult) error {
+	var failed, skipped int
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			skipped++
+		case r.failed():
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	if skipped > 0 {
+		return fmt.Errorf("command failed in %d of %d worktree(s), %d skipped", failed, len(results), skipped)
+	}
+	return fmt.Errorf("command failed in %d of %d worktree(s)", failed, len(results))
+}
+
+// getLocalWorktreesForParallelExec returns every worktree matching pattern,
+// or the worktrees picked with the multi-select finder when pattern is empty.
+func getLocalWorktreesForParallelExec(cfg *models.Config, pattern string) ([]models.Worktree, error) {
+	g, err := git.NewFromCwd()
+	if err != nil {
+		// Not in a git repo, try global
+		return getGlobalWorktreesForParallelExec(cfg, pattern)
+	}
+
+	wm := worktree.New(g, cfg)
+
+	if pattern != "" {
+		matches, err := wm.GetMatchingWorktrees(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
+		}
+		return matches, nil
+	}
+
+	worktrees, err := wm.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(worktrees) == 0 {
+		return nil, worktree.ErrNoWorktreeFound
+	}
+
+	selected, err := CreateFinder(g, cfg).SelectMultipleWorktrees(worktrees)
+	if err != nil {
+		return nil, fmt.Errorf("worktree selection cancelled: %w", err)
+	}
+	return selected, nil
+}
+
+// getGlobalWorktreesForParallelExec is the global variant of
+// getLocalWorktreesForParallelExec. Branches are labelled with the repository.
+func getGlobalWorktreesForParallelExec(cfg *models.Config, pattern string) ([]models.Worktree, error) {
+	entries, err := discoverGlobalWorktreesMatching(cfg.Worktree.BaseDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w across all repositories", worktree.ErrNoWorktreeFound)
+	}
+
+	if pattern != "" {
+		matches := discovery.FilterGlobalWorktrees(entries, pattern)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
+		}
+		return discovery.ConvertToWorktreeModels(matches, true), nil
+	}
+
+	selected, err := CreateGlobalFinder(cfg).SelectMultipleWorktrees(discovery.ConvertToWorktreeModels(entries, true))
+	if err != nil {
+		return nil, fmt.Errorf("worktree selection cancelled: %w", err)
+	}
+	return selected, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestParseExecArgs_Parallel(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    execArgs
+		wantErr string
+	}{
+		{
+			name: "parallel with workers",
+			args: []string{"-p", "--workers", "3", "--fail-fast", "feat", "--", "make", "test"},
+			want: execArgs{pattern: "feat", commandArgs: []string{"make", "test"}, parallel: true, failFast: true, workers: 3},
+		},
+		{
+			name: "workers with equals",
+			args: []string{"--parallel", "--workers=2", "--", "true"},
+			want: execArgs{commandArgs: []string{"true"}, parallel: true, workers: 2},
+		},
+		{name: "invalid workers", args: []string{"-p", "--workers", "0", "--", "true"}, wantErr: "invalid --workers"},
+		{name: "missing workers value", args: []string{"-p", "--workers"}, wantErr: "flag needs an argument"},
+		{name: "fail-fast without parallel", args: []string{"--fail-fast", "--", "true"}, wantErr: "require --parallel"},
+		{
+			name: "json",
+			args: []string{"-p", "--json", "--", "true"},
+			want: execArgs{commandArgs: []string{"true"}, parallel: true, json: true},
+		},
+		{name: "json without parallel", args: []string{"--json", "--", "true"}, wantErr: "require --parallel"},
+		{name: "stay with parallel", args: []string{"-p", "-s", "--", "true"}, wantErr: "--stay cannot be used"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExecArgs(execCmd, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseExecArgs() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExecArgs() error = %v", err)
+			}
+			if got.pattern != tt.want.pattern || got.parallel != tt.want.parallel ||
+				got.failFast != tt.want.failFast || got.workers != tt.want.workers || got.json != tt.want.json ||
+				strings.Join(got.commandArgs, " ") != strings.Join(tt.want.commandArgs, " ") {
+				t.Errorf("parseExecArgs() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultExecWorkers(t *testing.T) {
+	if got := defaultExecWorkers(1); got != 1 {
+		t.Errorf("defaultExecWorkers(1) = %d, want 1", got)
+	}
+	if got := defaultExecWorkers(0); got != 1 {
+		t.Errorf("defaultExecWorkers(0) = %d, want 1", got)
+	}
+	if got := defaultExecWorkers(1 << 20); got < 1 || got >= 1<<20 {
+		t.Errorf("defaultExecWorkers(large) = %d, want capped at CPU count", got)
+	}
+}
+
+// newExecTargets creates one temporary worktree directory per branch, each
+// containing a "marker" file with the branch name.
+func newExecTargets(t *testing.T, branches ...string) []models.Worktree {
+	t.Helper()
+	var targets []models.Worktree
+	for _, branch := range branches {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "marker"), []byte(branch+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write marker: %v", err)
+		}
+		targets = append(targets, models.Worktree{Branch: branch, Path: dir})
+	}
+	return targets
+}
+
+// markFailing makes the test scripts exit non-zero in dir.
+func markFailing(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "fail"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write fail marker: %v", err)
+	}
+}
+
+func TestRunParallelExec(t *testing.T) {
+	targets := newExecTargets(t, "a", "b", "c")
+
+	markFailing(t, targets[1].Path)
+
+	script := `cat marker; if [ -e fail ]; then echo boom >&2; exit 3; fi`
+	results := runParallelExec(context.Background(), targets, []string{"sh", "-c", script}, 2, false)
+
+	if len(results) != len(targets) {
+		t.Fatalf("got %d results, want %d", len(results), len(targets))
+	}
+	for i, r := range results {
+		if r.Worktree.Path != targets[i].Path {
+			t.Errorf("result[%d] path = %s, want %s (results must keep target order)", i, r.Worktree.Path, targets[i].Path)
+		}
+		if strings.TrimSpace(r.Stdout) != r.Worktree.Branch {
+			t.Errorf("result[%d] stdout = %q, want %q", i, r.Stdout, r.Worktree.Branch)
+		}
+	}
+	if results[0].ExitCode != 0 || results[2].ExitCode != 0 {
+		t.Errorf("exit codes = %d, %d, want 0", results[0].ExitCode, results[2].ExitCode)
+	}
+	if results[1].ExitCode != 3 || strings.TrimSpace(results[1].Stderr) != "boom" {
+		t.Errorf("result[1] = exit %d stderr %q, want exit 3 stderr boom", results[1].ExitCode, results[1].Stderr)
+	}
+
+	err := execResultsError(results)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Errorf("execResultsError() = %v, want failure in 1 of 3", err)
+	}
+}
+
+func TestRunParallelExec_FailFast(t *testing.T) {
+	targets := newExecTargets(t, "fail", "slow", "never")
+
+	// The first worktree fails immediately; the second would sleep far longer
+	// than the test allows unless it is cancelled.
+	markFailing(t, targets[0].Path)
+	script := `if [ -e fail ]; then exit 1; fi; sleep 30`
+
+	start := time.Now()
+	results := runParallelExec(context.Background(), targets, []string{"sh", "-c", script}, 2, true)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("fail-fast run took %v, want running commands to be cancelled", elapsed)
+	}
+
+	if results[0].ExitCode != 1 || results[0].Skipped {
+		t.Errorf("result[0] = %+v, want exit 1", results[0])
+	}
+	if results[1].Err == nil && !results[1].Skipped {
+		t.Errorf("result[1] = %+v, want cancelled or skipped", results[1])
+	}
+	if !results[2].Skipped {
+		t.Errorf("result[2] = %+v, want skipped", results[2])
+	}
+}
+
+func TestPrintExecResultsJSON(t *testing.T) {
+	targets := newExecTargets(t, "ok", "bad")
+	markFailing(t, targets[1].Path)
+
+	script := `if [ -e fail ]; then exit 7; fi; echo done`
+	results := runParallelExec(context.Background(), targets, []string{"sh", "-c", script}, 2, false)
+
+	var buf bytes.Buffer
+	if err := printExecResultsJSON(&buf, results); err != nil {
+		t.Fatalf("printExecResultsJSON() error = %v", err)
+	}
+
+	var records []execJSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(records) != len(targets) {
+		t.Fatalf("got %d records, want one per worktree (%d)", len(records), len(targets))
+	}
+	for i, want := range []int{0, 7} {
+		r := records[i]
+		if r.Branch != targets[i].Branch || r.Path != targets[i].Path {
+			t.Errorf("record[%d] = %s %s, want %s %s", i, r.Branch, r.Path, targets[i].Branch, targets[i].Path)
+		}
+		if r.ExitCode != want {
+			t.Errorf("record[%d] exit_code = %d, want %d", i, r.ExitCode, want)
+		}
+		if r.Duration <= 0 {
+			t.Errorf("record[%d] duration = %v, want > 0", i, r.Duration)
+		}
+	}
+	if strings.TrimSpace(records[0].Stdout) != "done" {
+		t.Errorf("record[0] stdout = %q, want done", records[0].Stdout)
+	}
+}
+
+func TestPrintExecResultsJSON_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printExecResultsJSON(&buf, nil); err != nil {
+		t.Fatalf("printExecResultsJSON() error = %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("output = %q, want []", got)
+	}
+}
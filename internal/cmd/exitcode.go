@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/d-kuro/gwq/internal/finder"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by gwq. They are part of the CLI contract so scripts
+// can tell failure classes apart; do not renumber existing values.
+const (
+	ExitOK                = 0 // success
+	ExitError             = 1 // any failure not covered below
+	ExitUsage             = 2 // invalid arguments, flags, or unknown command
+	ExitNoWorktree        = 3 // no worktree matched the request
+	ExitGitError          = 4 // a git command failed
+	ExitSelectionCanceled = 5 // the fuzzy finder was cancelled
+)
+
+// usageError marks errors caused by invalid command-line usage.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// cobraUsagePrefixes match usage errors that cobra creates without a type or
+// hook (unknown subcommands and missing required flags).
+var cobraUsagePrefixes = []string{"unknown command ", "required flag(s) "}
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return ExitUsage
+	}
+	for _, prefix := range cobraUsagePrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return ExitUsage
+		}
+	}
+
+	var gitErr *git.Error
+	switch {
+	case errors.Is(err, finder.ErrSelectionCancelled):
+		return ExitSelectionCanceled
+	case errors.Is(err, worktree.ErrNoWorktreeFound):
+		return ExitNoWorktree
+	case errors.As(err, &gitErr):
+		return ExitGitError
+	default:
+		return ExitError
+	}
+}
+
+var markUsageErrorsOnce sync.Once
+
+// markUsageErrors wraps flag parsing and positional argument validation of
+// root and all its subcommands so that their errors are reported as usage
+// errors. It must run after all commands are registered.
+func markUsageErrors(root *cobra.Command) {
+	markUsageErrorsOnce.Do(func() {
+		root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+			return &usageError{err: err}
+		})
+		wrapArgs(root)
+	})
+}
+
+func wrapArgs(c *cobra.Command) {
+	if validate := c.Args; validate != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &usageError{err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range c.Commands() {
+		wrapArgs(sub)
+	}
+}
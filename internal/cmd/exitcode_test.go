@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/finder"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+)
+
+func TestExitCode(t *testing.T) {
+	_, gitErr := git.New(t.TempDir()).RunCommand("rev-parse", "--verify", "no-such-ref")
+	if gitErr == nil {
+		t.Fatal("expected git command to fail outside a repository")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "generic", err: errors.New("boom"), want: ExitError},
+		{name: "usage", err: &usageError{err: errors.New("accepts 2 arg(s), received 1")}, want: ExitUsage},
+		{name: "unknown command", err: errors.New(`unknown command "nope" for "gwq"`), want: ExitUsage},
+		{
+			name: "no worktree found",
+			err:  fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, "feat"),
+			want: ExitNoWorktree,
+		},
+		{name: "git error", err: fmt.Errorf("failed to list worktrees: %w", gitErr), want: ExitGitError},
+		{
+			name: "selection cancelled",
+			err:  fmt.Errorf("worktree selection cancelled: %w", finder.ErrSelectionCancelled),
+			want: ExitSelectionCanceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_CommandUsage(t *testing.T) {
+	markUsageErrors(rootCmd)
+
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "wrong argument count", args: []string{"rename", "only-one"}},
+		{name: "unknown flag", args: []string{"list", "--no-such-flag"}},
+		{name: "unknown command", args: []string{"no-such-command"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.Execute()
+			if got := exitCode(err); got != ExitUsage {
+				t.Errorf("exitCode(%v) = %d, want %d", err, got, ExitUsage)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// FilterFunc reports whether a worktree status matches a filter.
+type FilterFunc func(*models.WorktreeStatus) bool
+
+// filterStringFields are the text fields usable with =, != and ~=.
+var filterStringFields = map[string]func(*models.WorktreeStatus) string{
+	"branch":     func(s *models.WorktreeStatus) string { return s.Branch },
+	"path":       func(s *models.WorktreeStatus) string { return s.Path },
+	"repository": func(s *models.WorktreeStatus) string { return s.Repository },
+	"repo":       func(s *models.WorktreeStatus) string { return s.Repository },
+}
+
+// filterNumericFields are the counters usable with =, !=, <, <=, > and >=.
+var filterNumericFields = map[string]func(*models.WorktreeStatus) int{
+	"ahead":     func(s *models.WorktreeStatus) int { return s.GitStatus.Ahead },
+	"behind":    func(s *models.WorktreeStatus) int { return s.GitStatus.Behind },
+	"modified":  func(s *models.WorktreeStatus) int { return s.GitStatus.Modified },
+	"added":     func(s *models.WorktreeStatus) int { return s.GitStatus.Added },
+	"deleted":   func(s *models.WorktreeStatus) int { return s.GitStatus.Deleted },
+	"untracked": func(s *models.WorktreeStatus) int { return s.GitStatus.Untracked },
+	"staged":    func(s *models.WorktreeStatus) int { return s.GitStatus.Staged },
+	"conflicts": func(s *models.WorktreeStatus) int { return s.GitStatus.Conflicts },
+	"changes":   func(s *models.WorktreeStatus) int { return countTotalChanges(s.GitStatus) },
+}
+
+// filterOperators lists the comparison operators, two-character ones first so
+// that "behind>=1" is not read as "behind>" "=1".
+var filterOperators = []string{"~=", "!=", ">=", "<=", "=", ">", "<"}
+
+// ParseFilter parses a filter expression. An expression is either a bare
+// status name ("stale", "modified", ...) or FIELD OP VALUE where:
+//
+//   - status supports = and != against a status name;
+//   - branch, path and repository support =, != and ~= (regular expression);
+//   - ahead, behind, modified, added, deleted, untracked, staged, conflicts
+//     and changes support =, !=, <, <=, > and >= against an integer.
+func ParseFilter(expr string) (FilterFunc, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	field, op, value, ok := splitFilterExpr(expr)
+	if !ok {
+		state, err := models.ParseWorktreeState(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: expected a status name or FIELD OP VALUE", expr)
+		}
+		return func(s *models.WorktreeStatus) bool { return s.Status == state }, nil
+	}
+
+	switch {
+	case field == "status":
+		return parseStatusFilter(expr, op, value)
+	case filterStringFields[field] != nil:
+		return parseStringFilter(expr, filterStringFields[field], op, value)
+	case filterNumericFields[field] != nil:
+		return parseNumericFilter(expr, filterNumericFields[field], op, value)
+	default:
+		return nil, fmt.Errorf("invalid filter %q: unknown field %q", expr, field)
+	}
+}
+
+// splitFilterExpr splits expr at its first operator. The field is
+// lower-cased; field and value are trimmed.
+func splitFilterExpr(expr string) (field, op, value string, ok bool) {
+	idx := strings.IndexAny(expr, "~!=<>")
+	if idx < 0 {
+		return "", "", "", false
+	}
+	for _, candidate := range filterOperators {
+		if strings.HasPrefix(expr[idx:], candidate) {
+			field = strings.ToLower(strings.TrimSpace(expr[:idx]))
+			value = strings.TrimSpace(expr[idx+len(candidate):])
+			return field, candidate, value, true
+		}
+	}
+	return "", "", "", false
+}
+
+func parseStatusFilter(expr, op, value string) (FilterFunc, error) {
+	state, err := models.ParseWorktreeState(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	switch op {
+	case "=":
+		return func(s *models.WorktreeStatus) bool { return s.Status == state }, nil
+	case "!=":
+		return func(s *models.WorktreeStatus) bool { return s.Status != state }, nil
+	default:
+		return nil, fmt.Errorf("invalid filter %q: status supports = and !=", expr)
+	}
+}
+
+func parseStringFilter(expr string, get func(*models.WorktreeStatus) string, op, value string) (FilterFunc, error) {
+	switch op {
+	case "=":
+		return func(s *models.WorktreeStatus) bool { return get(s) == value }, nil
+	case "!=":
+		return func(s *models.WorktreeStatus) bool { return get(s) != value }, nil
+	case "~=":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		return func(s *models.WorktreeStatus) bool { return re.MatchString(get(s)) }, nil
+	default:
+		return nil, fmt.Errorf("invalid filter %q: operator %s is not supported for text fields", expr, op)
+	}
+}
+
+func parseNumericFilter(expr string, get func(*models.WorktreeStatus) int, op, value string) (FilterFunc, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %q is not an integer", expr, value)
+	}
+	var compare func(int) bool
+	switch op {
+	case "=":
+		compare = func(v int) bool { return v == n }
+	case "!=":
+		compare = func(v int) bool { return v != n }
+	case "<":
+		compare = func(v int) bool { return v < n }
+	case "<=":
+		compare = func(v int) bool { return v <= n }
+	case ">":
+		compare = func(v int) bool { return v > n }
+	case ">=":
+		compare = func(v int) bool { return v >= n }
+	default:
+		return nil, fmt.Errorf("invalid filter %q: operator %s is not supported for numeric fields", expr, op)
+	}
+	return func(s *models.WorktreeStatus) bool { return compare(get(s)) }, nil
+}
+
+// ParseFilters parses each expression and combines them: all must match,
+// or with anyOf at least one. It returns nil when exprs is empty.
+func ParseFilters(exprs []string, anyOf bool) (FilterFunc, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	filters := make([]FilterFunc, 0, len(exprs))
+	for _, expr := range exprs {
+		f, err := ParseFilter(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if anyOf {
+		return func(s *models.WorktreeStatus) bool {
+			return slices.ContainsFunc(filters, func(f FilterFunc) bool { return f(s) })
+		}, nil
+	}
+	return func(s *models.WorktreeStatus) bool {
+		return !slices.ContainsFunc(filters, func(f FilterFunc) bool { return !f(s) })
+	}, nil
+}
+
+// filtersNeedRemote reports whether any expression compares ahead or behind
+// counts, which are only collected with an upstream comparison.
+func filtersNeedRemote(exprs []string) bool {
+	return slices.ContainsFunc(exprs, func(expr string) bool {
+		field, _, _, ok := splitFilterExpr(strings.TrimSpace(expr))
+		return ok && (field == "ahead" || field == "behind")
+	})
+}
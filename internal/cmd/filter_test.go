@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestParseFilter(t *testing.T) {
+	clean := &models.WorktreeStatus{
+		Branch:     "main",
+		Path:       "/src/myproject",
+		Repository: "github.com/me/myproject",
+		Status:     models.WorktreeStatusClean,
+	}
+	feature := &models.WorktreeStatus{
+		Branch:     "feature/login",
+		Path:       "/worktrees/myproject/feature-login",
+		Repository: "github.com/me/myproject",
+		Status:     models.WorktreeStatusModified,
+		GitStatus:  models.GitStatus{Modified: 2, Untracked: 1, Ahead: 3},
+	}
+	old := &models.WorktreeStatus{
+		Branch:    "fix/old",
+		Path:      "/worktrees/other/fix-old",
+		Status:    models.WorktreeStatusStale,
+		GitStatus: models.GitStatus{Behind: 5},
+	}
+
+	tests := []struct {
+		expr string
+		want [3]bool // matches clean, feature, old
+	}{
+		{expr: "stale", want: [3]bool{false, false, true}},
+		{expr: "Modified", want: [3]bool{false, true, false}},
+		{expr: "status=modified", want: [3]bool{false, true, false}},
+		{expr: "status = changed", want: [3]bool{false, true, false}},
+		{expr: "status!=clean", want: [3]bool{false, true, true}},
+		{expr: "branch~=feature", want: [3]bool{false, true, false}},
+		{expr: "branch~=^(main|fix/)", want: [3]bool{true, false, true}},
+		{expr: "branch=main", want: [3]bool{true, false, false}},
+		{expr: "branch!=main", want: [3]bool{false, true, true}},
+		{expr: "path~=/myproject/", want: [3]bool{false, true, false}},
+		{expr: "repo~=myproject$", want: [3]bool{true, true, false}},
+		{expr: "ahead>0", want: [3]bool{false, true, false}},
+		{expr: "behind>0", want: [3]bool{false, false, true}},
+		{expr: "behind>=5", want: [3]bool{false, false, true}},
+		{expr: "behind<5", want: [3]bool{true, true, false}},
+		{expr: "ahead<=0", want: [3]bool{true, false, true}},
+		{expr: "modified=2", want: [3]bool{false, true, false}},
+		{expr: "changes!=0", want: [3]bool{false, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error = %v", tt.expr, err)
+			}
+			got := [3]bool{f(clean), f(feature), f(old)}
+			if got != tt.want {
+				t.Errorf("ParseFilter(%q) matches %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"dirty",
+		"color=red",
+		"status=dirty",
+		"status>clean",
+		"branch>main",
+		"branch~=[",
+		"ahead>many",
+		"ahead~=1",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseFilter(expr); err == nil {
+				t.Errorf("ParseFilter(%q) expected an error", expr)
+			}
+		})
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	statuses := []*models.WorktreeStatus{
+		{Branch: "feature/a", Status: models.WorktreeStatusModified},
+		{Branch: "feature/b", Status: models.WorktreeStatusClean, GitStatus: models.GitStatus{Behind: 1}},
+		{Branch: "fix/c", Status: models.WorktreeStatusModified},
+		{Branch: "main", Status: models.WorktreeStatusClean},
+	}
+
+	tests := []struct {
+		name  string
+		exprs []string
+		anyOf bool
+		want  []string
+	}{
+		{name: "and", exprs: []string{"status=modified", "branch~=^feature/"}, want: []string{"feature/a"}},
+		{name: "or", exprs: []string{"status=modified", "behind>0"}, anyOf: true, want: []string{"feature/a", "feature/b", "fix/c"}},
+		{name: "single", exprs: []string{"clean"}, want: []string{"feature/b", "main"}},
+		{name: "and without match", exprs: []string{"clean", "branch~=^fix/"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFilters(tt.exprs, tt.anyOf)
+			if err != nil {
+				t.Fatalf("ParseFilters() error = %v", err)
+			}
+			var got []string
+			for _, s := range statuses {
+				if f(s) {
+					got = append(got, s.Branch)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseFilters() matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseFilters() matched %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if f, err := ParseFilters(nil, false); f != nil || err != nil {
+		t.Errorf("ParseFilters(nil) = %v, %v; want nil, nil", f != nil, err)
+	}
+	if _, err := ParseFilters([]string{"clean", "bogus"}, false); err == nil {
+		t.Error("ParseFilters() with an invalid expression expected an error")
+	}
+}
+
+func TestFiltersNeedRemote(t *testing.T) {
+	tests := []struct {
+		exprs []string
+		want  bool
+	}{
+		{exprs: nil, want: false},
+		{exprs: []string{"stale", "branch~=feature"}, want: false},
+		{exprs: []string{"stale", "behind>0"}, want: true},
+		{exprs: []string{"Ahead >= 2"}, want: true},
+	}
+	for _, tt := range tests {
+		if got := filtersNeedRemote(tt.exprs); got != tt.want {
+			t.Errorf("filtersNeedRemote(%v) = %v, want %v", tt.exprs, got, tt.want)
+		}
+	}
+}
+
+func TestArrangeListedWorktrees_Filter(t *testing.T) {
+	repo := initTestGitRepo(t)
+	ctx := &CommandContext{Config: &models.Config{}}
+	worktrees := []models.Worktree{{Branch: "main", Path: repo, IsMain: true}}
+
+	for _, tt := range []struct {
+		expr string
+		want int
+	}{
+		{expr: "changes=0", want: 1},
+		{expr: "status=conflict", want: 0},
+	} {
+		f, err := ParseFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) error = %v", tt.expr, err)
+		}
+		got, err := arrangeListedWorktrees(ctx, worktrees, listArrangement{filter: f})
+		if err != nil {
+			t.Fatalf("arrangeListedWorktrees(%q) error = %v", tt.expr, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("arrangeListedWorktrees(%q) = %+v, want %d worktrees", tt.expr, got, tt.want)
+		}
+	}
+}
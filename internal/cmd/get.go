@@ -81,7 +81,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 		}
 
 		if len(matches) == 0 {
-			return fmt.Errorf("no worktree found matching pattern: %s", args[0])
+			return fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, args[0])
 		} else if len(matches) == 1 {
 			path = matches[0].Path
 		} else {
@@ -89,7 +89,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 			f := CreateFinder(g, cfg)
 			selected, err := f.SelectWorktree(matches)
 			if err != nil {
-				return fmt.Errorf("worktree selection cancelled")
+				return fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 			path = selected.Path
 		}
@@ -100,7 +100,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 		}
 
 		if len(worktrees) == 0 {
-			return fmt.Errorf("no worktrees found")
+			return worktree.ErrNoWorktreeFound
 		}
 
 		if len(worktrees) == 1 {
@@ -109,7 +109,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 			f := CreateFinder(g, cfg)
 			selected, err := f.SelectWorktree(worktrees)
 			if err != nil {
-				return fmt.Errorf("worktree selection cancelled")
+				return fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 			path = selected.Path
 		}
@@ -126,24 +126,27 @@ func runGet(cmd *cobra.Command, args []string) error {
 }
 
 func getGlobalWorktreePath(cfg *models.Config, args []string) error {
-	entries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir)
+	var pattern string
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+	entries, err := discoverGlobalWorktreesMatching(cfg.Worktree.BaseDir, pattern)
 	if err != nil {
 		return err
 	}
 
 	if len(entries) == 0 {
-		return fmt.Errorf("no worktrees found across all repositories")
+		return fmt.Errorf("%w across all repositories", worktree.ErrNoWorktreeFound)
 	}
 
 	var selected *discovery.GlobalWorktreeEntry
 
 	if len(args) > 0 {
 		// Pattern matching
-		pattern := args[0]
 		matches := discovery.FilterGlobalWorktrees(entries, pattern)
 
 		if len(matches) == 0 {
-			return fmt.Errorf("no worktree matches pattern: %s", pattern)
+			return fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
 		} else if len(matches) == 1 {
 			selected = matches[0]
 		} else {
@@ -154,7 +157,7 @@ func getGlobalWorktreePath(cfg *models.Config, args []string) error {
 			f := CreateGlobalFinder(cfg)
 			selectedWT, err := f.SelectWorktree(worktrees)
 			if err != nil {
-				return fmt.Errorf("worktree selection cancelled")
+				return fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 
 			// Find the corresponding entry
@@ -172,7 +175,7 @@ func getGlobalWorktreePath(cfg *models.Config, args []string) error {
 		f := CreateGlobalFinder(cfg)
 		selectedWT, err := f.SelectWorktree(worktrees)
 		if err != nil {
-			return fmt.Errorf("worktree selection cancelled")
+			return fmt.Errorf("worktree selection cancelled: %w", err)
 		}
 
 		// Find the corresponding entry
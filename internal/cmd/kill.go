@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/process"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// killPollInterval is how often gwq kill checks whether signalled processes
+// have exited.
+const killPollInterval = 100 * time.Millisecond
+
+var (
+	killSignal  string
+	killForce   bool
+	killAll     bool
+	killYes     bool
+	killTimeout time.Duration
+)
+
+// killCmd represents the kill command.
+var killCmd = &cobra.Command{
+	Use:   "kill [pattern]",
+	Short: "Terminate processes running inside a worktree",
+	Long: `Send a signal to every process whose working directory is inside a worktree,
+such as dev servers, watchers or AI agents left running.
+
+Processes are found the same way as 'gwq status --show-processes'. A process
+in a worktree nested inside another belongs to the inner worktree only. The
+shell that runs gwq and the processes above it are never signalled.
+
+The signal defaults to SIGTERM and can be given by name (TERM, SIGTERM) or
+number (15); --force sends SIGKILL. gwq then waits up to --timeout for the
+processes to exit and reports those still running.
+
+If no pattern is provided, or several worktrees match, a fuzzy finder is shown.
+With --all, processes in every worktree of the repository except the main
+worktree are signalled after confirmation.`,
+	Example: `  # Stop everything running in a worktree
+  gwq kill feature/auth
+
+  # Send SIGINT instead of SIGTERM
+  gwq kill feature/auth --signal INT
+
+  # Kill processes that ignore SIGTERM
+  gwq kill feature/auth --force
+
+  # Stop processes in all worktrees without prompting
+  gwq kill --all --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: ExecuteWithArgs(true, runKill),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getWorktreeCompletions(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(killCmd)
+
+	killCmd.Flags().StringVar(&killSignal, "signal", "TERM", "Signal to send, by name or number")
+	killCmd.Flags().BoolVar(&killForce, "force", false, "Send SIGKILL")
+	killCmd.Flags().BoolVarP(&killAll, "all", "a", false, "Signal processes in all worktrees")
+	killCmd.Flags().BoolVarP(&killYes, "yes", "y", false, "Skip the confirmation prompt for --all")
+	killCmd.Flags().DurationVar(&killTimeout, "timeout", 5*time.Second, "How long to wait for processes to exit")
+	killCmd.MarkFlagsMutuallyExclusive("signal", "force")
+}
+
+// killTarget is a process selected for signalling and the worktree it runs in.
+type killTarget struct {
+	Worktree models.Worktree
+	Process  models.ProcessInfo
+}
+
+func runKill(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	sig := syscall.SIGKILL
+	if !killForce {
+		parsed, err := utils.ParseSignal(killSignal)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		sig = parsed
+	}
+	if killAll && len(args) > 0 {
+		return &usageError{err: fmt.Errorf("cannot specify a pattern with --all")}
+	}
+	if killTimeout < 0 {
+		return &usageError{err: fmt.Errorf("--timeout must not be negative")}
+	}
+
+	worktrees, err := ctx.WorktreeManager.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var selected []models.Worktree
+	if killAll {
+		selected = filterNonMainWorktrees(worktrees)
+	} else {
+		target, err := selectKillWorktree(ctx, worktrees, args)
+		if err != nil {
+			return err
+		}
+		selected = []models.Worktree{target}
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		IncludeProcess: true,
+		AgentNames:     ctx.Config.ProcessDetect.AgentNames,
+	})
+	// Collect for every worktree so nested worktrees keep their processes.
+	byPath, err := collector.WorktreeProcesses(context.Background(), worktrees)
+	if err != nil {
+		return err
+	}
+
+	// Never signal gwq's own ancestors, such as the invoking shell or an
+	// editor terminal running inside the worktree.
+	exclude := append(process.Ancestors(collector.processes, os.Getpid()), os.Getppid())
+	targets := killTargets(selected, byPath, exclude)
+	if len(targets) == 0 {
+		_, _ = fmt.Fprintln(out, "No processes running in the selected worktrees")
+		return nil
+	}
+
+	if killAll && !killYes && !confirmKill(os.Stdin, out, targets, sig) {
+		_, _ = fmt.Fprintln(out, "Operation cancelled")
+		return nil
+	}
+
+	pids := signalKillTargets(out, targets, sig, sendSignal)
+	if len(pids) == 0 {
+		return fmt.Errorf("failed to signal any process")
+	}
+
+	remaining, err := waitForExit(context.Background(), collector.processLister, pids, killTimeout, killPollInterval)
+	if err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		for _, t := range targets {
+			if slices.Contains(remaining, t.Process.PID) {
+				_, _ = fmt.Fprintf(out, "Still running: %d (%s) in %s\n", t.Process.PID, t.Process.Command, t.Worktree.Branch)
+			}
+		}
+		return fmt.Errorf("%d process(es) did not exit within %s", len(remaining), killTimeout)
+	}
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("%d process(es) exited", len(pids)))
+	return nil
+}
+
+// selectKillWorktree resolves the worktree to signal from an optional pattern,
+// showing the fuzzy finder when there is no pattern or several match.
+func selectKillWorktree(ctx *CommandContext, worktrees []models.Worktree, args []string) (models.Worktree, error) {
+	candidates := worktrees
+	if len(args) > 0 {
+		matches, err := ctx.WorktreeManager.GetMatchingWorktrees(args[0])
+		if err != nil {
+			return models.Worktree{}, err
+		}
+		if len(matches) == 0 {
+			return models.Worktree{}, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, args[0])
+		}
+		candidates = matches
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	selected, err := ctx.GetFinder().SelectWorktree(candidates)
+	if err != nil {
+		return models.Worktree{}, fmt.Errorf("worktree selection cancelled: %w", err)
+	}
+	return *selected, nil
+}
+
+// killTargets returns the processes running in worktrees, skipping the
+// excluded PIDs (the ancestors of gwq).
+func killTargets(worktrees []models.Worktree, byPath map[string][]models.ProcessInfo, exclude []int) []killTarget {
+	var targets []killTarget
+	for _, wt := range worktrees {
+		for _, p := range byPath[wt.Path] {
+			if slices.Contains(exclude, p.PID) {
+				continue
+			}
+			targets = append(targets, killTarget{Worktree: wt, Process: p})
+		}
+	}
+	return targets
+}
+
+// confirmKill lists the processes about to be signalled and asks for
+// confirmation.
+func confirmKill(in io.Reader, out io.Writer, targets []killTarget, sig syscall.Signal) bool {
+	_, _ = fmt.Fprintf(out, "This will send %s to %d process(es):\n", utils.SignalName(sig), len(targets))
+	for _, t := range targets {
+		_, _ = fmt.Fprintf(out, "  %d %s (%s)\n", t.Process.PID, t.Process.Command, t.Worktree.Branch)
+	}
+	_, _ = fmt.Fprint(out, "Are you sure? (y/N): ")
+
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+// sendSignal delivers sig to the process with the given PID.
+func sendSignal(pid int, sig syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Signal(sig)
+}
+
+// signalKillTargets sends sig to every target with send, reporting each
+// result, and returns the PIDs that were signalled.
+func signalKillTargets(out io.Writer, targets []killTarget, sig syscall.Signal, send func(int, syscall.Signal) error) []int {
+	name := utils.SignalName(sig)
+	var pids []int
+	for _, t := range targets {
+		if err := send(t.Process.PID, sig); err != nil {
+			_, _ = fmt.Fprintf(out, "Failed to send %s to %d (%s): %v\n", name, t.Process.PID, t.Process.Command, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "Sent %s to %d (%s) in %s\n", name, t.Process.PID, t.Process.Command, t.Worktree.Branch)
+		pids = append(pids, t.Process.PID)
+	}
+	return pids
+}
+
+// waitForExit polls lister until none of pids is running or timeout has
+// passed, and returns the PIDs still running.
+func waitForExit(ctx context.Context, lister process.Lister, pids []int, timeout, interval time.Duration) ([]int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		processes, err := lister.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list processes: %w", err)
+		}
+		var remaining []int
+		for _, p := range processes {
+			if slices.Contains(pids, p.PID) {
+				remaining = append(remaining, p.PID)
+			}
+		}
+		if len(remaining) == 0 || !time.Now().Before(deadline) {
+			slices.Sort(remaining)
+			return remaining, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
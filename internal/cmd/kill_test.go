@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/process"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestStatusCollector_WorktreeProcesses(t *testing.T) {
+	lister := fakeProcessLister{
+		{PID: 10, Command: "vim", Cwd: "/repo"},
+		{PID: 11, Command: "npm", Cwd: "/repo/worktrees/feature/web"},
+		{PID: 12, Command: "claude", Cwd: "/repo/worktrees/feature"},
+		{PID: 13, Command: "less", Cwd: "/elsewhere"},
+	}
+	worktrees := []models.Worktree{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/repo/worktrees/feature", Branch: "feature"},
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{IncludeProcess: true, ProcessLister: lister})
+	got, err := collector.WorktreeProcesses(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("WorktreeProcesses() error = %v", err)
+	}
+
+	pids := func(path string) []int {
+		var result []int
+		for _, p := range got[path] {
+			result = append(result, p.PID)
+		}
+		return result
+	}
+	if want := []int{10}; !slices.Equal(pids("/repo"), want) {
+		t.Errorf("main worktree processes = %v, want %v", pids("/repo"), want)
+	}
+	if want := []int{11, 12}; !slices.Equal(pids("/repo/worktrees/feature"), want) {
+		t.Errorf("nested worktree processes = %v, want %v", pids("/repo/worktrees/feature"), want)
+	}
+	if got["/repo/worktrees/feature"][1].Type != process.TypeAIAgent {
+		t.Errorf("claude was not classified as an agent: %+v", got["/repo/worktrees/feature"][1])
+	}
+}
+
+func TestKillTargets_ExcludesAncestors(t *testing.T) {
+	wt := models.Worktree{Path: "/wt", Branch: "feature"}
+	byPath := map[string][]models.ProcessInfo{
+		"/wt":    {{PID: 90, Command: "tmux"}, {PID: 100, Command: "zsh"}, {PID: 101, Command: "node"}},
+		"/other": {{PID: 200, Command: "vim"}},
+	}
+
+	targets := killTargets([]models.Worktree{wt}, byPath, []int{100, 90, 1})
+	if len(targets) != 1 || targets[0].Process.PID != 101 || targets[0].Worktree.Branch != "feature" {
+		t.Errorf("killTargets() = %+v, want only node in feature", targets)
+	}
+}
+
+func TestSignalKillTargets(t *testing.T) {
+	targets := []killTarget{
+		{Worktree: models.Worktree{Branch: "a"}, Process: models.ProcessInfo{PID: 1, Command: "node"}},
+		{Worktree: models.Worktree{Branch: "a"}, Process: models.ProcessInfo{PID: 2, Command: "gone"}},
+	}
+	sent := map[int]syscall.Signal{}
+	send := func(pid int, sig syscall.Signal) error {
+		if pid == 2 {
+			return errors.New("no such process")
+		}
+		sent[pid] = sig
+		return nil
+	}
+
+	var out bytes.Buffer
+	pids := signalKillTargets(&out, targets, syscall.SIGINT, send)
+	if !slices.Equal(pids, []int{1}) {
+		t.Errorf("signalled = %v, want [1]", pids)
+	}
+	if sent[1] != syscall.SIGINT {
+		t.Errorf("sent %v to 1, want SIGINT", sent[1])
+	}
+	if !strings.Contains(out.String(), "Sent SIGINT to 1 (node)") || !strings.Contains(out.String(), "Failed to send SIGINT to 2") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+// exitingLister reports its processes for a fixed number of calls, after
+// which the processes in exits are gone.
+type exitingLister struct {
+	processes []process.Process
+	exits     []int
+	after     int
+	calls     int
+}
+
+func (l *exitingLister) List(context.Context) ([]process.Process, error) {
+	l.calls++
+	if l.calls <= l.after {
+		return l.processes, nil
+	}
+	return slices.DeleteFunc(slices.Clone(l.processes), func(p process.Process) bool {
+		return slices.Contains(l.exits, p.PID)
+	}), nil
+}
+
+func TestWaitForExit(t *testing.T) {
+	processes := []process.Process{{PID: 1}, {PID: 2}, {PID: 3}}
+
+	t.Run("AllExit", func(t *testing.T) {
+		lister := &exitingLister{processes: processes, exits: []int{1, 2}, after: 2}
+		remaining, err := waitForExit(context.Background(), lister, []int{1, 2}, time.Second, time.Millisecond)
+		if err != nil {
+			t.Fatalf("waitForExit() error = %v", err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("remaining = %v, want none", remaining)
+		}
+		if lister.calls != 3 {
+			t.Errorf("lister called %d times, want 3", lister.calls)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		lister := &exitingLister{processes: processes, exits: []int{1}}
+		remaining, err := waitForExit(context.Background(), lister, []int{1, 2}, 20*time.Millisecond, time.Millisecond)
+		if err != nil {
+			t.Fatalf("waitForExit() error = %v", err)
+		}
+		if !slices.Equal(remaining, []int{2}) {
+			t.Errorf("remaining = %v, want [2]", remaining)
+		}
+	})
+}
+
+func TestConfirmKill(t *testing.T) {
+	targets := []killTarget{{Worktree: models.Worktree{Branch: "a"}, Process: models.ProcessInfo{PID: 7, Command: "node"}}}
+
+	var out bytes.Buffer
+	if !confirmKill(strings.NewReader("y\n"), &out, targets, syscall.SIGTERM) {
+		t.Error("confirmKill() = false for 'y'")
+	}
+	if !strings.Contains(out.String(), "send SIGTERM to 1 process(es)") || !strings.Contains(out.String(), "7 node (a)") {
+		t.Errorf("prompt = %q", out.String())
+	}
+	if confirmKill(strings.NewReader("\n"), &out, targets, syscall.SIGTERM) {
+		t.Error("confirmKill() = true for empty answer")
+	}
+}
@@ -1,16 +1,40 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/d-kuro/gwq/internal/table"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
-	listVerbose bool
-	listJSON    bool
-	listGlobal  bool
+	listVerbose  bool
+	listJSON     bool
+	listOutput   string
+	listGlobal   bool
+	listNoCache  bool
+	listNoMain   bool
+	listExpand   bool
+	listWatch    bool
+	listInterval int
+	listSort     string
+	listReverse  bool
+	listFilters  []string
+	listFilterOr bool
+	listState    string
+	listDedupBy  string
+	listBehind   bool
 )
 
 // listCmd represents the list command.
@@ -24,7 +48,49 @@ When run inside a git repository, shows worktrees for the current repository.
 When run outside a git repository, shows all worktrees in the configured base directory.
 Use -g flag to always show all worktrees from the base directory.
 Use -v flag for detailed information including commit hashes and creation times.
-Use --json flag to output in JSON format for scripting.`,
+Use -o json or -o csv for output suitable for scripting; --json is a
+shorthand for -o json.
+
+In global mode, repositories that have no worktrees besides the main one are
+collapsed into a single summary line; use --expand to list them as well.
+Use --no-main to hide main worktrees altogether. JSON and CSV output are never
+collapsed.
+
+Global discovery results are cached in the gwq config directory and reused
+while the base directory is unchanged. Use --no-cache to force a rescan.
+
+By default worktrees are listed in the order git reports them. --sort orders
+them by name (branch), path, activity (most recently active first), status
+(conflict, modified, staged, stale, clean) or commit-date (newest HEAD commit
+first); activity and status collect a lightweight status for each worktree
+first. Ties are broken by path. --reverse inverts the order.
+
+--filter keeps only worktrees matching an expression. An expression is a
+status name (clean, modified, staged, conflict, stale) or FIELD OP VALUE:
+  status=modified, status!=clean       compare the status
+  branch~=^feature/, path~=/myproject/ match a regular expression (= and != also work)
+  ahead>0, behind>=1, changes=0        compare a counter (=, !=, <, <=, >, >=)
+Counters are ahead, behind, modified, added, deleted, untracked, staged,
+conflicts and changes. Repeated --filter flags must all match; with
+--filter-or any of them may match.
+
+--filter-status is a faster alternative for the common case: it runs only
+'git status --porcelain' in each worktree, in parallel, and keeps those that
+are clean, modified, staged or conflict. It skips the activity scan, so it
+cannot select stale worktrees.
+
+--deduplicate-by branch shows each branch of a repository only once. The
+same repository can be discovered more than once, e.g. when it is cloned
+twice under the base directory; the entry at the most canonical path is
+kept: one in the directory named after its branch, then the shortest path.
+
+--commits-behind adds a BEHIND column with how many commits each branch is
+behind its upstream. It compares with the remote-tracking branch as last
+fetched, without contacting the remote, so run 'git fetch' or 'gwq sync'
+first for current numbers. Branches without an upstream show "-".
+
+With --watch the list is rescanned every --interval seconds and redrawn
+whenever it changes, until interrupted with Ctrl+C.`,
 	Example: `  # Simple list
   gwq list
 
@@ -35,10 +101,46 @@ Use --json flag to output in JSON format for scripting.`,
   gwq list -v
 
   # JSON format for scripting
-  gwq list --json
+  gwq list -o json
+
+  # CSV format
+  gwq list -o csv
 
   # Show all worktrees from base directory (from anywhere)
-  gwq list -g`,
+  gwq list -g
+
+  # Include repositories without additional worktrees
+  gwq list -g --expand
+
+  # Only show additional worktrees, not main repositories
+  gwq list -g --no-main
+
+  # Rescan the base directory, ignoring the discovery cache
+  gwq list -g --no-cache
+
+  # Most recently active worktrees first
+  gwq list --sort activity
+
+  # Alphabetical by branch, Z to A
+  gwq list -s name -r
+
+  # Worktrees with uncommitted changes on feature branches
+  gwq list -f status=modified -f 'branch~=^feature/'
+
+  # Quickly list worktrees with uncommitted changes
+  gwq list --filter-status modified
+
+  # Worktrees that are ahead or behind their upstream
+  gwq list -f 'ahead>0' -f 'behind>0' --filter-or
+
+  # Spot branches that need a rebase
+  gwq list -g --commits-behind
+
+  # Show each repository branch once across duplicate clones
+  gwq list -g --deduplicate-by branch
+
+  # Redraw the list as worktrees are added and removed
+  gwq list -g --watch`,
 	RunE: runList,
 }
 
@@ -47,10 +149,70 @@ func init() {
 
 	listCmd.Flags().BoolVarP(&listVerbose, "verbose", "v", false, "Show detailed information")
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output in JSON format")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", "table", "Output format (table, json, csv)")
 	listCmd.Flags().BoolVarP(&listGlobal, "global", "g", false, "Show all worktrees from the configured base directory")
+	listCmd.Flags().BoolVar(&listNoCache, "no-cache", false, "Ignore the discovery cache and rescan the base directory")
+	listCmd.Flags().BoolVar(&listNoMain, "no-main", false, "Hide main worktrees")
+	listCmd.Flags().BoolVar(&listExpand, "expand", false, "In global mode, also list repositories without additional worktrees")
+	listCmd.Flags().BoolVarP(&listWatch, "watch", "w", false, "Redraw the list periodically as it changes")
+	listCmd.Flags().IntVarP(&listInterval, "interval", "i", 5, "Refresh interval in seconds for watch mode")
+	listCmd.Flags().StringVarP(&listSort, "sort", "s", "", "Sort by: name, path, activity, status, commit-date")
+	listCmd.Flags().BoolVarP(&listReverse, "reverse", "r", false, "Reverse the sort order")
+	listCmd.Flags().StringArrayVarP(&listFilters, "filter", "f", nil, "Only show worktrees matching an expression (repeatable, e.g. status=modified, behind>0)")
+	listCmd.Flags().BoolVar(&listFilterOr, "filter-or", false, "Show worktrees matching any --filter instead of all")
+	listCmd.Flags().StringVar(&listDedupBy, "deduplicate-by", "", "Show entries sharing this key only once (branch)")
+	listCmd.Flags().BoolVar(&listBehind, "commits-behind", false, "Show how many commits each branch is behind its fetched upstream")
+	listCmd.Flags().StringVar(&listState, "filter-status", "", "Only show worktrees in this state, using git status only (clean, modified, staged, conflict)")
+	listCmd.MarkFlagsMutuallyExclusive("no-main", "expand")
+
+	_ = listCmd.RegisterFlagCompletionFunc("filter-status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		states := make([]string, len(listFilterStates))
+		for i, s := range listFilterStates {
+			states[i] = string(s)
+		}
+		return states, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = listCmd.RegisterFlagCompletionFunc("deduplicate-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"branch"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = listCmd.RegisterFlagCompletionFunc("sort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return worktreeSortKeys, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	format, err := resolveListOutputFormat(cmd.Flags().Changed("output"))
+	if err != nil {
+		return &usageError{err: err}
+	}
+	if listSort != "" {
+		if err := validateWorktreeSortKey(listSort); err != nil {
+			return &usageError{err: err}
+		}
+	}
+	if listReverse && listSort == "" {
+		return &usageError{err: fmt.Errorf("--reverse requires --sort")}
+	}
+	if listFilterOr && len(listFilters) == 0 {
+		return &usageError{err: fmt.Errorf("--filter-or requires --filter")}
+	}
+	if listDedupBy != "" && listDedupBy != "branch" {
+		return &usageError{err: fmt.Errorf("invalid --deduplicate-by %q: must be branch", listDedupBy)}
+	}
+	arrangement, err := newListArrangement()
+	if err != nil {
+		return &usageError{err: err}
+	}
+	if listWatch {
+		if format != "table" {
+			return &usageError{err: fmt.Errorf("--watch cannot be combined with -o %s", format)}
+		}
+		if listInterval <= 0 {
+			return &usageError{err: fmt.Errorf("--interval must be positive")}
+		}
+	}
+	w := cmd.OutOrStdout()
+
 	// Try git context first, fall back to non-git if needed
 	ctx, err := NewGitCommandContext()
 	if err != nil {
@@ -60,7 +222,26 @@ func runList(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	ctx.NoDiscoveryCache = listNoCache
+
+	if listWatch {
+		watchCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		// Only the first scan may bypass the discovery cache; later scans rely
+		// on it to stay cheap.
+		render := func(out io.Writer) error {
+			defer func() { ctx.NoDiscoveryCache = false }()
+			return renderList(out, ctx, format, arrangement)
+		}
+		return watchList(watchCtx, w, time.Duration(listInterval)*time.Second, render)
+	}
 
+	return renderList(w, ctx, format, arrangement)
+}
+
+// renderList writes the worktree list for the current repository, or for
+// the base directory in global mode, to w.
+func renderList(w io.Writer, ctx *CommandContext, format string, arrangement listArrangement) error {
 	return ctx.WithGlobalLocalSupport(
 		listGlobal,
 		func(ctx *CommandContext) error {
@@ -69,29 +250,43 @@ func runList(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return fmt.Errorf("failed to list worktrees: %w", err)
 			}
+			if listNoMain {
+				worktrees = filterNonMainWorktrees(worktrees)
+			}
+			if listDedupBy != "" {
+				worktrees = deduplicateByBranch(worktrees)
+			}
+			if worktrees, err = arrangeListedWorktrees(ctx, worktrees, arrangement); err != nil {
+				return err
+			}
+			if listBehind {
+				annotateListedCommitsBehind(worktrees)
+			}
 
-			if listJSON {
-				return ctx.Printer.PrintWorktreesJSON(worktrees)
+			if format != "table" {
+				return outputWorktrees(w, worktrees, format, listedWorktreeChanges)
 			}
 
-			ctx.Printer.PrintWorktrees(worktrees, listVerbose)
+			ctx.Printer.FprintWorktrees(w, worktrees, listVerbose)
+			printBranchDirMismatchHint(w, worktrees)
+			printNoUpstreamHint(w, worktrees)
 			return nil
 		},
 		func(ctx *CommandContext) error {
 			// Global mode - show all worktrees from base directory
-			return showGlobalWorktrees(ctx)
+			return showGlobalWorktrees(ctx, w, format, arrangement)
 		},
 	)
 }
 
-func showGlobalWorktrees(ctx *CommandContext) error {
+func showGlobalWorktrees(ctx *CommandContext, w io.Writer, format string, arrangement listArrangement) error {
 	worktreePointers, err := ctx.DiscoverGlobalWorktrees()
 	if err != nil {
 		return fmt.Errorf("failed to discover worktrees: %w", err)
 	}
 
-	if len(worktreePointers) == 0 {
-		ctx.Printer.PrintInfo("No worktrees found in " + ctx.Config.Worktree.BaseDir)
+	if len(worktreePointers) == 0 && format == "table" {
+		_, _ = fmt.Fprintln(w, "No worktrees found in "+ctx.Config.Worktree.BaseDir)
 		return nil
 	}
 
@@ -100,11 +295,259 @@ func showGlobalWorktrees(ctx *CommandContext) error {
 	for _, w := range worktreePointers {
 		worktrees = append(worktrees, *w)
 	}
+	if listNoMain {
+		worktrees = filterNonMainWorktrees(worktrees)
+	}
+	if listDedupBy != "" {
+		worktrees = deduplicateByBranch(worktrees)
+	}
+	if worktrees, err = arrangeListedWorktrees(ctx, worktrees, arrangement); err != nil {
+		return err
+	}
 
-	if listJSON {
-		return ctx.Printer.PrintWorktreesJSON(worktrees)
+	if format != "table" {
+		if listBehind {
+			annotateListedCommitsBehind(worktrees)
+		}
+		return outputWorktrees(w, worktrees, format, listedWorktreeChanges)
 	}
 
-	ctx.Printer.PrintWorktrees(worktrees, listVerbose)
+	collapsed := 0
+	if !listExpand && !listNoMain {
+		worktrees, collapsed = collapseMainOnlyRepos(worktrees)
+	}
+	// Counted after collapsing so hidden main worktrees cost nothing.
+	if listBehind {
+		annotateListedCommitsBehind(worktrees)
+	}
+
+	if len(worktrees) > 0 {
+		ctx.Printer.FprintWorktrees(w, worktrees, listVerbose)
+		printBranchDirMismatchHint(w, worktrees)
+		printNoUpstreamHint(w, worktrees)
+	}
+	if collapsed > 0 {
+		_, _ = fmt.Fprintf(w, "%d repositories without additional worktrees not shown (use --expand)\n", collapsed)
+	}
 	return nil
 }
+
+// printNoUpstreamHint explains why --commits-behind added no column: no
+// listed branch has an upstream.
+func printNoUpstreamHint(w io.Writer, worktrees []models.Worktree) {
+	if !listBehind || len(worktrees) == 0 {
+		return
+	}
+	for _, wt := range worktrees {
+		if wt.CommitsBehind != nil {
+			return
+		}
+	}
+	_, _ = fmt.Fprintln(w, "No listed branch has an upstream to compare with")
+}
+
+// printBranchDirMismatchHint explains the [dir mismatch] markers of the
+// verbose table, if any.
+func printBranchDirMismatchHint(w io.Writer, worktrees []models.Worktree) {
+	if !listVerbose {
+		return
+	}
+	n := 0
+	for _, wt := range worktrees {
+		if wt.BranchDirMismatch {
+			n++
+		}
+	}
+	if n > 0 {
+		_, _ = fmt.Fprintf(w, "%d worktree(s) are not in the directory named after their branch (renamed outside gwq?); use 'gwq move' to relocate them\n", n)
+	}
+}
+
+// watchList calls render every interval and redraws w with its output
+// until ctx is done. The screen is only redrawn when the output changed,
+// so an unchanged list does not flicker. A failed render is shown in place
+// of the list and retried on the next tick.
+func watchList(ctx context.Context, w io.Writer, interval time.Duration, render func(io.Writer) error) error {
+	const (
+		hideCursor  = "\033[?25l"
+		showCursor  = "\033[?25h"
+		clearScreen = "\033[H\033[2J"
+	)
+
+	_, _ = fmt.Fprint(w, hideCursor)
+	defer func() { _, _ = fmt.Fprint(w, showCursor) }()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous string
+	for {
+		var buf bytes.Buffer
+		if err := render(&buf); err != nil {
+			buf.Reset()
+			_, _ = fmt.Fprintf(&buf, "Error: %v\n", err)
+		}
+		if current := buf.String(); current != previous {
+			_, _ = fmt.Fprintf(w, "%s%s\n[Press Ctrl+C to exit]\n", clearScreen, current)
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// newListArrangement builds the listArrangement for the --filter-status,
+// --filter, --filter-or, --sort and --reverse flags.
+func newListArrangement() (listArrangement, error) {
+	filter, err := ParseFilters(listFilters, listFilterOr)
+	if err != nil {
+		return listArrangement{}, err
+	}
+	var state models.WorktreeState
+	if listState != "" {
+		if state, err = parseListFilterState(listState); err != nil {
+			return listArrangement{}, err
+		}
+	}
+	return listArrangement{
+		state:       state,
+		filter:      filter,
+		fetchRemote: filtersNeedRemote(listFilters),
+		sortBy:      listSort,
+		reverse:     listReverse,
+	}, nil
+}
+
+// collapseMainOnlyRepos drops repositories whose only entry is their main
+// worktree, keeping entry order. Worktrees are grouped by repository (by
+// RepositoryInfo, or by path when unknown). It returns the kept worktrees
+// and the number of repositories collapsed.
+func collapseMainOnlyRepos(worktrees []models.Worktree) ([]models.Worktree, int) {
+	repoKey := func(wt models.Worktree) string {
+		if wt.RepositoryInfo != nil {
+			return wt.RepositoryInfo.FullPath
+		}
+		return wt.Path
+	}
+
+	hasLinked := make(map[string]bool)
+	for _, wt := range worktrees {
+		if !wt.IsMain {
+			hasLinked[repoKey(wt)] = true
+		}
+	}
+
+	var kept []models.Worktree
+	collapsed := 0
+	for _, wt := range worktrees {
+		if wt.IsMain && !hasLinked[repoKey(wt)] {
+			collapsed++
+			continue
+		}
+		kept = append(kept, wt)
+	}
+	return kept, collapsed
+}
+
+// deduplicateByBranch keeps one entry per repository and branch, in the
+// position of the first one. Of the duplicates it keeps the one in the
+// directory named after its branch, then the one with the shortest path.
+// Entries without a known repository or on a detached HEAD are never
+// merged.
+func deduplicateByBranch(worktrees []models.Worktree) []models.Worktree {
+	canonical := func(a, b models.Worktree) bool {
+		if a.BranchDirMismatch != b.BranchDirMismatch {
+			return !a.BranchDirMismatch
+		}
+		if len(a.Path) != len(b.Path) {
+			return len(a.Path) < len(b.Path)
+		}
+		return a.Path < b.Path
+	}
+
+	index := make(map[string]int)
+	var kept []models.Worktree
+	for _, wt := range worktrees {
+		if wt.RepositoryInfo == nil || wt.Branch == "" || wt.Branch == "HEAD" {
+			kept = append(kept, wt)
+			continue
+		}
+		key := wt.RepositoryInfo.FullPath + "\x00" + wt.Branch
+		if i, ok := index[key]; ok {
+			if canonical(wt, kept[i]) {
+				kept[i] = wt
+			}
+			continue
+		}
+		index[key] = len(kept)
+		kept = append(kept, wt)
+	}
+	return kept
+}
+
+// resolveListOutputFormat reconciles --output with the legacy --json flag.
+// outputSet reports whether --output was given explicitly; --json then only
+// agrees with -o json, so a conflicting request is never silently resolved.
+func resolveListOutputFormat(outputSet bool) (string, error) {
+	format := strings.ToLower(listOutput)
+	switch format {
+	case "table", "json", "csv":
+	default:
+		return "", fmt.Errorf("invalid output format %q: must be table, json, or csv", listOutput)
+	}
+	if listJSON {
+		if outputSet && format != "json" {
+			return "", fmt.Errorf("--json cannot be combined with -o %s", format)
+		}
+		format = "json"
+	}
+	return format, nil
+}
+
+// listedWorktreeChanges reads the working tree state of a listed worktree
+// for the CSV status and changes columns.
+func listedWorktreeChanges(ctx context.Context, path string) (models.WorktreeState, models.GitStatus, error) {
+	return NewStatusCollectorWithOptions(StatusCollectorOptions{}).WorkingTreeChanges(ctx, path)
+}
+
+// outputWorktrees writes worktrees in a machine-readable format. Paths are
+// written as they are, without icons or tilde abbreviation. CSV rows also
+// carry each worktree's status and number of changed files, read through
+// changes.
+func outputWorktrees(w io.Writer, worktrees []models.Worktree, format string, changes changesReader) error {
+	if format == "csv" {
+		counts := collectWorktreeChanges(context.Background(), worktrees, 0, changes)
+		headers := []string{"path", "branch", "commit", "is_main", "status", "changes"}
+		if listBehind {
+			headers = append(headers, "commits_behind")
+		}
+		t := table.New().SetOutput(w).Headers(headers...)
+		for i, wt := range worktrees {
+			changed := ""
+			if counts[i].State != models.WorktreeStatusUnknown {
+				changed = strconv.Itoa(counts[i].Changes)
+			}
+			row := []string{wt.Path, wt.Branch, wt.CommitHash, strconv.FormatBool(wt.IsMain), string(counts[i].State), changed}
+			if listBehind {
+				behind := ""
+				if wt.CommitsBehind != nil {
+					behind = strconv.Itoa(*wt.CommitsBehind)
+				}
+				row = append(row, behind)
+			}
+			t.Row(row...)
+		}
+		return t.WriteCSV()
+	}
+
+	if worktrees == nil {
+		worktrees = []models.Worktree{} // encode as [] rather than null
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(worktrees)
+}
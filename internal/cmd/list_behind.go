@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// maxCommitsBehindConcurrency limits how many worktrees 'gwq list
+// --commits-behind' counts at once.
+const maxCommitsBehindConcurrency = 8
+
+// behindCounter returns how many commits the worktree at path is behind its
+// upstream; ok is false when it has none.
+type behindCounter func(ctx context.Context, path string) (behind int, ok bool)
+
+// annotateCommitsBehind sets CommitsBehind on each worktree with an
+// upstream, leaving it nil on the others. count runs for at most workers
+// worktrees at a time; workers <= 0 means one per CPU.
+func annotateCommitsBehind(ctx context.Context, worktrees []models.Worktree, workers int, count behindCounter) {
+	if len(worktrees) == 0 {
+		return
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(workers, len(worktrees)) {
+		wg.Go(func() {
+			for idx := range jobs {
+				// Each index is written by exactly one worker.
+				if behind, ok := count(ctx, worktrees[idx].Path); ok {
+					worktrees[idx].CommitsBehind = &behind
+				} else {
+					worktrees[idx].CommitsBehind = nil
+				}
+			}
+		})
+	}
+
+	for idx := range worktrees {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// annotateListedCommitsBehind counts the commits behind for 'gwq list
+// --commits-behind' from the remote-tracking branches already fetched.
+func annotateListedCommitsBehind(worktrees []models.Worktree) {
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{})
+	annotateCommitsBehind(context.Background(), worktrees, maxCommitsBehindConcurrency, collector.CommitsBehind)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestAnnotateCommitsBehind_GitUpstream(t *testing.T) {
+	origin := initTestGitRepo(t)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "-q", origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("git clone failed: %v: %s", err, out)
+	}
+	for _, msg := range []string{"one", "two"} {
+		git(origin, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-q", "--allow-empty", "-m", msg)
+	}
+	git(clone, "fetch", "-q")
+
+	local := filepath.Join(t.TempDir(), "local")
+	git(clone, "worktree", "add", "-q", "-b", "local", local)
+	detached := filepath.Join(t.TempDir(), "detached")
+	git(clone, "worktree", "add", "-q", "--detach", detached)
+
+	worktrees := []models.Worktree{
+		{Branch: "main", Path: clone, IsMain: true},
+		{Branch: "local", Path: local},
+		{Branch: "HEAD", Path: detached},
+	}
+	annotateCommitsBehind(context.Background(), worktrees, 2, NewStatusCollectorWithOptions(StatusCollectorOptions{}).CommitsBehind)
+
+	if got := worktrees[0].CommitsBehind; got == nil || *got != 2 {
+		t.Errorf("main CommitsBehind = %v, want 2", got)
+	}
+	for _, wt := range worktrees[1:] {
+		if wt.CommitsBehind != nil {
+			t.Errorf("%s CommitsBehind = %d, want nil without upstream", wt.Branch, *wt.CommitsBehind)
+		}
+	}
+}
+
+func TestAnnotateCommitsBehind_Bounded(t *testing.T) {
+	worktrees := make([]models.Worktree, 10)
+	for i := range worktrees {
+		worktrees[i] = models.Worktree{Path: "/wt/" + string(rune('a'+i))}
+	}
+
+	var inFlight, peak atomic.Int32
+	count := func(ctx context.Context, path string) (int, bool) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if path == "/wt/c" {
+			return 0, false
+		}
+		return int(path[len(path)-1] - 'a'), true
+	}
+
+	annotateCommitsBehind(context.Background(), worktrees, 3, count)
+
+	for i, wt := range worktrees {
+		if i == 2 {
+			if wt.CommitsBehind != nil {
+				t.Errorf("%s CommitsBehind = %d, want nil", wt.Path, *wt.CommitsBehind)
+			}
+			continue
+		}
+		if wt.CommitsBehind == nil || *wt.CommitsBehind != i {
+			t.Errorf("%s CommitsBehind = %v, want %d", wt.Path, wt.CommitsBehind, i)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// listFilterStates are the states 'gwq list --filter-status' can select.
+// Stale needs activity information, which --filter-status does not collect.
+var listFilterStates = []models.WorktreeState{
+	models.WorktreeStatusClean,
+	models.WorktreeStatusModified,
+	models.WorktreeStatusStaged,
+	models.WorktreeStatusConflict,
+}
+
+// parseListFilterState parses the --filter-status value.
+func parseListFilterState(s string) (models.WorktreeState, error) {
+	state, err := models.ParseWorktreeState(s)
+	if err != nil || !slices.Contains(listFilterStates, state) {
+		return "", fmt.Errorf("invalid --filter-status %q: must be one of clean, modified, staged, conflict (use --filter stale for stale worktrees)", s)
+	}
+	return state, nil
+}
+
+// stateClassifier returns the working tree state of the worktree at path.
+type stateClassifier func(ctx context.Context, path string) (models.WorktreeState, error)
+
+// filterWorktreesByState keeps the worktrees whose working tree state is
+// want, preserving order. classify runs for at most workers worktrees at a
+// time; workers <= 0 means one per CPU. Worktrees that cannot be classified
+// are dropped with a warning.
+func filterWorktreesByState(ctx context.Context, worktrees []models.Worktree, want models.WorktreeState, workers int, classify stateClassifier) []models.Worktree {
+	if len(worktrees) == 0 {
+		return nil
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	matched := make([]bool, len(worktrees))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(workers, len(worktrees)) {
+		wg.Go(func() {
+			for idx := range jobs {
+				state, err := classify(ctx, worktrees[idx].Path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to read status of %s: %v\n", worktrees[idx].Path, err)
+					continue
+				}
+				matched[idx] = state == want
+			}
+		})
+	}
+
+	for idx := range worktrees {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	var kept []models.Worktree
+	for idx, wt := range worktrees {
+		if matched[idx] {
+			kept = append(kept, wt)
+		}
+	}
+	return kept
+}
+
+// worktreeChanges is the working tree state of a worktree and its number of
+// changed files, as 'gwq list -o csv' reports them.
+type worktreeChanges struct {
+	State   models.WorktreeState
+	Changes int
+}
+
+// changesReader returns the working tree state and file counts of the
+// worktree at path.
+type changesReader func(ctx context.Context, path string) (models.WorktreeState, models.GitStatus, error)
+
+// collectWorktreeChanges reads the changes of each worktree, in order, at
+// most workers at a time; workers <= 0 means one per CPU. Worktrees that
+// cannot be read are reported as unknown with a warning.
+func collectWorktreeChanges(ctx context.Context, worktrees []models.Worktree, workers int, read changesReader) []worktreeChanges {
+	changes := make([]worktreeChanges, len(worktrees))
+	if len(worktrees) == 0 {
+		return changes
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(workers, len(worktrees)) {
+		wg.Go(func() {
+			for idx := range jobs {
+				state, status, err := read(ctx, worktrees[idx].Path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to read status of %s: %v\n", worktrees[idx].Path, err)
+					changes[idx] = worktreeChanges{State: models.WorktreeStatusUnknown}
+					continue
+				}
+				changes[idx] = worktreeChanges{State: state, Changes: countTotalChanges(status)}
+			}
+		})
+	}
+
+	for idx := range worktrees {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	return changes
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestParseListFilterState(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    models.WorktreeState
+		wantErr bool
+	}{
+		{input: "modified", want: models.WorktreeStatusModified},
+		{input: "Clean", want: models.WorktreeStatusClean},
+		{input: "conflicted", want: models.WorktreeStatusConflict},
+		{input: "staged", want: models.WorktreeStatusStaged},
+		{input: "stale", wantErr: true},
+		{input: "unknown", wantErr: true},
+		{input: "dirty", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseListFilterState(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseListFilterState(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("parseListFilterState(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFilterWorktreesByState_GitStatus(t *testing.T) {
+	repo := initTestGitRepo(t)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	base := t.TempDir()
+	paths := map[string]string{}
+	for _, name := range []string{"clean", "untracked", "staged", "edited"} {
+		paths[name] = filepath.Join(base, name)
+		git(repo, "worktree", "add", "-q", "-b", name, paths[name])
+	}
+	if err := os.WriteFile(filepath.Join(paths["untracked"], "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(paths["staged"], "added.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(paths["staged"], "add", "added.txt")
+	if err := os.WriteFile(filepath.Join(paths["edited"], "tracked.txt"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(paths["edited"], "add", "tracked.txt")
+	git(paths["edited"], "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "add tracked")
+	if err := os.WriteFile(filepath.Join(paths["edited"], "tracked.txt"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees := []models.Worktree{
+		{Branch: "main", Path: repo, IsMain: true},
+		{Branch: "clean", Path: paths["clean"]},
+		{Branch: "untracked", Path: paths["untracked"]},
+		{Branch: "staged", Path: paths["staged"]},
+		{Branch: "edited", Path: paths["edited"]},
+	}
+	classify := NewStatusCollectorWithOptions(StatusCollectorOptions{}).WorkingTreeState
+
+	tests := []struct {
+		state models.WorktreeState
+		want  []string
+	}{
+		{state: models.WorktreeStatusClean, want: []string{"main", "clean"}},
+		{state: models.WorktreeStatusModified, want: []string{"untracked", "edited"}},
+		{state: models.WorktreeStatusStaged, want: []string{"staged"}},
+		{state: models.WorktreeStatusConflict, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			var got []string
+			for _, wt := range filterWorktreesByState(context.Background(), worktrees, tt.state, 2, classify) {
+				got = append(got, wt.Branch)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterWorktreesByState(%s) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterWorktreesByState_BoundedAndSkipsErrors(t *testing.T) {
+	worktrees := make([]models.Worktree, 10)
+	for i := range worktrees {
+		worktrees[i] = models.Worktree{Branch: string(rune('a' + i)), Path: "/wt/" + string(rune('a'+i))}
+	}
+
+	var inFlight, peak atomic.Int32
+	classify := func(ctx context.Context, path string) (models.WorktreeState, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		switch path {
+		case "/wt/b":
+			return models.WorktreeStatusUnknown, errors.New("not a git repository")
+		case "/wt/c", "/wt/f":
+			return models.WorktreeStatusModified, nil
+		}
+		return models.WorktreeStatusClean, nil
+	}
+
+	got := filterWorktreesByState(context.Background(), worktrees, models.WorktreeStatusModified, 3, classify)
+	if len(got) != 2 || got[0].Branch != "c" || got[1].Branch != "f" {
+		t.Errorf("filterWorktreesByState() = %+v, want c and f", got)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+}
+
+func TestCollectWorktreeChanges(t *testing.T) {
+	worktrees := []models.Worktree{{Path: "/wt/a"}, {Path: "/wt/b"}, {Path: "/wt/c"}}
+	read := func(ctx context.Context, path string) (models.WorktreeState, models.GitStatus, error) {
+		switch path {
+		case "/wt/b":
+			return models.WorktreeStatusUnknown, models.GitStatus{}, errors.New("not a git repository")
+		case "/wt/c":
+			return models.WorktreeStatusStaged, models.GitStatus{Staged: 1, Modified: 2}, nil
+		}
+		return models.WorktreeStatusClean, models.GitStatus{}, nil
+	}
+
+	got := collectWorktreeChanges(context.Background(), worktrees, 2, read)
+	want := []worktreeChanges{
+		{State: models.WorktreeStatusClean},
+		{State: models.WorktreeStatusUnknown},
+		{State: models.WorktreeStatusStaged, Changes: 3},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("collectWorktreeChanges() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/ui"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestCollapseMainOnlyRepos(t *testing.T) {
+	single := &models.RepositoryInfo{FullPath: "github.com/user/single"}
+	multi := &models.RepositoryInfo{FullPath: "github.com/user/multi"}
+
+	tests := []struct {
+		name          string
+		worktrees     []models.Worktree
+		wantPaths     []string
+		wantCollapsed int
+	}{
+		{
+			name: "main-only repository is collapsed",
+			worktrees: []models.Worktree{
+				{Path: "/src/single", IsMain: true, RepositoryInfo: single},
+			},
+			wantPaths:     nil,
+			wantCollapsed: 1,
+		},
+		{
+			name: "repository with worktrees keeps its main entry",
+			worktrees: []models.Worktree{
+				{Path: "/src/multi", IsMain: true, RepositoryInfo: multi},
+				{Path: "/wt/multi-a", RepositoryInfo: multi},
+				{Path: "/wt/multi-b", RepositoryInfo: multi},
+			},
+			wantPaths:     []string{"/src/multi", "/wt/multi-a", "/wt/multi-b"},
+			wantCollapsed: 0,
+		},
+		{
+			name: "mixed repositories keep order",
+			worktrees: []models.Worktree{
+				{Path: "/src/single", IsMain: true, RepositoryInfo: single},
+				{Path: "/src/multi", IsMain: true, RepositoryInfo: multi},
+				{Path: "/wt/multi-a", RepositoryInfo: multi},
+			},
+			wantPaths:     []string{"/src/multi", "/wt/multi-a"},
+			wantCollapsed: 1,
+		},
+		{
+			name: "linked worktree discovered without its main repository",
+			worktrees: []models.Worktree{
+				{Path: "/wt/orphan", RepositoryInfo: single},
+			},
+			wantPaths:     []string{"/wt/orphan"},
+			wantCollapsed: 0,
+		},
+		{
+			name: "unknown repositories are grouped by path",
+			worktrees: []models.Worktree{
+				{Path: "/src/a", IsMain: true},
+				{Path: "/src/b", IsMain: true},
+			},
+			wantPaths:     nil,
+			wantCollapsed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, collapsed := collapseMainOnlyRepos(tt.worktrees)
+			var paths []string
+			for _, wt := range got {
+				paths = append(paths, wt.Path)
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("collapseMainOnlyRepos() paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if collapsed != tt.wantCollapsed {
+				t.Errorf("collapseMainOnlyRepos() collapsed = %d, want %d", collapsed, tt.wantCollapsed)
+			}
+		})
+	}
+}
+
+func TestDeduplicateByBranch(t *testing.T) {
+	app := &models.RepositoryInfo{FullPath: "github.com/user/app"}
+	other := &models.RepositoryInfo{FullPath: "github.com/user/other"}
+
+	tests := []struct {
+		name      string
+		worktrees []models.Worktree
+		wantPaths []string
+	}{
+		{
+			name: "same repository and branch from two clones",
+			worktrees: []models.Worktree{
+				{Path: "/base/mirror/github.com/user/app-feature", Branch: "feature", RepositoryInfo: app, BranchDirMismatch: true},
+				{Path: "/base/github.com/user/app", Branch: "main", IsMain: true, RepositoryInfo: app},
+				{Path: "/base/github.com/user/app-feature", Branch: "feature", RepositoryInfo: app},
+			},
+			wantPaths: []string{"/base/github.com/user/app-feature", "/base/github.com/user/app"},
+		},
+		{
+			name: "shortest path wins when both match their branch",
+			worktrees: []models.Worktree{
+				{Path: "/base/long/app", Branch: "main", IsMain: true, RepositoryInfo: app},
+				{Path: "/base/app", Branch: "main", IsMain: true, RepositoryInfo: app},
+			},
+			wantPaths: []string{"/base/app"},
+		},
+		{
+			name: "same branch in different repositories",
+			worktrees: []models.Worktree{
+				{Path: "/base/app", Branch: "main", RepositoryInfo: app},
+				{Path: "/base/other", Branch: "main", RepositoryInfo: other},
+			},
+			wantPaths: []string{"/base/app", "/base/other"},
+		},
+		{
+			name: "detached and unknown entries are kept",
+			worktrees: []models.Worktree{
+				{Path: "/base/app-a", Branch: "HEAD", RepositoryInfo: app},
+				{Path: "/base/app-b", Branch: "HEAD", RepositoryInfo: app},
+				{Path: "/x/a", Branch: "main"},
+				{Path: "/x/b", Branch: "main"},
+			},
+			wantPaths: []string{"/base/app-a", "/base/app-b", "/x/a", "/x/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			for _, wt := range deduplicateByBranch(tt.worktrees) {
+				paths = append(paths, wt.Path)
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("deduplicateByBranch() paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// assertGolden compares got with testdata/<name>, rewriting the file
+// instead when the test binary runs with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("failed to create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestOutputWorktrees(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	worktrees := []models.Worktree{
+		{Path: "/src/repo", Branch: "main", CommitHash: "0123456789abcdef", IsMain: true, CreatedAt: created},
+		{
+			Path: "/wt/repo/feature", Branch: "feature/a,b", CommitHash: "fedcba9876543210", CreatedAt: created,
+			RepositoryInfo: &models.RepositoryInfo{Host: "github.com", Owner: "user", Repository: "repo", FullPath: "github.com/user/repo"},
+		},
+	}
+
+	changes := func(ctx context.Context, path string) (models.WorktreeState, models.GitStatus, error) {
+		if path == "/wt/repo/feature" {
+			return models.WorktreeStatusModified, models.GitStatus{Modified: 2, Untracked: 1}, nil
+		}
+		return models.WorktreeStatusClean, models.GitStatus{}, nil
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := outputWorktrees(&buf, worktrees, format, changes); err != nil {
+				t.Fatalf("outputWorktrees() error = %v", err)
+			}
+			assertGolden(t, "list."+format+".golden", buf.Bytes())
+		})
+	}
+}
+
+func TestOutputWorktrees_EmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := outputWorktrees(&buf, nil, "json", nil); err != nil {
+		t.Fatalf("outputWorktrees() error = %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("outputWorktrees() = %q, want []", got)
+	}
+}
+
+func TestResolveListOutputFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		outputSet bool
+		json      bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "default table", output: "table", want: "table"},
+		{name: "csv", output: "csv", outputSet: true, want: "csv"},
+		{name: "case insensitive", output: "JSON", outputSet: true, want: "json"},
+		{name: "legacy json flag", output: "table", json: true, want: "json"},
+		{name: "json flag with -o json", output: "json", outputSet: true, json: true, want: "json"},
+		{name: "invalid format", output: "jsonl", outputSet: true, wantErr: true},
+		{name: "json flag with csv", output: "csv", outputSet: true, json: true, wantErr: true},
+		{name: "json flag with explicit table", output: "table", outputSet: true, json: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listOutput, listJSON = tt.output, tt.json
+			t.Cleanup(func() { listOutput, listJSON = "table", false })
+
+			got, err := resolveListOutputFormat(tt.outputSet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveListOutputFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveListOutputFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchList_RedrawsOnlyOnChange(t *testing.T) {
+	// A fake discovery source: the list gains a worktree on the third scan.
+	scans := [][]models.Worktree{
+		{{Path: "/wt/repo/main", Branch: "main", IsMain: true}},
+		{{Path: "/wt/repo/main", Branch: "main", IsMain: true}},
+		{{Path: "/wt/repo/main", Branch: "main", IsMain: true}, {Path: "/wt/repo/feature", Branch: "feature"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	printer := ui.New(&models.UIConfig{})
+	calls := 0
+	render := func(w io.Writer) error {
+		printer.FprintWorktrees(w, scans[calls], false)
+		calls++
+		if calls == len(scans) {
+			cancel()
+		}
+		return nil
+	}
+
+	var out bytes.Buffer
+	if err := watchList(ctx, &out, time.Millisecond, render); err != nil {
+		t.Fatalf("watchList() error = %v", err)
+	}
+
+	frames := strings.Split(out.String(), "\033[H\033[2J")[1:]
+	if len(frames) != 2 {
+		t.Fatalf("got %d redraws, want 2 (unchanged scan must not redraw):\n%q", len(frames), out.String())
+	}
+	if strings.Contains(frames[0], "/wt/repo/feature") {
+		t.Errorf("first render already shows the new worktree:\n%s", frames[0])
+	}
+	if !strings.Contains(frames[1], "/wt/repo/feature") {
+		t.Errorf("second render is missing the new worktree:\n%s", frames[1])
+	}
+}
+
+func TestWatchList_ShowsRenderErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	render := func(w io.Writer) error {
+		cancel()
+		return errors.New("base directory vanished")
+	}
+
+	var out bytes.Buffer
+	if err := watchList(ctx, &out, time.Millisecond, render); err != nil {
+		t.Fatalf("watchList() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Error: base directory vanished") {
+		t.Errorf("output = %q, want the render error", out.String())
+	}
+}
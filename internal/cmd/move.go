@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/d-kuro/gwq/internal/discovery"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var moveGlobal bool
+
+// moveCmd represents the move command.
+var moveCmd = &cobra.Command{
+	Use:     "move <pattern> <new-path>",
+	Aliases: []string{"mv"},
+	Short:   "Move a worktree to a different directory",
+	Long: `Move a worktree directory to a custom path.
+
+The directory is renamed (or copied and removed when the destination is on a
+different filesystem), then 'git worktree repair' updates git's internal
+worktree references. Stored metadata of gwq tmux sessions that refer to the old
+path is updated as well. The destination may be a new path or an empty
+directory; the command aborts if anything else is already there.
+
+If multiple worktrees match the pattern, an interactive fuzzy finder will be shown.
+The main worktree cannot be moved, and the command must be run from outside
+the worktree being moved.`,
+	Example: `  # Move a worktree to a custom location
+  gwq move feature/auth ~/src/auth-review
+
+  # Move any worktree from the base directory
+  gwq move -g myapp:feature/auth /tmp/auth`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		if moveGlobal {
+			return getGlobalWorktreeCompletions(cmd, args, toComplete)
+		}
+		return getRemoveCompletions(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.Flags().BoolVarP(&moveGlobal, "global", "g", false, "Move any worktree in the configured base directory")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	ctx, err := NewGitCommandContext()
+	if err != nil {
+		ctx, err = NewCommandContext()
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.WithGlobalLocalSupport(
+		moveGlobal,
+		func(ctx *CommandContext) error {
+			target, err := selectNonMainWorktree(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return moveWorktree(ctx, ctx.WorktreeManager, target, args[1])
+		},
+		func(ctx *CommandContext) error {
+			target, err := selectGlobalWorktree(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			// Run git from the main repository: the worktree itself is about to move.
+			mainRepo, err := git.New(target.Path).GetMainRepositoryPath()
+			if err != nil {
+				return fmt.Errorf("failed to get repository path: %w", err)
+			}
+			wm := worktree.New(git.New(mainRepo), ctx.Config)
+			return moveWorktree(ctx, wm, target, args[1])
+		},
+	)
+}
+
+func moveWorktree(ctx *CommandContext, wm *worktree.Manager, target models.Worktree, newPath string) error {
+	if inside, err := cwdInside(target.Path); err != nil {
+		return err
+	} else if inside {
+		return fmt.Errorf("cannot move the current worktree; run 'gwq move' from another directory")
+	}
+
+	dst, err := wm.Move(target, newPath)
+	if err != nil {
+		return err
+	}
+
+	relocateWorktreeReferences(target.Path, dst, target.Branch)
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("Moved worktree %s to %s", target.Branch, dst))
+	return nil
+}
+
+// relocateWorktreeReferences updates the registry and stored tmux session
+// metadata after a worktree directory has moved. Failures only warn.
+func relocateWorktreeReferences(oldPath, newPath, branch string) {
+	relocateRegistryEntry(oldPath, newPath, branch)
+
+	if _, err := tmux.NewSessionManager(nil).RelocateSessions(oldPath, newPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update tmux session metadata: %v\n", err)
+	}
+}
+
+// selectNonMainWorktree resolves pattern to a single non-main worktree of the
+// current repository, showing the fuzzy finder when several match.
+func selectNonMainWorktree(ctx *CommandContext, pattern string) (models.Worktree, error) {
+	matches, err := ctx.WorktreeManager.GetMatchingWorktrees(pattern)
+	if err != nil {
+		return models.Worktree{}, err
+	}
+	matches = filterNonMainWorktrees(matches)
+
+	switch len(matches) {
+	case 0:
+		return models.Worktree{}, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
+	case 1:
+		return matches[0], nil
+	default:
+		selected, err := ctx.GetFinder().SelectWorktree(matches)
+		if err != nil {
+			return models.Worktree{}, fmt.Errorf("worktree selection cancelled: %w", err)
+		}
+		return *selected, nil
+	}
+}
+
+// selectGlobalWorktree resolves pattern to a single non-main worktree in the
+// configured base directory, showing the fuzzy finder when several match.
+func selectGlobalWorktree(ctx *CommandContext, pattern string) (models.Worktree, error) {
+	entries, err := discoverGlobalWorktreesMatching(ctx.Config.Worktree.BaseDir, pattern)
+	if err != nil {
+		return models.Worktree{}, fmt.Errorf("failed to discover worktrees: %w", err)
+	}
+
+	var matches []*discovery.GlobalWorktreeEntry
+	for _, entry := range discovery.FilterGlobalWorktrees(entries, pattern) {
+		if !entry.IsMain {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.Worktree{}, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, pattern)
+	case 1:
+		return globalEntryToWorktree(matches[0]), nil
+	default:
+		selected, err := ctx.GetGlobalFinder().SelectWorktree(discovery.ConvertToWorktreeModels(matches, true))
+		if err != nil {
+			return models.Worktree{}, fmt.Errorf("worktree selection cancelled: %w", err)
+		}
+		for _, entry := range matches {
+			if entry.Path == selected.Path {
+				return globalEntryToWorktree(entry), nil
+			}
+		}
+		return models.Worktree{}, fmt.Errorf("no worktree selected")
+	}
+}
+
+func globalEntryToWorktree(entry *discovery.GlobalWorktreeEntry) models.Worktree {
+	return models.Worktree{
+		Path:           entry.Path,
+		Branch:         entry.Branch,
+		CommitHash:     entry.CommitHash,
+		IsMain:         entry.IsMain,
+		RepositoryInfo: entry.RepositoryInfo,
+	}
+}
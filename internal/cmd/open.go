@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var openGlobal bool
+
+// openCmd represents the open command.
+var openCmd = &cobra.Command{
+	Use:   "open [pattern]",
+	Short: "Open a worktree in the file manager",
+	Long: `Open a worktree with the system's default handler for directories,
+usually the file manager.
+
+The worktree is chosen like in 'gwq exec': by pattern, or with the fuzzy
+finder when no pattern is given or several worktrees match. The directory
+is opened with 'open' on macOS, 'explorer' on Windows and 'xdg-open'
+elsewhere. Set worktree.open_command to use another program; it is split
+on whitespace and the worktree path is appended as the last argument.`,
+	Example: `  # Open the feature worktree
+  gwq open feature
+
+  # Pick from all worktrees
+  gwq open -g
+
+  # Always open worktrees in VS Code
+  gwq config set worktree.open_command code`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runOpen,
+	ValidArgsFunction: getWorktreeCompletions,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().BoolVarP(&openGlobal, "global", "g", false, "Select from all worktrees")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	var pattern string
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+
+	var path string
+	if openGlobal {
+		path, err = getGlobalWorktreePathForExec(cfg, pattern)
+	} else {
+		path, err = getLocalWorktreePathForExec(cfg, pattern)
+	}
+	if err != nil {
+		return err
+	}
+
+	return openWorktree(context.Background(), command.NewStandardExecutor(), runtime.GOOS, cfg.Worktree.OpenCommand, path)
+}
+
+// openWorktree opens path with the opener for goos, or with openCommand
+// when it is set.
+func openWorktree(ctx context.Context, executor command.CommandExecutor, goos, openCommand, path string) error {
+	name, args := openerCommand(goos, openCommand)
+	if err := executor.Execute(ctx, name, append(args, path)...); err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", path, name, err)
+	}
+	return nil
+}
+
+// openerCommand returns the program and leading arguments that open a
+// directory on goos. A non-blank openCommand takes precedence and is split
+// on whitespace.
+func openerCommand(goos, openCommand string) (string, []string) {
+	if fields := strings.Fields(openCommand); len(fields) > 0 {
+		return fields[0], fields[1:]
+	}
+	switch goos {
+	case "darwin":
+		return "open", nil
+	case "windows":
+		return "explorer", nil
+	default:
+		return "xdg-open", nil
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/command"
+)
+
+// openExecutor is a fake command.CommandExecutor that records the opener
+// invocation.
+type openExecutor struct {
+	command.CommandExecutor // unused methods panic
+
+	err  error
+	name string
+	args []string
+}
+
+func (e *openExecutor) Execute(_ context.Context, name string, args ...string) error {
+	e.name, e.args = name, args
+	return e.err
+}
+
+func TestOpenWorktree(t *testing.T) {
+	const path = "/worktrees/feature"
+
+	tests := []struct {
+		name        string
+		goos        string
+		openCommand string
+		wantName    string
+		wantArgs    []string
+	}{
+		{name: "macOS", goos: "darwin", wantName: "open", wantArgs: []string{path}},
+		{name: "Linux", goos: "linux", wantName: "xdg-open", wantArgs: []string{path}},
+		{name: "other Unix", goos: "freebsd", wantName: "xdg-open", wantArgs: []string{path}},
+		{name: "Windows", goos: "windows", wantName: "explorer", wantArgs: []string{path}},
+		{name: "configured command", goos: "darwin", openCommand: "code", wantName: "code", wantArgs: []string{path}},
+		{name: "configured command with args", goos: "linux", openCommand: "  code --new-window ", wantName: "code", wantArgs: []string{"--new-window", path}},
+		{name: "blank configured command", goos: "windows", openCommand: "   ", wantName: "explorer", wantArgs: []string{path}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &openExecutor{}
+			if err := openWorktree(context.Background(), executor, tt.goos, tt.openCommand, path); err != nil {
+				t.Fatalf("openWorktree() error = %v", err)
+			}
+			if executor.name != tt.wantName || !slices.Equal(executor.args, tt.wantArgs) {
+				t.Errorf("ran %s %q, want %s %q", executor.name, executor.args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestOpenWorktree_Error(t *testing.T) {
+	executor := &openExecutor{err: errors.New("exit status 3")}
+
+	err := openWorktree(context.Background(), executor, "linux", "", "/worktrees/feature")
+	if err == nil {
+		t.Fatal("openWorktree() succeeded, want error")
+	}
+	if !strings.Contains(err.Error(), "xdg-open") || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("error = %q, want opener name and cause", err)
+	}
+}
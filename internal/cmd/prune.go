@@ -1,20 +1,29 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
 	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
-	pruneExpired bool
-	pruneDryRun  bool
-	pruneForce   bool
+	pruneExpired   bool
+	pruneDryRun    bool
+	pruneForce     bool
+	pruneOlderThan string
+	pruneStaleOnly bool
+	pruneVerbose   bool
 )
 
 // pruneCmd represents the prune command.
@@ -26,10 +35,22 @@ var pruneCmd = &cobra.Command{
 This command removes administrative files from .git/worktrees for worktrees
 whose working directories have been deleted from the filesystem.
 
-With --expired flag, removes worktrees that have passed their expiration date.`,
+With --dry-run, lists the entries that would be removed without touching
+them; --verbose adds the reason git gives for each.
+
+With --expired flag, removes worktrees that have passed their expiration date.
+
+With --older-than and/or --stale-only, removes worktrees of the current
+repository by activity instead: --older-than removes worktrees whose last
+activity is older than the given duration (e.g. 12h, 30d, 2w, 3mo), and
+--stale-only removes worktrees that 'gwq status' reports as stale. When both
+are given, a worktree must satisfy both. The main worktree is never removed.`,
 	Example: `  # Clean up stale worktree information
   gwq prune
 
+  # Preview stale worktree information and why it is stale
+  gwq prune --dry-run --verbose
+
   # Preview expired worktrees
   gwq prune --expired --dry-run
 
@@ -37,7 +58,13 @@ With --expired flag, removes worktrees that have passed their expiration date.`,
   gwq prune --expired
 
   # Force remove even if dirty
-  gwq prune --expired --force`,
+  gwq prune --expired --force
+
+  # Preview worktrees without activity in the last 30 days
+  gwq prune --older-than 30d --dry-run
+
+  # Remove stale worktrees
+  gwq prune --stale-only`,
 	RunE: runPrune,
 }
 
@@ -47,16 +74,42 @@ func init() {
 	pruneCmd.Flags().BoolVar(&pruneExpired, "expired", false, "Remove expired worktrees")
 	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed")
 	pruneCmd.Flags().BoolVar(&pruneForce, "force", false, "Remove even if uncommitted changes")
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "Remove worktrees with no activity for this long (e.g. 30d, 2w)")
+	pruneCmd.Flags().BoolVar(&pruneStaleOnly, "stale-only", false, "Remove only stale worktrees")
+	pruneCmd.Flags().BoolVarP(&pruneVerbose, "verbose", "v", false, "List pruned entries with the reason they are stale")
 }
 
 func runPrune(cmd *cobra.Command, args []string) error {
+	inactive := pruneOlderThan != "" || pruneStaleOnly
+	if pruneExpired && inactive {
+		return &usageError{err: fmt.Errorf("--expired cannot be combined with --older-than or --stale-only")}
+	}
 	if pruneExpired {
 		return runPruneExpired(cmd, args)
 	}
+	if inactive {
+		return ExecuteWithArgs(true, runPruneInactive)(cmd, args)
+	}
 
 	return ExecuteWithContext(true, func(ctx *CommandContext) error {
-		if err := ctx.WorktreeManager.Prune(); err != nil {
-			return fmt.Errorf("failed to prune worktrees: %w", err)
+		switch {
+		case pruneDryRun:
+			entries, err := ctx.WorktreeManager.PruneDryRun()
+			if err != nil {
+				return fmt.Errorf("failed to list stale worktree information: %w", err)
+			}
+			printPruneEntries(cmd.OutOrStdout(), entries, true, pruneVerbose)
+			return nil
+		case pruneVerbose:
+			entries, err := ctx.WorktreeManager.PruneVerbose()
+			if err != nil {
+				return fmt.Errorf("failed to prune worktrees: %w", err)
+			}
+			printPruneEntries(cmd.OutOrStdout(), entries, false, true)
+		default:
+			if err := ctx.WorktreeManager.Prune(); err != nil {
+				return fmt.Errorf("failed to prune worktrees: %w", err)
+			}
 		}
 
 		ctx.Printer.PrintSuccess("Pruned stale worktree information")
@@ -64,6 +117,29 @@ func runPrune(cmd *cobra.Command, args []string) error {
 	})(cmd, args)
 }
 
+// printPruneEntries lists stale administrative entries, with git's reason
+// for each when verbose.
+func printPruneEntries(w io.Writer, entries []worktree.PruneEntry, dryRun, verbose bool) {
+	if len(entries) == 0 {
+		if dryRun {
+			_, _ = fmt.Fprintln(w, "No stale worktree information to prune")
+		}
+		return
+	}
+
+	verb := "Pruned"
+	if dryRun {
+		verb = "Would prune"
+	}
+	for _, e := range entries {
+		if verbose && e.Reason != "" {
+			_, _ = fmt.Fprintf(w, "%s: %s (%s)\n", verb, e.Path, e.Reason)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", verb, e.Path)
+		}
+	}
+}
+
 func runPruneExpired(cmd *cobra.Command, args []string) error {
 	reg, err := registry.New()
 	if err != nil {
@@ -156,6 +232,120 @@ func runPruneExpired(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runPruneInactive removes worktrees of the current repository selected by
+// --older-than and --stale-only.
+func runPruneInactive(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	var olderThan time.Duration
+	if pruneOlderThan != "" {
+		d, err := utils.ParseDuration(pruneOlderThan)
+		if err != nil {
+			return &usageError{err: fmt.Errorf("invalid --older-than: %w", err)}
+		}
+		olderThan = d
+	}
+
+	worktrees, err := ctx.WorktreeManager.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var targets []*models.Worktree
+	for i := range worktrees {
+		if !worktrees[i].IsMain {
+			targets = append(targets, &worktrees[i])
+		}
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		BaseDir:            ctx.Config.Worktree.BaseDir,
+		ActivitySampleSize: ctx.Config.Status.ActivitySampleSize,
+	})
+	statuses, err := collector.CollectAll(context.Background(), targets)
+	if err != nil {
+		return fmt.Errorf("failed to collect worktree statuses: %w", err)
+	}
+
+	candidates := selectInactiveWorktrees(statuses, olderThan, pruneOlderThan != "", pruneStaleOnly, time.Now())
+	if len(candidates) == 0 {
+		fmt.Println("No matching worktrees found")
+		return nil
+	}
+
+	var removed, skipped int
+	for _, s := range candidates {
+		if !pruneForce {
+			dirty, err := isWorktreeDirty(s.Path)
+			if err != nil {
+				fmt.Printf("Warning: could not check status for %s: %v\n", s.Path, err)
+				skipped++
+				continue
+			}
+			if dirty {
+				if pruneDryRun {
+					fmt.Printf("Would skip (uncommitted changes): %s\n", s.Path)
+				} else {
+					fmt.Printf("Skipping (uncommitted changes): %s (use --force to override)\n", s.Path)
+				}
+				skipped++
+				continue
+			}
+		}
+
+		if pruneDryRun {
+			fmt.Printf("Would remove: %s (branch: %s, last activity: %s)\n", s.Path, s.Branch, formatLastActivity(s.LastActivity))
+			removed++
+			continue
+		}
+
+		if err := ctx.WorktreeManager.Remove(s.Path, pruneForce); err != nil {
+			fmt.Printf("Failed to remove worktree %s: %v\n", s.Path, err)
+			skipped++
+			continue
+		}
+		if reg, err := registry.New(); err == nil {
+			_ = reg.Unregister(s.Path)
+		}
+
+		fmt.Printf("Removed: %s (branch: %s, last activity: %s)\n", s.Path, s.Branch, formatLastActivity(s.LastActivity))
+		removed++
+	}
+
+	if pruneDryRun {
+		fmt.Printf("\nDry run: would remove %d worktree(s), skip %d\n", removed, skipped)
+	} else {
+		fmt.Printf("\nRemoved %d inactive worktree(s), skipped %d\n", removed, skipped)
+	}
+
+	return nil
+}
+
+// selectInactiveWorktrees returns the statuses whose last activity is older
+// than olderThan (when checkAge is set) and, if staleOnly is set, that are
+// stale. Worktrees with unknown activity never match the age check.
+func selectInactiveWorktrees(statuses []*models.WorktreeStatus, olderThan time.Duration, checkAge, staleOnly bool, now time.Time) []*models.WorktreeStatus {
+	var selected []*models.WorktreeStatus
+	for _, s := range statuses {
+		if staleOnly && s.Status != models.WorktreeStatusStale {
+			continue
+		}
+		if checkAge {
+			if s.LastActivity.IsZero() || now.Sub(s.LastActivity) <= olderThan {
+				continue
+			}
+		}
+		selected = append(selected, s)
+	}
+	return selected
+}
+
+// formatLastActivity renders an activity timestamp for prune output.
+func formatLastActivity(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s, %s", t.Format("2006-01-02 15:04"), formatActivity(t))
+}
+
 // isWorktreeDirty checks if a worktree has uncommitted changes.
 func isWorktreeDirty(path string) (bool, error) {
 	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
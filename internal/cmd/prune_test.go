@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSelectInactiveWorktrees(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	statuses := []*models.WorktreeStatus{
+		{Path: "/wt/recent", Status: models.WorktreeStatusClean, LastActivity: now.Add(-2 * day)},
+		{Path: "/wt/old", Status: models.WorktreeStatusModified, LastActivity: now.Add(-40 * day)},
+		{Path: "/wt/stale", Status: models.WorktreeStatusStale, LastActivity: now.Add(-20 * day)},
+		{Path: "/wt/unknown", Status: models.WorktreeStatusUnknown},
+	}
+
+	tests := []struct {
+		name      string
+		olderThan time.Duration
+		checkAge  bool
+		staleOnly bool
+		want      []string
+	}{
+		{name: "older than 30 days", olderThan: 30 * day, checkAge: true, want: []string{"/wt/old"}},
+		{name: "older than 10 days", olderThan: 10 * day, checkAge: true, want: []string{"/wt/old", "/wt/stale"}},
+		{name: "zero duration matches known activity", olderThan: 0, checkAge: true, want: []string{"/wt/recent", "/wt/old", "/wt/stale"}},
+		{name: "stale only", staleOnly: true, want: []string{"/wt/stale"}},
+		{name: "stale and older than 30 days", olderThan: 30 * day, checkAge: true, staleOnly: true, want: nil},
+		{name: "exact boundary is not older", olderThan: 2 * day, checkAge: true, want: []string{"/wt/old", "/wt/stale"}},
+		{name: "no filters", want: []string{"/wt/recent", "/wt/old", "/wt/stale", "/wt/unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectInactiveWorktrees(statuses, tt.olderThan, tt.checkAge, tt.staleOnly, now)
+			var paths []string
+			for _, s := range got {
+				paths = append(paths, s.Path)
+			}
+			if !slices.Equal(paths, tt.want) {
+				t.Errorf("selectInactiveWorktrees() = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintPruneEntries(t *testing.T) {
+	entries := []worktree.PruneEntry{
+		{Path: "worktrees/feature", Reason: "gitdir file points to non-existent location"},
+		{Path: "worktrees/fix"},
+	}
+
+	tests := []struct {
+		name    string
+		entries []worktree.PruneEntry
+		dryRun  bool
+		verbose bool
+		want    string
+	}{
+		{
+			name:    "dry run",
+			entries: entries,
+			dryRun:  true,
+			want:    "Would prune: worktrees/feature\nWould prune: worktrees/fix\n",
+		},
+		{
+			name:    "dry run verbose",
+			entries: entries,
+			dryRun:  true,
+			verbose: true,
+			want:    "Would prune: worktrees/feature (gitdir file points to non-existent location)\nWould prune: worktrees/fix\n",
+		},
+		{
+			name:    "verbose",
+			entries: entries[:1],
+			verbose: true,
+			want:    "Pruned: worktrees/feature (gitdir file points to non-existent location)\n",
+		},
+		{name: "dry run nothing stale", dryRun: true, want: "No stale worktree information to prune\n"},
+		{name: "verbose nothing stale", verbose: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printPruneEntries(&buf, tt.entries, tt.dryRun, tt.verbose)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printPruneEntries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+// reattachCmd represents the reattach command.
+var reattachCmd = &cobra.Command{
+	Use:   "reattach",
+	Short: "Attach to the most recently active tmux session",
+	Long: `Attach to the gwq tmux session with the most recent activity, so you can
+jump back to what you were doing without picking a session.
+
+Inside tmux the current client is switched to the session instead. When
+several sessions were last active at the same time, or tmux reports no
+activity times, a fuzzy finder is shown.`,
+	Example: `  # Jump back to the last active session
+  gwq reattach`,
+	Args: cobra.NoArgs,
+	RunE: runReattach,
+}
+
+func init() {
+	rootCmd.AddCommand(reattachCmd)
+}
+
+func runReattach(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sessionManager := tmux.NewSessionManager(nil)
+
+	sessions, err := sessionManager.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no tmux sessions found")
+	}
+
+	session, err := chooseReattachSession(sessions, func(candidates []*tmux.Session) (*tmux.Session, error) {
+		return selectSessionWithFinder(candidates, cfg)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sessionManager.AttachSessionDirect(session)
+}
+
+// chooseReattachSession returns the most recently active session, calling
+// selectFn to pick among the candidates when that is ambiguous.
+func chooseReattachSession(sessions []*tmux.Session, selectFn func([]*tmux.Session) (*tmux.Session, error)) (*tmux.Session, error) {
+	candidates := tmux.MostRecentlyActive(sessions)
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		candidates = sessions
+	}
+
+	session, err := selectFn(candidates)
+	if err != nil {
+		return nil, fmt.Errorf("session selection cancelled: %w", err)
+	}
+	if session == nil {
+		return nil, fmt.Errorf("no session selected")
+	}
+	return session, nil
+}
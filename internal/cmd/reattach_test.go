@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+func TestChooseReattachSession(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	old := &tmux.Session{SessionName: "gwq-run-old-1", LastActivity: now.Add(-time.Hour)}
+	recent := &tmux.Session{SessionName: "gwq-run-recent-1", LastActivity: now}
+	tied := &tmux.Session{SessionName: "gwq-run-tied-1", LastActivity: now}
+	unknown := &tmux.Session{SessionName: "gwq-run-unknown-1"}
+
+	tests := []struct {
+		name          string
+		sessions      []*tmux.Session
+		wantOffered   int // sessions passed to the finder; 0 means not shown
+		want          *tmux.Session
+		finderChoice  int
+		finderFailure bool
+		wantErr       bool
+	}{
+		{name: "MostRecent", sessions: []*tmux.Session{old, recent, unknown}, want: recent},
+		{name: "Tie", sessions: []*tmux.Session{old, recent, tied}, wantOffered: 2, finderChoice: 1, want: tied},
+		{name: "NoActivity", sessions: []*tmux.Session{unknown}, wantOffered: 1, want: unknown},
+		{name: "Cancelled", sessions: []*tmux.Session{recent, tied}, wantOffered: 2, finderFailure: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offered := 0
+			got, err := chooseReattachSession(tt.sessions, func(candidates []*tmux.Session) (*tmux.Session, error) {
+				offered = len(candidates)
+				if tt.finderFailure {
+					return nil, errors.New("abort")
+				}
+				return candidates[tt.finderChoice], nil
+			})
+			if offered != tt.wantOffered {
+				t.Errorf("finder offered %d sessions, want %d", offered, tt.wantOffered)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Error("chooseReattachSession() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("chooseReattachSession() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("chooseReattachSession() = %s, want %s", got.SessionName, tt.want.SessionName)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ var (
 	removeGlobal      bool
 	deleteBranch      bool
 	forceDeleteBranch bool
+	removeMerged      bool
 )
 
 // removeCmd represents the remove command.
@@ -37,7 +38,12 @@ Use -b flag to also delete the branch after removing the worktree.
 
 When run inside a git repository, shows worktrees for the current repository.
 When run outside a git repository, shows all worktrees from the configured base directory.
-Use -g flag to always show all worktrees from the base directory.`,
+Use -g flag to always show all worktrees from the base directory.
+
+With --merged, worktrees whose branch is already merged into the default
+branch are pre-selected in the finder so finished work can be removed in one
+go. The main worktree and detached worktrees are never selected. Combined with
+--dry-run, the merged worktrees are listed without showing the finder.`,
 	Example: `  # Select and delete using fuzzy finder
   gwq remove
 
@@ -56,6 +62,9 @@ Use -g flag to always show all worktrees from the base directory.`,
   # Show what would be deleted
   gwq remove --dry-run feature/old
 
+  # Remove worktrees (and branches) already merged into the default branch
+  gwq remove --merged -b
+
   # Remove from all worktrees in base directory
   gwq remove -g myapp:feature/old`,
 	RunE: runRemove,
@@ -75,6 +84,7 @@ func init() {
 	removeCmd.Flags().BoolVarP(&removeGlobal, "global", "g", false, "Remove from any worktree in the configured base directory")
 	removeCmd.Flags().BoolVarP(&deleteBranch, "delete-branch", "b", false, "Also delete the branch after removing worktree")
 	removeCmd.Flags().BoolVar(&forceDeleteBranch, "force-delete-branch", false, "Force delete the branch even if not merged")
+	removeCmd.Flags().BoolVar(&removeMerged, "merged", false, "Pre-select worktrees merged into the default branch")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
@@ -110,7 +120,15 @@ func removeLocalWorktree(ctx *CommandContext, args []string) error {
 
 	var toRemove []models.Worktree
 
-	if len(args) > 0 {
+	if removeMerged {
+		toRemove, err = selectMergedWorktrees(ctx, nonMainWorktrees, args)
+		if err != nil {
+			return err
+		}
+		if len(toRemove) == 0 {
+			return nil
+		}
+	} else if len(args) > 0 {
 		// Get all matching worktrees
 		matches, err := ctx.WorktreeManager.GetMatchingWorktrees(args[0])
 		if err != nil {
@@ -126,21 +144,21 @@ func removeLocalWorktree(ctx *CommandContext, args []string) error {
 		}
 
 		if len(nonMainMatches) == 0 {
-			return fmt.Errorf("no worktree found matching pattern: %s", args[0])
+			return fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, args[0])
 		} else if len(nonMainMatches) == 1 {
 			toRemove = nonMainMatches
 		} else {
 			// Multiple matches - use fuzzy finder
 			selected, err := ctx.GetFinder().SelectMultipleWorktrees(nonMainMatches)
 			if err != nil {
-				return fmt.Errorf("worktree selection cancelled")
+				return fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 			toRemove = selected
 		}
 	} else {
 		selected, err := ctx.GetFinder().SelectMultipleWorktrees(nonMainWorktrees)
 		if err != nil {
-			return fmt.Errorf("worktree selection cancelled")
+			return fmt.Errorf("worktree selection cancelled: %w", err)
 		}
 		toRemove = selected
 	}
@@ -183,6 +201,54 @@ func removeLocalWorktree(ctx *CommandContext, args []string) error {
 	return nil
 }
 
+// selectMergedWorktrees offers candidates, narrowed by the optional pattern
+// in args, in the multi-select finder with the worktrees merged into the
+// default branch pre-selected. With --dry-run the merged worktrees are
+// returned without showing the finder.
+func selectMergedWorktrees(ctx *CommandContext, candidates []models.Worktree, args []string) ([]models.Worktree, error) {
+	if len(args) > 0 {
+		matches, err := ctx.WorktreeManager.GetMatchingWorktrees(args[0])
+		if err != nil {
+			return nil, err
+		}
+		candidates = filterNonMainWorktrees(matches)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, args[0])
+		}
+	}
+
+	merged, err := ctx.WorktreeManager.ListMergedWorktrees("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merged worktrees: %w", err)
+	}
+	isMerged := make(map[string]bool, len(merged))
+	for _, wt := range merged {
+		isMerged[wt.Path] = true
+	}
+
+	var mergedCandidates []models.Worktree
+	for _, wt := range candidates {
+		if isMerged[wt.Path] {
+			mergedCandidates = append(mergedCandidates, wt)
+		}
+	}
+	if len(mergedCandidates) == 0 {
+		fmt.Println("No worktrees merged into the default branch")
+		return nil, nil
+	}
+	if removeDryRun {
+		return mergedCandidates, nil
+	}
+
+	selected, err := ctx.GetFinder().SelectMultipleWorktreesPreselected(candidates, func(i int) bool {
+		return isMerged[candidates[i].Path]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("worktree selection cancelled: %w", err)
+	}
+	return selected, nil
+}
+
 func filterNonMainWorktrees(worktrees []models.Worktree) []models.Worktree {
 	var filtered []models.Worktree
 	for _, wt := range worktrees {
@@ -194,13 +260,21 @@ func filterNonMainWorktrees(worktrees []models.Worktree) []models.Worktree {
 }
 
 func removeGlobalWorktree(ctx *CommandContext, args []string) error {
-	entries, err := discovery.DiscoverGlobalWorktrees(ctx.Config.Worktree.BaseDir)
+	if removeMerged {
+		return fmt.Errorf("--merged is only supported inside a git repository, without --global")
+	}
+
+	var pattern string
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+	entries, err := discoverGlobalWorktreesMatching(ctx.Config.Worktree.BaseDir, pattern)
 	if err != nil {
 		return fmt.Errorf("failed to discover worktrees: %w", err)
 	}
 
 	if len(entries) == 0 {
-		return fmt.Errorf("no worktrees found in %s", ctx.Config.Worktree.BaseDir)
+		return fmt.Errorf("%w in %s", worktree.ErrNoWorktreeFound, ctx.Config.Worktree.BaseDir)
 	}
 
 	// Filter out main worktrees
@@ -239,7 +313,7 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 		}
 
 		if len(matches) == 0 {
-			return fmt.Errorf("no worktree matches pattern: %s", args[0])
+			return fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, args[0])
 		} else if len(matches) == 1 {
 			toRemove = matches
 		} else {
@@ -255,7 +329,7 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 			f := finder.NewWithUI(g, &ctx.Config.Finder, &ctx.Config.UI)
 			selected, err := f.SelectMultipleWorktrees(worktrees)
 			if err != nil {
-				return fmt.Errorf("worktree selection cancelled")
+				return fmt.Errorf("worktree selection cancelled: %w", err)
 			}
 
 			// Map selected worktrees back to entries
@@ -278,7 +352,7 @@ func removeGlobalWorktree(ctx *CommandContext, args []string) error {
 		f := ctx.GetGlobalFinder()
 		selected, err := f.SelectMultipleWorktrees(worktrees)
 		if err != nil {
-			return fmt.Errorf("worktree selection cancelled")
+			return fmt.Errorf("worktree selection cancelled: %w", err)
 		}
 
 		// Map selected worktrees back to entries
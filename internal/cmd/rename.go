@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var renameKeepDir bool
+
+// renameCmd represents the rename command.
+var renameCmd = &cobra.Command{
+	Use:   "rename <pattern> <new-branch>",
+	Short: "Rename a worktree's branch and directory",
+	Long: `Rename the branch checked out in a worktree and move the worktree to the
+directory that 'gwq add' would use for the new branch name.
+
+The branch is renamed with 'git branch -m', the directory is moved, and
+'git worktree repair' updates git's internal worktree references. Stored
+metadata of gwq tmux sessions that refer to the old path is updated as well.
+The command aborts without changes if the new branch or the target directory
+already exists. Use --keep-dir to rename only the branch.
+
+If multiple worktrees match the pattern, an interactive fuzzy finder will be shown.
+The main worktree and worktrees with a detached HEAD cannot be renamed. Unless
+--keep-dir is given, the command must be run from outside the worktree being
+renamed.`,
+	Example: `  # Rename feature/old to feature/new and move its directory
+  gwq rename feature/old feature/new
+
+  # Rename only the branch, leaving the directory where it is
+  gwq rename --keep-dir feature/old feature/new
+
+  # Pick among several matching worktrees
+  gwq rename feature feature/renamed`,
+	Args: cobra.ExactArgs(2),
+	RunE: ExecuteWithArgs(true, runRename),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getRemoveCompletions(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().BoolVar(&renameKeepDir, "keep-dir", false, "Rename only the branch and keep the worktree directory")
+}
+
+func runRename(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	pattern, newBranch := args[0], args[1]
+
+	target, err := selectNonMainWorktree(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	if !renameKeepDir {
+		if inside, err := cwdInside(target.Path); err != nil {
+			return err
+		} else if inside {
+			return fmt.Errorf("cannot move the current worktree; run 'gwq rename' from another directory or use --keep-dir")
+		}
+	}
+
+	newPath, err := ctx.WorktreeManager.Rename(target, newBranch, !renameKeepDir)
+	if err != nil {
+		return err
+	}
+
+	relocateWorktreeReferences(target.Path, newPath, newBranch)
+
+	ctx.Printer.PrintSuccess(fmt.Sprintf("Renamed %s to %s", target.Branch, newBranch))
+	if newPath != target.Path {
+		ctx.Printer.PrintSuccess(fmt.Sprintf("Moved worktree to %s", newPath))
+	}
+	return nil
+}
+
+// cwdInside reports whether the current working directory is path or below it.
+func cwdInside(path string) (bool, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	rel, err := filepath.Rel(path, cwd)
+	if err != nil {
+		return false, nil
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))), nil
+}
+
+// relocateRegistryEntry moves a registry entry (e.g. one with an expiry)
+// to the worktree's new path and branch. Registry failures are not fatal.
+func relocateRegistryEntry(oldPath, newPath, newBranch string) {
+	reg, err := registry.New()
+	if err != nil {
+		return
+	}
+
+	entry, ok := reg.Get(oldPath)
+	if !ok {
+		return
+	}
+
+	moved := *entry
+	moved.Path = newPath
+	moved.Branch = newBranch
+	if err := reg.Register(&moved); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
+		return
+	}
+	_ = reg.Unregister(oldPath)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCwdInside(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "feature")
+	nested := filepath.Join(worktree, "src")
+	sibling := filepath.Join(root, "feature-other")
+	for _, dir := range []string{nested, sibling} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		cwd  string
+		want bool
+	}{
+		{"worktree root", worktree, true},
+		{"nested directory", nested, true},
+		{"sibling with shared prefix", sibling, false},
+		{"parent directory", root, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(tt.cwd)
+
+			got, err := cwdInside(worktree)
+			if err != nil {
+				t.Fatalf("cwdInside() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("cwdInside() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
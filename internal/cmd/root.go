@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 
 	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/discovery"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,8 @@ var (
 	date    = "unknown"
 )
 
+var rootQuiet bool
+
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
 	Use:   "gwq",
@@ -29,9 +32,11 @@ a fuzzy finder interface.`,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// The process exit code reflects the failure class; see exitCode.
 func Execute() {
+	markUsageErrors(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -39,10 +44,17 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	rootCmd.PersistentFlags().BoolVar(&rootQuiet, "quiet", false,
+		"Log worktree discovery warnings at debug level instead of stderr (same as "+discovery.QuietEnv+"=1)")
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	if rootQuiet {
+		// Exported so nested gwq invocations (e.g. from tmux sessions) stay quiet too.
+		_ = os.Setenv(discovery.QuietEnv, "1")
+	}
 	if err := config.Init(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
 		os.Exit(1)
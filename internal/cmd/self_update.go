@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/update"
+	"github.com/spf13/cobra"
+)
+
+var (
+	selfUpdateCheckOnly bool
+	selfUpdateYes       bool
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update gwq to the latest release",
+	Long: `Download the latest gwq release for this OS and architecture from GitHub,
+verify it against the release checksums, and replace the running binary.
+
+gwq asks for confirmation before replacing itself unless --yes is given.
+If gwq was installed by a package manager (e.g. Homebrew) or into a
+directory you cannot write to, update it the way it was installed instead.`,
+	Example: `  # Check whether a newer release exists
+  gwq self-update --check-only
+
+  # Update without a confirmation prompt
+  gwq self-update --yes`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheckOnly, "check-only", false, "Only report whether an update is available")
+	selfUpdateCmd.Flags().BoolVarP(&selfUpdateYes, "yes", "y", false, "Skip the confirmation prompt")
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	out := cmd.OutOrStdout()
+	updater := update.New()
+
+	current := currentVersion()
+	release, err := updater.LatestRelease(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmp, err := update.CompareVersions(current, release.Version)
+	if err != nil {
+		return fmt.Errorf("cannot compare current version %q with %s: this looks like a development build", current, release.Version)
+	}
+	if cmp >= 0 {
+		_, _ = fmt.Fprintf(out, "gwq %s is up to date\n", current)
+		return nil
+	}
+	if selfUpdateCheckOnly {
+		_, _ = fmt.Fprintf(out, "A new version is available: %s -> %s\n", current, release.Version)
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the gwq executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+	if advice := packageManagerAdvice(exePath); advice != "" {
+		return errors.New(advice)
+	}
+	if err := updater.CheckWritable(exePath); err != nil {
+		if errors.Is(err, update.ErrNotWritable) {
+			return fmt.Errorf("%w\ngwq may have been installed by a package manager or into a system directory; update it the same way it was installed", err)
+		}
+		return err
+	}
+
+	if !selfUpdateYes && !confirmSelfUpdate(os.Stdin, out, current, release.Version) {
+		_, _ = fmt.Fprintln(out, "Update cancelled")
+		return nil
+	}
+
+	binary, err := updater.DownloadBinary(ctx, release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	if err := updater.Replace(exePath, binary); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "Updated gwq %s -> %s (%s)\n", current, release.Version, exePath)
+	return nil
+}
+
+// currentVersion returns the version reported by 'gwq version'.
+func currentVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return getVersion(info)
+	}
+	return version
+}
+
+// packageManagerAdvice returns how to update a gwq installed by a known
+// package manager, or "" when exePath does not look like one.
+func packageManagerAdvice(exePath string) string {
+	if strings.Contains(filepath.ToSlash(exePath), "/Cellar/") {
+		return fmt.Sprintf("%s is managed by Homebrew; run 'brew upgrade gwq' instead", exePath)
+	}
+	return ""
+}
+
+func confirmSelfUpdate(in io.Reader, out io.Writer, current, latest string) bool {
+	_, _ = fmt.Fprintf(out, "Update gwq %s -> %s? [y/N]: ", current, latest)
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
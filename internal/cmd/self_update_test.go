@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPackageManagerAdvice(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantBrew bool
+	}{
+		{path: "/opt/homebrew/Cellar/gwq/0.1.0/bin/gwq", wantBrew: true},
+		{path: "/usr/local/Cellar/gwq/0.1.0/bin/gwq", wantBrew: true},
+		{path: "/home/user/go/bin/gwq"},
+		{path: "/usr/local/bin/gwq"},
+	}
+	for _, tt := range tests {
+		got := packageManagerAdvice(tt.path)
+		if gotBrew := strings.Contains(got, "brew upgrade"); gotBrew != tt.wantBrew {
+			t.Errorf("packageManagerAdvice(%q) = %q, want Homebrew advice %v", tt.path, got, tt.wantBrew)
+		}
+	}
+}
+
+func TestConfirmSelfUpdate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: "n\n"},
+		{input: "\n"},
+		{input: ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirmSelfUpdate(strings.NewReader(tt.input), &out, "v0.1.0", "v0.2.0"); got != tt.want {
+			t.Errorf("confirmSelfUpdate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(out.String(), "v0.1.0 -> v0.2.0") {
+			t.Errorf("prompt = %q, want both versions", out.String())
+		}
+	}
+}
@@ -216,22 +216,22 @@ func TestCompletion_LaunchShellFalse_WrapsAdd(t *testing.T) {
 		fallback string // alternative substring acceptable (e.g., fish syntax)
 	}{
 		{
-			name:   "bash dispatches cd|add via __gwq_shim_cd",
+			name:   "bash dispatches cd|add|switch|clone via __gwq_shim_cd",
 			shell:  "bash",
 			cmd:    completionBashCmd,
-			needle: "cd|add)",
+			needle: "cd|add|switch|clone)",
 		},
 		{
-			name:   "zsh dispatches cd|add via __gwq_shim_cd",
+			name:   "zsh dispatches cd|add|switch|clone via __gwq_shim_cd",
 			shell:  "zsh",
 			cmd:    completionZshCmd,
-			needle: "cd|add)",
+			needle: "cd|add|switch|clone)",
 		},
 		{
-			name:     "fish dispatches cd add via switch",
+			name:     "fish dispatches cd add switch clone via switch",
 			shell:    "fish",
 			cmd:      completionFishCmd,
-			needle:   "case cd add",
+			needle:   "case cd add switch clone",
 			fallback: "__gwq_shim_cd",
 		},
 	}
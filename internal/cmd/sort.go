@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/ui"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// worktreeSortKeys are the keys accepted by 'gwq list --sort'.
+var worktreeSortKeys = []string{"name", "path", "activity", "status", "commit-date"}
+
+// SortWorktrees sorts worktree statuses in place by name (branch,
+// alphabetical), path, activity (most recent first) or status (conflict,
+// modified, staged, stale, clean). Ties are broken by path.
+func SortWorktrees(worktrees []*models.WorktreeStatus, by string) error {
+	by = strings.ToLower(by)
+	var compare statusComparator
+	switch by {
+	case "path":
+		compare = func(a, b *models.WorktreeStatus) int { return cmp.Compare(a.Path, b.Path) }
+	case "name", "activity", "status":
+		compare = statusComparators[by]
+	default:
+		return fmt.Errorf("invalid sort key %q for worktree statuses", by)
+	}
+	slices.SortStableFunc(worktrees, func(a, b *models.WorktreeStatus) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Path, b.Path))
+	})
+	return nil
+}
+
+// validateWorktreeSortKey returns an error unless key is one of
+// worktreeSortKeys.
+func validateWorktreeSortKey(key string) error {
+	if !slices.Contains(worktreeSortKeys, strings.ToLower(key)) {
+		return fmt.Errorf("invalid sort key %q: must be one of %s", key, strings.Join(worktreeSortKeys, ", "))
+	}
+	return nil
+}
+
+// sortKeyNeedsStatus reports whether sorting by key requires collected
+// status information rather than just the worktree list.
+func sortKeyNeedsStatus(key string) bool {
+	key = strings.ToLower(key)
+	return key == "activity" || key == "status"
+}
+
+// listArrangement holds the --filter-status, --filter and --sort settings of
+// 'gwq list'.
+type listArrangement struct {
+	state       models.WorktreeState // --filter-status; "" for any
+	filter      FilterFunc
+	fetchRemote bool // filter compares ahead/behind
+	sortBy      string
+	reverse     bool
+}
+
+// arrangeListedWorktrees drops worktrees not in a.state or not matching
+// a.filter and orders the rest by a.sortBy. Status is collected once, without process scanning, and
+// only when the filter or sort key needs it.
+func arrangeListedWorktrees(ctx *CommandContext, worktrees []models.Worktree, a listArrangement) ([]models.Worktree, error) {
+	if a.state != "" {
+		collector := NewStatusCollectorWithOptions(StatusCollectorOptions{})
+		worktrees = filterWorktreesByState(context.Background(), worktrees, a.state, 0, collector.WorkingTreeState)
+	}
+	if a.filter == nil && a.sortBy == "" {
+		return worktrees, nil
+	}
+
+	statuses := make([]*models.WorktreeStatus, 0, len(worktrees))
+	if a.filter != nil || sortKeyNeedsStatus(a.sortBy) {
+		targets := make([]*models.Worktree, len(worktrees))
+		for i := range worktrees {
+			targets[i] = &worktrees[i]
+		}
+		collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+			IncludeProcess:     false,
+			FetchRemote:        a.fetchRemote,
+			BaseDir:            ctx.Config.Worktree.BaseDir,
+			ActivitySampleSize: ctx.Config.Status.ActivitySampleSize,
+		})
+		collected, err := collector.CollectAll(context.Background(), targets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect worktree statuses: %w", err)
+		}
+		statuses = collected
+	} else {
+		for _, wt := range worktrees {
+			statuses = append(statuses, &models.WorktreeStatus{Path: wt.Path, Branch: wt.Branch})
+		}
+	}
+
+	if a.filter != nil {
+		matched := make(map[string]bool, len(statuses))
+		statuses = slices.DeleteFunc(statuses, func(s *models.WorktreeStatus) bool {
+			matched[s.Path] = a.filter(s)
+			return !matched[s.Path]
+		})
+		worktrees = slices.DeleteFunc(slices.Clone(worktrees), func(wt models.Worktree) bool {
+			return !matched[wt.Path]
+		})
+	}
+
+	if a.sortBy == "" {
+		return worktrees, nil
+	}
+	// Commit dates come from git, not from the collected status.
+	if strings.EqualFold(a.sortBy, "commit-date") {
+		worktrees = slices.Clone(worktrees)
+		if err := ui.SortWorktrees(worktrees, a.sortBy); err != nil {
+			return nil, err
+		}
+		if a.reverse {
+			slices.Reverse(worktrees)
+		}
+		return worktrees, nil
+	}
+	if err := SortWorktrees(statuses, a.sortBy); err != nil {
+		return nil, err
+	}
+	if a.reverse {
+		slices.Reverse(statuses)
+	}
+	return reorderWorktrees(worktrees, statuses), nil
+}
+
+// reorderWorktrees returns worktrees in the order of statuses, matched by
+// path. Worktrees without a status keep their relative order at the end.
+func reorderWorktrees(worktrees []models.Worktree, statuses []*models.WorktreeStatus) []models.Worktree {
+	byPath := make(map[string]models.Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		byPath[wt.Path] = wt
+	}
+
+	sorted := make([]models.Worktree, 0, len(worktrees))
+	for _, s := range statuses {
+		if wt, ok := byPath[s.Path]; ok {
+			sorted = append(sorted, wt)
+			delete(byPath, s.Path)
+		}
+	}
+	for _, wt := range worktrees {
+		if _, ok := byPath[wt.Path]; ok {
+			sorted = append(sorted, wt)
+		}
+	}
+	return sorted
+}
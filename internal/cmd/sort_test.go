@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSortWorktrees(t *testing.T) {
+	now := time.Now()
+	newStatuses := func() []*models.WorktreeStatus {
+		return []*models.WorktreeStatus{
+			{Branch: "main", Path: "/repo", Status: models.WorktreeStatusClean, LastActivity: now.Add(-48 * time.Hour)},
+			{Branch: "feature/b", Path: "/worktrees/a", Status: models.WorktreeStatusStale, LastActivity: now.Add(-30 * 24 * time.Hour)},
+			{Branch: "feature/a", Path: "/worktrees/c", Status: models.WorktreeStatusModified, LastActivity: now},
+			{Branch: "fix/x", Path: "/worktrees/b", Status: models.WorktreeStatusConflict, LastActivity: now.Add(-time.Hour)},
+		}
+	}
+
+	tests := []struct {
+		by      string
+		want    []string // expected branch order
+		wantErr bool
+	}{
+		{by: "name", want: []string{"feature/a", "feature/b", "fix/x", "main"}},
+		{by: "NAME", want: []string{"feature/a", "feature/b", "fix/x", "main"}},
+		{by: "path", want: []string{"main", "feature/b", "fix/x", "feature/a"}},
+		{by: "activity", want: []string{"feature/a", "fix/x", "main", "feature/b"}},
+		{by: "status", want: []string{"fix/x", "feature/a", "feature/b", "main"}},
+		{by: "changes", wantErr: true},
+		{by: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			statuses := newStatuses()
+			err := SortWorktrees(statuses, tt.by)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SortWorktrees(%q) error = %v, wantErr %v", tt.by, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, s := range statuses {
+				got = append(got, s.Branch)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortWorktrees(%q) = %v, want %v", tt.by, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortWorktrees_TiesBrokenByPath(t *testing.T) {
+	statuses := []*models.WorktreeStatus{
+		{Branch: "feature", Path: "/worktrees/b"},
+		{Branch: "feature", Path: "/worktrees/a"},
+	}
+	if err := SortWorktrees(statuses, "name"); err != nil {
+		t.Fatalf("SortWorktrees() error = %v", err)
+	}
+	if statuses[0].Path != "/worktrees/a" {
+		t.Errorf("SortWorktrees() = [%s %s], want /worktrees/a first", statuses[0].Path, statuses[1].Path)
+	}
+}
+
+func TestArrangeListedWorktrees_Sort(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Branch: "main", Path: "/repo", IsMain: true},
+		{Branch: "zeta", Path: "/worktrees/zeta"},
+		{Branch: "alpha", Path: "/worktrees/alpha"},
+	}
+	ctx := &CommandContext{Config: &models.Config{}}
+
+	tests := []struct {
+		by      string
+		reverse bool
+		want    []string
+	}{
+		{by: "name", want: []string{"alpha", "main", "zeta"}},
+		{by: "name", reverse: true, want: []string{"zeta", "main", "alpha"}},
+		{by: "path", want: []string{"main", "alpha", "zeta"}},
+		// No repositories behind these paths: equal dates fall back to path.
+		{by: "commit-date", want: []string{"main", "alpha", "zeta"}},
+	}
+	for _, tt := range tests {
+		got, err := arrangeListedWorktrees(ctx, slices.Clone(worktrees), listArrangement{sortBy: tt.by, reverse: tt.reverse})
+		if err != nil {
+			t.Fatalf("arrangeListedWorktrees(%q) error = %v", tt.by, err)
+		}
+		var branches []string
+		for _, wt := range got {
+			branches = append(branches, wt.Branch)
+		}
+		if !slices.Equal(branches, tt.want) {
+			t.Errorf("arrangeListedWorktrees(%q, reverse=%v) = %v, want %v", tt.by, tt.reverse, branches, tt.want)
+		}
+	}
+}
+
+func TestArrangeListedWorktrees_Activity(t *testing.T) {
+	repo := initTestGitRepo(t)
+	ctx := &CommandContext{Config: &models.Config{}}
+
+	got, err := arrangeListedWorktrees(ctx, []models.Worktree{{Branch: "main", Path: repo, IsMain: true}}, listArrangement{sortBy: "activity"})
+	if err != nil {
+		t.Fatalf("arrangeListedWorktrees(activity) error = %v", err)
+	}
+	if len(got) != 1 || got[0].Path != repo {
+		t.Errorf("arrangeListedWorktrees(activity) = %+v, want the single worktree", got)
+	}
+}
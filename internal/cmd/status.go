@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/config"
 	"github.com/d-kuro/gwq/internal/discovery"
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/template"
+	"github.com/d-kuro/gwq/internal/tmux"
 	"github.com/d-kuro/gwq/internal/ui"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
@@ -22,13 +27,19 @@ var (
 	statusInterval    int
 	statusFilter      string
 	statusSort        string
+	statusReverse     bool
 	statusJSON        bool
 	statusCSV         bool
+	statusOutput      string
 	statusVerbose     bool
 	statusGlobal      bool
 	statusShowProcess bool
 	statusNoFetch     bool
 	statusStaleDays   int
+	statusAgent       bool
+	statusStaleOnly   bool
+	statusTimeout     time.Duration
+	statusWTTimeout   time.Duration
 )
 
 var statusCmd = &cobra.Command{
@@ -37,12 +48,27 @@ var statusCmd = &cobra.Command{
 	Long: `Show status of all worktrees including git status, recent activity, and optional process information.
 
 This command provides a comprehensive view of all worktrees' current state, which is essential
-for managing multiple AI coding agents working in parallel across different worktrees.`,
+for managing multiple AI coding agents working in parallel across different worktrees.
+
+Worktrees on slow disks or network mounts can stall collection. --timeout
+bounds the whole collection and --worktree-timeout each worktree; worktrees
+that run out of time are shown with an unknown status instead of failing the
+command.
+
+A clean worktree with no activity for --stale-days days is shown as stale;
+worktrees with uncommitted changes keep their modified, staged or conflict
+state however old they are. --stale-only lists just the stale ones.
+
+With --watch the display is redrawn in place every --interval seconds, and
+immediately when the terminal is resized, until interrupted with Ctrl+C.`,
 	Example: `  # Table view with basic status
   gwq status
   
   # JSON output for scripting
   gwq status --json
+
+  # One JSON object per worktree (newline-delimited)
+  gwq status -o jsonl
   
   # Watch mode with 5 second interval
   gwq status --watch
@@ -50,11 +76,26 @@ for managing multiple AI coding agents working in parallel across different work
   # Include process information
   gwq status --show-processes
   
+  # Show agent tmux sessions next to each worktree
+  gwq status --agent
+
   # Filter modified worktrees
   gwq status --filter modified
+
+  # Clean worktrees untouched for a week
+  gwq status --stale-only --stale-days 7
+
+  # Busiest worktrees first
+  gwq status --sort changes
+
+  # Least recently active first
+  gwq status --sort activity --reverse
   
   # Global status from anywhere
-  gwq status --global`,
+  gwq status --global
+
+  # Do not wait more than 10 seconds for slow worktrees
+  gwq status --timeout 10s`,
 	RunE: runStatus,
 }
 
@@ -64,17 +105,45 @@ func init() {
 	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Auto-refresh mode")
 	statusCmd.Flags().IntVarP(&statusInterval, "interval", "i", 5, "Refresh interval in seconds for watch mode")
 	statusCmd.Flags().StringVarP(&statusFilter, "filter", "f", "", "Filter by status (changed, up to date, inactive)")
-	statusCmd.Flags().StringVarP(&statusSort, "sort", "s", "", "Sort by field (branch, modified, activity)")
+	statusCmd.Flags().StringVarP(&statusSort, "sort", "s", "", "Sort by field (branch, repo, status, activity, changes)")
+	statusCmd.Flags().BoolVar(&statusReverse, "reverse", false, "Reverse the sort order")
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
 	statusCmd.Flags().BoolVar(&statusCSV, "csv", false, "Output as CSV")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "table", "Output format (table, json, jsonl, csv)")
 	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Show additional information")
 	statusCmd.Flags().BoolVarP(&statusGlobal, "global", "g", false, "Show all worktrees from base directory")
 	statusCmd.Flags().BoolVar(&statusShowProcess, "show-processes", false, "Include running processes (slower)")
 	statusCmd.Flags().BoolVar(&statusNoFetch, "no-fetch", false, "Skip remote status check (faster)")
-	statusCmd.Flags().IntVar(&statusStaleDays, "stale-days", 14, "Days of inactivity before marking as stale")
+	statusCmd.Flags().BoolVar(&statusAgent, "agent", false, "Show agent tmux sessions running in each worktree")
+	statusCmd.Flags().IntVar(&statusStaleDays, "stale-days", 14, "Days of inactivity before a clean worktree is marked stale")
+	statusCmd.Flags().BoolVar(&statusStaleOnly, "stale-only", false, "Only show stale worktrees (same as --filter stale)")
+	statusCmd.MarkFlagsMutuallyExclusive("stale-only", "filter")
+	statusCmd.Flags().DurationVar(&statusTimeout, "timeout", 0, "Give up on worktrees still being collected after this long (0 for no limit)")
+	statusCmd.Flags().DurationVar(&statusWTTimeout, "worktree-timeout", 0, "Give up on a single worktree after this long (0 for no limit)")
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	format, err := resolveStatusOutputFormat(cmd.Flags().Changed("output"))
+	if err != nil {
+		return err
+	}
+	statusOutput = format
+
+	if statusTimeout < 0 || statusWTTimeout < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
+	if statusStaleDays <= 0 {
+		return fmt.Errorf("--stale-days must be positive")
+	}
+	if statusStaleOnly {
+		statusFilter = string(models.WorktreeStatusStale)
+	}
+	if statusFilter != "" {
+		if _, err := models.ParseWorktreeState(statusFilter); err != nil {
+			return err
+		}
+	}
+
 	if statusWatch {
 		return runStatusWatch(cmd, time.Duration(statusInterval)*time.Second)
 	}
@@ -98,7 +167,7 @@ func runStatusOnce(cmd *cobra.Command) error {
 
 	statuses = applyFiltersAndSort(statuses)
 
-	return outputStatuses(statuses, printer, cfg)
+	return outputStatuses(cmd.OutOrStdout(), statuses, printer, cfg)
 }
 
 func runStatusWatch(cmd *cobra.Command, interval time.Duration) error {
@@ -107,95 +176,69 @@ func runStatusWatch(cmd *cobra.Command, interval time.Duration) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
 
 	printer := ui.New(&cfg.UI)
 
-	// Setup watch mode (cursor control and cancellation)
-	cleanup, ctx := setupWatchMode()
-	defer cleanup()
-
-	// Create refresh function for status updates
-	refresh := createRefreshFunction(ctx, cfg, printer)
-
-	// Run the watch loop with periodic refreshes
-	return runWatchLoop(ctx, refresh, interval)
-}
-
-// setupWatchMode initializes cursor control and cancellation handling
-func setupWatchMode() (func(), context.Context) {
-	hideCursor := "\033[?25l"
-	showCursor := "\033[?25h"
-
-	fmt.Print(hideCursor)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	resize := make(chan os.Signal, 1)
+	ui.NotifyResize(resize)
+	defer signal.Stop(resize)
 
-	// Setup interrupt signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	refresher := ui.NewTerminalRefresher(cmd.OutOrStdout())
+	refresher.Start()
+	defer refresher.Stop()
 
-	go func() {
-		<-sigChan
-		cancel()
-	}()
-
-	cleanup := func() {
-		fmt.Print(showCursor)
-		cancel()
+	render := func(w io.Writer) error {
+		return renderStatusWatch(ctx, w, cfg, printer)
 	}
-
-	return cleanup, ctx
+	return runWatchLoop(ctx, refresher, render, interval, resize)
 }
 
-// createRefreshFunction creates the refresh function for watch mode
-func createRefreshFunction(ctx context.Context, cfg *models.Config, printer *ui.Printer) func() error {
-	clearScreen := "\033[H\033[2J"
-
-	return func() error {
-		fmt.Print(clearScreen)
-
-		statuses, err := collectWorktreeStatuses(ctx, cfg, printer)
-		if err != nil {
-			return fmt.Errorf("failed to collect worktree statuses: %w", err)
-		}
-
-		statuses = applyFiltersAndSort(statuses)
+// renderStatusWatch writes one frame of watch mode: the summary header
+// followed by the status output.
+func renderStatusWatch(ctx context.Context, w io.Writer, cfg *models.Config, printer *ui.Printer) error {
+	statuses, err := collectWorktreeStatuses(ctx, cfg, printer)
+	if err != nil {
+		return fmt.Errorf("failed to collect worktree statuses: %w", err)
+	}
 
-		// Display summary header
-		if err := displayWatchHeader(statuses); err != nil {
-			return err
-		}
+	statuses = applyFiltersAndSort(statuses)
 
-		// Output status details
-		if err := outputStatuses(statuses, printer, cfg); err != nil {
-			return err
-		}
+	displayWatchHeader(w, statuses, time.Now())
 
-		fmt.Println("\n[Press Ctrl+C to exit]")
-		return nil
+	if err := outputStatuses(w, statuses, printer, cfg); err != nil {
+		return err
 	}
+
+	_, _ = fmt.Fprintln(w, "\n[Press Ctrl+C to exit]")
+	return nil
 }
 
-// displayWatchHeader displays the summary header for watch mode
-func displayWatchHeader(statuses []*models.WorktreeStatus) error {
+// displayWatchHeader writes the summary header for watch mode
+func displayWatchHeader(w io.Writer, statuses []*models.WorktreeStatus, now time.Time) {
 	summary := calculateSummary(statuses)
 	currentRepo := getCurrentRepository()
 
-	fmt.Printf("Worktrees Status (%s) - Updated: %s\n",
-		currentRepo, time.Now().Format("15:04:05"))
-	fmt.Printf("Total: %d | Changed: %d | Up to date: %d | Inactive: %d\n\n",
+	_, _ = fmt.Fprintf(w, "Worktrees Status (%s) - Last updated: %s\n",
+		currentRepo, now.Format("15:04:05"))
+	_, _ = fmt.Fprintf(w, "Total: %d | Changed: %d | Up to date: %d | Inactive: %d\n\n",
 		summary.Total, summary.Modified, summary.Clean, summary.Stale)
-
-	return nil
 }
 
-// runWatchLoop runs the main watch loop with periodic refreshes
-func runWatchLoop(ctx context.Context, refresh func() error, interval time.Duration) error {
+// runWatchLoop renders a frame every interval until ctx is done, redrawing
+// the last frame whenever resize fires. A failed refresh shows the error in
+// place of the status.
+func runWatchLoop(ctx context.Context, refresher *ui.TerminalRefresher, render func(io.Writer) error, interval time.Duration, resize <-chan os.Signal) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// Initial refresh
-	if err := refresh(); err != nil {
+	if err := refresher.Render(render); err != nil {
 		return err
 	}
 
@@ -204,19 +247,86 @@ func runWatchLoop(ctx context.Context, refresh func() error, interval time.Durat
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-resize:
+			_ = refresher.Redraw()
 		case <-ticker.C:
-			if err := refresh(); err != nil {
-				fmt.Printf("Error: %v\n", err)
+			if err := refresher.Render(render); err != nil {
+				_ = refresher.Render(func(w io.Writer) error {
+					_, err := fmt.Fprintf(w, "Error: %v\n", err)
+					return err
+				})
 			}
 		}
 	}
 }
 
 func collectWorktreeStatuses(ctx context.Context, cfg *models.Config, printer *ui.Printer) ([]*models.WorktreeStatus, error) {
+	worktrees, err := listStatusWorktrees(cfg, statusGlobal)
+	if err != nil {
+		return nil, err
+	}
+
+	repoTemplate, err := statusRepositoryTemplate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		IncludeProcess:     statusShowProcess,
+		FetchRemote:        !statusNoFetch,
+		StaleThreshold:     time.Duration(statusStaleDays) * 24 * time.Hour,
+		BaseDir:            cfg.Worktree.BaseDir,
+		RepositoryTemplate: repoTemplate,
+		AgentNames:         cfg.ProcessDetect.AgentNames,
+		WorktreeTimeout:    statusWTTimeout,
+		ActivitySampleSize: cfg.Status.ActivitySampleSize,
+	})
+
+	collectCtx := ctx
+	if statusTimeout > 0 {
+		var cancel context.CancelFunc
+		collectCtx, cancel = context.WithTimeout(ctx, statusTimeout)
+		defer cancel()
+	}
+	statuses, err := collector.CollectAll(collectCtx, worktrees)
+	if err != nil {
+		return nil, err
+	}
+	if errors.Is(collectCtx.Err(), context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "Warning: status collection timed out after %s; unfinished worktrees are shown as unknown\n", statusTimeout)
+	}
+
+	if statusAgent {
+		sessions, err := tmux.NewSessionManager(nil).ListSessions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list tmux sessions: %v\n", err)
+		}
+		attachAgentSessions(statuses, sessions)
+	}
+	return statuses, nil
+}
+
+// statusRepositoryTemplate parses naming.status_template, returning nil when
+// it is not set.
+func statusRepositoryTemplate(cfg *models.Config) (*template.DisplayProcessor, error) {
+	if cfg.Naming.StatusTemplate == "" {
+		return nil, nil
+	}
+	p, err := template.NewDisplay(cfg.Naming.StatusTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming.status_template: %w", err)
+	}
+	return p, nil
+}
+
+// listStatusWorktrees returns the worktrees of the current repository, or
+// every worktree in the base directory when global is set or the current
+// directory is not inside a repository.
+func listStatusWorktrees(cfg *models.Config, global bool) ([]*models.Worktree, error) {
 	var worktrees []*models.Worktree
 
 	g, err := git.NewFromCwd()
-	if err != nil || statusGlobal {
+	if err != nil || global {
 		globalEntries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir)
 		if err != nil {
 			return nil, fmt.Errorf("failed to discover worktrees: %w", err)
@@ -224,31 +334,26 @@ func collectWorktreeStatuses(ctx context.Context, cfg *models.Config, printer *u
 		// Convert []*GlobalWorktreeEntry to []*models.Worktree
 		for _, entry := range globalEntries {
 			worktrees = append(worktrees, &models.Worktree{
-				Path:       entry.Path,
-				Branch:     entry.Branch,
-				CommitHash: entry.CommitHash,
-				IsMain:     entry.IsMain,
+				Path:           entry.Path,
+				Branch:         entry.Branch,
+				CommitHash:     entry.CommitHash,
+				IsMain:         entry.IsMain,
+				RepositoryInfo: entry.RepositoryInfo,
 			})
 		}
-	} else {
-		wm := worktree.New(g, cfg)
-		localWorktrees, err := wm.List()
-		if err != nil {
-			return nil, fmt.Errorf("failed to list worktrees: %w", err)
-		}
-		// Convert []models.Worktree to []*models.Worktree
-		for i := range localWorktrees {
-			worktrees = append(worktrees, &localWorktrees[i])
-		}
+		return worktrees, nil
 	}
 
-	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
-		IncludeProcess: statusShowProcess,
-		FetchRemote:    !statusNoFetch,
-		StaleThreshold: time.Duration(statusStaleDays) * 24 * time.Hour,
-		BaseDir:        cfg.Worktree.BaseDir,
-	})
-	return collector.CollectAll(ctx, worktrees)
+	wm := worktree.New(g, cfg)
+	localWorktrees, err := wm.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	// Convert []models.Worktree to []*models.Worktree
+	for i := range localWorktrees {
+		worktrees = append(worktrees, &localWorktrees[i])
+	}
+	return worktrees, nil
 }
 
 func applyFiltersAndSort(statuses []*models.WorktreeStatus) []*models.WorktreeStatus {
@@ -257,20 +362,51 @@ func applyFiltersAndSort(statuses []*models.WorktreeStatus) []*models.WorktreeSt
 	}
 
 	if statusSort != "" {
-		sortStatuses(statuses, statusSort)
+		sortStatuses(statuses, statusSort, statusReverse)
 	}
 
 	return statuses
 }
 
-func outputStatuses(statuses []*models.WorktreeStatus, printer *ui.Printer, cfg *models.Config) error {
+// resolveStatusOutputFormat reconciles --output with the legacy --json and
+// --csv flags, which remain shorthands for -o json and -o csv. outputSet
+// reports whether --output was given explicitly; a shorthand then has to
+// agree with it, so e.g. -o json --csv is rejected rather than guessed.
+func resolveStatusOutputFormat(outputSet bool) (string, error) {
+	format := strings.ToLower(statusOutput)
+	switch format {
+	case "table", "json", "jsonl", "csv":
+	default:
+		return "", fmt.Errorf("invalid output format %q: must be table, json, jsonl, or csv", statusOutput)
+	}
+
+	shorthand := ""
 	switch {
+	case statusJSON && statusCSV:
+		return "", fmt.Errorf("--json and --csv cannot be used together")
 	case statusJSON:
-		return outputJSON(statuses)
+		shorthand = "json"
 	case statusCSV:
-		return outputCSV(statuses)
+		shorthand = "csv"
 	default:
-		return outputTable(statuses, printer, statusVerbose)
+		return format, nil
+	}
+	if outputSet && format != shorthand {
+		return "", fmt.Errorf("--%s cannot be combined with -o %s", shorthand, format)
+	}
+	return shorthand, nil
+}
+
+func outputStatuses(w io.Writer, statuses []*models.WorktreeStatus, printer *ui.Printer, cfg *models.Config) error {
+	switch statusOutput {
+	case "json":
+		return outputJSON(w, statuses)
+	case "jsonl":
+		return outputJSONL(w, statuses)
+	case "csv":
+		return outputCSV(w, statuses)
+	default:
+		return outputTable(w, statuses, printer, statusVerbose, statusAgent)
 	}
 }
 
@@ -313,32 +449,16 @@ func calculateSummary(statuses []*models.WorktreeStatus) statusSummary {
 }
 
 func filterStatuses(statuses []*models.WorktreeStatus, filter string) []*models.WorktreeStatus {
-	var filtered []*models.WorktreeStatus
+	state, err := models.ParseWorktreeState(filter)
+	if err != nil {
+		return nil
+	}
 
+	var filtered []*models.WorktreeStatus
 	for _, s := range statuses {
-		switch filter {
-		case "modified", "changed":
-			if s.Status == models.WorktreeStatusModified {
-				filtered = append(filtered, s)
-			}
-		case "clean", "up to date":
-			if s.Status == models.WorktreeStatusClean {
-				filtered = append(filtered, s)
-			}
-		case "stale", "inactive":
-			if s.Status == models.WorktreeStatusStale {
-				filtered = append(filtered, s)
-			}
-		case "staged":
-			if s.Status == models.WorktreeStatusStaged {
-				filtered = append(filtered, s)
-			}
-		case "conflict", "conflicted":
-			if s.Status == models.WorktreeStatusConflict {
-				filtered = append(filtered, s)
-			}
+		if s.Status == state {
+			filtered = append(filtered, s)
 		}
 	}
-
 	return filtered
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// sessionWorktreeKey is the session metadata key holding the worktree a
+// session was started in.
+const sessionWorktreeKey = "worktree_path"
+
+// attachAgentSessions records each session on the status of the worktree it
+// runs in. Sessions are matched by their worktree_path metadata; sessions
+// without it (e.g. created by older versions) fall back to the innermost
+// worktree containing their working directory. Sessions outside every
+// worktree are ignored.
+func attachAgentSessions(statuses []*models.WorktreeStatus, sessions []*tmux.Session) {
+	byPath := make(map[string]*models.WorktreeStatus, len(statuses))
+	for _, s := range statuses {
+		byPath[filepath.Clean(s.Path)] = s
+	}
+
+	for _, session := range sessions {
+		var status *models.WorktreeStatus
+		if path := session.Metadata[sessionWorktreeKey]; path != "" {
+			status = byPath[filepath.Clean(path)]
+		} else if session.WorkingDir != "" {
+			status = containingWorktree(byPath, filepath.Clean(session.WorkingDir))
+		}
+		if status == nil {
+			continue
+		}
+
+		status.AgentSessions = append(status.AgentSessions, models.AgentSession{
+			Agent:       sessionAgent(session),
+			SessionName: session.SessionName,
+			StartTime:   session.StartTime,
+		})
+	}
+}
+
+// containingWorktree returns the status of the deepest worktree that
+// contains dir, walking up from dir itself.
+func containingWorktree(byPath map[string]*models.WorktreeStatus, dir string) *models.WorktreeStatus {
+	for {
+		if status, ok := byPath[dir]; ok {
+			return status
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// sessionAgent names the program a session runs: the first word of the
+// command given to 'gwq tmux run', or of the pane's current command.
+func sessionAgent(session *tmux.Session) string {
+	command := session.Metadata["orig_command"]
+	if command == "" {
+		command = session.Command
+	}
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
+
+// formatAgentSessions summarizes a worktree's sessions for the status table:
+// the first session's agent, name and age, plus a count of any others.
+func formatAgentSessions(sessions []models.AgentSession) (agent, name, duration string) {
+	if len(sessions) == 0 {
+		return "-", "-", "-"
+	}
+	first := sessions[0]
+	agent = first.Agent
+	if agent == "" {
+		agent = "-"
+	}
+	name = first.SessionName
+	if len(sessions) > 1 {
+		name = fmt.Sprintf("%s (+%d)", name, len(sessions)-1)
+	}
+	return agent, name, tmux.FormatSessionAge(time.Since(first.StartTime))
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestAttachAgentSessions(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sessions := []*tmux.Session{
+		{
+			SessionName: "gwq-run-claude-1",
+			Command:     "node",
+			WorkingDir:  "/wt/repo/feature/sub",
+			StartTime:   start,
+			Metadata:    map[string]string{"worktree_path": "/wt/repo/feature", "orig_command": "claude --continue"},
+		},
+		{
+			// Older session without worktree metadata: matched by directory.
+			SessionName: "gwq-run-aider-2",
+			Command:     "/usr/local/bin/aider",
+			WorkingDir:  "/src/repo/pkg",
+			StartTime:   start,
+			Metadata:    map[string]string{},
+		},
+		{
+			// Metadata wins over the working directory.
+			SessionName: "gwq-run-codex-3",
+			WorkingDir:  "/src/repo",
+			StartTime:   start,
+			Metadata:    map[string]string{"worktree_path": "/wt/repo/feature/", "orig_command": "codex"},
+		},
+		{
+			SessionName: "gwq-run-vim-4",
+			Command:     "vim",
+			WorkingDir:  "/elsewhere",
+			StartTime:   start,
+		},
+		{
+			SessionName: "gwq-run-gone-5",
+			WorkingDir:  "/src/repo",
+			Metadata:    map[string]string{"worktree_path": "/wt/repo/removed"},
+		},
+	}
+	statuses := []*models.WorktreeStatus{
+		{Path: "/src/repo", Branch: "main"},
+		{Path: "/wt/repo/feature", Branch: "feature"},
+		{Path: "/wt/repo/idle", Branch: "idle"},
+	}
+
+	attachAgentSessions(statuses, sessions)
+
+	want := map[string][]models.AgentSession{
+		"main": {{Agent: "aider", SessionName: "gwq-run-aider-2", StartTime: start}},
+		"feature": {
+			{Agent: "claude", SessionName: "gwq-run-claude-1", StartTime: start},
+			{Agent: "codex", SessionName: "gwq-run-codex-3", StartTime: start},
+		},
+		"idle": nil,
+	}
+	for _, s := range statuses {
+		got := s.AgentSessions
+		if len(got) != len(want[s.Branch]) {
+			t.Errorf("%s: got %d sessions %+v, want %+v", s.Branch, len(got), got, want[s.Branch])
+			continue
+		}
+		for i := range got {
+			if got[i] != want[s.Branch][i] {
+				t.Errorf("%s: session %d = %+v, want %+v", s.Branch, i, got[i], want[s.Branch][i])
+			}
+		}
+	}
+}
+
+func TestFormatAgentSessions(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name         string
+		sessions     []models.AgentSession
+		wantAgent    string
+		wantSession  string
+		wantDuration string
+	}{
+		{name: "none", wantAgent: "-", wantSession: "-", wantDuration: "-"},
+		{
+			name:         "single",
+			sessions:     []models.AgentSession{{Agent: "claude", SessionName: "gwq-run-a", StartTime: start}},
+			wantAgent:    "claude",
+			wantSession:  "gwq-run-a",
+			wantDuration: "2 hours",
+		},
+		{
+			name: "several",
+			sessions: []models.AgentSession{
+				{Agent: "claude", SessionName: "gwq-run-a", StartTime: start},
+				{Agent: "aider", SessionName: "gwq-run-b", StartTime: start},
+			},
+			wantAgent:    "claude",
+			wantSession:  "gwq-run-a (+1)",
+			wantDuration: "2 hours",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, session, duration := formatAgentSessions(tt.sessions)
+			if agent != tt.wantAgent || session != tt.wantSession || duration != tt.wantDuration {
+				t.Errorf("formatAgentSessions() = (%q, %q, %q), want (%q, %q, %q)",
+					agent, session, duration, tt.wantAgent, tt.wantSession, tt.wantDuration)
+			}
+		})
+	}
+}
@@ -2,14 +2,20 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/command"
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/process"
+	"github.com/d-kuro/gwq/internal/template"
+	"github.com/d-kuro/gwq/internal/url"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
@@ -19,23 +25,64 @@ type StatusCollectorOptions struct {
 	FetchRemote    bool
 	StaleThreshold time.Duration
 	BaseDir        string
+	// ProcessLister overrides platform process enumeration (used in tests).
+	ProcessLister process.Lister
+	// AgentNames are the commands classified as AI agents
+	// (process_detect.agent_names). Empty uses process.DefaultAgentNames.
+	AgentNames []string
+	// MaxRemoteConcurrency limits concurrent upstream comparisons (default 4).
+	MaxRemoteConcurrency int
+	// RemoteTimeout is the budget for all upstream comparisons in one
+	// CollectAll call (default 30s). Worktrees not compared in time keep
+	// zero ahead/behind counts.
+	RemoteTimeout time.Duration
+	// RepositoryTemplate formats the Repository field (naming.status_template).
+	// When nil, the repository is derived from the worktree's location.
+	RepositoryTemplate *template.DisplayProcessor
+	// WorktreeTimeout bounds the collection of a single worktree (0 means no
+	// limit). A worktree that runs out of time is reported as unknown.
+	WorktreeTimeout time.Duration
+	// ActivitySampleSize caps the tracked files stat'd to find a worktree's
+	// last activity (status.activity_sample_size, default 100). Larger
+	// values are more accurate in big repositories but slower.
+	ActivitySampleSize int
 }
 
+const (
+	defaultMaxRemoteConcurrency = 4
+	defaultRemoteTimeout        = 30 * time.Second
+	defaultActivitySampleSize   = 100
+)
+
 // StatusCollector collects status information for worktrees.
 type StatusCollector struct {
 	includeProcess bool
 	fetchRemote    bool
 	staleThreshold time.Duration
 	basedir        string
+	repoTemplate   *template.DisplayProcessor
+	processLister  process.Lister
+	agentNames     []string
+	processes      []process.Process // snapshot taken once per CollectAll
+
+	worktreeTimeout    time.Duration
+	activitySampleSize int
+	// stat reads file metadata for the last activity; replaced in tests.
+	stat func(name string) (os.FileInfo, error)
+
+	remoteSem      chan struct{}
+	remoteTimeout  time.Duration
+	remoteDeadline time.Time // set once per CollectAll
+	// remoteStatus compares a worktree with its upstream; replaced in tests.
+	remoteStatus func(ctx context.Context, g *git.Git, status *models.GitStatus) error
 }
 
 // NewStatusCollector creates a new status collector instance.
 func NewStatusCollector(includeProcess, fetchRemote bool) *StatusCollector {
-	return &StatusCollector{
-		includeProcess: includeProcess,
-		fetchRemote:    fetchRemote,
-		staleThreshold: 14 * 24 * time.Hour, // 14 days
-	}
+	return NewStatusCollectorWithOptions(StatusCollectorOptions{
+		IncludeProcess: includeProcess,
+		FetchRemote:    fetchRemote,
+	})
 }
 
 // NewStatusCollectorWithOptions creates a new status collector with custom options.
@@ -44,16 +91,44 @@ func NewStatusCollectorWithOptions(opts StatusCollectorOptions) *StatusCollector
 	if opts.StaleThreshold == 0 {
 		opts.StaleThreshold = 14 * 24 * time.Hour
 	}
+	if opts.ProcessLister == nil {
+		opts.ProcessLister = process.NewLister(command.NewStandardExecutor())
+	}
+	if len(opts.AgentNames) == 0 {
+		opts.AgentNames = process.DefaultAgentNames
+	}
+	if opts.MaxRemoteConcurrency <= 0 {
+		opts.MaxRemoteConcurrency = defaultMaxRemoteConcurrency
+	}
+	if opts.RemoteTimeout <= 0 {
+		opts.RemoteTimeout = defaultRemoteTimeout
+	}
+	if opts.ActivitySampleSize <= 0 {
+		opts.ActivitySampleSize = defaultActivitySampleSize
+	}
 
-	return &StatusCollector{
+	c := &StatusCollector{
 		includeProcess: opts.IncludeProcess,
 		fetchRemote:    opts.FetchRemote,
 		staleThreshold: opts.StaleThreshold,
 		basedir:        opts.BaseDir,
-	}
+		repoTemplate:   opts.RepositoryTemplate,
+		processLister:  opts.ProcessLister,
+		agentNames:     opts.AgentNames,
+		remoteSem:      make(chan struct{}, opts.MaxRemoteConcurrency),
+		remoteTimeout:  opts.RemoteTimeout,
+
+		worktreeTimeout:    opts.WorktreeTimeout,
+		activitySampleSize: opts.ActivitySampleSize,
+		stat:               os.Stat,
+	}
+	c.remoteStatus = c.fetchRemoteStatus
+	return c
 }
 
-// CollectAll collects status for all provided worktrees in parallel.
+// CollectAll collects status for all provided worktrees in parallel. When
+// ctx is done, or a worktree exceeds WorktreeTimeout, the affected worktrees
+// are reported with WorktreeStatusUnknown and their collection is abandoned.
 func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Worktree) ([]*models.WorktreeStatus, error) {
 	statuses := make([]*models.WorktreeStatus, len(worktrees))
 	var wg sync.WaitGroup
@@ -62,18 +137,23 @@ func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Wo
 
 	currentPath, _ := os.Getwd()
 
+	// Snapshot processes once, before spawning git commands, so every
+	// worktree is matched against the same listing.
+	if c.includeProcess {
+		processes, err := c.processLister.List(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list processes: %v\n", err)
+		}
+		c.processes = processes
+	}
+	c.remoteDeadline = time.Now().Add(c.remoteTimeout)
+
 	for i, wt := range worktrees {
 		wg.Add(1)
 		go func(idx int, worktree *models.Worktree) {
 			defer wg.Done()
 
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
-			status, err := c.collectOne(ctx, worktree)
+			status, err := c.collectWithin(ctx, worktree)
 			if err != nil {
 				mu.Lock()
 				if firstErr == nil {
@@ -107,20 +187,74 @@ func (c *StatusCollector) CollectAll(ctx context.Context, worktrees []*models.Wo
 	return validStatuses, nil
 }
 
+// collectWithin runs collectOne, giving up when ctx is done or the
+// worktree timeout passes. Git commands are cancelled with the context, but
+// filesystem scans are not, so an abandoned collection may keep running in
+// the background; its result is discarded.
+func (c *StatusCollector) collectWithin(ctx context.Context, worktree *models.Worktree) (*models.WorktreeStatus, error) {
+	if ctx.Err() != nil {
+		return c.timedOutStatus(worktree), nil
+	}
+	if c.worktreeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.worktreeTimeout)
+		defer cancel()
+	}
+
+	type result struct {
+		status *models.WorktreeStatus
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		status, err := c.collectOne(ctx, worktree)
+		done <- result{status, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err == nil && ctx.Err() != nil {
+			// Cancelled git commands leave the counts incomplete.
+			return c.timedOutStatus(worktree), nil
+		}
+		return r.status, r.err
+	case <-ctx.Done():
+		return c.timedOutStatus(worktree), nil
+	}
+}
+
+// timedOutStatus is the status reported for a worktree whose collection did
+// not finish in time.
+func (c *StatusCollector) timedOutStatus(worktree *models.Worktree) *models.WorktreeStatus {
+	return &models.WorktreeStatus{
+		Path:       worktree.Path,
+		Branch:     worktree.Branch,
+		CommitHash: worktree.CommitHash,
+		IsMain:     worktree.IsMain,
+		Repository: c.extractRepository(worktree.Path),
+		Status:     models.WorktreeStatusUnknown,
+	}
+}
+
 func (c *StatusCollector) collectOne(ctx context.Context, worktree *models.Worktree) (*models.WorktreeStatus, error) {
 	status := &models.WorktreeStatus{
 		Path:       worktree.Path,
 		Branch:     worktree.Branch,
-		Repository: c.extractRepository(worktree.Path),
+		CommitHash: worktree.CommitHash,
+		IsMain:     worktree.IsMain,
 		Status:     models.WorktreeStatusClean,
 	}
 
 	g := git.New(worktree.Path)
+	status.Repository = c.repositoryName(g, worktree)
 
 	gitStatus, err := c.collectGitStatus(ctx, g)
 	if err != nil {
 		// Log error but continue with minimal status
 		// fmt.Fprintf(os.Stderr, "Warning: Failed to collect git status for %s: %v\n", worktree.Path, err)
+		if errors.Is(err, git.ErrOutputTooLarge) {
+			fmt.Fprintf(os.Stderr, "Warning: %s has too many changes to count; its status is unknown\n", worktree.Path)
+		}
 		status.GitStatus = models.GitStatus{}
 		status.Status = models.WorktreeStatusUnknown
 	} else {
@@ -131,9 +265,7 @@ func (c *StatusCollector) collectOne(ctx context.Context, worktree *models.Workt
 	lastActivity, err := c.getLastActivity(worktree.Path)
 	if err == nil {
 		status.LastActivity = lastActivity
-		if time.Since(lastActivity) > c.staleThreshold {
-			status.Status = models.WorktreeStatusStale
-		}
+		status.Status = c.applyStaleness(status.Status, lastActivity, time.Now())
 	}
 
 	if c.includeProcess {
@@ -162,12 +294,40 @@ func (c *StatusCollector) collectGitStatus(ctx context.Context, g *git.Git) (*mo
 
 	if c.fetchRemote {
 		// Errors are ignored as remote might not be available
-		_ = c.fetchRemoteStatus(ctx, g, status)
+		_ = c.collectRemoteStatus(ctx, g, status)
 	}
 
 	return status, nil
 }
 
+// collectRemoteStatus runs remoteStatus under the concurrency limit and the
+// per-collection deadline. When the budget runs out, ahead/behind stay zero.
+func (c *StatusCollector) collectRemoteStatus(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+	if !c.remoteDeadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, c.remoteDeadline)
+		defer cancel()
+	}
+
+	select {
+	case c.remoteSem <- struct{}{}:
+		defer func() { <-c.remoteSem }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var remote models.GitStatus
+	if err := c.remoteStatus(ctx, g, &remote); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		// Partial counts from an interrupted comparison are misleading.
+		return ctx.Err()
+	}
+	status.Ahead, status.Behind = remote.Ahead, remote.Behind
+	return nil
+}
+
 // countFileStates counts modified, staged, added, deleted, and conflicted files
 func (c *StatusCollector) countFileStates(ctx context.Context, g *git.Git, status *models.GitStatus) error {
 	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -297,6 +457,46 @@ func (c *StatusCollector) countRevList(ctx context.Context, g *git.Git, revRange
 	return count
 }
 
+// CommitsBehind counts the commits HEAD of the worktree at path is behind
+// its upstream, as last fetched; it never contacts the remote. ok is false
+// when HEAD has no upstream, e.g. a local-only branch or a detached HEAD.
+func (c *StatusCollector) CommitsBehind(ctx context.Context, path string) (behind int, ok bool) {
+	g := git.New(path)
+	if _, err := c.getUpstreamBranch(ctx, g, "HEAD"); err != nil {
+		return 0, false
+	}
+	return c.countRevList(ctx, g, "HEAD..@{upstream}"), true
+}
+
+// WorkingTreeState classifies the worktree at path from a single
+// 'git status --porcelain' run, without activity or upstream information.
+// It can therefore never report WorktreeStatusStale.
+func (c *StatusCollector) WorkingTreeState(ctx context.Context, path string) (models.WorktreeState, error) {
+	state, _, err := c.WorkingTreeChanges(ctx, path)
+	return state, err
+}
+
+// WorkingTreeChanges is WorkingTreeState that also returns the file counts
+// the state was derived from.
+func (c *StatusCollector) WorkingTreeChanges(ctx context.Context, path string) (models.WorktreeState, models.GitStatus, error) {
+	gitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	output, err := git.New(path).RunWithContext(gitCtx, "status", "--porcelain=v1")
+	if err != nil {
+		return models.WorktreeStatusUnknown, models.GitStatus{}, err
+	}
+
+	status := &models.GitStatus{}
+	for line := range strings.SplitSeq(output, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		c.processStatusLine(line, status)
+	}
+	return c.determineWorktreeState(status), *status, nil
+}
+
 func (c *StatusCollector) determineWorktreeState(status *models.GitStatus) models.WorktreeState {
 	if status.Conflicts > 0 {
 		return models.WorktreeStatusConflict
@@ -310,6 +510,16 @@ func (c *StatusCollector) determineWorktreeState(status *models.GitStatus) model
 	return models.WorktreeStatusClean
 }
 
+// applyStaleness returns stale for a clean worktree inactive for longer than
+// the stale threshold. Uncommitted changes take precedence: a dirty worktree
+// keeps its state however old its last activity is.
+func (c *StatusCollector) applyStaleness(state models.WorktreeState, lastActivity, now time.Time) models.WorktreeState {
+	if state == models.WorktreeStatusClean && now.Sub(lastActivity) > c.staleThreshold {
+		return models.WorktreeStatusStale
+	}
+	return state
+}
+
 func (c *StatusCollector) getLastActivity(path string) (time.Time, error) {
 	// Use git ls-files to get tracked files efficiently
 	// This approach respects .gitignore patterns automatically and is much faster
@@ -339,7 +549,8 @@ func (c *StatusCollector) getLastActivity(path string) (time.Time, error) {
 	return latestTime, nil
 }
 
-// getLastActivityFromTrackedFiles gets the latest modification time from tracked files
+// getLastActivityFromTrackedFiles gets the latest modification time from a
+// sample of at most activitySampleSize tracked files.
 func (c *StatusCollector) getLastActivityFromTrackedFiles(g *git.Git, path string) (time.Time, error) {
 	// Get list of tracked files
 	// Using -z for null-terminated output to handle filenames with spaces
@@ -348,16 +559,16 @@ func (c *StatusCollector) getLastActivityFromTrackedFiles(g *git.Git, path strin
 		return time.Time{}, err
 	}
 
-	var latestTime time.Time
-	files := strings.SplitSeq(strings.TrimRight(output, "\x00"), "\x00")
-
-	for file := range files {
-		if file == "" {
-			continue
+	var files []string
+	for file := range strings.SplitSeq(strings.TrimRight(output, "\x00"), "\x00") {
+		if file != "" {
+			files = append(files, file)
 		}
+	}
 
-		fullPath := filepath.Join(path, file)
-		info, err := os.Stat(fullPath)
+	var latestTime time.Time
+	for _, file := range sampleFiles(files, c.activitySampleSize) {
+		info, err := c.stat(filepath.Join(path, file))
 		if err != nil {
 			continue // Skip files we can't stat
 		}
@@ -370,6 +581,19 @@ func (c *StatusCollector) getLastActivityFromTrackedFiles(g *git.Git, path strin
 	return latestTime, nil
 }
 
+// sampleFiles returns at most n files spread evenly over files, so the
+// sample covers every part of the tree rather than its first directories.
+func sampleFiles(files []string, n int) []string {
+	if len(files) <= n {
+		return files
+	}
+	sample := make([]string, n)
+	for i := range n {
+		sample[i] = files[i*len(files)/n]
+	}
+	return sample
+}
+
 // getLastActivityFromUntrackedFiles gets the latest modification time from untracked files
 func (c *StatusCollector) getLastActivityFromUntrackedFiles(g *git.Git, path string) time.Time {
 	var latestTime time.Time
@@ -453,6 +677,37 @@ func (c *StatusCollector) getLastActivityFallback(path string) (time.Time, error
 	return latestTime, nil
 }
 
+// repositoryName renders the configured repository template for worktree,
+// falling back to extractRepository when no template is set, the remote
+// cannot be parsed, or the template fails to render.
+func (c *StatusCollector) repositoryName(g *git.Git, worktree *models.Worktree) string {
+	if c.repoTemplate == nil {
+		return c.extractRepository(worktree.Path)
+	}
+
+	remoteURL, err := g.GetRepositoryURL()
+	if err != nil {
+		return c.extractRepository(worktree.Path)
+	}
+	repoInfo, err := url.ParseRepositoryURL(remoteURL)
+	if err != nil {
+		return c.extractRepository(worktree.Path)
+	}
+
+	name, err := c.repoTemplate.Render(&template.TemplateData{
+		Host:       repoInfo.Host,
+		Owner:      repoInfo.Owner,
+		Repository: repoInfo.Repository,
+		Branch:     worktree.Branch,
+		Hash:       template.ShortHash(repoInfo.FullPath + "/" + worktree.Branch),
+		Path:       worktree.Path,
+	})
+	if err != nil {
+		return c.extractRepository(worktree.Path)
+	}
+	return name
+}
+
 func (c *StatusCollector) extractRepository(path string) string {
 	// Return basename if basedir is not set
 	if c.basedir == "" {
@@ -491,16 +746,43 @@ func (c *StatusCollector) extractRepository(path string) string {
 	return filepath.Base(path)
 }
 
+// collectProcesses returns the processes whose working directory is inside
+// worktreePath, with the configured AI tools classified as ai_agent.
 func (c *StatusCollector) collectProcesses(ctx context.Context, worktreePath string) ([]models.ProcessInfo, error) {
-	// TODO: Implement process detection for AI agents and other tools
-	// This would involve:
-	// 1. Scanning for processes with working directory in worktreePath
-	// 2. Identifying known AI agent processes (claude, copilot, cursor, etc.)
-	// 3. Detecting common development tools (npm, cargo, python, etc.)
-	// 4. Platform-specific process enumeration (ps on Unix, tasklist on Windows)
-	//
-	// For now, this is a stub that returns an empty slice.
-	// The actual implementation is deferred as it requires platform-specific code
-	// and careful consideration of performance implications.
-	return []models.ProcessInfo{}, nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return process.InPath(c.processes, worktreePath, c.agentNames), nil
+}
+
+// WorktreeProcesses takes a fresh process snapshot and returns the processes
+// running in each of worktrees, keyed by path. A process inside nested
+// worktrees is attributed to the innermost one only.
+func (c *StatusCollector) WorktreeProcesses(ctx context.Context, worktrees []models.Worktree) (map[string][]models.ProcessInfo, error) {
+	processes, err := c.processLister.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	c.processes = processes
+
+	byDepth := slices.Clone(worktrees)
+	slices.SortFunc(byDepth, func(a, b models.Worktree) int {
+		return len(b.Path) - len(a.Path)
+	})
+
+	result := make(map[string][]models.ProcessInfo, len(worktrees))
+	claimed := make(map[int]bool)
+	for _, wt := range byDepth {
+		inside, err := c.collectProcesses(ctx, wt.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range inside {
+			if !claimed[p.PID] {
+				claimed[p.PID] = true
+				result[wt.Path] = append(result[wt.Path], p)
+			}
+		}
+	}
+	return result, nil
 }
@@ -3,7 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -14,7 +14,7 @@ import (
 )
 
 // outputJSON outputs worktree statuses in JSON format.
-func outputJSON(statuses []*models.WorktreeStatus) error {
+func outputJSON(w io.Writer, statuses []*models.WorktreeStatus) error {
 	summary := calculateSummary(statuses)
 
 	output := struct {
@@ -25,16 +25,27 @@ func outputJSON(statuses []*models.WorktreeStatus) error {
 		Worktrees: statuses,
 	}
 
-	encoder := json.NewEncoder(os.Stdout)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(output)
 }
 
+// outputJSONL outputs one JSON object per worktree status, one per line.
+func outputJSONL(w io.Writer, statuses []*models.WorktreeStatus) error {
+	encoder := json.NewEncoder(w)
+	for _, s := range statuses {
+		if err := encoder.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // outputCSV outputs worktree statuses in CSV format.
-func outputCSV(statuses []*models.WorktreeStatus) error {
-	t := table.New().Headers(
-		"branch", "status", "modified", "added", "deleted",
-		"ahead", "behind", "last_activity", "process",
+func outputCSV(w io.Writer, statuses []*models.WorktreeStatus) error {
+	t := table.New().SetOutput(w).Headers(
+		"path", "branch", "commit", "is_main", "status", "changes",
+		"modified", "added", "deleted", "ahead", "behind", "last_activity", "process",
 	)
 
 	for _, s := range statuses {
@@ -48,8 +59,12 @@ func outputCSV(statuses []*models.WorktreeStatus) error {
 		}
 
 		t.Row(
+			s.Path,
 			s.Branch,
+			s.CommitHash,
+			strconv.FormatBool(s.IsMain),
 			string(s.Status),
+			strconv.Itoa(countTotalChanges(s.GitStatus)),
 			strconv.Itoa(s.GitStatus.Modified),
 			strconv.Itoa(s.GitStatus.Added),
 			strconv.Itoa(s.GitStatus.Deleted),
@@ -63,19 +78,24 @@ func outputCSV(statuses []*models.WorktreeStatus) error {
 	return t.WriteCSV()
 }
 
-// outputTable outputs worktree statuses in table format.
-func outputTable(statuses []*models.WorktreeStatus, printer *ui.Printer, verbose bool) error {
+// outputTable outputs worktree statuses in table format. With agent set,
+// each row also shows the worktree's agent tmux session.
+func outputTable(w io.Writer, statuses []*models.WorktreeStatus, printer *ui.Printer, verbose, agent bool) error {
 	if len(statuses) == 0 {
-		fmt.Println("No worktrees found")
+		_, _ = fmt.Fprintln(w, "No worktrees found")
 		return nil
 	}
 
-	var t *table.Builder
+	headers := []string{"BRANCH", "STATUS", "CHANGES"}
 	if verbose {
-		t = table.New().Headers("BRANCH", "STATUS", "CHANGES", "AHEAD/BEHIND", "ACTIVITY", "PROCESS")
+		headers = append(headers, "AHEAD/BEHIND", "ACTIVITY", "PROCESS")
 	} else {
-		t = table.New().Headers("BRANCH", "STATUS", "CHANGES", "ACTIVITY")
+		headers = append(headers, "ACTIVITY")
+	}
+	if agent {
+		headers = append(headers, "AGENT", "SESSION", "DURATION")
 	}
+	t := table.New().SetOutput(w).Headers(headers...)
 
 	for _, s := range statuses {
 		// Apply marker for current worktree, with consistent spacing
@@ -87,16 +107,25 @@ func outputTable(statuses []*models.WorktreeStatus, printer *ui.Printer, verbose
 		}
 
 		status := formatStatusNoColor(s.Status)
+		if icon := s.Status.Icon(); icon != "" && printer != nil && printer.UseIcons() {
+			status = icon + " " + status
+		}
 		changes := formatChanges(s.GitStatus)
 		activity := formatActivity(s.LastActivity)
 
+		row := []string{branchWithMarker, status, changes}
 		if verbose {
 			aheadBehind := formatAheadBehind(s.GitStatus.Ahead, s.GitStatus.Behind)
 			process := formatProcess(s.ActiveProcess)
-			t.Row(branchWithMarker, status, changes, aheadBehind, activity, process)
+			row = append(row, aheadBehind, activity, process)
 		} else {
-			t.Row(branchWithMarker, status, changes, activity)
+			row = append(row, activity)
+		}
+		if agent {
+			name, session, duration := formatAgentSessions(s.AgentSessions)
+			row = append(row, name, session, duration)
 		}
+		t.Row(row...)
 	}
 
 	return t.Println()
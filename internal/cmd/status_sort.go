@@ -1,74 +1,86 @@
 package cmd
 
 import (
+	"cmp"
 	"slices"
 	"strings"
 
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
+// statusComparator orders two worktree statuses. Comparators return rows in
+// their natural "most interesting first" order; --reverse negates them.
+type statusComparator func(a, b *models.WorktreeStatus) int
+
+// statusComparators maps sort keys (and their aliases) to comparators.
+var statusComparators = map[string]statusComparator{
+	"branch":   compareStatusByBranch,
+	"name":     compareStatusByBranch,
+	"repo":     compareStatusByRepository,
+	"status":   compareStatusByState,
+	"activity": compareStatusByActivity,
+	"time":     compareStatusByActivity,
+	"changes":  compareStatusByChanges,
+	"modified": compareStatusByChanges,
+	"ahead":    compareStatusByAhead,
+	"behind":   compareStatusByBehind,
+}
+
 // sortStatuses sorts worktree statuses based on the specified field.
-func sortStatuses(statuses []*models.WorktreeStatus, sortBy string) {
-	switch strings.ToLower(sortBy) {
-	case "branch", "name":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			if a.Branch < b.Branch {
-				return -1
-			} else if a.Branch > b.Branch {
-				return 1
-			}
-			return 0
-		})
-	case "status":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			aPriority := getStatusPriority(a.Status)
-			bPriority := getStatusPriority(b.Status)
-			if aPriority < bPriority {
-				return -1
-			} else if aPriority > bPriority {
-				return 1
-			}
-			return 0
-		})
-	case "modified", "changes":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			aChanges := countTotalChanges(a.GitStatus)
-			bChanges := countTotalChanges(b.GitStatus)
-			if aChanges > bChanges {
-				return -1
-			} else if aChanges < bChanges {
-				return 1
-			}
-			return 0
-		})
-	case "activity", "time":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			if a.LastActivity.After(b.LastActivity) {
-				return -1
-			} else if a.LastActivity.Before(b.LastActivity) {
-				return 1
-			}
-			return 0
-		})
-	case "ahead":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			if a.GitStatus.Ahead > b.GitStatus.Ahead {
-				return -1
-			} else if a.GitStatus.Ahead < b.GitStatus.Ahead {
-				return 1
-			}
-			return 0
-		})
-	case "behind":
-		slices.SortFunc(statuses, func(a, b *models.WorktreeStatus) int {
-			if a.GitStatus.Behind > b.GitStatus.Behind {
-				return -1
-			} else if a.GitStatus.Behind < b.GitStatus.Behind {
-				return 1
-			}
-			return 0
+// Unknown fields leave the order untouched. Sorting is stable so rows that
+// compare equal keep their collection order.
+func sortStatuses(statuses []*models.WorktreeStatus, sortBy string, reverse bool) {
+	compare, ok := statusComparators[strings.ToLower(sortBy)]
+	if !ok {
+		return
+	}
+
+	if reverse {
+		slices.SortStableFunc(statuses, func(a, b *models.WorktreeStatus) int {
+			return compare(b, a)
 		})
+		return
 	}
+
+	slices.SortStableFunc(statuses, compare)
+}
+
+// compareStatusByBranch orders alphabetically by branch name.
+func compareStatusByBranch(a, b *models.WorktreeStatus) int {
+	return cmp.Compare(a.Branch, b.Branch)
+}
+
+// compareStatusByRepository orders alphabetically by repository, then branch.
+func compareStatusByRepository(a, b *models.WorktreeStatus) int {
+	return cmp.Or(
+		cmp.Compare(a.Repository, b.Repository),
+		cmp.Compare(a.Branch, b.Branch),
+	)
+}
+
+// compareStatusByState orders by status priority (conflicts first).
+func compareStatusByState(a, b *models.WorktreeStatus) int {
+	return cmp.Compare(getStatusPriority(a.Status), getStatusPriority(b.Status))
+}
+
+// compareStatusByActivity orders by most recent activity first.
+func compareStatusByActivity(a, b *models.WorktreeStatus) int {
+	return b.LastActivity.Compare(a.LastActivity)
+}
+
+// compareStatusByChanges orders by total dirty count, busiest first.
+func compareStatusByChanges(a, b *models.WorktreeStatus) int {
+	return cmp.Compare(countTotalChanges(b.GitStatus), countTotalChanges(a.GitStatus))
+}
+
+// compareStatusByAhead orders by commits ahead of upstream, most first.
+func compareStatusByAhead(a, b *models.WorktreeStatus) int {
+	return cmp.Compare(b.GitStatus.Ahead, a.GitStatus.Ahead)
+}
+
+// compareStatusByBehind orders by commits behind upstream, most first.
+func compareStatusByBehind(a, b *models.WorktreeStatus) int {
+	return cmp.Compare(b.GitStatus.Behind, a.GitStatus.Behind)
 }
 
 // getStatusPriority returns a priority value for sorting statuses.
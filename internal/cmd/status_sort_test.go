@@ -14,6 +14,7 @@ func TestSortStatuses(t *testing.T) {
 		name     string
 		statuses []*models.WorktreeStatus
 		sortBy   string
+		reverse  bool
 		want     []string // expected branch order
 	}{
 		{
@@ -26,6 +27,27 @@ func TestSortStatuses(t *testing.T) {
 			sortBy: "branch",
 			want:   []string{"feature/a", "feature/z", "main"},
 		},
+		{
+			name: "sort by branch reversed",
+			statuses: []*models.WorktreeStatus{
+				{Branch: "feature/z"},
+				{Branch: "feature/a"},
+				{Branch: "main"},
+			},
+			sortBy:  "branch",
+			reverse: true,
+			want:    []string{"main", "feature/z", "feature/a"},
+		},
+		{
+			name: "sort by repo then branch",
+			statuses: []*models.WorktreeStatus{
+				{Branch: "main", Repository: "github.com/o/zeta"},
+				{Branch: "fix", Repository: "github.com/o/alpha"},
+				{Branch: "add", Repository: "github.com/o/alpha"},
+			},
+			sortBy: "repo",
+			want:   []string{"add", "fix", "main"},
+		},
 		{
 			name: "sort by status",
 			statuses: []*models.WorktreeStatus{
@@ -46,6 +68,36 @@ func TestSortStatuses(t *testing.T) {
 			sortBy: "changes",
 			want:   []string{"many", "few", "none"},
 		},
+		{
+			name: "sort by changes reversed",
+			statuses: []*models.WorktreeStatus{
+				{Branch: "few", GitStatus: models.GitStatus{Modified: 1}},
+				{Branch: "many", GitStatus: models.GitStatus{Modified: 10, Added: 5}},
+				{Branch: "none", GitStatus: models.GitStatus{}},
+			},
+			sortBy:  "changes",
+			reverse: true,
+			want:    []string{"none", "few", "many"},
+		},
+		{
+			name: "ties keep collection order",
+			statuses: []*models.WorktreeStatus{
+				{Branch: "second", Status: models.WorktreeStatusClean},
+				{Branch: "first", Status: models.WorktreeStatusModified},
+				{Branch: "third", Status: models.WorktreeStatusClean},
+			},
+			sortBy: "status",
+			want:   []string{"first", "second", "third"},
+		},
+		{
+			name: "unknown key leaves order untouched",
+			statuses: []*models.WorktreeStatus{
+				{Branch: "b"},
+				{Branch: "a"},
+			},
+			sortBy: "bogus",
+			want:   []string{"b", "a"},
+		},
 		{
 			name: "sort by activity",
 			statuses: []*models.WorktreeStatus{
@@ -80,7 +132,7 @@ func TestSortStatuses(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sortStatuses(tt.statuses, tt.sortBy)
+			sortStatuses(tt.statuses, tt.sortBy, tt.reverse)
 
 			for i, expected := range tt.want {
 				if tt.statuses[i].Branch != expected {
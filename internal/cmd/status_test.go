@@ -1,10 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/process"
+	"github.com/d-kuro/gwq/internal/template"
+	"github.com/d-kuro/gwq/internal/ui"
 	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/viper"
 )
 
 func TestCalculateSummary(t *testing.T) {
@@ -314,3 +330,667 @@ func TestFormatProcess(t *testing.T) {
 		})
 	}
 }
+
+type fakeProcessLister []process.Process
+
+func (f fakeProcessLister) List(context.Context) ([]process.Process, error) {
+	return f, nil
+}
+
+func TestStatusCollector_ActiveProcess(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	lister := fakeProcessLister{
+		{PID: 42, Command: "claude", Cwd: dir},
+		{PID: 43, Command: "vim", Cwd: filepath.Join(dir, "sub")},
+		{PID: 44, Command: "aider", Cwd: "/elsewhere"},
+	}
+	worktrees := []*models.Worktree{{Path: dir, Branch: "main"}}
+
+	tests := []struct {
+		name           string
+		includeProcess bool
+		agentNames     []string
+		want           []models.ProcessInfo
+	}{
+		{name: "disabled", includeProcess: false, want: nil},
+		{
+			name:           "enabled",
+			includeProcess: true,
+			want: []models.ProcessInfo{
+				{PID: 42, Command: "claude", Type: process.TypeAIAgent},
+				{PID: 43, Command: "vim"},
+			},
+		},
+		{
+			name:           "configured agent names",
+			includeProcess: true,
+			agentNames:     []string{"vim"},
+			want: []models.ProcessInfo{
+				{PID: 42, Command: "claude"},
+				{PID: 43, Command: "vim", Type: process.TypeAIAgent},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+				IncludeProcess: tt.includeProcess,
+				ProcessLister:  lister,
+				AgentNames:     tt.agentNames,
+			})
+			statuses, err := collector.CollectAll(context.Background(), worktrees)
+			if err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+			got := statuses[0].ActiveProcess
+			if len(got) != len(tt.want) {
+				t.Fatalf("ActiveProcess = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("ActiveProcess[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// initTestGitRepo creates a git repository with one commit on main.
+func initTestGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-b", "main", dir},
+		{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestStatusCollector_RemoteConcurrencyLimit(t *testing.T) {
+	repo := initTestGitRepo(t)
+	worktrees := make([]*models.Worktree, 12)
+	for i := range worktrees {
+		worktrees[i] = &models.Worktree{Path: repo, Branch: "main"}
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		FetchRemote:          true,
+		MaxRemoteConcurrency: 3,
+	})
+
+	var inFlight, peak atomic.Int32
+	collector.remoteStatus = func(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		status.Ahead, status.Behind = 1, 2
+		return nil
+	}
+
+	statuses, err := collector.CollectAll(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrent remote calls = %d, want <= 3", got)
+	}
+	for i, s := range statuses {
+		if s.GitStatus.Ahead != 1 || s.GitStatus.Behind != 2 {
+			t.Errorf("status[%d] ahead/behind = %d/%d, want 1/2", i, s.GitStatus.Ahead, s.GitStatus.Behind)
+		}
+	}
+}
+
+func TestStatusCollector_RemoteTimeoutBudget(t *testing.T) {
+	repo := initTestGitRepo(t)
+	worktrees := []*models.Worktree{
+		{Path: repo, Branch: "main"},
+		{Path: repo, Branch: "main"},
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		FetchRemote:          true,
+		MaxRemoteConcurrency: 1,
+		RemoteTimeout:        100 * time.Millisecond,
+	})
+	collector.remoteStatus = func(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+		status.Ahead = 5 // partial result that must be discarded
+		<-ctx.Done()
+		return nil
+	}
+
+	start := time.Now()
+	statuses, err := collector.CollectAll(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v, want graceful degradation", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("CollectAll() took %v, want it bounded by the remote budget", elapsed)
+	}
+	for i, s := range statuses {
+		if s.GitStatus.Ahead != 0 || s.GitStatus.Behind != 0 {
+			t.Errorf("status[%d] ahead/behind = %d/%d, want 0/0", i, s.GitStatus.Ahead, s.GitStatus.Behind)
+		}
+	}
+}
+
+func TestStatusCollector_RepositoryTemplate(t *testing.T) {
+	repo := initTestGitRepo(t)
+	if out, err := exec.Command("git", "-C", repo, "remote", "add", "origin", "git@github.com:owner/app.git").CombinedOutput(); err != nil {
+		t.Fatalf("git remote add failed: %v: %s", err, out)
+	}
+	noRemote := initTestGitRepo(t)
+
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		want     string
+	}{
+		{name: "owner and repository", template: "{{.Owner}}/{{.Repository}}", path: repo, want: "owner/app"},
+		{name: "repository and branch", template: "{{.Repository}}@{{.Branch}}", path: repo, want: "app@feature/x"},
+		{name: "default without template", path: repo, want: filepath.Base(repo)},
+		{name: "fallback without remote", template: "{{.Owner}}/{{.Repository}}", path: noRemote, want: filepath.Base(noRemote)},
+		{name: "fallback on render error", template: "{{.Missing}}", path: repo, want: filepath.Base(repo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := StatusCollectorOptions{}
+			if tt.template != "" {
+				p, err := template.NewDisplay(tt.template)
+				if err != nil {
+					t.Fatalf("NewDisplay() error = %v", err)
+				}
+				opts.RepositoryTemplate = p
+			}
+			collector := NewStatusCollectorWithOptions(opts)
+
+			statuses, err := collector.CollectAll(context.Background(), []*models.Worktree{{Path: tt.path, Branch: "feature/x"}})
+			if err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+			if got := statuses[0].Repository; got != tt.want {
+				t.Errorf("Repository = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveStatusOutputFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		outputSet bool
+		json      bool
+		csv       bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "default table", output: "table", want: "table"},
+		{name: "jsonl", output: "jsonl", outputSet: true, want: "jsonl"},
+		{name: "case insensitive", output: "JSON", outputSet: true, want: "json"},
+		{name: "legacy json flag", output: "table", json: true, want: "json"},
+		{name: "legacy csv flag", output: "table", csv: true, want: "csv"},
+		{name: "csv flag agrees with -o csv", output: "csv", outputSet: true, csv: true, want: "csv"},
+		{name: "invalid format", output: "yaml", outputSet: true, wantErr: true},
+		{name: "json and csv", output: "table", json: true, csv: true, wantErr: true},
+		{name: "-o json with csv flag", output: "json", outputSet: true, csv: true, wantErr: true},
+		{name: "-o jsonl with json flag", output: "jsonl", outputSet: true, json: true, wantErr: true},
+		{name: "explicit table with json flag", output: "table", outputSet: true, json: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusOutput, statusJSON, statusCSV = tt.output, tt.json, tt.csv
+			t.Cleanup(func() { statusOutput, statusJSON, statusCSV = "table", false, false })
+
+			got, err := resolveStatusOutputFormat(tt.outputSet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveStatusOutputFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveStatusOutputFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputJSONL(t *testing.T) {
+	statuses := []*models.WorktreeStatus{
+		{Path: "/wt/a", Branch: "a", Status: models.WorktreeStatusClean},
+		{Path: "/wt/b", Branch: "b", Status: models.WorktreeStatusModified},
+	}
+
+	var buf bytes.Buffer
+	if err := outputJSONL(&buf, statuses); err != nil {
+		t.Fatalf("outputJSONL() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(statuses) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(statuses), buf.String())
+	}
+	for i, line := range lines {
+		var got models.WorktreeStatus
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if got.Path != statuses[i].Path {
+			t.Errorf("line %d path = %q, want %q", i, got.Path, statuses[i].Path)
+		}
+	}
+}
+
+func TestOutputStatuses_Golden(t *testing.T) {
+	activity := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	statuses := []*models.WorktreeStatus{
+		{
+			Path: "/src/repo", Branch: "main", CommitHash: "0123456789abcdef", IsMain: true,
+			Repository: "github.com/user/repo",
+			Status:     models.WorktreeStatusClean, LastActivity: activity, IsCurrent: true,
+		},
+		{
+			Path: "/wt/repo/feature", Branch: "feature", CommitHash: "fedcba9876543210",
+			Repository:   "github.com/user/repo",
+			Status:       models.WorktreeStatusModified,
+			GitStatus:    models.GitStatus{Modified: 2, Added: 1, Untracked: 3, Ahead: 1, Behind: 4},
+			LastActivity: activity,
+			ActiveProcess: []models.ProcessInfo{
+				{PID: 42, Command: "claude", Type: "ai_agent"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+	}{
+		{name: "json", write: func(b *bytes.Buffer) error { return outputJSON(b, statuses) }},
+		{name: "csv", write: func(b *bytes.Buffer) error { return outputCSV(b, statuses) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatalf("output error = %v", err)
+			}
+			assertGolden(t, "status."+tt.name+".golden", buf.Bytes())
+		})
+	}
+}
+
+func TestStatusCmd_OutputJSON(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	baseDir := t.TempDir()
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	for _, args := range [][]string{
+		{"init", "-b", "main", repoDir},
+		{"-C", repoDir, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "init"},
+		{"-C", repoDir, "remote", "add", "origin", "https://github.com/user/repo.git"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+		statusOutput, statusGlobal, statusNoFetch = "table", false, false
+	})
+	viper.Set("worktree.basedir", baseDir)
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	t.Cleanup(func() { rootCmd.SetOut(nil) })
+	rootCmd.SetArgs([]string{"status", "--output", "json", "--global", "--no-fetch"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var got struct {
+		Worktrees []*models.WorktreeStatus `json:"worktrees"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("status output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got.Worktrees) != 1 {
+		t.Fatalf("got %d worktrees, want 1", len(got.Worktrees))
+	}
+	if got.Worktrees[0].Path != repoDir {
+		t.Errorf("worktree path = %q, want %q", got.Worktrees[0].Path, repoDir)
+	}
+}
+
+// cancelAfterWriter cancels once it has received n writes.
+type cancelAfterWriter struct {
+	bytes.Buffer
+	n      int
+	cancel context.CancelFunc
+}
+
+func (w *cancelAfterWriter) Write(p []byte) (int, error) {
+	if w.n--; w.n == 0 {
+		defer w.cancel()
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestRunWatchLoop_RedrawsOnResize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The initial frame and the redraw after the resize.
+	out := &cancelAfterWriter{n: 2, cancel: cancel}
+	resize := make(chan os.Signal, 1)
+	renders := 0
+	render := func(w io.Writer) error {
+		renders++
+		resize <- os.Interrupt
+		_, err := io.WriteString(w, "frame\n")
+		return err
+	}
+
+	if err := runWatchLoop(ctx, ui.NewTerminalRefresher(out), render, time.Hour, resize); err != nil {
+		t.Fatalf("runWatchLoop() error = %v", err)
+	}
+	if renders != 1 {
+		t.Errorf("render called %d times, want 1 (resize must not re-collect)", renders)
+	}
+	if got := strings.Count(out.String(), "frame\n"); got != 2 {
+		t.Errorf("frame drawn %d times, want 2", got)
+	}
+}
+
+func TestRenderStatusWatchFrame_IncludesRows(t *testing.T) {
+	oldOutput := statusOutput
+	statusOutput = "table"
+	t.Cleanup(func() { statusOutput = oldOutput })
+
+	statuses := []*models.WorktreeStatus{
+		{Path: "/src/repo", Branch: "main", Status: models.WorktreeStatusClean},
+		{Path: "/wt/feature", Branch: "feature/watch", Status: models.WorktreeStatusModified},
+	}
+	render := func(w io.Writer) error {
+		displayWatchHeader(w, statuses, time.Now())
+		return outputStatuses(w, statuses, ui.New(&models.UIConfig{}), &models.Config{})
+	}
+
+	var out bytes.Buffer
+	if err := ui.NewTerminalRefresher(&out).Render(render); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"Total: 2", "BRANCH", "main", "feature/watch", "changed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("frame = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestDisplayWatchHeader(t *testing.T) {
+	var out bytes.Buffer
+	statuses := []*models.WorktreeStatus{{Status: models.WorktreeStatusClean}}
+	displayWatchHeader(&out, statuses, time.Date(2025, 1, 1, 9, 30, 5, 0, time.UTC))
+
+	if !strings.Contains(out.String(), "Last updated: 09:30:05") {
+		t.Errorf("header = %q, want Last updated time", out.String())
+	}
+	if !strings.Contains(out.String(), "Total: 1 |") {
+		t.Errorf("header = %q, want summary", out.String())
+	}
+}
+
+func TestStatusCollector_ApplyStaleness(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-20 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		threshold    time.Duration
+		state        models.WorktreeState
+		lastActivity time.Time
+		want         models.WorktreeState
+	}{
+		{name: "CleanAndOld", state: models.WorktreeStatusClean, lastActivity: old, want: models.WorktreeStatusStale},
+		{name: "CleanAndRecent", state: models.WorktreeStatusClean, lastActivity: recent, want: models.WorktreeStatusClean},
+		{name: "ModifiedAndOld", state: models.WorktreeStatusModified, lastActivity: old, want: models.WorktreeStatusModified},
+		{name: "StagedAndOld", state: models.WorktreeStatusStaged, lastActivity: old, want: models.WorktreeStatusStaged},
+		{name: "ConflictAndOld", state: models.WorktreeStatusConflict, lastActivity: old, want: models.WorktreeStatusConflict},
+		{name: "LongerThreshold", threshold: 30 * 24 * time.Hour, state: models.WorktreeStatusClean, lastActivity: old, want: models.WorktreeStatusClean},
+		{name: "ShorterThreshold", threshold: 30 * time.Minute, state: models.WorktreeStatusClean, lastActivity: recent, want: models.WorktreeStatusStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewStatusCollectorWithOptions(StatusCollectorOptions{StaleThreshold: tt.threshold})
+			if got := c.applyStaleness(tt.state, tt.lastActivity, now); got != tt.want {
+				t.Errorf("applyStaleness() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusCollector_StaleThreshold(t *testing.T) {
+	repo := initTestGitRepo(t)
+	file := filepath.Join(repo, "tracked.txt")
+	if err := os.WriteFile(file, []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"-C", repo, "add", "tracked.txt"},
+		{"-C", repo, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "add"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	touch := func() {
+		t.Helper()
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(repo, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	touch()
+
+	collect := func(threshold time.Duration) models.WorktreeState {
+		t.Helper()
+		c := NewStatusCollectorWithOptions(StatusCollectorOptions{StaleThreshold: threshold})
+		statuses, err := c.CollectAll(context.Background(), []*models.Worktree{{Path: repo, Branch: "main"}})
+		if err != nil || len(statuses) != 1 {
+			t.Fatalf("CollectAll() = %v, %v", statuses, err)
+		}
+		return statuses[0].Status
+	}
+
+	if got := collect(7 * 24 * time.Hour); got != models.WorktreeStatusStale {
+		t.Errorf("status with a 7 day threshold = %q, want stale", got)
+	}
+	if got := collect(14 * 24 * time.Hour); got != models.WorktreeStatusClean {
+		t.Errorf("status with a 14 day threshold = %q, want clean", got)
+	}
+
+	// Old but uncommitted changes stay modified.
+	if err := os.WriteFile(file, []byte("version 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	touch()
+	if got := collect(7 * 24 * time.Hour); got != models.WorktreeStatusModified {
+		t.Errorf("status of an old dirty worktree = %q, want modified", got)
+	}
+
+	// So do old staged changes.
+	if out, err := exec.Command("git", "-C", repo, "add", "tracked.txt").CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v: %s", err, out)
+	}
+	touch()
+	if got := collect(7 * 24 * time.Hour); got != models.WorktreeStatusStaged {
+		t.Errorf("status of an old staged worktree = %q, want staged", got)
+	}
+}
+
+func TestStatusCollector_WorktreeTimeout(t *testing.T) {
+	slow := initTestGitRepo(t)
+	fast := initTestGitRepo(t)
+	worktrees := []*models.Worktree{
+		{Path: slow, Branch: "slow"},
+		{Path: fast, Branch: "fast"},
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		FetchRemote:     true,
+		WorktreeTimeout: 50 * time.Millisecond,
+	})
+	slowRoot, err := filepath.EvalSymlinks(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector.remoteStatus = func(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+		if root, _ := g.RunCommand("rev-parse", "--show-toplevel"); strings.TrimSpace(root) == slowRoot {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	statuses, err := collector.CollectAll(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("CollectAll() returned %d statuses, want 2", len(statuses))
+	}
+	if statuses[0].Status != models.WorktreeStatusUnknown || statuses[0].Path != slow || statuses[0].Branch != "slow" {
+		t.Errorf("slow worktree = %+v, want unknown status with its path and branch", statuses[0])
+	}
+	if statuses[1].Status != models.WorktreeStatusClean {
+		t.Errorf("fast worktree status = %q, want clean", statuses[1].Status)
+	}
+}
+
+func TestStatusCollector_CollectAllAbandonsOnDeadline(t *testing.T) {
+	repo := initTestGitRepo(t)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{FetchRemote: true})
+	// Ignores cancellation, like a filesystem call stuck on a network mount.
+	collector.remoteStatus = func(ctx context.Context, g *git.Git, status *models.GitStatus) error {
+		<-release
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	statuses, err := collector.CollectAll(ctx, []*models.Worktree{{Path: repo, Branch: "main"}})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("CollectAll() took %s after the deadline", elapsed)
+	}
+	if len(statuses) != 1 || statuses[0].Status != models.WorktreeStatusUnknown {
+		t.Errorf("CollectAll() = %+v, want one unknown status", statuses)
+	}
+
+	// A context that is already done still reports every worktree.
+	statuses, err = collector.CollectAll(ctx, []*models.Worktree{{Path: repo, Branch: "main"}, {Path: repo, Branch: "other"}})
+	if err != nil || len(statuses) != 2 {
+		t.Errorf("CollectAll() with a done context = %d statuses, %v; want 2", len(statuses), err)
+	}
+}
+
+func TestStatusCollector_ActivitySampleSize(t *testing.T) {
+	repo := initTestGitRepo(t)
+	for i := range 25 {
+		if err := os.WriteFile(filepath.Join(repo, fmt.Sprintf("file%02d.txt", i)), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out, err := exec.Command("git", "-C", repo, "add", ".").CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v: %s", err, out)
+	}
+
+	tests := []struct {
+		name       string
+		sampleSize int
+		wantStats  int
+	}{
+		{name: "default covers small repositories", sampleSize: 0, wantStats: 25},
+		{name: "configured size bounds stats", sampleSize: 10, wantStats: 10},
+		{name: "size larger than repository", sampleSize: 1000, wantStats: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewStatusCollectorWithOptions(StatusCollectorOptions{ActivitySampleSize: tt.sampleSize})
+			var stats int
+			collector.stat = func(name string) (os.FileInfo, error) {
+				stats++
+				return os.Stat(name)
+			}
+
+			latest, err := collector.getLastActivityFromTrackedFiles(git.New(repo), repo)
+			if err != nil {
+				t.Fatalf("getLastActivityFromTrackedFiles() error = %v", err)
+			}
+			if stats != tt.wantStats {
+				t.Errorf("stat'd %d files, want %d", stats, tt.wantStats)
+			}
+			if latest.IsZero() {
+				t.Error("last activity is zero")
+			}
+		})
+	}
+}
+
+func TestSampleFiles(t *testing.T) {
+	files := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 20, want: files},
+		{n: 10, want: files},
+		{n: 5, want: []string{"a", "c", "e", "g", "i"}},
+		{n: 3, want: []string{"a", "d", "g"}},
+		{n: 1, want: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d", tt.n), func(t *testing.T) {
+			if got := sampleFiles(files, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("sampleFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
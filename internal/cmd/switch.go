@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var switchCmd = &cobra.Command{
+	Use:   "switch [pattern]",
+	Short: "Change to the main worktree or a named worktree of this repository",
+	Long: `Change to the main worktree of the current repository, from any of its
+linked worktrees.
+
+With a pattern, change to the first worktree of the current repository whose
+branch or path matches it instead. Unlike 'gwq cd', no fuzzy finder is shown.
+
+Like 'gwq cd', this launches a new shell unless shell integration is enabled
+(cd.launch_shell=false), in which case the current shell's directory changes.`,
+	Example: `  # Jump back to the main worktree
+  gwq switch
+
+  # Jump to the worktree for feature/auth
+  gwq switch auth`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: ExecuteWithArgs(true, runSwitch),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getWorktreeCompletions(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(switchCmd)
+}
+
+func runSwitch(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	if err := requireCdIntegration(ctx.Config, "switch"); err != nil {
+		return err
+	}
+
+	var pattern string
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+
+	path, err := resolveSwitchPath(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	return changeDirectory(path)
+}
+
+// resolveSwitchPath returns the main worktree of the current repository when
+// pattern is empty, and otherwise the first worktree matching pattern.
+func resolveSwitchPath(ctx *CommandContext, pattern string) (string, error) {
+	if pattern == "" {
+		path, err := ctx.Git.GetMainRepositoryPath()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve main worktree: %w", err)
+		}
+		return path, nil
+	}
+	return ctx.WorktreeManager.GetWorktreePath(pattern)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/worktree"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestResolveSwitchPath(t *testing.T) {
+	mainDir := initTestGitRepo(t)
+	mainDir, _ = filepath.EvalSymlinks(mainDir)
+	featureDir := filepath.Join(t.TempDir(), "feature-auth")
+	if out, err := exec.Command("git", "-C", mainDir, "worktree", "add", "-b", "feature/auth", featureDir).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add failed: %v: %s", err, out)
+	}
+	featureDir, _ = filepath.EvalSymlinks(featureDir)
+
+	// Resolve from inside the linked worktree, as a user would.
+	g := git.New(featureDir)
+	ctx := &CommandContext{
+		Config:          &models.Config{},
+		Git:             g,
+		WorktreeManager: worktree.New(g, &models.Config{}),
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr error
+	}{
+		{name: "no pattern resolves main worktree", pattern: "", want: mainDir},
+		{name: "pattern matches branch", pattern: "auth", want: featureDir},
+		{name: "pattern is case insensitive", pattern: "FEATURE/AUTH", want: featureDir},
+		{name: "no match", pattern: "does-not-exist", wantErr: worktree.ErrNoWorktreeFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSwitchPath(ctx, tt.pattern)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("resolveSwitchPath(%q) error = %v, want %v", tt.pattern, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSwitchPath(%q) error = %v", tt.pattern, err)
+			}
+			if resolved, err := filepath.EvalSymlinks(got); err == nil {
+				got = resolved
+			}
+			if got != tt.want {
+				t.Errorf("resolveSwitchPath(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/table"
+	"github.com/d-kuro/gwq/internal/ui"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncFetch  bool
+	syncGlobal bool
+	syncOutput string
+)
+
+// maxSyncFetchConcurrency limits how many repositories are fetched at once.
+const maxSyncFetchConcurrency = 4
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Report how far worktrees have diverged from their upstreams",
+	Long: `Report how many commits each worktree's branch is ahead of and behind its
+upstream branch, along with any unresolved merge conflicts.
+
+By default the comparison uses the remote-tracking branches as they are, so
+nothing is downloaded. With --fetch, 'git fetch --all' is run first in each
+repository; worktrees of the same repository share a single fetch.
+
+Worktrees that are in sync with their upstream (or have none) are dimmed.`,
+	Example: `  # Compare against the last fetched state
+  gwq sync
+
+  # Fetch all remotes first
+  gwq sync --fetch
+
+  # Every worktree in the base directory
+  gwq sync --fetch --global
+
+  # JSON output for scripting
+  gwq sync -o json`,
+	Args: cobra.NoArgs,
+	RunE: ExecuteWithArgs(false, runSync),
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncFetch, "fetch", false, "Run 'git fetch --all' in each repository first")
+	syncCmd.Flags().BoolVarP(&syncGlobal, "global", "g", false, "Show all worktrees from base directory")
+	syncCmd.Flags().StringVarP(&syncOutput, "output", "o", "table", "Output format (table, json)")
+}
+
+// syncRow is one worktree's divergence from its upstream.
+type syncRow struct {
+	Path      string `json:"path"`
+	Branch    string `json:"branch"`
+	Ahead     int    `json:"ahead"`
+	Behind    int    `json:"behind"`
+	Conflicts int    `json:"conflicts"`
+}
+
+func runSync(ctx *CommandContext, cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(syncOutput)
+	if format != "table" && format != "json" {
+		return &usageError{err: fmt.Errorf("invalid output format %q: must be table or json", syncOutput)}
+	}
+
+	worktrees, err := listStatusWorktrees(ctx.Config, syncGlobal)
+	if err != nil {
+		return err
+	}
+
+	bg := context.Background()
+	if syncFetch {
+		roots := syncRepoRoots(worktrees, func(path string) (string, error) {
+			return git.New(path).GetMainRepositoryPath()
+		})
+		fetchRepositories(bg, roots, os.Stderr)
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		FetchRemote:        true,
+		BaseDir:            ctx.Config.Worktree.BaseDir,
+		ActivitySampleSize: ctx.Config.Status.ActivitySampleSize,
+	})
+	statuses, err := collector.CollectAll(bg, worktrees)
+	if err != nil {
+		return fmt.Errorf("failed to collect worktree statuses: %w", err)
+	}
+
+	rows := make([]syncRow, 0, len(statuses))
+	for _, s := range statuses {
+		rows = append(rows, syncRow{
+			Path:      s.Path,
+			Branch:    s.Branch,
+			Ahead:     s.GitStatus.Ahead,
+			Behind:    s.GitStatus.Behind,
+			Conflicts: s.GitStatus.Conflicts,
+		})
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+	return printSyncTable(cmd.OutOrStdout(), rows, ctx.Printer)
+}
+
+// syncRepoRoots returns the main repository of each worktree, without
+// duplicates and in the order first seen. Worktrees whose repository cannot
+// be resolved are skipped with a warning.
+func syncRepoRoots(worktrees []*models.Worktree, rootOf func(path string) (string, error)) []string {
+	seen := make(map[string]bool)
+	var roots []string
+	for _, wt := range worktrees {
+		root, err := rootOf(wt.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to resolve repository of %s: %v\n", wt.Path, err)
+			continue
+		}
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// fetchRepositories runs 'git fetch --all' in each repository, a few at a
+// time. A failed fetch is reported to warnings and leaves that repository's
+// remote-tracking branches as they were.
+func fetchRepositories(ctx context.Context, roots []string, warnings io.Writer) {
+	sem := make(chan struct{}, maxSyncFetchConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, root := range roots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if _, err := git.New(root).RunWithContext(ctx, "fetch", "--all", "--quiet"); err != nil {
+				mu.Lock()
+				_, _ = fmt.Fprintf(warnings, "Warning: failed to fetch %s: %v\n", root, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// printSyncTable renders the divergence table, dimming worktrees that are
+// neither ahead of nor behind their upstream.
+func printSyncTable(w io.Writer, rows []syncRow, printer *ui.Printer) error {
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(w, "No worktrees found")
+		return nil
+	}
+
+	t := table.New().SetOutput(w).Headers("WORKTREE", "BRANCH", "↑AHEAD", "↓BEHIND", "CONFLICTS")
+	for _, r := range rows {
+		path := r.Path
+		if printer != nil && printer.UseTildeHome() {
+			path = utils.TildePath(path)
+		}
+		columns := []string{path, r.Branch, strconv.Itoa(r.Ahead), strconv.Itoa(r.Behind), strconv.Itoa(r.Conflicts)}
+		if r.Ahead == 0 && r.Behind == 0 {
+			t.DimRow(columns...)
+		} else {
+			t.Row(columns...)
+		}
+	}
+	return t.Println()
+}
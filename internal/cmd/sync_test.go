@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSyncRepoRoots(t *testing.T) {
+	roots := map[string]string{
+		"/wt/a-main":    "/repos/a",
+		"/wt/a-feature": "/repos/a",
+		"/wt/b-main":    "/repos/b",
+		"/wt/a-fix":     "/repos/a",
+	}
+	rootOf := func(path string) (string, error) {
+		if root, ok := roots[path]; ok {
+			return root, nil
+		}
+		return "", errors.New("not a repository")
+	}
+
+	worktrees := []*models.Worktree{
+		{Path: "/wt/a-main"},
+		{Path: "/wt/a-feature"},
+		{Path: "/wt/gone"},
+		{Path: "/wt/b-main"},
+		{Path: "/wt/a-fix"},
+	}
+
+	got := syncRepoRoots(worktrees, rootOf)
+	want := []string{"/repos/a", "/repos/b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("syncRepoRoots() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchRepositories_BehindAfterFetch(t *testing.T) {
+	upstream := initTestGitRepo(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	runGit("clone", "--quiet", upstream, clone)
+	runGit("-C", upstream, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "second")
+
+	worktrees := []*models.Worktree{{Path: clone, Branch: "main"}}
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{FetchRemote: true})
+
+	statuses, err := collector.CollectAll(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if got := statuses[0].GitStatus.Behind; got != 0 {
+		t.Fatalf("Behind before fetch = %d, want 0", got)
+	}
+
+	var warnings bytes.Buffer
+	fetchRepositories(context.Background(), []string{clone}, &warnings)
+	if warnings.Len() > 0 {
+		t.Fatalf("unexpected warnings: %s", warnings.String())
+	}
+
+	statuses, err = collector.CollectAll(context.Background(), worktrees)
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if got := statuses[0].GitStatus.Behind; got != 1 {
+		t.Errorf("Behind after fetch = %d, want 1", got)
+	}
+}
+
+func TestFetchRepositories_Warning(t *testing.T) {
+	var warnings bytes.Buffer
+	missing := filepath.Join(t.TempDir(), "missing")
+	fetchRepositories(context.Background(), []string{missing}, &warnings)
+
+	if !strings.Contains(warnings.String(), "Warning: failed to fetch "+missing) {
+		t.Errorf("warnings = %q, want a fetch failure for %s", warnings.String(), missing)
+	}
+}
+
+func TestPrintSyncTable(t *testing.T) {
+	rows := []syncRow{
+		{Path: "/wt/feature", Branch: "feature", Ahead: 2, Behind: 1},
+		{Path: "/wt/main", Branch: "main"},
+	}
+
+	var buf bytes.Buffer
+	if err := printSyncTable(&buf, rows, nil); err != nil {
+		t.Fatalf("printSyncTable() error = %v", err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("output to a non-terminal contains escape sequences: %q", out)
+	}
+	for _, want := range []string{"WORKTREE", "↑AHEAD", "↓BEHIND", "CONFLICTS", "/wt/feature", "/wt/main"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -20,8 +20,14 @@ or processes, enabling process persistence, monitoring, and history management.`
   # Attach to session
   gwq tmux attach auth
 
+  # Show the output of a session, even after it ended
+  gwq tmux logs auth
+
   # Terminate session
-  gwq tmux kill auth`,
+  gwq tmux kill auth
+
+  # Remove metadata for sessions killed outside gwq
+  gwq tmux prune`,
 }
 
 func init() {
@@ -92,6 +92,24 @@ func runTmuxAttach(cmd *cobra.Command, args []string) error {
 	return sessionManager.AttachSessionDirect(sessionToAttach)
 }
 
+// matchSession returns the session matching pattern, letting the user pick
+// with the fuzzy finder when several match.
+func matchSession(sessions []*tmux.Session, pattern string, cfg *models.Config) (*tmux.Session, error) {
+	matches := findMatchingSessions(sessions, pattern)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no session found matching pattern: %s", pattern)
+	case 1:
+		return matches[0], nil
+	default:
+		session, err := selectSessionWithFinder(matches, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("session selection cancelled: %w", err)
+		}
+		return session, nil
+	}
+}
+
 func findMatchingSessions(sessions []*tmux.Session, pattern string) []*tmux.Session {
 	pattern = strings.ToLower(pattern)
 	var matches []*tmux.Session
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/d-kuro/gwq/internal/agent"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/internal/utils"
+)
+
+// wrapExitCodeCommand makes command write its exit status to the sentinel
+// file at path when it finishes, keeping the status for any wrapper around
+// it.
+func wrapExitCodeCommand(command, path string) string {
+	return fmt.Sprintf(`(%s); gwq_status=$?; echo $gwq_status > "%s"; (exit $gwq_status)`,
+		command, utils.EscapeForShell(path))
+}
+
+// executionStatus is what is known about whether a session's command has
+// finished.
+type executionStatus struct {
+	Done     bool
+	ExitCode int
+}
+
+// checkExecutionStatus reports whether the command of session finished. The
+// exit code sentinel written by 'gwq tmux run' is definitive; without it the
+// captured pane is matched against a's completion patterns, if a is given.
+func checkExecutionStatus(session *tmux.Session, capture func() (string, error), a agent.Agent) (executionStatus, error) {
+	if path := session.Metadata[tmux.MetadataExitFile]; path != "" {
+		code, done, err := tmux.ReadExitCode(path)
+		if err != nil {
+			return executionStatus{}, err
+		}
+		if done {
+			return executionStatus{Done: true, ExitCode: code}, nil
+		}
+	}
+
+	if a == nil || capture == nil {
+		return executionStatus{}, nil
+	}
+	pane, err := capture()
+	if err != nil {
+		return executionStatus{}, err
+	}
+	done, code, err := a.CheckCompletion(pane)
+	if err != nil {
+		return executionStatus{}, err
+	}
+	return executionStatus{Done: done, ExitCode: code}, nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/agent"
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+func TestWrapExitCodeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{name: "success", command: "true", want: "0\n"},
+		{name: "failure", command: "exit 3", want: "3\n", wantErr: true},
+		{name: "pipeline", command: "echo hi | grep -q nope", want: "1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), `task "1".exit`)
+			err := exec.Command("sh", "-c", wrapExitCodeCommand(tt.command, path)).Run()
+
+			content, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("sentinel not written: %v", readErr)
+			}
+			if string(content) != tt.want {
+				t.Errorf("sentinel = %q, want %q", content, tt.want)
+			}
+
+			// The wrapper keeps the command's status for outer wrappers.
+			if (err != nil) != tt.wantErr {
+				t.Errorf("wrapped command error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckExecutionStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeSentinel := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	session := func(exitFile string) *tmux.Session {
+		return &tmux.Session{Metadata: map[string]string{tmux.MetadataExitFile: exitFile}}
+	}
+	claude, err := agent.NewDefaultRegistry(nil).Get("claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paneResult := func() (string, error) { return `{"type":"result","subtype":"success"}`, nil }
+	paneRunning := func() (string, error) { return "thinking...", nil }
+
+	tests := []struct {
+		name    string
+		session *tmux.Session
+		capture func() (string, error)
+		agent   agent.Agent
+		want    executionStatus
+		wantErr bool
+	}{
+		{
+			name:    "sentinel wins over the pane",
+			session: session(writeSentinel("failed.exit", "2\n")),
+			capture: paneResult,
+			agent:   claude,
+			want:    executionStatus{Done: true, ExitCode: 2},
+		},
+		{
+			name:    "partial sentinel falls back to the pane",
+			session: session(writeSentinel("partial.exit", "0")),
+			capture: paneResult,
+			agent:   claude,
+			want:    executionStatus{Done: true},
+		},
+		{
+			name:    "no sentinel and running pane",
+			session: session(filepath.Join(dir, "missing.exit")),
+			capture: paneRunning,
+			agent:   claude,
+		},
+		{
+			name:    "no sentinel and no agent",
+			session: &tmux.Session{},
+		},
+		{
+			name:    "corrupt sentinel",
+			session: session(writeSentinel("corrupt.exit", "x\n")),
+			wantErr: true,
+		},
+		{
+			name:    "capture error",
+			session: &tmux.Session{},
+			capture: func() (string, error) { return "", errors.New("no such session") },
+			agent:   claude,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkExecutionStatus(tt.session, tt.capture, tt.agent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkExecutionStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("checkExecutionStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,23 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/config"
-	"github.com/d-kuro/gwq/internal/table"
 	"github.com/d-kuro/gwq/internal/tmux"
-	"github.com/d-kuro/gwq/internal/ui"
-	"github.com/d-kuro/gwq/internal/utils"
-	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
-	tmuxListJSON  bool
-	tmuxListCSV   bool
-	tmuxListWatch bool
-	tmuxListSort  string
+	tmuxListFormat string
+	tmuxListJSON   bool
+	tmuxListCSV    bool
+	tmuxListWatch  bool
+	tmuxListSort   string
 )
 
 var tmuxListCmd = &cobra.Command{
@@ -28,12 +26,19 @@ var tmuxListCmd = &cobra.Command{
 	Long: `List active tmux sessions with their information.
 
 Shows running tmux sessions with context, identifier, duration and working directory.
-Supports various output formats and real-time monitoring.`,
+Supports various output formats and real-time monitoring.
+
+--format selects table (default), json or csv output; --json and --csv are
+shorthands. CSV rows have the columns session_name, context, identifier,
+command, started (RFC 3339) and duration.`,
 	Example: `  # List all sessions
   gwq tmux list
 
-  # JSON output for scripting  
-  gwq tmux list --json
+  # JSON output for scripting
+  gwq tmux list --format json
+
+  # CSV output
+  gwq tmux list -f csv
 
   # Real-time monitoring
   gwq tmux list --watch
@@ -46,49 +51,77 @@ Supports various output formats and real-time monitoring.`,
 func init() {
 	tmuxCmd.AddCommand(tmuxListCmd)
 
-	tmuxListCmd.Flags().BoolVar(&tmuxListJSON, "json", false, "Output as JSON")
-	tmuxListCmd.Flags().BoolVar(&tmuxListCSV, "csv", false, "Output as CSV")
+	tmuxListCmd.Flags().StringVarP(&tmuxListFormat, "format", "f", "table", "Output format (table, json, csv)")
+	tmuxListCmd.Flags().BoolVar(&tmuxListJSON, "json", false, "Output as JSON (same as --format json)")
+	tmuxListCmd.Flags().BoolVar(&tmuxListCSV, "csv", false, "Output as CSV (same as --format csv)")
+	tmuxListCmd.MarkFlagsMutuallyExclusive("json", "csv")
+	_ = tmuxListCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return tmux.SessionFormats(), cobra.ShellCompDirectiveNoFileComp
+	})
 	tmuxListCmd.Flags().BoolVarP(&tmuxListWatch, "watch", "w", false, "Real-time monitoring")
 	tmuxListCmd.Flags().StringVarP(&tmuxListSort, "sort", "s", "", "Sort by field (duration, context, identifier)")
 }
 
 func runTmuxList(cmd *cobra.Command, args []string) error {
+	format, err := resolveTmuxListFormat(cmd)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	if tmuxListWatch && format != "table" {
+		return &usageError{err: fmt.Errorf("--watch cannot be combined with --format %s", format)}
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	renderer, err := tmux.NewSessionRenderer(format, tmux.RenderOptions{TildeHome: cfg.UI.TildeHome})
+	if err != nil {
+		return &usageError{err: err}
+	}
 
 	sessionManager := tmux.NewSessionManager(nil)
 
+	// Best-effort cleanup of metadata for sessions killed outside gwq
+	_, _ = sessionManager.Prune()
+
 	if tmuxListWatch {
-		return runTmuxListWatch(sessionManager, cfg)
+		return runTmuxListWatch(sessionManager, renderer)
 	}
 
-	return runTmuxListOnce(sessionManager, cfg)
+	return runTmuxListOnce(cmd.OutOrStdout(), sessionManager, renderer)
 }
 
-func runTmuxListOnce(sessionManager *tmux.SessionManager, cfg *models.Config) error {
-	sessions, err := sessionManager.ListSessions()
-	if err != nil {
-		return fmt.Errorf("failed to list sessions: %w", err)
-	}
-
-	sortedSessions := applySessionSort(sessions, tmuxListSort)
-
+// resolveTmuxListFormat reconciles --format with the --json and --csv
+// shorthands.
+func resolveTmuxListFormat(cmd *cobra.Command) (string, error) {
+	format := strings.ToLower(tmuxListFormat)
+	shorthand := ""
 	switch {
 	case tmuxListJSON:
-		return outputSessionsJSON(sortedSessions)
+		shorthand = "json"
 	case tmuxListCSV:
-		return outputSessionsCSV(sortedSessions)
-	default:
-		printer := ui.New(&cfg.UI)
-		return outputSessionsTable(sortedSessions, printer)
+		shorthand = "csv"
+	}
+	if shorthand == "" {
+		return format, nil
+	}
+	if cmd.Flags().Changed("format") && format != shorthand {
+		return "", fmt.Errorf("--%s cannot be combined with --format %s", shorthand, format)
 	}
+	return shorthand, nil
 }
 
-func runTmuxListWatch(sessionManager *tmux.SessionManager, cfg *models.Config) error {
-	printer := ui.New(&cfg.UI)
+func runTmuxListOnce(w io.Writer, sessionManager *tmux.SessionManager, renderer tmux.SessionRenderer) error {
+	sessions, err := sessionManager.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return renderer.Render(w, applySessionSort(sessions, tmuxListSort))
+}
 
+func runTmuxListWatch(sessionManager *tmux.SessionManager, renderer tmux.SessionRenderer) error {
 	hideCursor := "\033[?25l"
 	showCursor := "\033[?25h"
 	clearScreen := "\033[H\033[2J"
@@ -112,7 +145,7 @@ func runTmuxListWatch(sessionManager *tmux.SessionManager, cfg *models.Config) e
 		fmt.Printf("tmux Sessions - Updated: %s\n", time.Now().Format("15:04:05"))
 		fmt.Printf("Total: %d sessions\n\n", len(sessions))
 
-		if err := outputSessionsTable(sortedSessions, printer); err != nil {
+		if err := renderer.Render(os.Stdout, sortedSessions); err != nil {
 			return err
 		}
 
@@ -142,92 +175,3 @@ func applySessionSort(sessions []*tmux.Session, sortBy string) []*tmux.Session {
 	// TODO: Implement sorting if needed in the future
 	return sessions
 }
-
-func outputSessionsJSON(sessions []*tmux.Session) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(sessions)
-}
-
-func outputSessionsCSV(sessions []*tmux.Session) error {
-	t := table.New().Headers("context", "identifier", "duration", "command", "working_dir", "session_name")
-
-	// Write data
-	for _, session := range sessions {
-		duration := time.Since(session.StartTime).Round(time.Second).String()
-		t.Row(
-			session.Context,
-			session.Identifier,
-			duration,
-			session.Command,
-			session.WorkingDir,
-			session.SessionName,
-		)
-	}
-
-	return t.WriteCSV()
-}
-
-func outputSessionsTable(sessions []*tmux.Session, printer *ui.Printer) error {
-	if len(sessions) == 0 {
-		printer.PrintInfo("No tmux sessions found")
-		return nil
-	}
-
-	t := table.New().Headers("SESSION", "DURATION", "WORKING_DIR")
-
-	for _, session := range sessions {
-		sessionIdentifier := session.Context + "/" + session.Identifier
-		duration := formatSessionDuration(session.StartTime)
-		workdir := formatWorkingDir(session.WorkingDir, printer)
-
-		t.Row(sessionIdentifier, duration, workdir)
-	}
-
-	return t.Println()
-}
-
-func formatSessionDuration(startTime time.Time) string {
-	duration := time.Since(startTime)
-	switch {
-	case duration < time.Minute:
-		return "just now"
-	case duration < time.Hour:
-		mins := int(duration.Minutes())
-		if mins == 1 {
-			return "1 min"
-		}
-		return fmt.Sprintf("%d mins", mins)
-	case duration < 24*time.Hour:
-		hours := int(duration.Hours())
-		if hours == 1 {
-			return "1 hour"
-		}
-		return fmt.Sprintf("%d hours", hours)
-	case duration < 7*24*time.Hour:
-		days := int(duration.Hours() / 24)
-		if days == 1 {
-			return "1 day"
-		}
-		return fmt.Sprintf("%d days", days)
-	default:
-		weeks := int(duration.Hours() / 24 / 7)
-		if weeks == 1 {
-			return "1 week"
-		}
-		return fmt.Sprintf("%d weeks", weeks)
-	}
-}
-
-func formatWorkingDir(workdir string, printer *ui.Printer) string {
-	// Apply tilde home replacement first if enabled
-	if printer != nil && printer.UseTildeHome() {
-		workdir = utils.TildePath(workdir)
-	}
-
-	// Then apply truncation if needed
-	if len(workdir) > 30 {
-		return "..." + workdir[len(workdir)-27:]
-	}
-	return workdir
-}
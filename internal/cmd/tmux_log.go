@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tmuxLogOutput string
+	tmuxLogFollow bool
+)
+
+// tmuxLogPollInterval is how often --follow captures the pane again.
+const tmuxLogPollInterval = 500 * time.Millisecond
+
+var tmuxLogCmd = &cobra.Command{
+	Use:   "log <pattern>",
+	Short: "Save the pane buffer of a tmux session to a file",
+	Long: `Save the text of a running tmux session's pane, including its whole
+scrollback, to a file.
+
+The file defaults to <worktree>/<session name>.log, in the worktree the
+session was started in. With --output - the text is written to stdout.
+With --follow the pane is captured again every 500ms and new lines are
+appended until the session ends.
+
+Unlike 'gwq tmux logs', which prints the output captured while the session
+ran, this saves what the pane currently shows, as plain text without color
+codes, and only works while the session exists.`,
+	Example: `  # Save the build session's output to <worktree>/<session>.log
+  gwq tmux log build
+
+  # Keep saving new output until the session ends
+  gwq tmux log --follow build
+
+  # Save to a specific file
+  gwq tmux log -o /tmp/build.log build`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTmuxLog,
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxLogCmd)
+
+	tmuxLogCmd.Flags().StringVarP(&tmuxLogOutput, "output", "o", "", "File to write (default: <worktree>/<session name>.log, - for stdout)")
+	tmuxLogCmd.Flags().BoolVarP(&tmuxLogFollow, "follow", "f", false, "Append new output until the session ends")
+}
+
+func runTmuxLog(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sessionManager := tmux.NewSessionManager(nil)
+	sessions, err := sessionManager.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	session, err := matchSession(sessions, args[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	output := tmuxLogOutput
+	if output == "" {
+		output = sessionLogFile(session)
+	}
+	var w io.Writer = cmd.OutOrStdout()
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	tmuxCmd := tmux.NewTmuxCommand("")
+	capture := func() (string, error) { return tmuxCmd.CapturePane(session.SessionName, tmux.HistoryStart) }
+	alive := func() bool { return tmuxLogFollow && tmuxCmd.HasSession(session.SessionName) }
+	if err := followPane(cmd.Context(), w, capture, alive, tmuxLogPollInterval); err != nil {
+		return err
+	}
+
+	if output != "-" {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Saved output of %s to %s\n", session.SessionName, output)
+	}
+	return nil
+}
+
+// sessionLogFile returns the default file for the pane buffer of session:
+// <session name>.log in its worktree, or in its working directory.
+func sessionLogFile(session *tmux.Session) string {
+	dir := session.Metadata[sessionWorktreeKey]
+	if dir == "" {
+		dir = session.WorkingDir
+	}
+	return filepath.Join(dir, session.SessionName+".log")
+}
+
+// followPane writes the captured pane text to w. While alive reports true it
+// captures again every interval and writes only the lines added since. The
+// last line is held back until the session ends, as it may still grow.
+func followPane(ctx context.Context, w io.Writer, capture func() (string, error), alive func() bool, interval time.Duration) error {
+	var written []string
+	var pending string
+	for captured := false; ; captured = true {
+		text, err := capture()
+		running := err == nil && alive()
+		if err != nil {
+			if !captured || alive() {
+				return err
+			}
+			// The session ended after the previous capture.
+			text = strings.Join(append(slices.Clone(written), pending), "\n")
+		}
+
+		lines := paneLines(text)
+		complete := lines
+		pending = ""
+		if running && len(lines) > 0 {
+			complete, pending = lines[:len(lines)-1], lines[len(lines)-1]
+		}
+		for _, line := range newPaneLines(written, complete) {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		written = complete
+		if !running {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if pending != "" {
+				_, err := fmt.Fprintln(w, pending)
+				return err
+			}
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// paneLines splits captured pane text into lines, dropping the blank lines
+// tmux pads the visible pane with.
+func paneLines(text string) []string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// newPaneLines returns the lines of cur that follow what prev already
+// covered. When old lines have scrolled out of the history, cur is matched
+// against the longest tail of prev it starts with.
+func newPaneLines(prev, cur []string) []string {
+	if len(cur) >= len(prev) && slices.Equal(prev, cur[:len(prev)]) {
+		return cur[len(prev):]
+	}
+	for k := min(len(prev), len(cur)); k > 0; k-- {
+		if slices.Equal(prev[len(prev)-k:], cur[:k]) {
+			return cur[k:]
+		}
+	}
+	return cur
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+)
+
+func TestNewPaneLines(t *testing.T) {
+	tests := []struct {
+		name string
+		prev []string
+		cur  []string
+		want []string
+	}{
+		{name: "first capture", cur: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "appended", prev: []string{"a", "b"}, cur: []string{"a", "b", "c", "d"}, want: []string{"c", "d"}},
+		{name: "unchanged", prev: []string{"a", "b"}, cur: []string{"a", "b"}},
+		{name: "history scrolled", prev: []string{"a", "b", "c"}, cur: []string{"b", "c", "d"}, want: []string{"d"}},
+		{name: "no overlap", prev: []string{"a", "b"}, cur: []string{"x", "y"}, want: []string{"x", "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newPaneLines(tt.prev, tt.cur); !slices.Equal(got, tt.want) {
+				t.Errorf("newPaneLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowPane(t *testing.T) {
+	captures := []string{
+		"$ make\nbuilding\n\n\n",
+		"$ make\nbuilding\ncompil",
+		"$ make\nbuilding\ncompiling\ndone\n\n",
+	}
+
+	tests := []struct {
+		name    string
+		follow  bool
+		aliveN  int // number of alive checks that report true
+		capErrs bool
+		want    string
+	}{
+		{name: "dump", want: "$ make\nbuilding\n"},
+		{name: "follow until the session ends", follow: true, aliveN: 2, want: "$ make\nbuilding\ncompiling\ndone\n"},
+		{name: "session ends between captures", follow: true, aliveN: 2, capErrs: true, want: "$ make\nbuilding\ncompil\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, checks := 0, 0
+			capture := func() (string, error) {
+				calls++
+				if tt.capErrs && calls == 3 {
+					return "", errors.New("can't find session")
+				}
+				return captures[min(calls, len(captures))-1], nil
+			}
+			alive := func() bool {
+				checks++
+				return tt.follow && checks <= tt.aliveN
+			}
+
+			var buf bytes.Buffer
+			if err := followPane(context.Background(), &buf, capture, alive, time.Millisecond); err != nil {
+				t.Fatalf("followPane() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("followPane() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowPane_CaptureError(t *testing.T) {
+	capture := func() (string, error) { return "", errors.New("can't find session") }
+	err := followPane(context.Background(), &bytes.Buffer{}, capture, func() bool { return false }, time.Millisecond)
+	if err == nil {
+		t.Error("followPane() expected the first capture's error")
+	}
+}
+
+func TestSessionLogFile(t *testing.T) {
+	session := &tmux.Session{SessionName: "gwq-run-build-20250101000000", WorkingDir: "/wt/sub"}
+	if got, want := sessionLogFile(session), filepath.Join("/wt/sub", "gwq-run-build-20250101000000.log"); got != want {
+		t.Errorf("sessionLogFile() = %q, want %q", got, want)
+	}
+
+	session.Metadata = map[string]string{sessionWorktreeKey: "/wt"}
+	if got, want := sessionLogFile(session), filepath.Join("/wt", "gwq-run-build-20250101000000.log"); got != want {
+		t.Errorf("sessionLogFile() with worktree = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var tmuxLogsFollow bool
+
+// tmuxLogsPollInterval is how often --follow checks the log for new output.
+const tmuxLogsPollInterval = 500 * time.Millisecond
+
+var tmuxLogsCmd = &cobra.Command{
+	Use:   "logs [pattern]",
+	Short: "Show the captured output of a tmux session",
+	Long: `Show the output captured from a tmux session started by gwq.
+
+The output of every session's first pane is appended to a log file while
+the session runs, and the file is kept after the session ends. Running
+sessions are matched first, like in 'gwq tmux attach'; when none match, the
+logs of ended sessions are searched. With no pattern a running session is
+picked with the fuzzy finder.
+
+The log holds the raw terminal output, including color codes.`,
+	Example: `  # Print the output of the session matching 'build'
+  gwq tmux logs build
+
+  # Keep printing new output until the session ends
+  gwq tmux logs -f build`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTmuxLogs,
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxLogsCmd)
+
+	tmuxLogsCmd.Flags().BoolVarP(&tmuxLogsFollow, "follow", "f", false, "Print new output until the session ends")
+}
+
+func runTmuxLogs(cmd *cobra.Command, args []string) error {
+	sessionManager := tmux.NewSessionManager(nil)
+	logDir := tmux.DefaultSessionLogDir()
+
+	var pattern string
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+	session, logPath, err := resolveSessionLog(sessionManager, logDir, pattern)
+	if err != nil {
+		return err
+	}
+
+	// A session that outlives its command stops being followed once the
+	// command's exit code is recorded.
+	alive := func() bool {
+		if session == nil || !sessionManager.HasSession(session.SessionName) {
+			return false
+		}
+		status, err := checkExecutionStatus(session, nil, nil)
+		return err != nil || !status.Done
+	}
+	if !tmuxLogsFollow {
+		alive = func() bool { return false }
+	}
+	if err := followLog(cmd.Context(), cmd.OutOrStdout(), logPath, tmuxLogsPollInterval, alive); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no output captured for %s; sessions started before output capture have no log", strings.TrimSuffix(filepath.Base(logPath), ".log"))
+		}
+		return err
+	}
+
+	if tmuxLogsFollow && session != nil {
+		if status, err := checkExecutionStatus(session, nil, nil); err == nil && status.Done {
+			fmt.Fprintf(os.Stderr, "Command exited with status %d\n", status.ExitCode)
+		}
+	}
+	return nil
+}
+
+// resolveSessionLog finds the log of the running session matching pattern,
+// or of an ended one. session is nil for ended sessions.
+func resolveSessionLog(sm *tmux.SessionManager, logDir, pattern string) (session *tmux.Session, logPath string, err error) {
+	sessions, err := sm.ListSessions()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	matches := sessions
+	if pattern != "" {
+		matches = findMatchingSessions(sessions, pattern)
+	}
+	if len(matches) == 1 {
+		return matches[0], tmux.SessionLogPath(logDir, matches[0].SessionName), nil
+	}
+	if len(matches) > 1 {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load config: %w", err)
+		}
+		selected, err := selectSessionWithFinder(matches, cfg)
+		if err != nil {
+			return nil, "", fmt.Errorf("session selection cancelled: %w", err)
+		}
+		return selected, tmux.SessionLogPath(logDir, selected.SessionName), nil
+	}
+	if pattern == "" {
+		return nil, "", fmt.Errorf("no tmux sessions found")
+	}
+
+	logs, err := tmux.FindSessionLogs(logDir, pattern)
+	if err != nil {
+		return nil, "", err
+	}
+	switch len(logs) {
+	case 0:
+		return nil, "", fmt.Errorf("no session or log found matching pattern: %s", pattern)
+	case 1:
+		return nil, logs[0], nil
+	default:
+		names := make([]string, len(logs))
+		for i, l := range logs {
+			names[i] = strings.TrimSuffix(filepath.Base(l), ".log")
+		}
+		return nil, "", fmt.Errorf("pattern %q matches logs of several ended sessions: %s", pattern, strings.Join(names, ", "))
+	}
+}
+
+// followLog copies the log at path to w. While alive reports true it keeps
+// polling for appended output every interval, and copies what was written
+// until the session ended.
+func followLog(ctx context.Context, w io.Writer, path string, interval time.Duration, alive func() bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	for {
+		// Check liveness before copying so output written just before the
+		// session ended is not lost.
+		running := alive()
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		if !running {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
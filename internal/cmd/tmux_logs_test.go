@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollowLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	if err := os.WriteFile(path, []byte("line 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The session prints another line, then ends after the next check.
+	checks := 0
+	alive := func() bool {
+		checks++
+		switch checks {
+		case 1:
+			return true
+		case 2:
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = f.WriteString("line 2\n")
+			_ = f.Close()
+			return false
+		default:
+			t.Fatal("liveness checked after the session ended")
+			return false
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := followLog(context.Background(), &buf, path, time.Millisecond, alive); err != nil {
+		t.Fatalf("followLog() error = %v", err)
+	}
+	if got, want := buf.String(), "line 1\nline 2\n"; got != want {
+		t.Errorf("followLog() wrote %q, want %q", got, want)
+	}
+}
+
+func TestFollowLog_EndedSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.log")
+	if err := os.WriteFile(path, []byte("done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := followLog(context.Background(), &buf, path, time.Hour, func() bool { return false }); err != nil {
+		t.Fatalf("followLog() error = %v", err)
+	}
+	if got := buf.String(); got != "done\n" {
+		t.Errorf("followLog() wrote %q, want %q", got, "done\n")
+	}
+
+	if err := followLog(context.Background(), &buf, filepath.Join(t.TempDir(), "missing.log"), time.Hour, func() bool { return false }); !os.IsNotExist(err) {
+		t.Errorf("followLog(missing) error = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tmuxNewWindowCommand string
+)
+
+// newWindowFallbackContext is the context of the session created when no
+// session matches.
+const newWindowFallbackContext = "shell"
+
+var tmuxNewWindowCmd = &cobra.Command{
+	Use:   "new-window <session-pattern> <worktree-pattern>",
+	Short: "Open a worktree in a new window of a tmux session",
+	Long: `Open a worktree in a new window of the tmux session matching the given
+pattern. The window starts a shell in the worktree, or --command if given,
+and becomes the session's current window.
+
+If multiple sessions match the pattern, an interactive fuzzy finder will be
+shown. If none match, a new session is created in the worktree instead, with
+the pattern as its identifier.`,
+	Example: `  # Open the auth worktree next to the dev server
+  gwq tmux new-window dev-server feature/auth
+
+  # Run an editor in the new window
+  gwq tmux new-window dev-server feature/auth --command nvim`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTmuxNewWindow,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return getTmuxSessionCompletions(cmd, nil, toComplete)
+		case 1:
+			return getWorktreeCompletions(cmd, nil, toComplete)
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+	},
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxNewWindowCmd)
+
+	tmuxNewWindowCmd.Flags().StringVar(&tmuxNewWindowCommand, "command", "", "Command to run in the new window instead of a shell")
+}
+
+func runTmuxNewWindow(cmd *cobra.Command, args []string) error {
+	sessionPattern, worktreePattern := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	workDir, err := resolveWorktreePath(worktreePattern, cfg)
+	if err != nil {
+		return err
+	}
+
+	sessionManager := tmux.NewSessionManager(nil)
+
+	sessions, err := sessionManager.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	msg, err := openWorktreeWindow(cmd.Context(), tmux.NewTmuxCommand(""), sessionManager, sessions, sessionPattern, workDir, tmuxNewWindowCommand, cfg)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), msg)
+	return nil
+}
+
+// windowOpener is the part of tmux.TmuxInterface openWorktreeWindow uses.
+type windowOpener interface {
+	NewWindow(sessionName, workDir string) error
+	NewWindowCommand(sessionName, workDir, command string) error
+}
+
+// sessionCreator is the part of tmux.SessionManagerInterface
+// openWorktreeWindow uses.
+type sessionCreator interface {
+	CreateSession(ctx context.Context, opts tmux.SessionOptions) (*tmux.Session, error)
+}
+
+// openWorktreeWindow opens workDir in a new window of the session matching
+// pattern, or in a new session when none matches, and describes the result.
+func openWorktreeWindow(ctx context.Context, tmuxCmd windowOpener, sm sessionCreator, sessions []*tmux.Session, pattern, workDir, command string, cfg *models.Config) (string, error) {
+	if len(findMatchingSessions(sessions, pattern)) == 0 {
+		session, err := sm.CreateSession(ctx, tmux.SessionOptions{
+			Context:    newWindowFallbackContext,
+			Identifier: pattern,
+			WorkingDir: workDir,
+			Command:    command,
+			Metadata:   map[string]string{sessionWorktreeKey: workDir},
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("No session matches %s; created session %s", pattern, session.SessionName), nil
+	}
+
+	session, err := matchSession(sessions, pattern, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if command != "" {
+		err = tmuxCmd.NewWindowCommand(session.SessionName, workDir, command)
+	} else {
+		err = tmuxCmd.NewWindow(session.SessionName, workDir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open window in %s: %w", session.SessionName, err)
+	}
+	return fmt.Sprintf("Opened %s in a new window of %s", workDir, session.SessionName), nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+type fakeWindowOpener struct {
+	calls []string
+}
+
+func (f *fakeWindowOpener) NewWindow(sessionName, workDir string) error {
+	f.calls = append(f.calls, fmt.Sprintf("window %s %s", sessionName, workDir))
+	return nil
+}
+
+func (f *fakeWindowOpener) NewWindowCommand(sessionName, workDir, command string) error {
+	f.calls = append(f.calls, fmt.Sprintf("window %s %s %q", sessionName, workDir, command))
+	return nil
+}
+
+type fakeSessionCreator struct {
+	created []tmux.SessionOptions
+}
+
+func (f *fakeSessionCreator) CreateSession(_ context.Context, opts tmux.SessionOptions) (*tmux.Session, error) {
+	f.created = append(f.created, opts)
+	return &tmux.Session{SessionName: "gwq-" + opts.Context + "-" + opts.Identifier}, nil
+}
+
+func TestOpenWorktreeWindow(t *testing.T) {
+	sessions := []*tmux.Session{
+		{SessionName: "gwq-run-dev-server-20260101000000", Context: "run", Identifier: "dev-server"},
+		{SessionName: "gwq-claude-auth-20260101000000", Context: "claude", Identifier: "auth"},
+	}
+
+	tests := []struct {
+		name        string
+		pattern     string
+		command     string
+		wantCalls   []string
+		wantCreated bool
+		wantMsg     string
+	}{
+		{
+			name:      "shell in matching session",
+			pattern:   "dev",
+			wantCalls: []string{"window gwq-run-dev-server-20260101000000 /wt"},
+			wantMsg:   "Opened /wt in a new window of gwq-run-dev-server-20260101000000",
+		},
+		{
+			name:      "command in matching session",
+			pattern:   "auth",
+			command:   "nvim",
+			wantCalls: []string{`window gwq-claude-auth-20260101000000 /wt "nvim"`},
+			wantMsg:   "Opened /wt in a new window of gwq-claude-auth-20260101000000",
+		},
+		{
+			name:        "no match creates session",
+			pattern:     "review",
+			command:     "nvim",
+			wantCreated: true,
+			wantMsg:     "No session matches review; created session gwq-shell-review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opener := &fakeWindowOpener{}
+			creator := &fakeSessionCreator{}
+
+			msg, err := openWorktreeWindow(context.Background(), opener, creator, sessions, tt.pattern, "/wt", tt.command, &models.Config{})
+			if err != nil {
+				t.Fatalf("openWorktreeWindow() error = %v", err)
+			}
+			if msg != tt.wantMsg {
+				t.Errorf("message = %q, want %q", msg, tt.wantMsg)
+			}
+			if !slices.Equal(opener.calls, tt.wantCalls) {
+				t.Errorf("window calls = %q, want %q", opener.calls, tt.wantCalls)
+			}
+
+			if !tt.wantCreated {
+				if len(creator.created) != 0 {
+					t.Errorf("created sessions = %+v, want none", creator.created)
+				}
+				return
+			}
+			if len(creator.created) != 1 {
+				t.Fatalf("created %d sessions, want 1", len(creator.created))
+			}
+			opts := creator.created[0]
+			if opts.Identifier != tt.pattern || opts.WorkingDir != "/wt" || opts.Command != tt.command {
+				t.Errorf("session options = %+v", opts)
+			}
+			if opts.Metadata[sessionWorktreeKey] != "/wt" {
+				t.Errorf("worktree metadata = %q, want /wt", opts.Metadata[sessionWorktreeKey])
+			}
+		})
+	}
+}
+
+func TestSessionCompletions(t *testing.T) {
+	sessions := []*tmux.Session{
+		{SessionName: "gwq-run-dev-server-1", Identifier: "dev-server"},
+		{SessionName: "gwq-run-docs-1", Identifier: "docs"},
+		{SessionName: "gwq-claude-auth-1", Identifier: "auth"},
+	}
+
+	got := sessionCompletions(sessions, "d")
+	want := []string{"dev-server\tSession: gwq-run-dev-server-1", "docs\tSession: gwq-run-docs-1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sessionCompletions() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveWorktreePath_ExactBranch(t *testing.T) {
+	repo := initTestGitRepo(t)
+	base := t.TempDir()
+	featDir := filepath.Join(base, "feat")
+	featureDir := filepath.Join(base, "feature")
+	for _, args := range [][]string{
+		{"-C", repo, "worktree", "add", "-b", "feat", featDir},
+		{"-C", repo, "worktree", "add", "-b", "feature", featureDir},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	t.Chdir(repo)
+	cfg := &models.Config{Worktree: models.WorktreeConfig{BaseDir: base}}
+
+	got, err := resolveWorktreePath("feat", cfg)
+	if err != nil {
+		t.Fatalf("resolveWorktreePath(feat) error = %v", err)
+	}
+	if !sameFile(got, featDir) {
+		t.Errorf("resolveWorktreePath(feat) = %q, want %q", got, featDir)
+	}
+
+	if _, err := resolveWorktreePath("fea", cfg); err == nil {
+		t.Error("resolveWorktreePath(fea) error = nil, want an ambiguous match error")
+	}
+}
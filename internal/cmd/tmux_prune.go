@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var tmuxPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove metadata for tmux sessions that no longer exist",
+	Long: `Remove stored metadata for gwq tmux sessions that no longer exist.
+
+gwq records metadata for the sessions it creates. Sessions killed outside gwq
+(e.g. with 'tmux kill-session') leave stale entries behind; this command
+removes them. 'gwq tmux list' also prunes automatically.`,
+	Example: `  # Remove metadata for dead sessions
+  gwq tmux prune`,
+	Args: cobra.NoArgs,
+	RunE: runTmuxPrune,
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxPruneCmd)
+}
+
+func runTmuxPrune(cmd *cobra.Command, args []string) error {
+	sessionManager := tmux.NewSessionManager(nil)
+
+	removed, err := sessionManager.Prune()
+	if err != nil {
+		return fmt.Errorf("failed to prune session metadata: %w", err)
+	}
+
+	fmt.Printf("Pruned metadata for %d session(s)\n", removed)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+var tmuxRenameCmd = &cobra.Command{
+	Use:   "rename <pattern> <new-identifier>",
+	Short: "Change the identifier of a tmux session",
+	Long: `Change the identifier of the tmux session matching the given pattern.
+
+The tmux session is renamed to gwq-<context>-<new-identifier>-<start time>,
+the name 'gwq tmux run' would have given it, and its metadata and captured
+output follow. If multiple sessions match the pattern, an interactive fuzzy
+finder will be shown.`,
+	Example: `  # Rename the session running the tests
+  gwq tmux rename make-test test-suite`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTmuxRename,
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxRenameCmd)
+}
+
+func runTmuxRename(cmd *cobra.Command, args []string) error {
+	pattern, newIdentifier := args[0], strings.TrimSpace(args[1])
+	if newIdentifier == "" {
+		return &usageError{err: fmt.Errorf("new identifier must not be empty")}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sessionManager := tmux.NewSessionManager(nil)
+
+	sessions, err := sessionManager.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	session, err := matchSession(sessions, pattern, cfg)
+	if err != nil {
+		return err
+	}
+
+	newName, err := sessionManager.RenameSession(session, newIdentifier)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Renamed session %s to %s\n", session.SessionName, newName)
+	return nil
+}
@@ -6,10 +6,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/d-kuro/gwq/internal/agent"
 	"github.com/d-kuro/gwq/internal/config"
 	"github.com/d-kuro/gwq/internal/discovery"
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/tasklog"
 	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/internal/utils"
 	"github.com/d-kuro/gwq/internal/worktree"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/spf13/cobra"
@@ -21,6 +24,7 @@ var (
 	tmuxRunContext     string
 	tmuxRunDetach      bool
 	tmuxRunAutoCleanup bool
+	tmuxRunAgent       string
 )
 
 var tmuxRunCmd = &cobra.Command{
@@ -30,7 +34,11 @@ var tmuxRunCmd = &cobra.Command{
 
 Creates a new tmux session and executes the specified command within it.
 By default, the session persists after command completion (tmux native behavior).
-The session can be detached, monitored, and attached to later.`,
+The session can be detached, monitored, and attached to later.
+
+With --agent, the arguments are a prompt for the named coding agent instead
+of a shell command. "claude" is built in; others are configured under
+agents.<name> with an executable and argument templates.`,
 	Example: `  # Run command (session persists after completion)
   gwq tmux run "npm run dev"
 
@@ -47,7 +55,10 @@ The session can be detached, monitored, and attached to later.`,
   gwq tmux run --context build "npm run build"
 
   # Run and stay attached
-  gwq tmux run --no-detach "npm start"`,
+  gwq tmux run --no-detach "npm start"
+
+  # Start a coding agent on a prompt
+  gwq tmux run --agent claude -w feature/auth "add login tests"`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runTmuxRun,
 }
@@ -60,6 +71,7 @@ func init() {
 	tmuxRunCmd.Flags().StringVar(&tmuxRunContext, "context", "", "Context for the session (default: 'run')")
 	tmuxRunCmd.Flags().BoolVar(&tmuxRunDetach, "no-detach", false, "Stay attached to the session after creation")
 	tmuxRunCmd.Flags().BoolVar(&tmuxRunAutoCleanup, "auto-cleanup", false, "Automatically kill session when command completes")
+	tmuxRunCmd.Flags().StringVar(&tmuxRunAgent, "agent", "", "Start the named coding agent with the arguments as its prompt")
 }
 
 func runTmuxRun(cmd *cobra.Command, args []string) error {
@@ -80,17 +92,62 @@ func runTmuxRun(cmd *cobra.Command, args []string) error {
 		context = "run"
 	}
 
+	idSource := command
+	if tmuxRunAgent != "" {
+		a, err := agent.NewDefaultRegistry(cfg).Get(tmuxRunAgent)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		if err := a.HealthCheck(cmd.Context()); err != nil {
+			return err
+		}
+		command, err = agentShellCommand(a, agent.Task{Prompt: command, Worktree: workingDir})
+		if err != nil {
+			return err
+		}
+		if tmuxRunContext == "" {
+			context = a.Name()
+		}
+		idSource = a.Name()
+	}
+
 	identifier := tmuxRunIdentifier
 	if identifier == "" {
 		// Generate identifier from command or working directory
-		identifier = generateIdentifierFromCommand(command, workingDir)
+		identifier = generateIdentifierFromCommand(idSource, workingDir)
 	}
 
-	// Modify command for auto-cleanup if requested
 	finalCommand := command
+
+	// Record the exit status in a sentinel file next to the session logs,
+	// innermost so the other wrappers still see the command's status.
+	exitID := utils.GenerateShortID()
+	exitFile := tmux.ExitCodePath(tmux.DefaultSessionLogDir(), exitID)
+	if err := os.MkdirAll(filepath.Dir(exitFile), 0755); err == nil {
+		finalCommand = wrapExitCodeCommand(finalCommand, exitFile)
+	} else {
+		exitFile = ""
+	}
+
+	// Report the exit status to the task log when it is enabled
+	taskLog, err := taskLogger(cfg)
+	if err != nil {
+		return err
+	}
+	var taskID string
+	if taskLog != nil {
+		taskID = exitID
+		gwqPath, err := os.Executable()
+		if err != nil {
+			gwqPath = "gwq"
+		}
+		finalCommand = wrapTaskCommand(finalCommand, gwqPath, taskLog.Path(), taskID)
+	}
+
+	// Modify command for auto-cleanup if requested
 	if tmuxRunAutoCleanup {
 		// Add a hook to kill the session when the command completes
-		finalCommand = fmt.Sprintf("(%s); tmux kill-session -t $TMUX_PANE", command)
+		finalCommand = fmt.Sprintf("(%s); tmux kill-session -t $TMUX_PANE", finalCommand)
 	}
 
 	sessionManager := tmux.NewSessionManager(nil)
@@ -106,12 +163,36 @@ func runTmuxRun(cmd *cobra.Command, args []string) error {
 			"orig_command": command,
 		},
 	}
+	// Record the worktree so 'gwq status --agent' can match the session.
+	taskWorktree := workingDir
+	if root, err := git.New(workingDir).GetRepositoryPath(); err == nil {
+		opts.Metadata[sessionWorktreeKey] = root
+		taskWorktree = root
+	}
+	if taskID != "" {
+		opts.Metadata[sessionTaskKey] = taskID
+	}
+	if exitFile != "" {
+		opts.Metadata[tmux.MetadataExitFile] = exitFile
+	}
+
+	logTask(taskLog, tasklog.Record{Event: tasklog.EventCreated, TaskID: taskID, Worktree: taskWorktree, Command: command})
 
 	session, err := sessionManager.CreateSession(cmd.Context(), opts)
 	if err != nil {
+		logTask(taskLog, tasklog.Record{Event: tasklog.EventFailed, TaskID: taskID, Worktree: taskWorktree, Command: command, Error: err.Error()})
 		return fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
+	logTask(taskLog, tasklog.Record{
+		Event:      tasklog.EventStarted,
+		TaskID:     taskID,
+		Worktree:   taskWorktree,
+		Session:    session.SessionName,
+		Command:    command,
+		BaseCommit: headCommit(taskWorktree),
+	})
+
 	fmt.Printf("Created tmux session: %s\n", session.SessionName)
 	fmt.Printf("Session ID: %s\n", session.ID)
 	fmt.Printf("Command: %s\n", command)
@@ -135,6 +216,20 @@ func runTmuxRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// agentShellCommand renders a's command line for task as a shell command,
+// quoting every argument so prompts pass through unchanged.
+func agentShellCommand(a agent.Agent, task agent.Task) (string, error) {
+	argv, err := a.Command(task)
+	if err != nil {
+		return "", err
+	}
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = `"` + utils.EscapeForShell(arg) + `"`
+	}
+	return strings.Join(quoted, " "), nil
+}
+
 func determineWorkingDirectory(cfg *models.Config) (string, error) {
 	if tmuxRunWorktree != "" {
 		// Worktree specified - find and validate it
@@ -150,6 +245,10 @@ func determineWorkingDirectory(cfg *models.Config) (string, error) {
 	return cwd, nil
 }
 
+// resolveWorktreePath resolves worktreePattern to a single worktree path: an
+// existing absolute path, a worktree of the current repository whose branch
+// is exactly the pattern, or the only worktree matching it locally or
+// globally.
 func resolveWorktreePath(worktreePattern string, cfg *models.Config) (string, error) {
 	// Try to resolve as exact path first
 	if filepath.IsAbs(worktreePattern) {
@@ -163,6 +262,11 @@ func resolveWorktreePath(worktreePattern string, cfg *models.Config) (string, er
 	if err == nil {
 		// Try to find matching worktree in current repository
 		wm := worktree.New(g, cfg)
+		// An exact branch wins over substring matches, so "feat" is not
+		// ambiguous with "feature".
+		if wt, found, err := wm.GetWorktreeByExactBranch(worktreePattern); err == nil && found {
+			return wt.Path, nil
+		}
 		matches, err := wm.GetMatchingWorktrees(worktreePattern)
 		if err == nil && len(matches) > 0 {
 			if len(matches) == 1 {
@@ -173,14 +277,14 @@ func resolveWorktreePath(worktreePattern string, cfg *models.Config) (string, er
 	}
 
 	// Try global worktree discovery
-	entries, err := discovery.DiscoverGlobalWorktrees(cfg.Worktree.BaseDir)
+	entries, err := discoverGlobalWorktreesMatching(cfg.Worktree.BaseDir, worktreePattern)
 	if err != nil {
 		return "", fmt.Errorf("failed to discover worktrees: %w", err)
 	}
 
 	matches := discovery.FilterGlobalWorktrees(entries, worktreePattern)
 	if len(matches) == 0 {
-		return "", fmt.Errorf("no worktree found matching pattern: %s", worktreePattern)
+		return "", fmt.Errorf("%w matching pattern: %s", worktree.ErrNoWorktreeFound, worktreePattern)
 	}
 
 	if len(matches) > 1 {
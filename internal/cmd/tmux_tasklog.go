@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/tasklog"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// sessionTaskKey is the session metadata key holding the task log ID.
+const sessionTaskKey = "task_id"
+
+var tmuxTaskDoneLog string
+
+// tmuxTaskDoneCmd records the end of a task. 'gwq tmux run' appends it to the
+// session command when task_log is enabled; it is not meant to be run by hand.
+var tmuxTaskDoneCmd = &cobra.Command{
+	Use:    "task-done <task-id> <exit-code>",
+	Short:  "Record the completion of a 'gwq tmux run' task",
+	Hidden: true,
+	Args:   cobra.ExactArgs(2),
+	RunE:   runTmuxTaskDone,
+}
+
+func init() {
+	tmuxCmd.AddCommand(tmuxTaskDoneCmd)
+
+	tmuxTaskDoneCmd.Flags().StringVar(&tmuxTaskDoneLog, "log", "", "Task log file")
+	_ = tmuxTaskDoneCmd.MarkFlagRequired("log")
+}
+
+func runTmuxTaskDone(cmd *cobra.Command, args []string) error {
+	exitCode, err := strconv.Atoi(args[1])
+	if err != nil {
+		return &usageError{err: fmt.Errorf("invalid exit code %q", args[1])}
+	}
+	return finishTask(tasklog.New(tmuxTaskDoneLog), args[0], exitCode)
+}
+
+// finishTask appends the completed or failed record for taskID, listing the
+// files changed in the task's worktree since its session started.
+func finishTask(logger *tasklog.Logger, taskID string, exitCode int) error {
+	var files []string
+	if started, ok, err := logger.Last(taskID, tasklog.EventStarted); err == nil && ok && started.Worktree != "" {
+		files = changedFiles(started.Worktree, started.BaseCommit)
+	}
+	_, err := logger.Finish(taskID, exitCode, files)
+	return err
+}
+
+// taskLogger returns the task logger configured by task_log, or nil when task
+// logging is disabled.
+func taskLogger(cfg *models.Config) (*tasklog.Logger, error) {
+	if !cfg.TaskLog.Enabled {
+		return nil, nil
+	}
+	path := filepath.Join(config.Dir(), tasklog.DefaultFileName)
+	if cfg.TaskLog.Path != "" {
+		expanded, err := utils.ExpandPath(cfg.TaskLog.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task_log.path: %w", err)
+		}
+		path = expanded
+	}
+	return tasklog.New(path), nil
+}
+
+// wrapTaskCommand makes command report its exit status to the task log via
+// 'gwq tmux task-done' once it finishes.
+func wrapTaskCommand(command, gwqPath, logPath, taskID string) string {
+	return fmt.Sprintf(`(%s); "%s" tmux task-done --log "%s" "%s" $?`,
+		command, utils.EscapeForShell(gwqPath), utils.EscapeForShell(logPath), utils.EscapeForShell(taskID))
+}
+
+// headCommit returns HEAD of the repository at dir, or "" outside git.
+func headCommit(dir string) string {
+	out, err := git.New(dir).RunCommand("rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// changedFiles lists files in dir that differ from base, committed or not,
+// plus untracked files. Errors yield what could be collected.
+func changedFiles(dir, base string) []string {
+	g := git.New(dir)
+	var files []string
+
+	diffArgs := []string{"diff", "--name-only"}
+	if base != "" {
+		diffArgs = append(diffArgs, base)
+	} else {
+		diffArgs = append(diffArgs, "HEAD")
+	}
+	for _, args := range [][]string{diffArgs, {"ls-files", "--others", "--exclude-standard"}} {
+		out, err := g.RunCommand(args...)
+		if err != nil {
+			continue
+		}
+		for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+			if line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+// logTask appends r, warning instead of failing: the task log is an audit
+// aid and must not stop a run.
+func logTask(logger *tasklog.Logger, r tasklog.Record) {
+	if logger == nil {
+		return
+	}
+	if err := logger.Append(r); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
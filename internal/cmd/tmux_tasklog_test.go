@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/tasklog"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestTaskLogger(t *testing.T) {
+	if logger, err := taskLogger(&models.Config{}); err != nil || logger != nil {
+		t.Errorf("taskLogger() with logging disabled = %v, %v; want nil", logger, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	logger, err := taskLogger(&models.Config{TaskLog: models.TaskLogConfig{Enabled: true, Path: path}})
+	if err != nil {
+		t.Fatalf("taskLogger() error = %v", err)
+	}
+	if logger.Path() != path {
+		t.Errorf("Path() = %q, want %q", logger.Path(), path)
+	}
+}
+
+func TestFinishTask_ChangedFiles(t *testing.T) {
+	repo := initTestGitRepo(t)
+	base := headCommit(repo)
+	if base == "" {
+		t.Fatal("headCommit() returned empty")
+	}
+
+	// The fake agent commits one file and leaves another untracked.
+	if err := os.WriteFile(filepath.Join(repo, "committed.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"-C", repo, "add", "committed.txt"},
+		{"-C", repo, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-m", "agent"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("y"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := tasklog.New(filepath.Join(t.TempDir(), tasklog.DefaultFileName))
+	if err := logger.Append(tasklog.Record{Event: tasklog.EventStarted, TaskID: "t1", Worktree: repo, BaseCommit: base}); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishTask(logger, "t1", 0); err != nil {
+		t.Fatalf("finishTask() error = %v", err)
+	}
+
+	r, ok, err := logger.Last("t1", tasklog.EventCompleted)
+	if err != nil || !ok {
+		t.Fatalf("no completed record: %v", err)
+	}
+	if want := []string{"committed.txt", "new.txt"}; !slices.Equal(r.FilesChanged, want) {
+		t.Errorf("FilesChanged = %v, want %v", r.FilesChanged, want)
+	}
+}
+
+func TestWrapTaskCommand(t *testing.T) {
+	got := wrapTaskCommand("claude -p 'fix'", "/usr/bin/gwq", "/tmp/tasks.jsonl", "abc")
+	want := `(claude -p 'fix'); "/usr/bin/gwq" tmux task-done --log "/tmp/tasks.jsonl" "abc" $?`
+	if got != want {
+		t.Errorf("wrapTaskCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/config"
+	"github.com/d-kuro/gwq/internal/discovery"
+	"github.com/d-kuro/gwq/internal/ui"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchDebounce is the quiet window used to coalesce bursts of filesystem
+// events (e.g. `git worktree add` creating a directory and then its files).
+const watchDebounce = 100 * time.Millisecond
+
+var watchFormat string
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the base directory for worktree changes",
+	Long: `Watch the configured base directory for worktree creation and removal.
+
+In the default table format the status table of all worktrees is re-rendered
+whenever a worktree appears or disappears. With --format json, one JSON object
+per event is written to stdout, which is suitable for scripting.`,
+	Example: `  # Live status table
+  gwq watch
+
+  # Newline-delimited JSON events
+  gwq watch --format json | jq .`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVar(&watchFormat, "format", "table", "Output format (table, json)")
+}
+
+// watchEvent describes a worktree appearing or disappearing under the base directory.
+type watchEvent struct {
+	Type       string    `json:"type"` // "created" or "removed"
+	Path       string    `json:"path"`
+	Branch     string    `json:"branch"`
+	Repository string    `json:"repository"`
+	Time       time.Time `json:"time"`
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchFormat != "table" && watchFormat != "json" {
+		return fmt.Errorf("invalid format %q: must be table or json", watchFormat)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	baseDir := cfg.Worktree.BaseDir
+	if _, err := os.Stat(baseDir); err != nil {
+		return fmt.Errorf("base directory %s is not accessible: %w", baseDir, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := addWatchTree(watcher, baseDir, baseDir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", baseDir, err)
+	}
+
+	known, err := discoverWatchEntries(baseDir)
+	if err != nil {
+		return err
+	}
+
+	printer := ui.New(&cfg.UI)
+	render := func(entries map[string]*discovery.GlobalWorktreeEntry, events []watchEvent) error {
+		if watchFormat == "json" {
+			return writeWatchEvents(os.Stdout, events)
+		}
+		return renderWatchTable(ctx, cfg, printer, entries)
+	}
+
+	if watchFormat == "table" {
+		// renderWatchTable hides the cursor; show it again on every exit,
+		// including a failure of the first render.
+		defer fmt.Print("\033[?25h")
+	}
+	if err := render(known, nil); err != nil {
+		return err
+	}
+
+	// A nil channel blocks forever, so the timer case is inert until armed.
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if filepath.Base(event.Name) == ".git" {
+					// The directory became a worktree after it was watched;
+					// its contents are not of interest any more.
+					if dir := filepath.Dir(event.Name); dir != baseDir {
+						_ = watcher.Remove(dir)
+					}
+				} else {
+					// New intermediate directories (host/owner/repo) need their own watch.
+					_ = addWatchTree(watcher, baseDir, event.Name)
+				}
+			}
+			debounce = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: watch error: %v\n", err)
+		case <-debounce:
+			debounce = nil
+			current, err := discoverWatchEntries(baseDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			events := diffWorktreeEntries(known, current, time.Now())
+			known = current
+			if len(events) == 0 {
+				continue
+			}
+			if err := render(current, events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// addWatchTree adds watches for root and every directory below it that is not
+// itself a worktree. Worktree contents, such as node_modules or build
+// output, are not watched: creation and removal of a worktree is observed
+// on its parent directory. Only baseDir is watched when it is a worktree.
+func addWatchTree(watcher *fsnotify.Watcher, baseDir, root string) error {
+	if root != baseDir && isWorktreeDir(root) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if path != root && isWorktreeDir(path) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// isWorktreeDir reports whether dir has a .git entry, file or directory.
+func isWorktreeDir(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// discoverWatchEntries returns the worktrees under baseDir keyed by path.
+func discoverWatchEntries(baseDir string) (map[string]*discovery.GlobalWorktreeEntry, error) {
+	entries, err := discovery.DiscoverGlobalWorktrees(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover worktrees: %w", err)
+	}
+
+	byPath := make(map[string]*discovery.GlobalWorktreeEntry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	return byPath, nil
+}
+
+// diffWorktreeEntries returns created/removed events between two discovery
+// snapshots, ordered by path so output is deterministic.
+func diffWorktreeEntries(prev, curr map[string]*discovery.GlobalWorktreeEntry, now time.Time) []watchEvent {
+	var events []watchEvent
+
+	for path, entry := range curr {
+		if _, ok := prev[path]; !ok {
+			events = append(events, newWatchEvent("created", entry, now))
+		}
+	}
+	for path, entry := range prev {
+		if _, ok := curr[path]; !ok {
+			events = append(events, newWatchEvent("removed", entry, now))
+		}
+	}
+
+	slices.SortFunc(events, func(a, b watchEvent) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return events
+}
+
+func newWatchEvent(eventType string, entry *discovery.GlobalWorktreeEntry, now time.Time) watchEvent {
+	repository := entry.RepositoryURL
+	if entry.RepositoryInfo != nil {
+		repository = entry.RepositoryInfo.FullPath
+	}
+	return watchEvent{
+		Type:       eventType,
+		Path:       entry.Path,
+		Branch:     entry.Branch,
+		Repository: repository,
+		Time:       now,
+	}
+}
+
+// writeWatchEvents writes events as newline-delimited JSON.
+func writeWatchEvents(w io.Writer, events []watchEvent) error {
+	encoder := json.NewEncoder(w)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderWatchTable clears the screen and renders the status table for the given worktrees.
+func renderWatchTable(ctx context.Context, cfg *models.Config, printer *ui.Printer, entries map[string]*discovery.GlobalWorktreeEntry) error {
+	worktrees := make([]*models.Worktree, 0, len(entries))
+	for _, entry := range entries {
+		worktrees = append(worktrees, &models.Worktree{
+			Path:       entry.Path,
+			Branch:     entry.Branch,
+			CommitHash: entry.CommitHash,
+			IsMain:     entry.IsMain,
+		})
+	}
+
+	repoTemplate, err := statusRepositoryTemplate(cfg)
+	if err != nil {
+		return err
+	}
+
+	collector := NewStatusCollectorWithOptions(StatusCollectorOptions{
+		BaseDir:            cfg.Worktree.BaseDir,
+		RepositoryTemplate: repoTemplate,
+		ActivitySampleSize: cfg.Status.ActivitySampleSize,
+	})
+	statuses, err := collector.CollectAll(ctx, worktrees)
+	if err != nil {
+		return fmt.Errorf("failed to collect worktree statuses: %w", err)
+	}
+	sortStatuses(statuses, "repo", false)
+
+	fmt.Print("\033[?25l\033[H\033[2J")
+	fmt.Printf("Watching %s - Updated: %s\n\n", cfg.Worktree.BaseDir, time.Now().Format("15:04:05"))
+	if err := outputTable(os.Stdout, statuses, printer, false, false); err != nil {
+		return err
+	}
+	fmt.Println("\n[Press Ctrl+C to exit]")
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/discovery"
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestDiffWorktreeEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := &models.RepositoryInfo{FullPath: "github.com/o/r"}
+
+	prev := map[string]*discovery.GlobalWorktreeEntry{
+		"/wt/a": {Path: "/wt/a", Branch: "a", RepositoryInfo: info},
+		"/wt/b": {Path: "/wt/b", Branch: "b", RepositoryInfo: info},
+	}
+	curr := map[string]*discovery.GlobalWorktreeEntry{
+		"/wt/b": {Path: "/wt/b", Branch: "b", RepositoryInfo: info},
+		"/wt/c": {Path: "/wt/c", Branch: "c", RepositoryURL: "git@example.com:o/r.git"},
+	}
+
+	events := diffWorktreeEntries(prev, curr, now)
+	if len(events) != 2 {
+		t.Fatalf("diffWorktreeEntries() returned %d events, want 2", len(events))
+	}
+
+	want := []watchEvent{
+		{Type: "removed", Path: "/wt/a", Branch: "a", Repository: "github.com/o/r", Time: now},
+		{Type: "created", Path: "/wt/c", Branch: "c", Repository: "git@example.com:o/r.git", Time: now},
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event[%d] = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+
+	if got := diffWorktreeEntries(curr, curr, now); len(got) != 0 {
+		t.Errorf("diffWorktreeEntries() with identical snapshots = %v, want none", got)
+	}
+}
+
+func TestWriteWatchEvents(t *testing.T) {
+	var buf bytes.Buffer
+	events := []watchEvent{
+		{Type: "created", Path: "/wt/a", Branch: "a"},
+		{Type: "removed", Path: "/wt/b", Branch: "b"},
+	}
+
+	if err := writeWatchEvents(&buf, events); err != nil {
+		t.Fatalf("writeWatchEvents() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var got watchEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if got.Type != events[i].Type || got.Path != events[i].Path {
+			t.Errorf("line %d = %+v, want %+v", i, got, events[i])
+		}
+	}
+}
+
+func TestAddWatchTree_SkipsWorktreeContents(t *testing.T) {
+	baseDir := t.TempDir()
+	mkdir := func(parts ...string) string {
+		t.Helper()
+		dir := filepath.Join(append([]string{baseDir}, parts...)...)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	repo := mkdir("github.com", "user", "repo")
+	main := mkdir("github.com", "user", "repo", "main")
+	mkdir("github.com", "user", "repo", "main", ".git", "objects")
+	mkdir("github.com", "user", "repo", "main", "node_modules", "pkg")
+	linked := mkdir("github.com", "user", "repo", "feature")
+	mkdir("github.com", "user", "repo", "feature", "build")
+	if err := os.WriteFile(filepath.Join(linked, ".git"), []byte("gitdir: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := addWatchTree(watcher, baseDir, baseDir); err != nil {
+		t.Fatalf("addWatchTree() error = %v", err)
+	}
+	// A worktree directory that appears later is not watched either.
+	if err := addWatchTree(watcher, baseDir, linked); err != nil {
+		t.Fatalf("addWatchTree(worktree) error = %v", err)
+	}
+
+	got := watcher.WatchList()
+	slices.Sort(got)
+	want := []string{baseDir, filepath.Join(baseDir, "github.com"), filepath.Join(baseDir, "github.com", "user"), repo}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("watched = %v, want %v (not %s or %s)", got, want, main, linked)
+	}
+}
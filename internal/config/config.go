@@ -29,6 +29,12 @@ func getConfigDir() string {
 	return filepath.Join(home, ".config", "gwq")
 }
 
+// Dir returns the directory holding the global configuration and other gwq
+// state files.
+func Dir() string {
+	return getConfigDir()
+}
+
 // getLocalConfigPath returns the path to the local config file if it exists.
 // Returns empty string if no local config is found.
 func getLocalConfigPath() string {
@@ -106,15 +112,20 @@ func mergeLocalConfig(store *TrustStore, prompter trustPrompter, interactive boo
 		return fmt.Errorf("parse local config %s: %w", absPath, err)
 	}
 
-	for _, key := range localViper.AllKeys() {
+	mergeSettings(localViper)
+	return nil
+}
+
+// mergeSettings overrides the global viper with every key set in src,
+// merging repository_settings by repository.
+func mergeSettings(src *viper.Viper) {
+	for _, key := range src.AllKeys() {
 		if key == "repository_settings" {
-			mergeRepositorySettings(localViper)
+			mergeRepositorySettings(src)
 		} else {
-			viper.Set(key, localViper.Get(key))
+			viper.Set(key, src.Get(key))
 		}
 	}
-
-	return nil
 }
 
 // mergeRepositorySettings merges repository_settings from local config into global config.
@@ -173,9 +184,12 @@ func Init() error {
 	viper.SetDefault("cd.auto_cd_on_add", false)
 	viper.SetDefault("worktree.basedir", "~/worktrees")
 	viper.SetDefault("worktree.auto_mkdir", true)
+	viper.SetDefault("worktree.track_remote_if_exists", true)
 	viper.SetDefault("finder.preview", true)
+	viper.SetDefault("finder.sort_by", "name")
 	viper.SetDefault("ui.icons", true)
 	viper.SetDefault("ui.tilde_home", true)
+	viper.SetDefault("status.activity_sample_size", 100)
 
 	// Naming defaults
 	viper.SetDefault("naming.template", "{{.Host}}/{{.Owner}}/{{.Repository}}/{{.Branch}}")
@@ -197,6 +211,8 @@ func Init() error {
 		}
 	}
 
+	applyActiveProfile()
+
 	// Local config is untrusted: the prompter gates its loading so a cloned
 	// repository cannot auto-execute setup_commands. The trust store is loaded
 	// lazily inside mergeLocalConfig — no disk read when .gwq.toml is absent.
@@ -269,8 +285,7 @@ func SetGlobal(key string, value any) error {
 	_ = globalViper.ReadInConfig()
 	globalViper.Set(key, value)
 
-	configPath := filepath.Join(getConfigDir(), configName+"."+configType)
-	if err := globalViper.WriteConfigAs(configPath); err != nil {
+	if err := globalViper.WriteConfigAs(globalConfigFile()); err != nil {
 		return err
 	}
 
@@ -281,13 +296,11 @@ func SetGlobal(key string, value any) error {
 
 // SetLocal sets a configuration value and writes to the local config file (.gwq.toml).
 func SetLocal(key string, value any) error {
-	cwd, err := os.Getwd()
+	localConfigPath, err := localConfigFile()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
 
-	localConfigPath := filepath.Join(cwd, localConfigName+"."+configType)
-
 	localViper := viper.New()
 	localViper.SetConfigFile(localConfigPath)
 	localViper.SetConfigType(configType)
@@ -304,6 +317,89 @@ func SetLocal(key string, value any) error {
 	return nil
 }
 
+// SetPlan describes what SetGlobal or SetLocal would change, for a dry run.
+type SetPlan struct {
+	Key       string
+	Path      string // Config file that would be written
+	Local     bool   // Whether Path is the local .gwq.toml
+	Exists    bool   // Whether Path exists yet
+	Current   any    // Value of Key in Path, nil if unset there
+	Proposed  any
+	Effective any // Value of Key after merging all config sources
+	// ShadowedBy names the source that overrides a global write of Key
+	// (the active profile or the local config), or is empty.
+	ShadowedBy string
+}
+
+// PlanSet reports what setting key to value would change in the global
+// config, or in the local config when local is true, without writing.
+func PlanSet(key string, value any, local bool) (*SetPlan, error) {
+	path := globalConfigFile()
+	if local {
+		var err error
+		if path, err = localConfigFile(); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &SetPlan{
+		Key:       key,
+		Path:      path,
+		Local:     local,
+		Proposed:  value,
+		Effective: viper.Get(key),
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		plan.Exists = true
+		target := viper.New()
+		target.SetConfigFile(path)
+		target.SetConfigType(configType)
+		if err := target.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if target.IsSet(key) {
+			plan.Current = target.Get(key)
+		}
+	}
+
+	if !local {
+		plan.ShadowedBy = globalShadow(key)
+	}
+	return plan, nil
+}
+
+// globalShadow returns which source overrides key in the global config:
+// the active profile or a local .gwq.toml in the current directory.
+func globalShadow(key string) string {
+	if name := ActiveProfile(); name != "" && viper.IsSet(profilesKey+"."+name+"."+key) {
+		return fmt.Sprintf("profile %q", name)
+	}
+	if localPath := getLocalConfigPath(); localPath != "" {
+		localViper := viper.New()
+		localViper.SetConfigFile(localPath)
+		localViper.SetConfigType(configType)
+		if localViper.ReadInConfig() == nil && localViper.IsSet(key) {
+			return localPath
+		}
+	}
+	return ""
+}
+
+// globalConfigFile returns the path SetGlobal writes to.
+func globalConfigFile() string {
+	return filepath.Join(getConfigDir(), configName+"."+configType)
+}
+
+// localConfigFile returns the path SetLocal writes to.
+func localConfigFile() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return filepath.Join(cwd, localConfigName+"."+configType), nil
+}
+
 // Set sets a configuration value (defaults to global).
 func Set(key string, value any) error {
 	return SetGlobal(key, value)
@@ -85,6 +85,38 @@ setup_commands = ["touch bar"]
 	}
 }
 
+func TestRepositorySettingsTeardownCommandsParsing(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(func() { viper.Reset() })
+	viper.SetConfigType("toml")
+	configTOML := `
+[[repository_settings]]
+repository = "/tmp/repository1"
+setup_commands = ["docker compose up -d"]
+teardown_commands = ["docker compose down", "rm -rf tmp/cache"]
+
+[[repository_settings]]
+repository = "/tmp/repository2"
+setup_commands = ["touch bar"]
+`
+	if err := viper.ReadConfig(strings.NewReader(configTOML)); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.RepositorySettings) != 2 {
+		t.Fatalf("Expected 2 repository_settings, got %d", len(cfg.RepositorySettings))
+	}
+	if got := cfg.RepositorySettings[0].TeardownCommands; len(got) != 2 || got[0] != "docker compose down" || got[1] != "rm -rf tmp/cache" {
+		t.Errorf("First repository teardown_commands mismatch: %+v", got)
+	}
+	if got := cfg.RepositorySettings[1].TeardownCommands; len(got) != 0 {
+		t.Errorf("Second repository teardown_commands should be empty, got %+v", got)
+	}
+}
+
 func TestRepositorySettingsGlobPatternPreserved(t *testing.T) {
 	viper.Reset()
 	t.Cleanup(func() { viper.Reset() })
@@ -1064,3 +1096,90 @@ copy_files = [".env.evil"]
 		}
 	})
 }
+
+func TestPlanSet(t *testing.T) {
+	globalPath := setupProfileHome(t, `
+[finder]
+preview = true
+
+[ui]
+icons = true
+`)
+	localDir := t.TempDir()
+	localPath, _ := writeLocalConfig(t, localDir, []byte("[finder]\npreview = false\n"))
+	globalBefore, err := os.ReadFile(globalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("global", func(t *testing.T) {
+		plan, err := PlanSet("ui.icons", false, false)
+		if err != nil {
+			t.Fatalf("PlanSet() error = %v", err)
+		}
+		if plan.Path != globalPath || plan.Local || !plan.Exists {
+			t.Errorf("PlanSet() target = %q (local %v, exists %v), want existing %q", plan.Path, plan.Local, plan.Exists, globalPath)
+		}
+		if plan.Current != true || plan.Proposed != false || plan.ShadowedBy != "" {
+			t.Errorf("PlanSet() = %+v, want true -> false without shadowing", plan)
+		}
+	})
+
+	t.Run("global shadowed by local", func(t *testing.T) {
+		plan, err := PlanSet("finder.preview", true, false)
+		if err != nil {
+			t.Fatalf("PlanSet() error = %v", err)
+		}
+		if plan.ShadowedBy != localPath {
+			t.Errorf("PlanSet() ShadowedBy = %q, want %q", plan.ShadowedBy, localPath)
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		plan, err := PlanSet("ui.icons", false, true)
+		if err != nil {
+			t.Fatalf("PlanSet() error = %v", err)
+		}
+		if plan.Path != localPath || !plan.Local || plan.Current != nil {
+			t.Errorf("PlanSet() = %+v, want %s with ui.icons unset", plan, localPath)
+		}
+	})
+
+	globalAfter, err := os.ReadFile(globalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(globalAfter) != string(globalBefore) {
+		t.Errorf("PlanSet() modified the global config:\n%s", globalAfter)
+	}
+	localAfter, err := os.ReadFile(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(localAfter) != "[finder]\npreview = false\n" {
+		t.Errorf("PlanSet() modified the local config:\n%s", localAfter)
+	}
+	if viper.GetBool("ui.icons") != true {
+		t.Error("PlanSet() must not change the loaded configuration")
+	}
+}
+
+func TestPlanSet_NewLocalFile(t *testing.T) {
+	setupProfileHome(t, "")
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeDir(t, dir)
+
+	plan, err := PlanSet("finder.preview", false, true)
+	if err != nil {
+		t.Fatalf("PlanSet() error = %v", err)
+	}
+	if plan.Exists || plan.Path != filepath.Join(dir, ".gwq.toml") {
+		t.Errorf("PlanSet() = %+v, want a new .gwq.toml in %s", plan, dir)
+	}
+	if _, err := os.Stat(plan.Path); !os.IsNotExist(err) {
+		t.Errorf("PlanSet() created %s", plan.Path)
+	}
+}
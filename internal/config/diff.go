@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Kinds of SettingChange.
+const (
+	ChangeAdded   = "added"   // set locally, not in the global config
+	ChangeRemoved = "removed" // dropped by a local repository_settings entry
+	ChangeChanged = "changed" // set in both with different values
+)
+
+// SettingChange is one setting that the local config makes different from
+// the global config.
+type SettingChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	Global any    `json:"global,omitempty"`
+	Local  any    `json:"local,omitempty"`
+}
+
+// LocalDiff describes how the local .gwq.toml differs from the global config.
+type LocalDiff struct {
+	GlobalPath string          `json:"global_path"`
+	LocalPath  string          `json:"local_path,omitempty"`
+	Changes    []SettingChange `json:"changes"`
+}
+
+// DiffLocal compares the global config file with the local .gwq.toml in the
+// current directory. Both files are read as written, without defaults and
+// regardless of whether the local file is trusted. LocalPath is empty when
+// there is no local config.
+func DiffLocal() (*LocalDiff, error) {
+	diff := &LocalDiff{
+		GlobalPath: filepath.Join(getConfigDir(), configName+"."+configType),
+		Changes:    []SettingChange{},
+	}
+
+	globalViper := viper.New()
+	globalViper.SetConfigFile(diff.GlobalPath)
+	globalViper.SetConfigType(configType)
+	if err := globalViper.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read global config %s: %w", diff.GlobalPath, err)
+	}
+
+	localPath := getLocalConfigPath()
+	if localPath == "" {
+		return diff, nil
+	}
+	diff.LocalPath = localPath
+
+	localViper := viper.New()
+	localViper.SetConfigFile(localPath)
+	localViper.SetConfigType(configType)
+	if err := localViper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read local config %s: %w", localPath, err)
+	}
+
+	diff.Changes = DiffSettings(globalViper.AllSettings(), localViper.AllSettings())
+	return diff, nil
+}
+
+// DiffSettings lists what local changes relative to global, as merged by
+// mergeLocalConfig: every local key overrides the global one, and a local
+// repository_settings entry replaces the global entry with the same
+// repository. Keys only present globally are inherited and not reported,
+// except for fields missing from a replacing repository_settings entry.
+// Changes are sorted by key.
+func DiffSettings(global, local map[string]any) []SettingChange {
+	globalFlat := make(map[string]any)
+	flattenSettings("", withoutRepositorySettings(global), globalFlat)
+	localFlat := make(map[string]any)
+	flattenSettings("", withoutRepositorySettings(local), localFlat)
+
+	changes := []SettingChange{}
+	for key, lv := range localFlat {
+		gv, ok := globalFlat[key]
+		switch {
+		case !ok:
+			changes = append(changes, SettingChange{Key: key, Change: ChangeAdded, Local: lv})
+		case !reflect.DeepEqual(gv, lv):
+			changes = append(changes, SettingChange{Key: key, Change: ChangeChanged, Global: gv, Local: lv})
+		}
+	}
+
+	globalRepos := repositorySettingsByRepository(global)
+	for repo, lfields := range repositorySettingsByRepository(local) {
+		prefix := fmt.Sprintf("repository_settings[%s]", repo)
+		gfields, ok := globalRepos[repo]
+		if !ok {
+			for field, lv := range lfields {
+				changes = append(changes, SettingChange{Key: prefix + "." + field, Change: ChangeAdded, Local: lv})
+			}
+			continue
+		}
+		for field, lv := range lfields {
+			gv, ok := gfields[field]
+			switch {
+			case !ok:
+				changes = append(changes, SettingChange{Key: prefix + "." + field, Change: ChangeAdded, Local: lv})
+			case !reflect.DeepEqual(gv, lv):
+				changes = append(changes, SettingChange{Key: prefix + "." + field, Change: ChangeChanged, Global: gv, Local: lv})
+			}
+		}
+		for field, gv := range gfields {
+			if _, ok := lfields[field]; !ok {
+				changes = append(changes, SettingChange{Key: prefix + "." + field, Change: ChangeRemoved, Global: gv})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b SettingChange) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
+
+// flattenSettings stores every leaf of settings in out under its dotted key.
+func flattenSettings(prefix string, settings map[string]any, out map[string]any) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenSettings(key, nested, out)
+			continue
+		}
+		out[key] = value
+	}
+}
+
+func withoutRepositorySettings(settings map[string]any) map[string]any {
+	rest := make(map[string]any, len(settings))
+	for key, value := range settings {
+		if key != "repository_settings" {
+			rest[key] = value
+		}
+	}
+	return rest
+}
+
+// repositorySettingsByRepository indexes the flattened fields of each
+// repository_settings entry, except repository itself, by that field.
+// Entries without a repository are ignored, as they never match.
+func repositorySettingsByRepository(settings map[string]any) map[string]map[string]any {
+	entries, _ := settings["repository_settings"].([]any)
+	byRepo := make(map[string]map[string]any, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		repo, _ := fields["repository"].(string)
+		if repo == "" {
+			continue
+		}
+		rest := make(map[string]any, len(fields))
+		for field, value := range fields {
+			if field != "repository" {
+				rest[field] = value
+			}
+		}
+		flat := make(map[string]any)
+		flattenSettings("", rest, flat)
+		byRepo[repo] = flat
+	}
+	return byRepo
+}
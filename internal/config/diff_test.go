@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func readTestSettings(t *testing.T, content string) map[string]any {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("toml")
+	if err := v.ReadConfig(strings.NewReader(content)); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	return v.AllSettings()
+}
+
+func TestDiffSettings(t *testing.T) {
+	global := readTestSettings(t, `
+[worktree]
+basedir = "~/worktrees"
+
+[finder]
+preview = true
+
+[naming]
+sanitize_chars = { "/" = "-" }
+
+[[repository_settings]]
+repository = "~/src/app"
+copy_files = [".env"]
+setup_commands = ["npm install"]
+
+[[repository_settings]]
+repository = "~/src/untouched"
+setup_commands = ["make"]
+`)
+	local := readTestSettings(t, `
+[worktree]
+basedir = "~/worktrees"
+
+[finder]
+preview = false
+
+[naming]
+template = "{{.Repository}}/{{.Branch}}"
+sanitize_chars = { "/" = "_" }
+
+[[repository_settings]]
+repository = "~/src/app"
+setup_commands = ["pnpm install"]
+
+[[repository_settings]]
+repository = "~/src/new"
+copy_files = ["config.json"]
+`)
+
+	got := DiffSettings(global, local)
+	want := []SettingChange{
+		{Key: "finder.preview", Change: ChangeChanged, Global: true, Local: false},
+		{Key: "naming.sanitize_chars./", Change: ChangeChanged, Global: "-", Local: "_"},
+		{Key: "naming.template", Change: ChangeAdded, Local: "{{.Repository}}/{{.Branch}}"},
+		{Key: "repository_settings[~/src/app].copy_files", Change: ChangeRemoved, Global: []any{".env"}},
+		{Key: "repository_settings[~/src/app].setup_commands", Change: ChangeChanged, Global: []any{"npm install"}, Local: []any{"pnpm install"}},
+		{Key: "repository_settings[~/src/new].copy_files", Change: ChangeAdded, Local: []any{"config.json"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffSettings() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffSettingsIdentical(t *testing.T) {
+	settings := readTestSettings(t, `
+[finder]
+preview = true
+`)
+	if got := DiffSettings(settings, settings); len(got) != 0 {
+		t.Errorf("DiffSettings() = %+v, want no changes", got)
+	}
+}
+
+func TestDiffLocal(t *testing.T) {
+	tmpDir := t.TempDir()
+	homeDir := filepath.Join(tmpDir, "home")
+	localDir := filepath.Join(tmpDir, "local")
+	configDir := filepath.Join(homeDir, ".config", "gwq")
+	for _, dir := range []string{configDir, localDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	changeDir(t, localDir)
+
+	diff, err := DiffLocal()
+	if err != nil {
+		t.Fatalf("DiffLocal() without configs error = %v", err)
+	}
+	if diff.LocalPath != "" || len(diff.Changes) != 0 {
+		t.Errorf("DiffLocal() without local config = %+v, want no local path and no changes", diff)
+	}
+
+	globalPath := filepath.Join(configDir, "config.toml")
+	if err := os.WriteFile(globalPath, []byte("[finder]\npreview = true\n"), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, ".gwq.toml"), []byte("[finder]\npreview = false\n"), 0644); err != nil {
+		t.Fatalf("Failed to write local config: %v", err)
+	}
+
+	diff, err = DiffLocal()
+	if err != nil {
+		t.Fatalf("DiffLocal() error = %v", err)
+	}
+	if diff.GlobalPath != globalPath {
+		t.Errorf("GlobalPath = %q, want %q", diff.GlobalPath, globalPath)
+	}
+	if diff.LocalPath == "" {
+		t.Error("LocalPath is empty, want the local config path")
+	}
+	want := []SettingChange{{Key: "finder.preview", Change: ChangeChanged, Global: true, Local: false}}
+	if !reflect.DeepEqual(diff.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", diff.Changes, want)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/viper"
+)
+
+const (
+	activeProfileKey = "active_profile"
+	profilesKey      = "profiles"
+)
+
+// profileNamePattern restricts profile names to what can be used as a single
+// viper key segment.
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// applyActiveProfile overrides the global config with the settings of the
+// active profile, merging repository_settings the same way as the local
+// config. It runs before the local config is merged so .gwq.toml still wins.
+func applyActiveProfile() {
+	name := viper.GetString(activeProfileKey)
+	if name == "" {
+		return
+	}
+	profile := viper.Sub(profilesKey + "." + name)
+	if profile == nil {
+		fmt.Fprintf(os.Stderr, "gwq: active profile %q is not defined, ignoring it\n", name)
+		return
+	}
+	mergeSettings(profile)
+}
+
+// ActiveProfile returns the name of the active profile, or "" if none.
+func ActiveProfile() string {
+	return viper.GetString(activeProfileKey)
+}
+
+// ProfileNames returns the names of all defined profiles, sorted.
+func ProfileNames() []string {
+	var names []string
+	for name := range viper.GetStringMap(profilesKey) {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// UseProfile makes name the active profile in the global config. An empty
+// name deactivates profiles.
+func UseProfile(name string) error {
+	if name == "" {
+		return SetGlobal(activeProfileKey, "")
+	}
+	name, err := normalizeProfileName(name)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(ProfileNames(), name) {
+		return fmt.Errorf("profile %q is not defined - use 'gwq config profile list' to see available profiles", name)
+	}
+	return SetGlobal(activeProfileKey, name)
+}
+
+// CreateProfile saves the current effective configuration, including any
+// active profile and local overrides, as a new profile in the global config.
+func CreateProfile(name string) error {
+	name, err := normalizeProfileName(name)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ProfileNames(), name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return SetGlobal(profilesKey+"."+name, profileSettings(viper.AllSettings()))
+}
+
+// profileSettings returns settings without the profile bookkeeping keys,
+// with merged repository_settings converted back to their TOML form.
+func profileSettings(settings map[string]any) map[string]any {
+	out := make(map[string]any, len(settings))
+	for key, value := range settings {
+		switch key {
+		case activeProfileKey, profilesKey:
+			continue
+		case "repository_settings":
+			if rs, ok := value.([]models.RepositorySetting); ok {
+				value = repositorySettingsToMaps(rs)
+			}
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// repositorySettingsToMaps converts settings stored by mergeRepositorySettings
+// to maps keyed like the config file.
+func repositorySettingsToMaps(settings []models.RepositorySetting) []map[string]any {
+	out := make([]map[string]any, 0, len(settings))
+	for _, s := range settings {
+		m := map[string]any{"repository": s.Repository}
+		if len(s.SetupCommands) > 0 {
+			m["setup_commands"] = s.SetupCommands
+		}
+		if len(s.TeardownCommands) > 0 {
+			m["teardown_commands"] = s.TeardownCommands
+		}
+		if len(s.CopyFiles) > 0 {
+			m["copy_files"] = s.CopyFiles
+		}
+		if s.BaseDir != "" {
+			m["basedir"] = s.BaseDir
+		}
+		if len(s.EnvVars) > 0 {
+			m["env_vars"] = s.EnvVars
+		}
+		if s.AutoDotenv {
+			m["auto_dotenv"] = s.AutoDotenv
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// normalizeProfileName lower-cases name, since viper keys are
+// case-insensitive, and rejects names that are not a single key segment.
+func normalizeProfileName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !profileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	return name, nil
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/spf13/viper"
+)
+
+// setupProfileHome isolates HOME, writes globalConfig as the global config
+// file and loads it into the global viper.
+func setupProfileHome(t *testing.T, globalConfig string) string {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(func() { viper.Reset() })
+
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	configDir := filepath.Join(homeDir, ".config", "gwq")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	configPath := filepath.Join(configDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(globalConfig), 0644); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+
+	viper.SetConfigType("toml")
+	viper.SetConfigFile(configPath)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read global config: %v", err)
+	}
+	return configPath
+}
+
+func TestApplyActiveProfile(t *testing.T) {
+	setupProfileHome(t, `
+active_profile = "work"
+
+[worktree]
+basedir = "~/worktrees"
+auto_mkdir = true
+
+[finder]
+preview = true
+
+[[repository_settings]]
+repository = "~/src/app"
+setup_commands = ["npm install"]
+
+[[repository_settings]]
+repository = "~/src/other"
+copy_files = [".env"]
+
+[profiles.work.worktree]
+basedir = "~/work/worktrees"
+
+[profiles.work.finder]
+preview = false
+
+[[profiles.work.repository_settings]]
+repository = "~/src/app"
+setup_commands = ["pnpm install"]
+
+[profiles.personal.worktree]
+basedir = "~/personal"
+`)
+
+	applyActiveProfile()
+
+	if got := viper.GetString("worktree.basedir"); got != "~/work/worktrees" {
+		t.Errorf("worktree.basedir = %q, want the profile value", got)
+	}
+	if viper.GetBool("finder.preview") {
+		t.Error("finder.preview = true, want the profile value false")
+	}
+	if !viper.GetBool("worktree.auto_mkdir") {
+		t.Error("worktree.auto_mkdir should be kept from the base config")
+	}
+
+	var settings []models.RepositorySetting
+	if err := viper.UnmarshalKey("repository_settings", &settings); err != nil {
+		t.Fatalf("UnmarshalKey() error = %v", err)
+	}
+	if len(settings) != 2 {
+		t.Fatalf("repository_settings = %+v, want 2 entries", settings)
+	}
+	if settings[0].Repository != "~/src/app" || !slices.Equal(settings[0].SetupCommands, []string{"pnpm install"}) {
+		t.Errorf("repository_settings[0] = %+v, want the profile's ~/src/app entry", settings[0])
+	}
+	if settings[1].Repository != "~/src/other" {
+		t.Errorf("repository_settings[1] = %+v, want the base ~/src/other entry", settings[1])
+	}
+}
+
+func TestApplyActiveProfile_Undefined(t *testing.T) {
+	setupProfileHome(t, `
+active_profile = "missing"
+
+[finder]
+preview = true
+`)
+
+	applyActiveProfile()
+
+	if !viper.GetBool("finder.preview") {
+		t.Error("an undefined profile should leave the config unchanged")
+	}
+}
+
+func TestProfileNamesAndUse(t *testing.T) {
+	configPath := setupProfileHome(t, `
+[profiles.work.finder]
+preview = false
+
+[profiles.personal.finder]
+preview = true
+`)
+
+	if got, want := ProfileNames(), []string{"personal", "work"}; !slices.Equal(got, want) {
+		t.Errorf("ProfileNames() = %v, want %v", got, want)
+	}
+	if got := ActiveProfile(); got != "" {
+		t.Errorf("ActiveProfile() = %q, want none", got)
+	}
+
+	if err := UseProfile("Work"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+	if got := ActiveProfile(); got != "work" {
+		t.Errorf("ActiveProfile() = %q, want work", got)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "active_profile = 'work'") {
+		t.Errorf("global config does not record the active profile:\n%s", data)
+	}
+
+	if err := UseProfile("unknown"); err == nil {
+		t.Error("UseProfile(unknown) error = nil, want an undefined profile error")
+	}
+	if err := UseProfile("bad.name"); err == nil {
+		t.Error("UseProfile(bad.name) error = nil, want an invalid name error")
+	}
+}
+
+func TestCreateProfile(t *testing.T) {
+	configPath := setupProfileHome(t, `
+[worktree]
+basedir = "~/worktrees"
+
+[[repository_settings]]
+repository = "~/src/app"
+setup_commands = ["npm install"]
+`)
+	// Simulate a local config merge, which stores repository_settings as structs.
+	viper.Set("repository_settings", []models.RepositorySetting{{Repository: "~/src/app", SetupCommands: []string{"make"}}})
+	viper.Set("finder.preview", false)
+
+	if err := CreateProfile("snapshot"); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if err := CreateProfile("snapshot"); err == nil {
+		t.Error("CreateProfile() for an existing profile error = nil, want an error")
+	}
+
+	saved := viper.New()
+	saved.SetConfigFile(configPath)
+	if err := saved.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read global config: %v", err)
+	}
+	if got := saved.GetString("profiles.snapshot.worktree.basedir"); got != "~/worktrees" {
+		t.Errorf("profiles.snapshot.worktree.basedir = %q, want ~/worktrees", got)
+	}
+	if saved.GetBool("profiles.snapshot.finder.preview") {
+		t.Error("profiles.snapshot.finder.preview should be the effective value false")
+	}
+	var settings []models.RepositorySetting
+	if err := saved.UnmarshalKey("profiles.snapshot.repository_settings", &settings); err != nil {
+		t.Fatalf("UnmarshalKey() error = %v", err)
+	}
+	if len(settings) != 1 || settings[0].Repository != "~/src/app" || !slices.Equal(settings[0].SetupCommands, []string{"make"}) {
+		t.Errorf("profiles.snapshot.repository_settings = %+v, want the effective entry", settings)
+	}
+	if saved.IsSet("profiles.snapshot.profiles") {
+		t.Error("a profile must not contain other profiles")
+	}
+}
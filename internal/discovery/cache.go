@@ -0,0 +1,267 @@
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	cacheFilename = "discovery-cache.json"
+	cacheVersion  = 3 // Bump when the layout of cached entries changes
+)
+
+// cacheFile is the on-disk layout of the discovery cache. A single file holds
+// one record per base directory so switching basedir does not thrash the cache.
+type cacheFile struct {
+	Version int                    `json:"version"`
+	Records map[string]cacheRecord `json:"records"` // keyed by expanded base directory
+}
+
+// cacheRecord is the cached discovery result for one base directory.
+type cacheRecord struct {
+	BaseModTime time.Time             `json:"base_mod_time"`
+	ListingHash string                `json:"listing_hash"`
+	Worktrees   []cachedWorktreeEntry `json:"worktrees"`
+}
+
+// cachedWorktreeEntry pairs an entry with the .git mtime and HEAD stamp it
+// was extracted at, so a single worktree can be refreshed without
+// rescanning the rest.
+type cachedWorktreeEntry struct {
+	GitModTime time.Time            `json:"git_mod_time"`
+	HeadStamp  string               `json:"head_stamp"`
+	Entry      *GlobalWorktreeEntry `json:"entry"`
+}
+
+// fresh reports whether w still describes candidate c, whose HEAD stamp is
+// stamp. An empty stamp could not be computed and never matches.
+func (w cachedWorktreeEntry) fresh(c worktreeCandidate, stamp string) bool {
+	return stamp != "" && w.HeadStamp == stamp && w.GitModTime.Equal(c.GitModTime)
+}
+
+// DiscoverGlobalWorktreesCached behaves like DiscoverGlobalWorktrees but reuses
+// a persistent cache when the base directory has not changed. The cache is
+// invalidated when the base directory mtime or the set of discovered worktree
+// directories changes; individual entries are re-extracted when their .git
+// file or directory, HEAD, or checked-out branch ref changes. Cache read
+// and write failures are never fatal.
+func DiscoverGlobalWorktreesCached(baseDir string, opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+	return DiscoverGlobalWorktreesCachedContext(context.Background(), baseDir, opts)
+}
+
+// DiscoverGlobalWorktreesCachedContext is DiscoverGlobalWorktreesCached
+// with cancellation: the walk and the extraction of uncached entries, which
+// runs on a pool of workers like DiscoverGlobalWorktreesParallelContext,
+// stop promptly when ctx is cancelled, and ctx.Err() is returned without
+// touching the cache.
+func DiscoverGlobalWorktreesCachedContext(ctx context.Context, baseDir string, opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+	if opts == nil {
+		opts = &DiscoverOptions{}
+	}
+
+	baseDir, err := resolveBaseDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	baseInfo, err := os.Stat(baseDir)
+	if os.IsNotExist(err) {
+		return []*GlobalWorktreeEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat base directory: %w", err)
+	}
+
+	logger := opts.logger()
+	candidates, err := collectWorktreePathsContext(ctx, baseDir, "", logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath := opts.CachePath
+	if cachePath == "" {
+		cachePath = defaultCachePath()
+	}
+
+	cache := loadCache(cachePath)
+	record := cacheRecord{
+		BaseModTime: baseInfo.ModTime(),
+		ListingHash: hashCandidates(candidates),
+	}
+
+	// Reuse per-worktree entries only when the overall layout is unchanged.
+	cached := map[string]cachedWorktreeEntry{}
+	if prev, ok := cache.Records[baseDir]; ok && !opts.NoCache &&
+		prev.BaseModTime.Equal(record.BaseModTime) && prev.ListingHash == record.ListingHash {
+		for _, w := range prev.Worktrees {
+			if w.Entry != nil {
+				cached[w.Entry.Path] = w
+			}
+		}
+	}
+
+	// The listing hash always covers the full walk; the pattern only limits
+	// which entries are extracted (and therefore cached) on this run.
+	stamps := make([]string, len(candidates))
+	fresh := make([]*cachedWorktreeEntry, len(candidates))
+	var stale []worktreeCandidate
+	var staleIdx []int
+	for i, c := range candidates {
+		stamps[i] = headStamp(c.Path)
+		if w, ok := cached[c.Path]; ok && w.fresh(c, stamps[i]) {
+			fresh[i] = &w
+			continue
+		}
+		if pathMayMatch(baseDir, c.Path, opts.Pattern) {
+			stale = append(stale, c)
+			staleIdx = append(staleIdx, i)
+		}
+	}
+
+	extracted, err := extractCandidatesContext(ctx, stale, newRepoURLCache(), logger)
+	if err != nil {
+		return nil, err
+	}
+	for j, entry := range extracted {
+		if entry != nil {
+			i := staleIdx[j]
+			fresh[i] = &cachedWorktreeEntry{GitModTime: candidates[i].GitModTime, HeadStamp: stamps[i], Entry: entry}
+		}
+	}
+
+	var entries []*GlobalWorktreeEntry
+	for i, c := range candidates {
+		if fresh[i] == nil {
+			continue
+		}
+		record.Worktrees = append(record.Worktrees, *fresh[i])
+		if pathMayMatch(baseDir, c.Path, opts.Pattern) {
+			entries = append(entries, fresh[i].Entry)
+		}
+	}
+
+	cache.Records[baseDir] = record
+	if err := saveCache(cachePath, cache); err != nil {
+		logger.Warn("failed to write discovery cache", "path", cachePath, "error", err)
+	}
+
+	if entries == nil {
+		entries = []*GlobalWorktreeEntry{}
+	}
+	flagBranchDirMismatches(entries, baseDir, opts.Naming)
+	return entries, nil
+}
+
+// headStamp fingerprints what a commit or branch switch in the worktree at
+// worktreePath changes: its HEAD file, the loose ref of the checked-out
+// branch, packed-refs and, for reftable repositories, the table list. A
+// linked worktree's .git file is untouched by both, so its mtime alone
+// cannot tell. It returns "" when HEAD cannot be read.
+func headStamp(worktreePath string) string {
+	gitDir, err := resolveGitDir(worktreePath)
+	if err != nil {
+		return ""
+	}
+	headPath := filepath.Join(gitDir, "HEAD")
+	head, err := os.ReadFile(headPath)
+	if err != nil {
+		return ""
+	}
+
+	mainGitDir := resolveCommonDir(gitDir)
+	paths := []string{
+		headPath,
+		filepath.Join(mainGitDir, "packed-refs"),
+		filepath.Join(mainGitDir, "reftable", "tables.list"),
+	}
+	if branch, _, err := parseBranchOrCommitFromHead(string(head)); err == nil && branch != "" && isSafeBranchName(branch) {
+		paths = append(paths, filepath.Join(mainGitDir, "refs", "heads", filepath.FromSlash(branch)))
+	}
+
+	h := sha256.New()
+	h.Write(head)
+	for _, p := range paths {
+		// A missing file is part of the state too: the ref may be packed.
+		if info, err := os.Stat(p); err == nil {
+			_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", p, info.ModTime().UnixNano(), info.Size())
+		} else {
+			_, _ = fmt.Fprintf(h, "%s\x00-\n", p)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// defaultCachePath returns the discovery cache location under the gwq config dir.
+func defaultCachePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "gwq", cacheFilename)
+}
+
+// hashCandidates fingerprints the set of worktree directories found by the walk.
+func hashCandidates(candidates []worktreeCandidate) string {
+	h := sha256.New()
+	for _, c := range candidates {
+		_, _ = fmt.Fprintf(h, "%s\x00%t\n", c.Path, c.IsMain)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCache reads the cache file. A missing, corrupt, or outdated file yields
+// an empty cache so discovery falls back to a full scan.
+func loadCache(path string) *cacheFile {
+	empty := &cacheFile{Version: cacheVersion, Records: map[string]cacheRecord{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return empty
+	}
+
+	var cf cacheFile
+	if err := json.Unmarshal(data, &cf); err != nil || cf.Version != cacheVersion || cf.Records == nil {
+		return empty
+	}
+	return &cf
+}
+
+// saveCache writes the cache atomically via a temp file and rename.
+func saveCache(path string, cache *cacheFile) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshal cache: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create cache temp: %w", err)
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write cache temp: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("close cache temp: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename cache: %w", err)
+	}
+	return nil
+}
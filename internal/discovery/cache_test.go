@@ -0,0 +1,240 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupCachedBaseDir creates a base directory with one main worktree and
+// returns the base directory, the repository directory, and a cache path.
+func setupCachedBaseDir(t *testing.T) (string, string, string) {
+	t.Helper()
+
+	baseDir := t.TempDir()
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	initRepoAt(t, repoDir, "https://github.com/user/repo.git")
+
+	cachePath := filepath.Join(t.TempDir(), "discovery-cache.json")
+	return baseDir, repoDir, cachePath
+}
+
+// tamperCachedBranch rewrites every cached branch so a later cache hit is observable.
+func tamperCachedBranch(t *testing.T, cachePath, branch string) {
+	t.Helper()
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	var cf cacheFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		t.Fatalf("Failed to parse cache: %v", err)
+	}
+	for key, record := range cf.Records {
+		for _, w := range record.Worktrees {
+			w.Entry.Branch = branch
+		}
+		cf.Records[key] = record
+	}
+	data, err = json.Marshal(cf)
+	if err != nil {
+		t.Fatalf("Failed to marshal cache: %v", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+}
+
+func discoverCachedBranch(t *testing.T, baseDir string, opts *DiscoverOptions) string {
+	t.Helper()
+
+	entries, err := DiscoverGlobalWorktreesCached(baseDir, opts)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesCached() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	return entries[0].Branch
+}
+
+func TestDiscoverGlobalWorktreesCached_Hit(t *testing.T) {
+	baseDir, _, cachePath := setupCachedBaseDir(t)
+	opts := &DiscoverOptions{CachePath: cachePath}
+
+	if got := discoverCachedBranch(t, baseDir, opts); got != "main" {
+		t.Fatalf("First discovery branch = %q, want main", got)
+	}
+
+	tamperCachedBranch(t, cachePath, "from-cache")
+
+	if got := discoverCachedBranch(t, baseDir, opts); got != "from-cache" {
+		t.Errorf("Second discovery branch = %q, want cached value", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_NoCacheRescans(t *testing.T) {
+	baseDir, _, cachePath := setupCachedBaseDir(t)
+
+	discoverCachedBranch(t, baseDir, &DiscoverOptions{CachePath: cachePath})
+	tamperCachedBranch(t, cachePath, "from-cache")
+
+	if got := discoverCachedBranch(t, baseDir, &DiscoverOptions{CachePath: cachePath, NoCache: true}); got != "main" {
+		t.Errorf("NoCache discovery branch = %q, want main", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_MissAfterMtimeBump(t *testing.T) {
+	baseDir, _, cachePath := setupCachedBaseDir(t)
+	opts := &DiscoverOptions{CachePath: cachePath}
+
+	discoverCachedBranch(t, baseDir, opts)
+	tamperCachedBranch(t, cachePath, "from-cache")
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(baseDir, later, later); err != nil {
+		t.Fatalf("Failed to bump mtime: %v", err)
+	}
+
+	if got := discoverCachedBranch(t, baseDir, opts); got != "main" {
+		t.Errorf("Discovery after mtime bump branch = %q, want main", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_GitChangeRefreshesEntry(t *testing.T) {
+	baseDir, repoDir, cachePath := setupCachedBaseDir(t)
+	opts := &DiscoverOptions{CachePath: cachePath}
+
+	discoverCachedBranch(t, baseDir, opts)
+	tamperCachedBranch(t, cachePath, "from-cache")
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(repoDir, ".git"), later, later); err != nil {
+		t.Fatalf("Failed to bump .git mtime: %v", err)
+	}
+
+	if got := discoverCachedBranch(t, baseDir, opts); got != "main" {
+		t.Errorf("Discovery after .git change branch = %q, want main", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_CorruptCacheRecovers(t *testing.T) {
+	baseDir, _, cachePath := setupCachedBaseDir(t)
+	opts := &DiscoverOptions{CachePath: cachePath}
+
+	if err := os.WriteFile(cachePath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write corrupt cache: %v", err)
+	}
+
+	if got := discoverCachedBranch(t, baseDir, opts); got != "main" {
+		t.Errorf("Discovery with corrupt cache branch = %q, want main", got)
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	var cf cacheFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		t.Errorf("Cache was not rewritten as valid JSON: %v", err)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_LogsCacheWriteFailure(t *testing.T) {
+	baseDir, _, _ := setupCachedBaseDir(t)
+
+	// A cache path below a regular file cannot be created.
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	cachePath := filepath.Join(blocker, "discovery-cache.json")
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	entries, err := DiscoverGlobalWorktreesCached(baseDir, &DiscoverOptions{CachePath: cachePath, Logger: logger})
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesCached() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, `msg="failed to write discovery cache"`) {
+		t.Errorf("Expected a warning for the cache write, got:\n%s", out)
+	}
+	if !strings.Contains(out, "path="+cachePath) {
+		t.Errorf("Expected path=%s in log output, got:\n%s", cachePath, out)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_LinkedWorktreeHeadChange(t *testing.T) {
+	baseDir, repoDir, cachePath := setupCachedBaseDir(t)
+	opts := &DiscoverOptions{CachePath: cachePath}
+
+	repo := &TestRepository{Path: repoDir}
+	linkedDir := filepath.Join(baseDir, "github.com", "user", "repo", "feat")
+	if err := repo.run("worktree", "add", "-b", "feat", linkedDir); err != nil {
+		t.Fatalf("Failed to add worktree: %v", err)
+	}
+
+	linked := func() *GlobalWorktreeEntry {
+		t.Helper()
+		entries, err := DiscoverGlobalWorktreesCached(baseDir, opts)
+		if err != nil {
+			t.Fatalf("DiscoverGlobalWorktreesCached() error = %v", err)
+		}
+		for _, e := range entries {
+			if e.Path == linkedDir {
+				return e
+			}
+		}
+		t.Fatalf("linked worktree %s not discovered", linkedDir)
+		return nil
+	}
+
+	before := linked()
+	if before.Branch != "feat" {
+		t.Fatalf("First discovery branch = %q, want feat", before.Branch)
+	}
+
+	// Both only touch the repository's admin dir and refs, not the linked
+	// worktree's .git file.
+	wt := &TestRepository{Path: linkedDir}
+	if err := wt.run("commit", "--allow-empty", "-m", "more"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	afterCommit := linked()
+	if afterCommit.CommitHash == before.CommitHash {
+		t.Errorf("Discovery after commit kept cached commit %s", before.CommitHash)
+	}
+
+	if err := wt.run("checkout", "-q", "-b", "other"); err != nil {
+		t.Fatalf("Failed to switch branch: %v", err)
+	}
+	if got := linked().Branch; got != "other" {
+		t.Errorf("Discovery after branch switch branch = %q, want other", got)
+	}
+}
+
+func TestDiscoverGlobalWorktreesCachedContext_Cancelled(t *testing.T) {
+	baseDir, _, cachePath := setupCachedBaseDir(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DiscoverGlobalWorktreesCachedContext(ctx, baseDir, &DiscoverOptions{CachePath: cachePath})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("cancelled discovery wrote the cache (stat error = %v)", err)
+	}
+}
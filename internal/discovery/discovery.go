@@ -2,12 +2,17 @@
 package discovery
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/template"
 	"github.com/d-kuro/gwq/internal/url"
 	"github.com/d-kuro/gwq/internal/utils"
 	"github.com/d-kuro/gwq/pkg/models"
@@ -15,36 +20,162 @@ import (
 
 // GlobalWorktreeEntry represents a discovered worktree.
 type GlobalWorktreeEntry struct {
-	RepositoryURL  string              // Full repository URL
-	RepositoryInfo *url.RepositoryInfo // Parsed repository information
-	Branch         string
-	Path           string
-	CommitHash     string
-	IsMain         bool
+	RepositoryURL  string                 `json:"repository_url"`  // Full repository URL
+	RepositoryInfo *models.RepositoryInfo `json:"repository_info"` // Parsed repository information
+	Branch         string                 `json:"branch"`
+	Path           string                 `json:"path"`
+	CommitHash     string                 `json:"commit_hash"`
+	IsMain         bool                   `json:"is_main"`
+
+	// BranchDirMismatch is set when DiscoverOptions.Naming is given and the
+	// directory does not match the branch (see template.DirMatchesBranch).
+	BranchDirMismatch bool `json:"-"`
+}
+
+// DiscoverOptions controls optional discovery behavior.
+type DiscoverOptions struct {
+	NoCache   bool   // Ignore the on-disk cache and rescan (the cache is still refreshed)
+	CachePath string // Override the cache file location (default: <config dir>/gwq/discovery-cache.json)
+
+	// Pattern skips worktrees whose path relative to the base directory
+	// cannot match before their git metadata is read. It is a cheap
+	// pre-filter only: callers must still run FilterGlobalWorktrees, and
+	// worktrees whose checked-out branch differs from their directory name
+	// may be skipped.
+	Pattern string
+
+	// Logger receives diagnostics about skipped paths (at debug level) and
+	// non-fatal failures such as cache writes (at warn level). Nil means
+	// slog.Default().
+	Logger *slog.Logger
+
+	// Quiet logs warnings at debug level instead. It is implied when
+	// GWQ_QUIET_DISCOVERY is set.
+	Quiet bool
+
+	// Naming, when set, flags entries whose directory no longer matches
+	// their branch, e.g. after a rename outside gwq.
+	Naming *models.NamingConfig
+}
+
+// logger returns the configured logger or slog.Default(), demoting warnings
+// when quiet discovery is requested.
+func (o *DiscoverOptions) logger() *slog.Logger {
+	logger := o.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if o.Quiet || quietRequested() {
+		return slog.New(quietHandler{h: logger.Handler()})
+	}
+	return logger
+}
+
+// worktreeCandidate is a directory found during the walk that looks like a worktree.
+type worktreeCandidate struct {
+	Path       string
+	IsMain     bool
+	GitModTime time.Time // Modification time of the .git file or directory
 }
 
 // DiscoverGlobalWorktrees finds all worktrees in the configured base directory.
 func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
-	if baseDir == "" {
-		return nil, fmt.Errorf("base directory not configured")
+	return DiscoverGlobalWorktreesWithOptions(baseDir, nil)
+}
+
+// DiscoverGlobalWorktreesWithOptions finds worktrees in the base directory,
+// applying opts.Pattern as a path pre-filter before extraction. The cache
+// options are ignored; use DiscoverGlobalWorktreesCached for cached lookups.
+func DiscoverGlobalWorktreesWithOptions(baseDir string, opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+	if opts == nil {
+		opts = &DiscoverOptions{}
 	}
 
-	// Expand path (handles ~, env vars, and relative paths)
-	expandedPath, err := utils.ExpandPath(baseDir)
+	baseDir, err := resolveBaseDir(baseDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand base directory path: %w", err)
+		return nil, err
 	}
-	baseDir = expandedPath
 
 	// Check if base directory exists
 	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
 		return []*GlobalWorktreeEntry{}, nil
 	}
 
+	logger := opts.logger()
+	candidates, err := collectWorktreePaths(baseDir, opts.Pattern, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := newRepoURLCache()
 	var entries []*GlobalWorktreeEntry
+	for _, c := range candidates {
+		entry, err := extractCandidate(c, urls, logger)
+		if err != nil {
+			logSkippedCandidate(logger, c, err)
+			continue // Skip broken repos and worktrees
+		}
+		entries = append(entries, entry)
+	}
+
+	flagBranchDirMismatches(entries, baseDir, opts.Naming)
+	return entries, nil
+}
+
+// flagBranchDirMismatches sets BranchDirMismatch on linked worktree entries
+// whose directory does not match their branch under naming. Nothing is
+// flagged without naming.
+func flagBranchDirMismatches(entries []*GlobalWorktreeEntry, baseDir string, naming *models.NamingConfig) {
+	for _, e := range entries {
+		e.BranchDirMismatch = naming != nil && !e.IsMain && !template.DirMatchesBranch(*naming, baseDir, e.Path, e.Branch)
+	}
+}
+
+// resolveBaseDir validates and expands the configured base directory.
+func resolveBaseDir(baseDir string) (string, error) {
+	if baseDir == "" {
+		return "", fmt.Errorf("base directory not configured")
+	}
+
+	// Expand path (handles ~, env vars, and relative paths)
+	expandedPath, err := utils.ExpandPath(baseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand base directory path: %w", err)
+	}
+	return expandedPath, nil
+}
 
-	err = filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+// collectWorktreePaths walks baseDir and returns every directory that looks
+// like a main or linked worktree, in walk order. Main repositories are not
+// descended into; submodules are skipped. Bare repositories are not
+// descended into either, but their linked worktrees are enumerated from the
+// repository's worktrees/ directory, even when they live outside baseDir.
+// A non-empty pattern drops candidates whose relative path cannot match it
+// (see pathMayMatch). Paths that cannot be read are skipped and logged at
+// debug level.
+func collectWorktreePaths(baseDir, pattern string, logger *slog.Logger) ([]worktreeCandidate, error) {
+	return collectWorktreePathsContext(context.Background(), baseDir, pattern, logger)
+}
+
+// collectWorktreePathsContext is collectWorktreePaths with cancellation: the
+// walk stops and returns ctx.Err() as soon as ctx is done.
+func collectWorktreePathsContext(ctx context.Context, baseDir, pattern string, logger *slog.Logger) ([]worktreeCandidate, error) {
+	var candidates []worktreeCandidate
+	seen := make(map[string]bool)
+	add := func(c worktreeCandidate) {
+		if seen[c.Path] {
+			return
+		}
+		seen[c.Path] = true
+		candidates = append(candidates, c)
+	}
+
+	err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
+			logger.Debug("skipping unreadable path", "path", path, "error", err)
 			return nil // Skip errors and continue walking
 		}
 
@@ -60,23 +191,35 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 		gitPath := filepath.Join(path, ".git")
 		gitInfo, err := os.Stat(gitPath)
 		if err != nil {
+			if isBareRepo(path) {
+				for _, c := range bareRepoWorktrees(path) {
+					if pathMayMatch(baseDir, c.Path, pattern) {
+						add(c)
+					}
+				}
+				return filepath.SkipDir // Don't descend into objects/, refs/, ...
+			}
 			return nil // No .git entry, continue
 		}
 
+		matches := pathMayMatch(baseDir, path, pattern)
+
 		if gitInfo.IsDir() {
 			// Main worktree (.git is a directory)
-			entry, err := extractWorktreeInfo(path)
-			if err != nil {
-				return filepath.SkipDir // Skip broken repos but don't walk into them
+			if matches {
+				add(worktreeCandidate{Path: path, IsMain: true, GitModTime: gitInfo.ModTime()})
 			}
-			entry.IsMain = true
-			entries = append(entries, entry)
 			return filepath.SkipDir // Don't descend into the repo
 		}
 
+		if !matches {
+			return nil
+		}
+
 		// Linked worktree (.git is a file)
 		gitContent, err := os.ReadFile(gitPath)
 		if err != nil {
+			logger.Debug("skipping unreadable .git file", "path", gitPath, "error", err)
 			return nil
 		}
 
@@ -91,28 +234,116 @@ func DiscoverGlobalWorktrees(baseDir string) ([]*GlobalWorktreeEntry, error) {
 			return nil
 		}
 
-		entry, err := extractWorktreeInfo(path)
-		if err != nil {
-			return nil
-		}
-		entries = append(entries, entry)
+		add(worktreeCandidate{Path: path, GitModTime: gitInfo.ModTime()})
 		return nil
 	})
 
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to walk directory: %w", err)
 	}
 
-	return entries, nil
+	return candidates, nil
 }
 
-// extractWorktreeInfo extracts worktree information from a worktree directory.
-func extractWorktreeInfo(worktreePath string) (*GlobalWorktreeEntry, error) {
-	// Create a git instance for this worktree
-	g := git.New(worktreePath)
+// logSkippedCandidate records a worktree candidate whose git metadata could
+// not be read.
+func logSkippedCandidate(logger *slog.Logger, c worktreeCandidate, err error) {
+	logger.Debug("skipping worktree", "path", c.Path, "error", err)
+}
 
-	// Get repository URL
-	repoURL, err := g.GetRepositoryURL()
+// isBareRepo reports whether path is the top level of a bare repository,
+// i.e. it holds HEAD, config, and objects/ directly instead of in .git.
+func isBareRepo(path string) bool {
+	if info, err := os.Stat(filepath.Join(path, "HEAD")); err != nil || info.IsDir() {
+		return false
+	}
+	if info, err := os.Stat(filepath.Join(path, "config")); err != nil || info.IsDir() {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(path, "objects"))
+	return err == nil && info.IsDir()
+}
+
+// bareRepoWorktrees returns the linked worktrees registered in a bare
+// repository's worktrees/ directory. Each worktrees/<id>/gitdir file holds
+// the path of the worktree's .git file; entries whose worktree no longer
+// exists are skipped.
+func bareRepoWorktrees(repoPath string) []worktreeCandidate {
+	adminDirs, err := os.ReadDir(filepath.Join(repoPath, "worktrees"))
+	if err != nil {
+		return nil
+	}
+
+	var candidates []worktreeCandidate
+	for _, adminDir := range adminDirs {
+		if !adminDir.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(repoPath, "worktrees", adminDir.Name(), "gitdir"))
+		if err != nil {
+			continue
+		}
+		gitFile := strings.TrimSpace(string(content))
+		if !filepath.IsAbs(gitFile) {
+			gitFile = filepath.Join(repoPath, "worktrees", adminDir.Name(), gitFile)
+		}
+
+		gitInfo, err := os.Stat(gitFile)
+		if err != nil || gitInfo.IsDir() {
+			continue
+		}
+		candidates = append(candidates, worktreeCandidate{Path: filepath.Dir(gitFile), GitModTime: gitInfo.ModTime()})
+	}
+	return candidates
+}
+
+// pathMayMatch reports whether a worktree at path could satisfy pattern in
+// FilterGlobalWorktrees, judging only by its path relative to baseDir. Each
+// ":"-separated part of the pattern (as in "repo:branch") must appear in the
+// path either verbatim or with "/" sanitized to "-", the default branch
+// directory naming.
+func pathMayMatch(baseDir, path, pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil {
+		return true
+	}
+	rel = strings.ToLower(filepath.ToSlash(rel))
+
+	for _, part := range strings.Split(strings.ToLower(pattern), ":") {
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(rel, part) && !strings.Contains(rel, strings.ReplaceAll(part, "/", "-")) {
+			return false
+		}
+	}
+	return true
+}
+
+// extractCandidate extracts worktree information for a walk candidate,
+// reading the repository URL through urls.
+func extractCandidate(c worktreeCandidate, urls *repoURLCache, logger *slog.Logger) (*GlobalWorktreeEntry, error) {
+	entry, err := extractWorktreeInfo(c.Path, urls, logger)
+	if err != nil {
+		return nil, err
+	}
+	entry.IsMain = c.IsMain
+	return entry, nil
+}
+
+// extractWorktreeInfo extracts worktree information from a worktree directory.
+// A nil urls reads the repository URL without caching.
+func extractWorktreeInfo(worktreePath string, urls *repoURLCache, logger *slog.Logger) (*GlobalWorktreeEntry, error) {
+	// Get repository URL, shared by all worktrees of the repository
+	repoURL, err := urls.get(worktreePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository URL: %w", err)
 	}
@@ -123,16 +354,17 @@ func extractWorktreeInfo(worktreePath string) (*GlobalWorktreeEntry, error) {
 		return nil, fmt.Errorf("failed to parse repository URL: %w", err)
 	}
 
-	// Get current branch
-	branch, err := getCurrentBranch(worktreePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current branch: %w", err)
-	}
-
-	// Get commit hash
-	commitHash, err := getCurrentCommitHash(worktreePath)
+	// Read branch and commit from the git directory when possible, and ask
+	// git otherwise.
+	branch, commitHash, err := readWorktreeDetailsFast(worktreePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get commit hash: %w", err)
+		if errors.Is(err, ErrReftableStorage) {
+			logger.Debug("reading worktree details with git", "path", worktreePath, "reason", err)
+		}
+		branch, commitHash, err = readWorktreeDetails(worktreePath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &GlobalWorktreeEntry{
@@ -144,6 +376,26 @@ func extractWorktreeInfo(worktreePath string) (*GlobalWorktreeEntry, error) {
 	}, nil
 }
 
+// readWorktreeDetails gets the branch and commit hash of a worktree by
+// running git.
+func readWorktreeDetails(worktreePath string) (branch, commitHash string, err error) {
+	branch, err = getCurrentBranch(worktreePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	// A branch with no commits yet (unborn HEAD) is still a valid worktree,
+	// so it is reported with an empty commit hash.
+	commitHash, err = getCurrentCommitHash(worktreePath)
+	if err != nil {
+		if !isUnbornBranch(worktreePath, branch) {
+			return "", "", fmt.Errorf("failed to get commit hash: %w", err)
+		}
+		commitHash = ""
+	}
+	return branch, commitHash, nil
+}
+
 // getCurrentBranch gets the current branch name for a worktree.
 func getCurrentBranch(worktreePath string) (string, error) {
 	g := git.New(worktreePath)
@@ -151,7 +403,12 @@ func getCurrentBranch(worktreePath string) (string, error) {
 	// Use git rev-parse to get the current branch
 	output, err := g.RunCommand("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
-		return "", err
+		// rev-parse cannot resolve an unborn HEAD; symbolic-ref still can
+		symref, symErr := g.RunCommand("symbolic-ref", "--short", "HEAD")
+		if symErr != nil {
+			return "", err
+		}
+		return strings.TrimSpace(symref), nil
 	}
 
 	branch := strings.TrimSpace(output)
@@ -175,6 +432,23 @@ func getCurrentCommitHash(worktreePath string) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// isUnbornBranch reports whether branch is checked out in the worktree but
+// has no commits yet, i.e. HEAD points at a ref that does not exist.
+func isUnbornBranch(worktreePath, branch string) bool {
+	if branch == "" || branch == "HEAD" {
+		return false
+	}
+
+	g := git.New(worktreePath)
+	symref, err := g.RunCommand("symbolic-ref", "HEAD")
+	if err != nil || strings.TrimSpace(symref) != "refs/heads/"+branch {
+		return false
+	}
+
+	_, err = g.RunCommand("show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	return err != nil
+}
+
 // isSubmoduleGitDir checks whether a gitdir path points to a submodule
 // rather than a linked worktree. Submodule gitdirs always contain a
 // "/modules/" segment — either under .git/modules/ (submodules in the main
@@ -198,10 +472,11 @@ func ConvertToWorktreeModels(entries []*GlobalWorktreeEntry, showRepoName bool)
 		}
 
 		wt := models.Worktree{
-			Branch:     branch,
-			Path:       entry.Path,
-			CommitHash: entry.CommitHash,
-			IsMain:     entry.IsMain,
+			Branch:         branch,
+			Path:           entry.Path,
+			CommitHash:     entry.CommitHash,
+			IsMain:         entry.IsMain,
+			RepositoryInfo: entry.RepositoryInfo,
 		}
 		worktrees = append(worktrees, wt)
 	}
@@ -0,0 +1,245 @@
+package discovery
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errFastPathUnsupported is returned by the fast readers when the on-disk
+// layout is not one they understand; callers then ask git instead.
+var errFastPathUnsupported = errors.New("unsupported git directory layout")
+
+// ErrReftableStorage is returned by the fast readers for repositories using
+// the reftable ref storage format (extensions.refStorage = reftable), whose
+// binary tables replace both loose refs and packed-refs.
+var ErrReftableStorage = errors.New("repository uses reftable ref storage")
+
+// readWorktreeDetailsFast reads the branch and commit of the worktree at
+// worktreePath straight from its git directory, saving the git subprocesses
+// discovery would otherwise run per worktree. A detached HEAD is reported
+// as branch "HEAD", like 'git rev-parse --abbrev-ref HEAD' does. Any error,
+// including an unborn branch, means the caller should fall back to git.
+func readWorktreeDetailsFast(worktreePath string) (branch, commit string, err error) {
+	gitDir, err := resolveGitDir(worktreePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Reftable repositories keep a placeholder HEAD file, so the storage
+	// format is checked before HEAD is read.
+	mainGitDir := resolveCommonDir(gitDir)
+	storage, err := detectRefStorage(mainGitDir)
+	if err != nil {
+		return "", "", err
+	}
+	if storage == "reftable" {
+		return "", "", ErrReftableStorage
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", "", err
+	}
+	branch, commit, err = parseBranchOrCommitFromHead(string(head))
+	if err != nil {
+		return "", "", err
+	}
+	if commit != "" {
+		return "HEAD", commit, nil
+	}
+
+	if commit, err := readCommitFromRefSafe(mainGitDir, branch); err == nil {
+		return branch, commit, nil
+	}
+	if commit, err := readCommitFromPackedRefs(mainGitDir, branch); err == nil {
+		return branch, commit, nil
+	}
+	return "", "", fmt.Errorf("refs/heads/%s not found: %w", branch, errFastPathUnsupported)
+}
+
+// resolveGitDir returns the git directory of the worktree at worktreePath:
+// .git itself for a main worktree, or the directory a linked worktree's
+// .git file points to.
+func resolveGitDir(worktreePath string) (string, error) {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%s: %w", dotGit, errFastPathUnsupported)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return gitDir, nil
+}
+
+// resolveCommonDir returns the repository git directory shared by all
+// worktrees, named by gitDir's commondir file for linked worktrees.
+func resolveCommonDir(gitDir string) string {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return filepath.Clean(commonDir)
+}
+
+// detectRefStorage returns the ref storage format of the repository at
+// mainGitDir from the extensions.refStorage key of its config: "files"
+// when unset, "reftable" for reftable repositories.
+func detectRefStorage(mainGitDir string) (string, error) {
+	f, err := os.Open(filepath.Join(mainGitDir, "config"))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	storage := "files"
+	inExtensions := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			section, _, _ := strings.Cut(strings.Trim(line, "[]"), " ")
+			inExtensions = strings.EqualFold(strings.TrimSpace(section), "extensions")
+			continue
+		}
+		if !inExtensions {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		if strings.EqualFold(strings.TrimSpace(key), "refstorage") {
+			storage = strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return storage, nil
+}
+
+// parseBranchOrCommitFromHead parses the contents of a HEAD file: either
+// "ref: refs/heads/<branch>" or the object ID of a detached HEAD, SHA-1 or
+// SHA-256. Only the first line counts, and whitespace around and after the
+// ref, such as "ref:\trefs/heads/main  # note", is tolerated.
+func parseBranchOrCommitFromHead(head string) (branch, commit string, err error) {
+	line, _, _ := strings.Cut(head, "\n")
+	line = strings.TrimSpace(line)
+	if rest, ok := strings.CutPrefix(line, "ref:"); ok {
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return "", "", fmt.Errorf("empty HEAD ref: %w", errFastPathUnsupported)
+		}
+		ref := fields[0]
+		branch, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok || branch == "" {
+			return "", "", fmt.Errorf("HEAD points to %q: %w", ref, errFastPathUnsupported)
+		}
+		return branch, "", nil
+	}
+	if isObjectID(line) {
+		return "", line, nil
+	}
+	return "", "", fmt.Errorf("unrecognized HEAD %q: %w", line, errFastPathUnsupported)
+}
+
+// readCommitFromRefSafe reads the loose ref file of branch under
+// mainGitDir. Branch names that could escape refs/heads are rejected.
+func readCommitFromRefSafe(mainGitDir, branch string) (string, error) {
+	if !isSafeBranchName(branch) {
+		return "", fmt.Errorf("branch %q: %w", branch, errFastPathUnsupported)
+	}
+	content, err := os.ReadFile(filepath.Join(mainGitDir, "refs", "heads", filepath.FromSlash(branch)))
+	if err != nil {
+		return "", err
+	}
+	commit := strings.TrimSpace(string(content))
+	if !isObjectID(commit) {
+		return "", fmt.Errorf("refs/heads/%s holds %q: %w", branch, commit, errFastPathUnsupported)
+	}
+	return commit, nil
+}
+
+// readCommitFromPackedRefs looks branch up in mainGitDir/packed-refs, where
+// git moves refs on 'git pack-refs' and gc. Comment lines ("# pack-refs
+// with: ...") and peeled tag lines ("^<hash>") are skipped.
+func readCommitFromPackedRefs(mainGitDir, branch string) (string, error) {
+	f, err := os.Open(filepath.Join(mainGitDir, "packed-refs"))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	suffix := " refs/heads/" + branch
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		if !strings.HasSuffix(line, suffix) {
+			continue
+		}
+		commit := strings.TrimSuffix(line, suffix)
+		if !isObjectID(commit) {
+			return "", fmt.Errorf("packed-refs entry for %s: %w", branch, errFastPathUnsupported)
+		}
+		return commit, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("refs/heads/%s not in packed-refs: %w", branch, os.ErrNotExist)
+}
+
+// isSafeBranchName rejects names that would leave refs/heads when used as a
+// path.
+func isSafeBranchName(branch string) bool {
+	if branch == "" || strings.HasPrefix(branch, "/") || strings.Contains(branch, "\\") {
+		return false
+	}
+	for part := range strings.SplitSeq(branch, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// isObjectID reports whether s is a full object ID: 40 hex digits in SHA-1
+// repositories, 64 in SHA-256 ones (extensions.objectFormat = sha256).
+func isObjectID(s string) bool {
+	return (len(s) == 40 || len(s) == 64) && isHexString(s)
+}
+
+// isHexString reports whether s consists of lower-case hexadecimal digits,
+// as git writes object IDs.
+func isHexString(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,321 @@
+package discovery
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/git"
+)
+
+const (
+	hashMain    = "1111111111111111111111111111111111111111"
+	hashFeature = "2222222222222222222222222222222222222222"
+	hashTag     = "3333333333333333333333333333333333333333"
+	hashPeeled  = "4444444444444444444444444444444444444444"
+	hashSHA256  = "5555555555555555555555555555555555555555555555555555555555555555"
+)
+
+func TestReadCommitFromPackedRefs(t *testing.T) {
+	packed := strings.Join([]string{
+		"# pack-refs with: peeled fully-peeled sorted ",
+		hashFeature + " refs/heads/feature/login",
+		hashMain + " refs/heads/main",
+		hashFeature + " refs/remotes/origin/main",
+		hashTag + " refs/tags/v1.0.0",
+		"^" + hashPeeled,
+		"",
+	}, "\n")
+
+	tests := []struct {
+		name    string
+		content string
+		branch  string
+		want    string
+		wantErr bool
+	}{
+		{name: "branch", content: packed, branch: "main", want: hashMain},
+		{name: "nested branch", content: packed, branch: "feature/login", want: hashFeature},
+		{name: "remote ref is not a branch", content: packed, branch: "origin/main", wantErr: true},
+		{name: "suffix of another branch", content: packed, branch: "login", wantErr: true},
+		{name: "missing branch", content: packed, branch: "develop", wantErr: true},
+		{name: "CRLF line endings", content: strings.ReplaceAll(packed, "\n", "\r\n"), branch: "main", want: hashMain},
+		{name: "malformed hash", content: "not-a-hash refs/heads/main\n", branch: "main", wantErr: true},
+		{name: "sha256 object ID", content: hashSHA256 + " refs/heads/main\n", branch: "main", want: hashSHA256},
+		{name: "missing file", branch: "main", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(dir, "packed-refs"), []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := readCommitFromPackedRefs(dir, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readCommitFromPackedRefs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readCommitFromPackedRefs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBranchOrCommitFromHead(t *testing.T) {
+	tests := []struct {
+		name       string
+		head       string
+		wantBranch string
+		wantCommit string
+		wantErr    bool
+	}{
+		{name: "branch", head: "ref: refs/heads/main\n", wantBranch: "main"},
+		{name: "nested branch", head: "ref: refs/heads/feature/login\n", wantBranch: "feature/login"},
+		{name: "detached", head: hashMain + "\n", wantCommit: hashMain},
+		{name: "detached sha256", head: hashSHA256 + "\n", wantCommit: hashSHA256},
+		{name: "no trailing newline", head: "ref: refs/heads/main", wantBranch: "main"},
+		{name: "CRLF", head: "ref: refs/heads/main\r\n", wantBranch: "main"},
+		{name: "tab after ref:", head: "ref:\trefs/heads/main\n", wantBranch: "main"},
+		{name: "no space after ref:", head: "ref:refs/heads/main\n", wantBranch: "main"},
+		{name: "surrounding whitespace", head: "  ref:   refs/heads/main  \n", wantBranch: "main"},
+		{name: "trailing comment", head: "ref: refs/heads/main # set by tool\n", wantBranch: "main"},
+		{name: "extra lines ignored", head: hashMain + "\nsomething else\n", wantCommit: hashMain},
+		{name: "hash of wrong length", head: hashMain[:39] + "\n", wantErr: true},
+		{name: "upper-case hash", head: strings.ToUpper("abcdef"+hashMain[6:]) + "\n", wantErr: true},
+		{name: "empty ref", head: "ref: \n", wantErr: true},
+		{name: "non-branch ref", head: "ref: refs/remotes/origin/main\n", wantErr: true},
+		{name: "garbage", head: "garbage\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branch, commit, err := parseBranchOrCommitFromHead(tt.head)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBranchOrCommitFromHead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if branch != tt.wantBranch || commit != tt.wantCommit {
+				t.Errorf("parseBranchOrCommitFromHead() = (%q, %q), want (%q, %q)", branch, commit, tt.wantBranch, tt.wantCommit)
+			}
+		})
+	}
+}
+
+func TestReadCommitFromRefSafe_RejectsEscapes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "HEAD"), []byte(hashMain+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, branch := range []string{"../../HEAD", "/etc/passwd", "a//b", ""} {
+		if _, err := readCommitFromRefSafe(dir, branch); err == nil {
+			t.Errorf("readCommitFromRefSafe(%q) succeeded, want error", branch)
+		}
+	}
+}
+
+func TestReadWorktreeDetailsFast(t *testing.T) {
+	repo := NewTestRepository(t)
+	wantCommit := revParse(t, repo.Path, "HEAD")
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	if err := repo.run("worktree", "add", "-b", "feature/login", wtPath); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	t.Run("loose refs", func(t *testing.T) {
+		for _, path := range []string{repo.Path, wtPath} {
+			branch, commit, err := readWorktreeDetailsFast(path)
+			if err != nil {
+				t.Fatalf("readWorktreeDetailsFast(%s) error = %v", path, err)
+			}
+			if commit != wantCommit {
+				t.Errorf("commit = %q, want %q", commit, wantCommit)
+			}
+			if want := revParseAbbrev(t, path); branch != want {
+				t.Errorf("branch = %q, want %q", branch, want)
+			}
+		}
+	})
+
+	t.Run("packed refs", func(t *testing.T) {
+		if err := repo.run("pack-refs", "--all"); err != nil {
+			t.Fatalf("Failed to pack refs: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(repo.Path, ".git", "refs", "heads", "feature", "login")); !os.IsNotExist(err) {
+			t.Fatalf("expected loose ref to be packed, stat error = %v", err)
+		}
+
+		branch, commit, err := readWorktreeDetailsFast(wtPath)
+		if err != nil {
+			t.Fatalf("readWorktreeDetailsFast() error = %v", err)
+		}
+		if branch != "feature/login" || commit != wantCommit {
+			t.Errorf("readWorktreeDetailsFast() = (%q, %q), want (%q, %q)", branch, commit, "feature/login", wantCommit)
+		}
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		if _, err := git.New(wtPath).RunCommand("checkout", "--detach"); err != nil {
+			t.Fatalf("Failed to detach HEAD: %v", err)
+		}
+
+		branch, commit, err := readWorktreeDetailsFast(wtPath)
+		if err != nil {
+			t.Fatalf("readWorktreeDetailsFast() error = %v", err)
+		}
+		if branch != "HEAD" || commit != wantCommit {
+			t.Errorf("readWorktreeDetailsFast() = (%q, %q), want (%q, %q)", branch, commit, "HEAD", wantCommit)
+		}
+	})
+
+	t.Run("unborn branch falls back", func(t *testing.T) {
+		if _, err := git.New(wtPath).RunCommand("checkout", "--orphan", "empty"); err != nil {
+			t.Fatalf("Failed to create orphan branch: %v", err)
+		}
+
+		if _, _, err := readWorktreeDetailsFast(wtPath); err == nil {
+			t.Error("readWorktreeDetailsFast() succeeded for an unborn branch, want error")
+		}
+	})
+}
+
+func revParse(t *testing.T, dir, rev string) string {
+	t.Helper()
+	out, err := git.New(dir).RunCommand("rev-parse", rev)
+	if err != nil {
+		t.Fatalf("rev-parse %s: %v", rev, err)
+	}
+	return strings.TrimSpace(out)
+}
+
+func revParseAbbrev(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := git.New(dir).RunCommand("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse --abbrev-ref HEAD: %v", err)
+	}
+	return strings.TrimSpace(out)
+}
+
+func TestDetectRefStorage(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name:   "no extensions",
+			config: "[core]\n\trepositoryformatversion = 0\n\tbare = false\n",
+			want:   "files",
+		},
+		{
+			name:   "reftable",
+			config: "[core]\n\trepositoryformatversion = 1\n[extensions]\n\trefstorage = reftable\n",
+			want:   "reftable",
+		},
+		{
+			name:   "mixed case key",
+			config: "[Extensions]\n\trefStorage = reftable\n",
+			want:   "reftable",
+		},
+		{
+			name:   "key in another section",
+			config: "[core]\n\trefstorage = reftable\n[extensions]\n\tobjectformat = sha1\n",
+			want:   "files",
+		},
+		{
+			name:   "commented out",
+			config: "[extensions]\n#\trefstorage = reftable\n\t; refstorage = reftable\n",
+			want:   "files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "config"), []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := detectRefStorage(dir)
+			if err != nil {
+				t.Fatalf("detectRefStorage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("detectRefStorage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWorktreeDetailsFast_Reftable(t *testing.T) {
+	dir := t.TempDir()
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(filepath.Join(gitDir, "reftable"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"config": "[core]\n\trepositoryformatversion = 1\n[extensions]\n\trefstorage = reftable\n",
+		"HEAD":   "ref: refs/heads/.invalid\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(gitDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, _, err := readWorktreeDetailsFast(dir)
+	if !errors.Is(err, ErrReftableStorage) {
+		t.Errorf("readWorktreeDetailsFast() error = %v, want ErrReftableStorage", err)
+	}
+}
+
+func TestReadWorktreeDetailsFast_SHA256(t *testing.T) {
+	dir := t.TempDir()
+	g := git.New(dir)
+	if _, err := g.RunCommand("init", "-b", "main", "--object-format=sha256"); err != nil {
+		t.Skipf("git does not support sha256 repositories: %v", err)
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	if _, err := g.RunCommand("commit", "--allow-empty", "-m", "initial"); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	wantCommit := revParse(t, dir, "HEAD")
+	if len(wantCommit) != 64 {
+		t.Fatalf("rev-parse HEAD = %q, want a sha256 object ID", wantCommit)
+	}
+
+	for _, step := range []struct {
+		name       string
+		args       []string
+		wantBranch string
+	}{
+		{name: "loose ref", wantBranch: "main"},
+		{name: "packed ref", args: []string{"pack-refs", "--all"}, wantBranch: "main"},
+		{name: "detached", args: []string{"checkout", "--detach"}, wantBranch: "HEAD"},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			if step.args != nil {
+				if _, err := g.RunCommand(step.args...); err != nil {
+					t.Fatalf("git %v: %v", step.args, err)
+				}
+			}
+			branch, commit, err := readWorktreeDetailsFast(dir)
+			if err != nil {
+				t.Fatalf("readWorktreeDetailsFast() error = %v", err)
+			}
+			if branch != step.wantBranch || commit != wantCommit {
+				t.Errorf("readWorktreeDetailsFast() = (%q, %q), want (%q, %q)", branch, commit, step.wantBranch, wantCommit)
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package discovery
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/d-kuro/gwq/internal/git"
 	"github.com/d-kuro/gwq/internal/url"
+	"github.com/d-kuro/gwq/pkg/models"
 )
 
 // TestRepository creates a test git repository (copy from git package for testing)
@@ -102,7 +107,7 @@ func (r *TestRepository) getCurrentBranch() (string, error) {
 }
 
 // AddRemote adds a remote to the repository
-func (r *TestRepository) AddRemote(t *testing.T, name, url string) {
+func (r *TestRepository) AddRemote(t testing.TB, name, url string) {
 	t.Helper()
 	if err := r.run("remote", "add", name, url); err != nil {
 		t.Fatalf("Failed to add remote %s: %v", name, err)
@@ -150,7 +155,7 @@ func TestDiscoverGlobalWorktrees_NoWorktrees(t *testing.T) {
 
 // initRepoAt creates and initializes a git repository at the given directory
 // with an initial commit and a remote.
-func initRepoAt(t *testing.T, dir, remoteURL string) *TestRepository {
+func initRepoAt(t testing.TB, dir, remoteURL string) *TestRepository {
 	t.Helper()
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		t.Fatalf("Failed to create repo directory: %v", err)
@@ -261,6 +266,134 @@ func TestDiscoverGlobalWorktrees_DoesNotDescendIntoMainRepo(t *testing.T) {
 	}
 }
 
+func TestDiscoverGlobalWorktrees_UnbornBranch(t *testing.T) {
+	baseDir := t.TempDir()
+
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	repo := initRepoAt(t, repoDir, "https://github.com/user/repo.git")
+
+	repo.CreateBranch(t, "feature")
+	if err := repo.run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	worktreeDir := filepath.Join(baseDir, "github.com", "user", "repo", "fresh")
+	repo.CreateWorktree(t, worktreeDir, "feature")
+
+	// Point the linked worktree's HEAD at a branch with no commits.
+	wt := &TestRepository{Path: worktreeDir}
+	if err := wt.run("symbolic-ref", "HEAD", "refs/heads/fresh"); err != nil {
+		t.Fatalf("Failed to set unborn HEAD: %v", err)
+	}
+
+	entries, err := DiscoverGlobalWorktrees(baseDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	var unborn *GlobalWorktreeEntry
+	for _, e := range entries {
+		if e.Path == worktreeDir {
+			unborn = e
+		}
+	}
+	if unborn == nil {
+		t.Fatal("Unborn-branch worktree was not discovered")
+	}
+	if unborn.Branch != "fresh" {
+		t.Errorf("Expected branch 'fresh', got '%s'", unborn.Branch)
+	}
+	if unborn.CommitHash != "" {
+		t.Errorf("Expected empty commit hash, got '%s'", unborn.CommitHash)
+	}
+}
+
+func TestIsBareRepo(t *testing.T) {
+	bare := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bare, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write HEAD: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bare, "config"), []byte("[core]\n\tbare = true\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(bare, "objects"), 0755); err != nil {
+		t.Fatalf("Failed to create objects: %v", err)
+	}
+
+	if !isBareRepo(bare) {
+		t.Error("Expected simulated bare layout to be detected")
+	}
+
+	missingObjects := t.TempDir()
+	if err := os.WriteFile(filepath.Join(missingObjects, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write HEAD: %v", err)
+	}
+	if isBareRepo(missingObjects) {
+		t.Error("Expected directory without config/objects not to be a bare repo")
+	}
+
+	if isBareRepo(t.TempDir()) {
+		t.Error("Expected empty directory not to be a bare repo")
+	}
+}
+
+func TestDiscoverGlobalWorktrees_BareRepository(t *testing.T) {
+	baseDir := t.TempDir()
+
+	srcDir := filepath.Join(t.TempDir(), "src")
+	src := initRepoAt(t, srcDir, "https://github.com/user/repo.git")
+	src.CreateBranch(t, "feature")
+	src.CreateBranch(t, "inside")
+
+	bareDir := filepath.Join(baseDir, "github.com", "user", "repo.git")
+	if err := src.run("clone", "--bare", srcDir, bareDir); err != nil {
+		t.Fatalf("Failed to clone bare: %v", err)
+	}
+	bare := &TestRepository{Path: bareDir}
+	if err := bare.run("remote", "set-url", "origin", "https://github.com/user/repo.git"); err != nil {
+		t.Fatalf("Failed to set remote: %v", err)
+	}
+
+	// One worktree outside the base directory, one inside it.
+	outsideDir := filepath.Join(t.TempDir(), "feature")
+	insideDir := filepath.Join(baseDir, "github.com", "user", "repo", "inside")
+	for dir, branch := range map[string]string{outsideDir: "feature", insideDir: "inside"} {
+		if err := bare.run("worktree", "add", dir, branch); err != nil {
+			t.Fatalf("Failed to create worktree: %v", err)
+		}
+	}
+
+	entries, err := DiscoverGlobalWorktrees(baseDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		for _, e := range entries {
+			t.Logf("entry: %s (%s)", e.Path, e.Branch)
+		}
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	branches := map[string]string{}
+	for _, e := range entries {
+		branches[e.Path] = e.Branch
+		if e.IsMain {
+			t.Errorf("Linked worktree %s of a bare repository should not be main", e.Path)
+		}
+		if e.RepositoryInfo == nil || e.RepositoryInfo.Repository != "repo" {
+			t.Errorf("Unexpected repository info for %s: %+v", e.Path, e.RepositoryInfo)
+		}
+	}
+	if branches[outsideDir] != "feature" {
+		t.Errorf("Expected worktree outside base dir on 'feature', got %q", branches[outsideDir])
+	}
+	if branches[insideDir] != "inside" {
+		t.Errorf("Expected worktree inside base dir on 'inside', got %q", branches[insideDir])
+	}
+}
+
 func TestGetCurrentBranch_InvalidPath(t *testing.T) {
 	_, err := getCurrentBranch("/nonexistent/path")
 	if err == nil {
@@ -327,6 +460,9 @@ func TestConvertToWorktreeModels_WithRepoName(t *testing.T) {
 	if worktrees[0].Branch != expected {
 		t.Errorf("Expected branch '%s', got '%s'", expected, worktrees[0].Branch)
 	}
+	if worktrees[0].RepositoryInfo != repoInfo {
+		t.Errorf("Expected RepositoryInfo to be carried over, got %+v", worktrees[0].RepositoryInfo)
+	}
 }
 
 func TestFilterGlobalWorktrees_BranchMatch(t *testing.T) {
@@ -512,25 +648,82 @@ func TestDiscoverGlobalWorktrees_SkipsSubmodules(t *testing.T) {
 	}
 }
 
-// Benchmark tests
-func BenchmarkDiscoverGlobalWorktrees(b *testing.B) {
-	// Create a temporary directory with multiple worktrees
-	baseDir := b.TempDir()
+func TestPathMayMatch(t *testing.T) {
+	baseDir := "/base"
+
+	tests := []struct {
+		name    string
+		path    string
+		pattern string
+		want    bool
+	}{
+		{"empty pattern", "/base/github.com/o/repo/feature-x", "", true},
+		{"basename match", "/base/github.com/o/repo/feature-x", "feature", true},
+		{"repository match", "/base/github.com/o/repo/feature-x", "repo", true},
+		{"case insensitive", "/base/github.com/o/repo/feature-x", "FEATURE", true},
+		{"sanitized branch", "/base/github.com/o/repo/feature-x", "feature/x", true},
+		{"repo colon branch", "/base/github.com/o/repo/feature-x", "repo:feature", true},
+		{"no match", "/base/github.com/o/repo/feature-x", "bugfix", false},
+		{"repo colon branch mismatch", "/base/github.com/o/repo/feature-x", "other:feature", false},
+		{"base dir not considered", "/base/github.com/o/repo/main", "base", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathMayMatch(baseDir, tt.path, tt.pattern); got != tt.want {
+				t.Errorf("pathMayMatch(%q, %q) = %v, want %v", tt.path, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverGlobalWorktreesWithOptions_Pattern(t *testing.T) {
+	baseDir := t.TempDir()
 
-	// Create multiple repositories and worktrees
-	for i := range 10 {
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	repo := initRepoAt(t, repoDir, "https://github.com/user/repo.git")
+	repo.CreateBranch(t, "feature/auth")
+	if err := repo.run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	repo.CreateWorktree(t, filepath.Join(baseDir, "github.com", "user", "repo", "feature-auth"), "feature/auth")
+
+	entries, err := DiscoverGlobalWorktreesWithOptions(baseDir, &DiscoverOptions{Pattern: "feature/auth"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Branch != "feature/auth" {
+		t.Errorf("Expected branch 'feature/auth', got '%s'", entries[0].Branch)
+	}
+}
+
+// createMockWorktrees creates n directories with worktree-style .git files.
+func createMockWorktrees(tb testing.TB, baseDir string, n int) {
+	tb.Helper()
+
+	for i := range n {
 		repo := &TestRepository{Path: filepath.Join(baseDir, fmt.Sprintf("repo%d", i))}
 		if err := os.MkdirAll(repo.Path, 0755); err != nil {
-			b.Fatalf("Failed to create repo directory: %v", err)
+			tb.Fatalf("Failed to create repo directory: %v", err)
 		}
 
 		// Create a simple .git file for worktree simulation
 		gitFile := filepath.Join(repo.Path, ".git")
 		gitContent := fmt.Sprintf("gitdir: /path/to/main/repo/.git/worktrees/branch%d", i)
 		if err := os.WriteFile(gitFile, []byte(gitContent), 0644); err != nil {
-			b.Fatalf("Failed to create .git file: %v", err)
+			tb.Fatalf("Failed to create .git file: %v", err)
 		}
 	}
+}
+
+// Benchmark tests
+func BenchmarkDiscoverGlobalWorktrees(b *testing.B) {
+	// Create a temporary directory with multiple worktrees
+	baseDir := b.TempDir()
+	createMockWorktrees(b, baseDir, 10)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -540,6 +733,27 @@ func BenchmarkDiscoverGlobalWorktrees(b *testing.B) {
 	}
 }
 
+func BenchmarkDiscoverGlobalWorktreesWithPattern(b *testing.B) {
+	baseDir := b.TempDir()
+	createMockWorktrees(b, baseDir, 10)
+
+	// Report how many candidates reach extraction with and without the pre-filter.
+	for _, pattern := range []string{"", "repo5"} {
+		b.Run(fmt.Sprintf("pattern=%q", pattern), func(b *testing.B) {
+			candidates, err := collectWorktreePaths(baseDir, pattern, slog.Default())
+			if err != nil {
+				b.Fatalf("collectWorktreePaths() error = %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = DiscoverGlobalWorktreesWithOptions(baseDir, &DiscoverOptions{Pattern: pattern})
+			}
+			b.ReportMetric(float64(len(candidates)), "extractions/op")
+		})
+	}
+}
+
 func BenchmarkFilterGlobalWorktrees(b *testing.B) {
 	// Create a large slice of entries
 	entries := make([]*GlobalWorktreeEntry, 1000)
@@ -555,3 +769,190 @@ func BenchmarkFilterGlobalWorktrees(b *testing.B) {
 		FilterGlobalWorktrees(entries, "branch-500")
 	}
 }
+
+// newTestLogger returns a logger that writes text records at level and
+// above to the returned buffer.
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})), &buf
+}
+
+// addBrokenWorktree creates a directory under baseDir whose .git file
+// points at a missing gitdir, so discovery finds it but cannot read it.
+func addBrokenWorktree(t *testing.T, baseDir string) string {
+	t.Helper()
+
+	path := filepath.Join(baseDir, "github.com", "user", "broken")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	gitdir := filepath.Join(t.TempDir(), "missing", ".git", "worktrees", "broken")
+	if err := os.WriteFile(filepath.Join(path, ".git"), []byte("gitdir: "+gitdir+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .git file: %v", err)
+	}
+	return path
+}
+
+func TestDiscoverGlobalWorktreesWithOptions_LogsSkippedWorktree(t *testing.T) {
+	baseDir := t.TempDir()
+	initRepoAt(t, filepath.Join(baseDir, "github.com", "user", "repo"), "https://github.com/user/repo.git")
+	broken := addBrokenWorktree(t, baseDir)
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	entries, err := DiscoverGlobalWorktreesWithOptions(baseDir, &DiscoverOptions{Logger: logger})
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesWithOptions() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "level=DEBUG") || !strings.Contains(out, `msg="skipping worktree"`) {
+		t.Errorf("Expected a debug record for the skipped worktree, got:\n%s", out)
+	}
+	if !strings.Contains(out, "path="+broken) {
+		t.Errorf("Expected path=%s in log output, got:\n%s", broken, out)
+	}
+}
+
+func TestDiscoverGlobalWorktreesWithOptions_SkipsAreDebugOnly(t *testing.T) {
+	baseDir := t.TempDir()
+	addBrokenWorktree(t, baseDir)
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	if _, err := DiscoverGlobalWorktreesWithOptions(baseDir, &DiscoverOptions{Logger: logger}); err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesWithOptions() error = %v", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("Expected no records at info level, got:\n%s", buf.String())
+	}
+}
+
+func TestDiscoverGlobalWorktreesCached_QuietEnv(t *testing.T) {
+	newDiscovery := func(t *testing.T) (string, string) {
+		t.Helper()
+		baseDir := t.TempDir()
+		initRepoAt(t, filepath.Join(baseDir, "github.com", "user", "repo"), "https://github.com/user/repo.git")
+		addBrokenWorktree(t, baseDir)
+
+		unreadable := filepath.Join(baseDir, "github.com", "user", "locked")
+		if err := os.MkdirAll(unreadable, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.Chmod(unreadable, 0); err != nil {
+			t.Fatalf("Failed to chmod: %v", err)
+		}
+		t.Cleanup(func() { _ = os.Chmod(unreadable, 0755) })
+
+		// A cache path below a regular file cannot be written, producing
+		// the one warn-level diagnostic of a walk.
+		blocker := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(blocker, nil, 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		return baseDir, filepath.Join(blocker, "cache.json")
+	}
+
+	tests := []struct {
+		name      string
+		env       string
+		wantLevel string
+	}{
+		{name: "warnings visible by default", env: "", wantLevel: "level=WARN"},
+		{name: "env var demotes to debug", env: "1", wantLevel: "level=DEBUG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(QuietEnv, tt.env)
+			baseDir, cachePath := newDiscovery(t)
+
+			for _, level := range []slog.Level{slog.LevelInfo, slog.LevelDebug} {
+				logger, buf := newTestLogger(level)
+				entries, err := DiscoverGlobalWorktreesCached(baseDir, &DiscoverOptions{CachePath: cachePath, NoCache: true, Logger: logger})
+				if err != nil {
+					t.Fatalf("DiscoverGlobalWorktreesCached() error = %v", err)
+				}
+				if len(entries) != 1 {
+					t.Errorf("Expected 1 entry, got %d", len(entries))
+				}
+
+				out := buf.String()
+				if level == slog.LevelInfo {
+					if tt.env != "" && out != "" {
+						t.Errorf("Expected no records at info level, got:\n%s", out)
+					}
+					if tt.env == "" && !strings.Contains(out, "level=WARN") {
+						t.Errorf("Expected a warning at info level, got:\n%s", out)
+					}
+					continue
+				}
+				if !strings.Contains(out, tt.wantLevel+` msg="failed to write discovery cache"`) {
+					t.Errorf("Expected %s cache warning, got:\n%s", tt.wantLevel, out)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscoverGlobalWorktrees_BranchDirMismatch(t *testing.T) {
+	baseDir := t.TempDir()
+	repoRoot := filepath.Join(baseDir, "github.com", "user", "repo")
+	repo := initRepoAt(t, filepath.Join(repoRoot, "main"), "https://github.com/user/repo.git")
+
+	for _, branch := range []string{"feature/kept", "feature/old"} {
+		if err := repo.run("branch", branch); err != nil {
+			t.Fatalf("Failed to create branch: %v", err)
+		}
+		repo.CreateWorktree(t, filepath.Join(repoRoot, strings.ReplaceAll(branch, "/", "-")), branch)
+	}
+	// Rename a branch behind gwq's back.
+	if err := repo.run("branch", "-m", "feature/old", "feature/new"); err != nil {
+		t.Fatalf("Failed to rename branch: %v", err)
+	}
+
+	naming := &models.NamingConfig{
+		Template:      "{{.Host}}/{{.Owner}}/{{.Repository}}/{{.Branch}}",
+		SanitizeChars: map[string]string{"/": "-"},
+	}
+	discover := map[string]func(*DiscoverOptions) ([]*GlobalWorktreeEntry, error){
+		"plain": func(opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+			return DiscoverGlobalWorktreesWithOptions(baseDir, opts)
+		},
+		"cached": func(opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+			opts.CachePath = filepath.Join(t.TempDir(), "cache.json")
+			return DiscoverGlobalWorktreesCached(baseDir, opts)
+		},
+		"parallel": func(opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+			return DiscoverGlobalWorktreesParallelContext(context.Background(), baseDir, opts)
+		},
+	}
+
+	for name, fn := range discover {
+		t.Run(name, func(t *testing.T) {
+			entries, err := fn(&DiscoverOptions{Naming: naming})
+			if err != nil {
+				t.Fatalf("discovery error = %v", err)
+			}
+			got := map[string]bool{}
+			for _, e := range entries {
+				got[e.Branch] = e.BranchDirMismatch
+			}
+			want := map[string]bool{"main": false, "feature/kept": false, "feature/new": true}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("mismatch flags = %v, want %v", got, want)
+			}
+
+			entries, err = fn(&DiscoverOptions{})
+			if err != nil {
+				t.Fatalf("discovery error = %v", err)
+			}
+			for _, e := range entries {
+				if e.BranchDirMismatch {
+					t.Errorf("%s flagged without a naming config", e.Branch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// extractCandidateFunc is the extraction step used by parallel discovery.
+// Tests replace it to observe or slow down extraction.
+var extractCandidateFunc = extractCandidate
+
+// DiscoverGlobalWorktreesParallelContext finds worktrees in the base directory
+// like DiscoverGlobalWorktreesWithOptions, but extracts git metadata with a
+// pool of workers. Entries are returned in walk order. When ctx is cancelled
+// the walk and the workers stop promptly and ctx.Err() is returned.
+func DiscoverGlobalWorktreesParallelContext(ctx context.Context, baseDir string, opts *DiscoverOptions) ([]*GlobalWorktreeEntry, error) {
+	if opts == nil {
+		opts = &DiscoverOptions{}
+	}
+
+	baseDir, err := resolveBaseDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
+		return []*GlobalWorktreeEntry{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to stat base directory: %w", err)
+	}
+
+	logger := opts.logger()
+	candidates, err := collectWorktreePathsContext(ctx, baseDir, opts.Pattern, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := extractCandidatesContext(ctx, candidates, newRepoURLCache(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*GlobalWorktreeEntry, 0, len(results))
+	for _, entry := range results {
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	flagBranchDirMismatches(entries, baseDir, opts.Naming)
+	return entries, nil
+}
+
+// extractCandidatesContext extracts the git metadata of candidates with a
+// pool of workers. The result is indexed like candidates, with nil for
+// candidates that were skipped as broken. When ctx is cancelled the workers
+// stop promptly and ctx.Err() is returned.
+func extractCandidatesContext(ctx context.Context, candidates []worktreeCandidate, urls *repoURLCache, logger *slog.Logger) ([]*GlobalWorktreeEntry, error) {
+	results := make([]*GlobalWorktreeEntry, len(candidates))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(candidates)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Keep receiving until jobs is closed so the producer never blocks,
+			// but skip the work once the context is done.
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				entry, err := extractCandidateFunc(candidates[i], urls, logger)
+				if err != nil {
+					logSkippedCandidate(logger, candidates[i], err)
+					continue // Skip broken repos and worktrees
+				}
+				results[i] = entry
+			}
+		}()
+	}
+
+produce:
+	for i := range candidates {
+		select {
+		case <-ctx.Done():
+			break produce
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,129 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiscoverGlobalWorktreesParallelContext_MatchesSequential(t *testing.T) {
+	baseDir := t.TempDir()
+
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	repo := initRepoAt(t, repoDir, "https://github.com/user/repo.git")
+	repo.CreateBranch(t, "feature")
+	if err := repo.run("checkout", "main"); err != nil {
+		t.Fatalf("Failed to checkout main: %v", err)
+	}
+	repo.CreateWorktree(t, filepath.Join(baseDir, "github.com", "user", "repo", "feature"), "feature")
+
+	want, err := DiscoverGlobalWorktrees(baseDir)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktrees() error = %v", err)
+	}
+	got, err := DiscoverGlobalWorktreesParallelContext(context.Background(), baseDir, nil)
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesParallelContext() error = %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Path != want[i].Path || got[i].Branch != want[i].Branch || got[i].IsMain != want[i].IsMain {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiscoverGlobalWorktreesParallelContext_AlreadyCancelled(t *testing.T) {
+	baseDir := t.TempDir()
+	initRepoAt(t, filepath.Join(baseDir, "repo"), "https://github.com/user/repo.git")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := DiscoverGlobalWorktreesParallelContext(ctx, baseDir, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDiscoverGlobalWorktreesParallelContext_CancelledMidScan(t *testing.T) {
+	baseDir := t.TempDir()
+	createMockWorktrees(t, baseDir, 50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel from inside the first extraction and make every extraction slow,
+	// so most candidates are still queued when cancellation is observed.
+	var once sync.Once
+	var calls int
+	var mu sync.Mutex
+	orig := extractCandidateFunc
+	extractCandidateFunc = func(c worktreeCandidate, _ *repoURLCache, _ *slog.Logger) (*GlobalWorktreeEntry, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		once.Do(cancel)
+		time.Sleep(10 * time.Millisecond)
+		return &GlobalWorktreeEntry{Path: c.Path}, nil
+	}
+	t.Cleanup(func() { extractCandidateFunc = orig })
+
+	before := runtime.NumGoroutine()
+
+	_, err := DiscoverGlobalWorktreesParallelContext(ctx, baseDir, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls >= 50 {
+		t.Errorf("expected cancellation to skip extractions, got %d calls", calls)
+	}
+
+	// All workers are joined before returning, so no goroutines should remain.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines leaked: before=%d after=%d", before, after)
+	}
+}
+
+func BenchmarkDiscoverGlobalWorktreesParallel(b *testing.B) {
+	baseDir := b.TempDir()
+	createMockWorktrees(b, baseDir, 10)
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = DiscoverGlobalWorktreesParallelContext(ctx, baseDir, nil)
+	}
+}
+
+func TestDiscoverGlobalWorktreesParallelContext_LogsSkippedWorktree(t *testing.T) {
+	baseDir := t.TempDir()
+	broken := addBrokenWorktree(t, baseDir)
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	entries, err := DiscoverGlobalWorktreesParallelContext(context.Background(), baseDir, &DiscoverOptions{Logger: logger})
+	if err != nil {
+		t.Fatalf("DiscoverGlobalWorktreesParallelContext() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+	if out := buf.String(); !strings.Contains(out, `msg="skipping worktree"`) || !strings.Contains(out, "path="+broken) {
+		t.Errorf("Expected a skip record with path=%s, got:\n%s", broken, out)
+	}
+}
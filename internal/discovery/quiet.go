@@ -0,0 +1,48 @@
+package discovery
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// QuietEnv names the environment variable that, when set to a non-empty
+// value, demotes discovery warnings to debug level so scripts and shell
+// prompts get clean stderr.
+const QuietEnv = "GWQ_QUIET_DISCOVERY"
+
+// quietRequested reports whether QuietEnv is set.
+func quietRequested() bool {
+	return os.Getenv(QuietEnv) != ""
+}
+
+// quietHandler passes records to the wrapped handler, logging warnings at
+// debug level instead. Errors are left untouched.
+type quietHandler struct {
+	h slog.Handler
+}
+
+func (q quietHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return q.h.Enabled(ctx, demote(level))
+}
+
+func (q quietHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Level = demote(r.Level)
+	return q.h.Handle(ctx, r)
+}
+
+func (q quietHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return quietHandler{h: q.h.WithAttrs(attrs)}
+}
+
+func (q quietHandler) WithGroup(name string) slog.Handler {
+	return quietHandler{h: q.h.WithGroup(name)}
+}
+
+// demote maps warn-level records to debug.
+func demote(level slog.Level) slog.Level {
+	if level >= slog.LevelWarn && level < slog.LevelError {
+		return slog.LevelDebug
+	}
+	return level
+}
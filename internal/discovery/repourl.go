@@ -0,0 +1,76 @@
+package discovery
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/d-kuro/gwq/internal/git"
+)
+
+// repositoryURLFunc reads the origin URL of the repository containing
+// worktreePath. Tests replace it to count the underlying git calls.
+var repositoryURLFunc = func(worktreePath string) (string, error) {
+	return git.New(worktreePath).GetRepositoryURL()
+}
+
+// repoURLCache memoizes repository URLs for one discovery run. All worktrees
+// of a repository share its config, so the URL is read once per main git
+// directory. It is safe for concurrent use; a nil cache reads every time.
+type repoURLCache struct {
+	mu      sync.Mutex
+	entries map[string]*repoURLEntry
+}
+
+type repoURLEntry struct {
+	once sync.Once
+	url  string
+	err  error
+}
+
+func newRepoURLCache() *repoURLCache {
+	return &repoURLCache{entries: make(map[string]*repoURLEntry)}
+}
+
+// get returns the repository URL for worktreePath, reading it only for the
+// first worktree of each repository. Concurrent callers for the same
+// repository wait for that single read.
+func (c *repoURLCache) get(worktreePath string) (string, error) {
+	key := mainGitDir(worktreePath)
+	if c == nil || key == "" {
+		return repositoryURLFunc(worktreePath)
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &repoURLEntry{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.url, e.err = repositoryURLFunc(worktreePath)
+	})
+	return e.url, e.err
+}
+
+// mainGitDir resolves the git directory shared by all worktrees of the
+// repository containing worktreePath, without running git: the .git
+// directory of a main worktree, or the commondir of a linked worktree's
+// gitdir. It returns "" when the layout is not recognized.
+func mainGitDir(worktreePath string) string {
+	gitDir, err := resolveGitDir(worktreePath)
+	if err != nil {
+		return ""
+	}
+	return canonicalPath(resolveCommonDir(gitDir))
+}
+
+// canonicalPath resolves symlinks so that the same directory reached through
+// different paths yields one cache key.
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
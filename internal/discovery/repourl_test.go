@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// setupRepoWithWorktrees creates one repository with n linked worktrees
+// under baseDir and returns the path of its main worktree.
+func setupRepoWithWorktrees(tb testing.TB, baseDir string, n int) string {
+	tb.Helper()
+
+	repoDir := filepath.Join(baseDir, "github.com", "user", "repo", "main")
+	repo := initRepoAt(tb, repoDir, "https://github.com/user/repo.git")
+	for i := range n {
+		dir := filepath.Join(baseDir, "github.com", "user", "repo", fmt.Sprintf("feature-%d", i))
+		if err := repo.run("worktree", "add", "-b", fmt.Sprintf("feature-%d", i), dir); err != nil {
+			tb.Fatalf("Failed to add worktree: %v", err)
+		}
+	}
+	return repoDir
+}
+
+// countRepositoryURLCalls wraps repositoryURLFunc and returns a counter of
+// the underlying reads.
+func countRepositoryURLCalls(tb testing.TB) *atomic.Int64 {
+	tb.Helper()
+
+	var calls atomic.Int64
+	orig := repositoryURLFunc
+	repositoryURLFunc = func(worktreePath string) (string, error) {
+		calls.Add(1)
+		return orig(worktreePath)
+	}
+	tb.Cleanup(func() { repositoryURLFunc = orig })
+	return &calls
+}
+
+func TestMainGitDir(t *testing.T) {
+	baseDir := t.TempDir()
+	repoDir := setupRepoWithWorktrees(t, baseDir, 2)
+
+	want := mainGitDir(repoDir)
+	if want == "" {
+		t.Fatal("mainGitDir(main worktree) returned empty string")
+	}
+	if got := canonicalPath(filepath.Join(repoDir, ".git")); got != want {
+		t.Errorf("mainGitDir(main worktree) = %q, want %q", want, got)
+	}
+
+	for i := range 2 {
+		dir := filepath.Join(baseDir, "github.com", "user", "repo", fmt.Sprintf("feature-%d", i))
+		if got := mainGitDir(dir); got != want {
+			t.Errorf("mainGitDir(%s) = %q, want %q", dir, got, want)
+		}
+	}
+
+	if got := mainGitDir(t.TempDir()); got != "" {
+		t.Errorf("mainGitDir(non-repo) = %q, want empty", got)
+	}
+}
+
+func TestDiscoverGlobalWorktrees_ReadsRepositoryURLOncePerRepo(t *testing.T) {
+	baseDir := t.TempDir()
+	setupRepoWithWorktrees(t, baseDir, 3)
+	otherDir := filepath.Join(baseDir, "github.com", "user", "other", "main")
+	initRepoAt(t, otherDir, "https://github.com/user/other.git")
+
+	tests := []struct {
+		name     string
+		discover func() ([]*GlobalWorktreeEntry, error)
+	}{
+		{name: "sequential", discover: func() ([]*GlobalWorktreeEntry, error) {
+			return DiscoverGlobalWorktrees(baseDir)
+		}},
+		{name: "parallel", discover: func() ([]*GlobalWorktreeEntry, error) {
+			return DiscoverGlobalWorktreesParallelContext(context.Background(), baseDir, nil)
+		}},
+		{name: "cached", discover: func() ([]*GlobalWorktreeEntry, error) {
+			return DiscoverGlobalWorktreesCached(baseDir, &DiscoverOptions{
+				NoCache:   true,
+				CachePath: filepath.Join(t.TempDir(), "cache.json"),
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := countRepositoryURLCalls(t)
+
+			entries, err := tt.discover()
+			if err != nil {
+				t.Fatalf("discovery error = %v", err)
+			}
+			if len(entries) != 5 {
+				t.Fatalf("got %d entries, want 5", len(entries))
+			}
+			for _, e := range entries {
+				if e.RepositoryInfo == nil || e.RepositoryURL == "" {
+					t.Errorf("entry %s has no repository info", e.Path)
+				}
+			}
+			if got := calls.Load(); got != 2 {
+				t.Errorf("repository URL read %d times, want 2 (once per repository)", got)
+			}
+		})
+	}
+}
+
+func BenchmarkDiscoverGlobalWorktrees_SharedRepository(b *testing.B) {
+	baseDir := b.TempDir()
+	setupRepoWithWorktrees(b, baseDir, 8)
+	calls := countRepositoryURLCalls(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = DiscoverGlobalWorktrees(baseDir)
+	}
+	b.ReportMetric(float64(calls.Load())/float64(b.N), "url-reads/op")
+}
@@ -3,39 +3,18 @@ package duration
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
 	"time"
-)
 
-// dayRegex matches day notation like "7d", "30d", etc.
-var dayRegex = regexp.MustCompile(`^(\d+)d$`)
+	"github.com/d-kuro/gwq/internal/utils"
+)
 
 // Parse parses a duration string that supports both standard Go durations
-// (e.g., "1h", "30m", "2h30m") and day notation (e.g., "1d", "7d", "30d").
+// (e.g., "1h", "30m", "2h30m") and calendar notation (e.g., "1d", "2w", "3mo").
+// Unlike utils.ParseDuration, the result must be positive.
 func Parse(s string) (time.Duration, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return 0, fmt.Errorf("empty duration string")
-	}
-
-	// Check for day notation first
-	if matches := dayRegex.FindStringSubmatch(s); len(matches) == 2 {
-		days, err := strconv.Atoi(matches[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid day value: %w", err)
-		}
-		if days <= 0 {
-			return 0, fmt.Errorf("days must be positive")
-		}
-		return time.Duration(days) * 24 * time.Hour, nil
-	}
-
-	// Fall back to standard Go duration parsing
-	d, err := time.ParseDuration(s)
+	d, err := utils.ParseDuration(s)
 	if err != nil {
-		return 0, fmt.Errorf("invalid duration format: %w", err)
+		return 0, err
 	}
 
 	if d <= 0 {
@@ -1,8 +1,10 @@
 package filesystem
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 )
 
 // FileSystemInterface defines the contract for file system operations
@@ -12,6 +14,7 @@ type FileSystemInterface interface {
 	Remove(name string) error
 	RemoveAll(path string) error
 	Rename(oldpath, newpath string) error
+	CopyWithOptions(src, dst string, opts CopyOptions) error
 
 	// Directory operations
 	MkdirAll(path string, perm os.FileMode) error
@@ -36,6 +39,11 @@ type FileSystemInterface interface {
 	UserHomeDir() (string, error)
 }
 
+// CopyOptions controls CopyWithOptions.
+type CopyOptions struct {
+	PreserveTimestamps bool // Give the copy the modification time of the source
+}
+
 // File interface abstracts file operations
 type File interface {
 	io.Reader
@@ -77,6 +85,43 @@ func (fs *StandardFileSystem) Rename(oldpath, newpath string) error {
 	return os.Rename(oldpath, newpath)
 }
 
+// CopyWithOptions copies the regular file src to dst, creating or
+// truncating dst with the permission bits of src. The parent directory of
+// dst must exist.
+func (fs *StandardFileSystem) CopyWithOptions(src, dst string, opts CopyOptions) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if opts.PreserveTimestamps {
+		// A zero access time leaves it unchanged.
+		return os.Chtimes(dst, time.Time{}, info.ModTime())
+	}
+	return nil
+}
+
 // MkdirAll creates a directory path
 func (fs *StandardFileSystem) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -453,6 +454,54 @@ func TestStandardFileSystem_UserHomeDir(t *testing.T) {
 	}
 }
 
+func TestStandardFileSystem_CopyWithOptions(t *testing.T) {
+	fs := NewStandardFileSystem()
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "src.sh")
+	if err := os.WriteFile(src, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(src, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      CopyOptions
+		wantMtime bool
+	}{
+		{name: "default", opts: CopyOptions{}},
+		{name: "preserve timestamps", opts: CopyOptions{PreserveTimestamps: true}, wantMtime: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "dst.sh")
+			if err := fs.CopyWithOptions(src, dst, tt.opts); err != nil {
+				t.Fatalf("CopyWithOptions() error = %v", err)
+			}
+			data, err := os.ReadFile(dst)
+			if err != nil || string(data) != "#!/bin/sh\n" {
+				t.Errorf("copied content = %q, %v", data, err)
+			}
+			info, err := os.Stat(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm() != 0755 {
+				t.Errorf("mode = %v, want 0755", info.Mode().Perm())
+			}
+			if got := info.ModTime().Equal(old); got != tt.wantMtime {
+				t.Errorf("mtime preserved = %v, want %v", got, tt.wantMtime)
+			}
+		})
+	}
+
+	if err := fs.CopyWithOptions(tmpDir, filepath.Join(t.TempDir(), "dir"), CopyOptions{}); err == nil {
+		t.Error("CopyWithOptions() should reject a directory")
+	}
+}
+
 // Mock FileSystem for testing interface compliance
 type MockFileSystem struct {
 	files map[string][]byte
@@ -493,6 +542,15 @@ func (m *MockFileSystem) Rename(oldpath, newpath string) error {
 	return nil
 }
 
+func (m *MockFileSystem) CopyWithOptions(src, dst string, opts CopyOptions) error {
+	data, exists := m.files[src]
+	if !exists {
+		return os.ErrNotExist
+	}
+	m.files[dst] = data
+	return nil
+}
+
 func (m *MockFileSystem) MkdirAll(path string, perm os.FileMode) error {
 	return nil // Mock implementation
 }
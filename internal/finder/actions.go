@@ -0,0 +1,89 @@
+package finder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/ktr0731/go-fuzzyfinder"
+)
+
+// find runs the fuzzy finder. It is a variable so tests can replace the
+// interactive terminal.
+var find = fuzzyfinder.Find
+
+// KeyBinding is an action that can be run on a worktree from the finder.
+//
+// go-fuzzyfinder reserves its own keys (ctrl-d aborts, for example) and has no
+// hook for custom ones, so bindings are offered in a menu after a worktree has
+// been picked. Key is shown there as the binding's mnemonic, e.g. "ctrl-d".
+type KeyBinding struct {
+	Key    string
+	Label  string
+	Action func(wt models.Worktree) error
+}
+
+// ActionResult is the outcome of SelectWorktreeWithActions.
+type ActionResult struct {
+	Worktree models.Worktree
+	// Action is the binding that ran, or nil when the worktree was simply
+	// selected.
+	Action *KeyBinding
+}
+
+// SelectWorktreeWithActions lets the user pick a worktree and then either
+// select it or run one of bindings on it. Without bindings it behaves like
+// SelectWorktree.
+func (f *Finder) SelectWorktreeWithActions(worktrees []models.Worktree, bindings []KeyBinding) (*ActionResult, error) {
+	wt, err := f.SelectWorktree(worktrees)
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) == 0 {
+		return &ActionResult{Worktree: *wt}, nil
+	}
+
+	items := actionMenu(bindings)
+	idx, err := find(items, func(i int) string { return items[i] },
+		fuzzyfinder.WithPromptString(fmt.Sprintf("Action for %s> ", wt.Branch)))
+	if err != nil {
+		return nil, err
+	}
+	return dispatchAction(*wt, bindings, idx)
+}
+
+// actionMenu returns the entries of the action menu: "select" followed by
+// one entry per binding.
+func actionMenu(bindings []KeyBinding) []string {
+	width := len("enter")
+	for _, b := range bindings {
+		width = max(width, len(b.Key))
+	}
+
+	items := []string{fmt.Sprintf("%-*s  select", width, "enter")}
+	for _, b := range bindings {
+		items = append(items, fmt.Sprintf("%-*s  %s", width, b.Key, strings.ToLower(b.Label)))
+	}
+	return items
+}
+
+// dispatchAction runs the binding chosen at menu index idx, where 0 means
+// plain selection.
+func dispatchAction(wt models.Worktree, bindings []KeyBinding, idx int) (*ActionResult, error) {
+	if idx <= 0 {
+		return &ActionResult{Worktree: wt}, nil
+	}
+	if idx > len(bindings) {
+		return nil, fmt.Errorf("invalid action index %d", idx)
+	}
+
+	binding := &bindings[idx-1]
+	result := &ActionResult{Worktree: wt, Action: binding}
+	if binding.Action == nil {
+		return result, nil
+	}
+	if err := binding.Action(wt); err != nil {
+		return result, fmt.Errorf("%s %s: %w", strings.ToLower(binding.Label), wt.Branch, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package finder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+	"github.com/ktr0731/go-fuzzyfinder"
+)
+
+// stubFind replaces the interactive finder with one that returns picks in
+// order and records the items it was shown.
+func stubFind(t *testing.T, picks ...int) *[][]string {
+	t.Helper()
+	var shown [][]string
+	orig := find
+	t.Cleanup(func() { find = orig })
+
+	find = func(slice any, itemFunc func(i int) string, opts ...fuzzyfinder.Option) (int, error) {
+		n := 0
+		switch s := slice.(type) {
+		case []models.Worktree:
+			n = len(s)
+		case []string:
+			n = len(s)
+		}
+		var items []string
+		for i := range n {
+			items = append(items, itemFunc(i))
+		}
+		shown = append(shown, items)
+
+		if len(picks) == 0 {
+			return 0, fuzzyfinder.ErrAbort
+		}
+		pick := picks[0]
+		picks = picks[1:]
+		return pick, nil
+	}
+	return &shown
+}
+
+func TestSelectWorktreeWithActions(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Path: "/wt/main", Branch: "main", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature"},
+	}
+
+	var deleted, opened []string
+	bindings := []KeyBinding{
+		{Key: "ctrl-d", Label: "Delete", Action: func(wt models.Worktree) error {
+			deleted = append(deleted, wt.Branch)
+			return nil
+		}},
+		{Key: "ctrl-o", Label: "Open", Action: func(wt models.Worktree) error {
+			opened = append(opened, wt.Branch)
+			return errors.New("no editor")
+		}},
+	}
+	f := New(nil, &models.FinderConfig{})
+
+	t.Run("Select", func(t *testing.T) {
+		shown := stubFind(t, 1, 0)
+		result, err := f.SelectWorktreeWithActions(worktrees, bindings)
+		if err != nil {
+			t.Fatalf("SelectWorktreeWithActions() error = %v", err)
+		}
+		if result.Worktree.Branch != "feature" || result.Action != nil {
+			t.Errorf("result = %+v, want plain selection of feature", result)
+		}
+		menu := (*shown)[1]
+		if len(menu) != 3 || !strings.Contains(menu[1], "ctrl-d") || !strings.Contains(menu[2], "open") {
+			t.Errorf("action menu = %q", menu)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		stubFind(t, 1, 1)
+		result, err := f.SelectWorktreeWithActions(worktrees, bindings)
+		if err != nil {
+			t.Fatalf("SelectWorktreeWithActions() error = %v", err)
+		}
+		if result.Action == nil || result.Action.Key != "ctrl-d" {
+			t.Errorf("Action = %+v, want ctrl-d", result.Action)
+		}
+		if len(deleted) != 1 || deleted[0] != "feature" {
+			t.Errorf("deleted = %v, want [feature]", deleted)
+		}
+	})
+
+	t.Run("ActionError", func(t *testing.T) {
+		stubFind(t, 0, 2)
+		result, err := f.SelectWorktreeWithActions(worktrees, bindings)
+		if err == nil || !strings.Contains(err.Error(), "open main: no editor") {
+			t.Errorf("error = %v, want the action's error", err)
+		}
+		if result == nil || result.Action == nil || result.Action.Label != "Open" {
+			t.Errorf("result = %+v, want the Open action reported", result)
+		}
+	})
+
+	t.Run("CancelActionMenu", func(t *testing.T) {
+		stubFind(t, 1)
+		if _, err := f.SelectWorktreeWithActions(worktrees, bindings); !errors.Is(err, ErrSelectionCancelled) {
+			t.Errorf("error = %v, want ErrSelectionCancelled", err)
+		}
+	})
+
+	t.Run("NoBindings", func(t *testing.T) {
+		shown := stubFind(t, 0)
+		result, err := f.SelectWorktreeWithActions(worktrees, nil)
+		if err != nil {
+			t.Fatalf("SelectWorktreeWithActions() error = %v", err)
+		}
+		if result.Worktree.Branch != "main" || len(*shown) != 1 {
+			t.Errorf("result = %+v after %d finder calls, want main without a menu", result, len(*shown))
+		}
+	})
+}
+
+func TestDispatchAction_InvalidIndex(t *testing.T) {
+	if _, err := dispatchAction(models.Worktree{}, []KeyBinding{{Key: "ctrl-d"}}, 2); err == nil {
+		t.Error("dispatchAction() with an out-of-range index expected an error")
+	}
+}
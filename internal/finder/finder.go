@@ -3,21 +3,30 @@ package finder
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/internal/template"
 	"github.com/d-kuro/gwq/internal/tmux"
+	"github.com/d-kuro/gwq/internal/ui"
 	"github.com/d-kuro/gwq/internal/utils"
 	"github.com/d-kuro/gwq/pkg/models"
 	"github.com/ktr0731/go-fuzzyfinder"
 )
 
+// ErrSelectionCancelled is returned when the user aborts a selection
+// (e.g. with Esc or Ctrl+C).
+var ErrSelectionCancelled = fuzzyfinder.ErrAbort
+
 // Finder provides fuzzy finder functionality.
 type Finder struct {
 	git          *git.Git
 	config       *models.FinderConfig
 	useTildeHome bool
+	display      *template.DisplayProcessor      // finder.display_template, nil for the built-in format
+	statuses     map[string]models.WorktreeState // Worktree states by path, if collected
 }
 
 // New creates a new Finder instance.
@@ -34,7 +43,34 @@ func NewWithUI(g *git.Git, config *models.FinderConfig, uiConfig *models.UIConfi
 		git:          g,
 		config:       config,
 		useTildeHome: uiConfig.TildeHome,
+		display:      displayTemplate(config),
+	}
+}
+
+// NewWithStatus creates a Finder for commands that have already collected
+// worktree states, keyed by worktree path, so finder.display_template can
+// show them through {{.Status}} and {{.StatusIcon}}.
+func NewWithStatus(g *git.Git, config *models.FinderConfig, uiConfig *models.UIConfig, statuses map[string]models.WorktreeState) *Finder {
+	f := NewWithUI(g, config, uiConfig)
+	f.statuses = statuses
+	return f
+}
+
+// displayTemplate parses finder.display_template, returning nil when it is
+// unset or invalid ('gwq config validate' reports the latter) so the
+// built-in format is used.
+func displayTemplate(config *models.FinderConfig) *template.DisplayProcessor {
+	if config == nil || config.DisplayTemplate == "" {
+		return nil
+	}
+	p, err := template.NewDisplay(config.DisplayTemplate)
+	if err != nil {
+		return nil
+	}
+	if err := p.SetStatusIcons(config.StatusIcons); err != nil {
+		return nil
 	}
+	return p
 }
 
 // SelectWorktree displays a fuzzy finder for worktree selection.
@@ -42,6 +78,10 @@ func (f *Finder) SelectWorktree(worktrees []models.Worktree) (*models.Worktree,
 	if len(worktrees) == 0 {
 		return nil, fmt.Errorf("no worktrees available for selection")
 	}
+	worktrees, _, err := f.sortForDisplay(worktrees)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := []fuzzyfinder.Option{
 		fuzzyfinder.WithPromptString("Select worktree> "),
@@ -56,23 +96,7 @@ func (f *Finder) SelectWorktree(worktrees []models.Worktree) (*models.Worktree,
 		}))
 	}
 
-	idx, err := fuzzyfinder.Find(
-		worktrees,
-		func(i int) string {
-			wt := worktrees[i]
-			marker := ""
-			if wt.IsMain {
-				marker = "[main] "
-			}
-			path := wt.Path
-			if f.useTildeHome {
-				path = utils.TildePath(path)
-			}
-			return fmt.Sprintf("%s%s (%s)", marker, wt.Branch, path)
-		},
-		opts...,
-	)
-
+	idx, err := find(worktrees, f.formatWorktreeForDisplay(worktrees), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +147,29 @@ func (f *Finder) SelectBranch(branches []models.Branch) (*models.Branch, error)
 
 // SelectMultipleWorktrees displays a fuzzy finder for multiple worktree selection.
 func (f *Finder) SelectMultipleWorktrees(worktrees []models.Worktree) ([]models.Worktree, error) {
+	return f.SelectMultipleWorktreesPreselected(worktrees, nil)
+}
+
+// SelectMultipleWorktreesPreselected is SelectMultipleWorktrees with the
+// worktrees for which preselected returns true already selected. A nil
+// preselected selects nothing.
+func (f *Finder) SelectMultipleWorktreesPreselected(worktrees []models.Worktree, preselected func(i int) bool) ([]models.Worktree, error) {
 	if len(worktrees) == 0 {
 		return nil, fmt.Errorf("no worktrees available for multiple selection")
 	}
+	worktrees, order, err := f.sortForDisplay(worktrees)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := []fuzzyfinder.Option{
 		fuzzyfinder.WithPromptString("Select worktrees (Tab to select multiple)> "),
 	}
+	if preselected != nil {
+		opts = append(opts, fuzzyfinder.WithPreselected(func(i int) bool {
+			return preselected(order[i])
+		}))
+	}
 
 	if f.config.Preview {
 		opts = append(opts, fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
@@ -140,22 +180,7 @@ func (f *Finder) SelectMultipleWorktrees(worktrees []models.Worktree) ([]models.
 		}))
 	}
 
-	indices, err := fuzzyfinder.FindMulti(
-		worktrees,
-		func(i int) string {
-			wt := worktrees[i]
-			marker := ""
-			if wt.IsMain {
-				marker = "[main] "
-			}
-			path := wt.Path
-			if f.useTildeHome {
-				path = utils.TildePath(path)
-			}
-			return fmt.Sprintf("%s%s (%s)", marker, wt.Branch, path)
-		},
-		opts...,
-	)
+	indices, err := fuzzyfinder.FindMulti(worktrees, f.formatWorktreeForDisplay(worktrees), opts...)
 
 	if err != nil {
 		return nil, err
@@ -169,6 +194,31 @@ func (f *Finder) SelectMultipleWorktrees(worktrees []models.Worktree) ([]models.
 	return selected, nil
 }
 
+// sortForDisplay returns a copy of worktrees in finder.sort_by order and,
+// for each entry, its index in worktrees.
+func (f *Finder) sortForDisplay(worktrees []models.Worktree) ([]models.Worktree, []int, error) {
+	sorted := slices.Clone(worktrees)
+	order := make([]int, len(worktrees))
+	for i := range order {
+		order[i] = i
+	}
+	if f.config.SortBy == "" {
+		return sorted, order, nil
+	}
+
+	if err := ui.SortWorktrees(sorted, f.config.SortBy); err != nil {
+		return nil, nil, fmt.Errorf("invalid finder.sort_by: %w", err)
+	}
+	index := make(map[string]int, len(worktrees))
+	for i, wt := range worktrees {
+		index[wt.Path] = i
+	}
+	for i, wt := range sorted {
+		order[i] = index[wt.Path]
+	}
+	return sorted, order, nil
+}
+
 // SelectSession displays a fuzzy finder for session selection.
 func (f *Finder) SelectSession(sessions []*tmux.Session) (*tmux.Session, error) {
 	if len(sessions) == 0 {
@@ -262,6 +312,44 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
+// formatWorktreeForDisplay returns the line shown for each worktree in the finder.
+func (f *Finder) formatWorktreeForDisplay(worktrees []models.Worktree) func(int) string {
+	return func(i int) string {
+		wt := worktrees[i]
+		path := wt.Path
+		if f.useTildeHome {
+			path = utils.TildePath(path)
+		}
+		if f.display != nil {
+			if line, err := f.display.Render(f.displayData(wt, path)); err == nil {
+				return line
+			}
+		}
+		marker := ""
+		if wt.IsMain {
+			marker = "[main] "
+		}
+		return fmt.Sprintf("%s%s (%s)", marker, wt.Branch, path)
+	}
+}
+
+// displayData returns the finder.display_template data of wt, shown at path.
+func (f *Finder) displayData(wt models.Worktree, path string) *template.TemplateData {
+	data := &template.TemplateData{
+		Branch: wt.Branch,
+		Path:   path,
+		IsMain: wt.IsMain,
+		Status: f.statuses[wt.Path],
+	}
+	if info := wt.RepositoryInfo; info != nil {
+		data.Host = info.Host
+		data.Owner = info.Owner
+		data.Repository = info.Repository
+		data.Hash = template.ShortHash(info.FullPath + "/" + wt.Branch)
+	}
+	return data
+}
+
 // generateWorktreePreview generates preview content for a worktree.
 func (f *Finder) generateWorktreePreview(wt models.Worktree, maxLines int) string {
 	path := wt.Path
@@ -275,6 +363,10 @@ func (f *Finder) generateWorktreePreview(wt models.Worktree, maxLines int) strin
 		fmt.Sprintf("Created: %s", wt.CreatedAt.Format("2006-01-02 15:04")),
 	}
 
+	if wt.RepositoryInfo != nil {
+		preview = append(preview, fmt.Sprintf("Repository: %s", wt.RepositoryInfo.FullPath))
+	}
+
 	if wt.IsMain {
 		preview = append(preview, "Type: Main worktree")
 	} else {
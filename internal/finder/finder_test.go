@@ -429,6 +429,31 @@ func TestGenerateWorktreePreview_WithTildeHome(t *testing.T) {
 	}
 }
 
+func TestGenerateWorktreePreview_RepositoryInfo(t *testing.T) {
+	wt := models.Worktree{
+		Branch: "feature-branch",
+		Path:   "/home/user/project/feature",
+		RepositoryInfo: &models.RepositoryInfo{
+			Host:       "github.com",
+			Owner:      "user",
+			Repository: "project",
+			FullPath:   "github.com/user/project",
+		},
+	}
+
+	finder := &Finder{git: nil}
+	preview := finder.generateWorktreePreview(wt, 20)
+
+	if !strings.Contains(preview, "Repository: github.com/user/project") {
+		t.Errorf("Expected preview to contain the repository, got:\n%s", preview)
+	}
+
+	wt.RepositoryInfo = nil
+	if preview := finder.generateWorktreePreview(wt, 20); strings.Contains(preview, "Repository:") {
+		t.Errorf("Expected no repository line without RepositoryInfo, got:\n%s", preview)
+	}
+}
+
 func TestGenerateBranchPreview_Current(t *testing.T) {
 	branch := models.Branch{
 		Name:      "main",
@@ -587,6 +612,67 @@ func TestFormatSessionForDisplay(t *testing.T) {
 	}
 }
 
+func TestFormatWorktreeForDisplay(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Branch: "main", Path: "/home/user/project", IsMain: true},
+		{Branch: "project:feature", Path: "/home/user/worktrees/feature"},
+	}
+
+	finder := &Finder{}
+	formatter := finder.formatWorktreeForDisplay(worktrees)
+
+	if got, want := formatter(0), "[main] main (/home/user/project)"; got != want {
+		t.Errorf("formatWorktreeForDisplay(0) = %s, expected %s", got, want)
+	}
+	if got, want := formatter(1), "project:feature (/home/user/worktrees/feature)"; got != want {
+		t.Errorf("formatWorktreeForDisplay(1) = %s, expected %s", got, want)
+	}
+}
+
+func TestFormatWorktreeForDisplay_Template(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Branch: "main", Path: "/home/user/project", IsMain: true},
+		{Branch: "feature", Path: "/home/user/worktrees/feature"},
+	}
+	config := &models.FinderConfig{
+		DisplayTemplate: "{{.StatusIcon}} {{if .IsMain}}[main] {{end}}{{.Branch}}",
+		StatusIcons:     map[string]string{"modified": "M"},
+	}
+
+	tests := []struct {
+		name     string
+		finder   *Finder
+		expected []string
+	}{
+		{
+			name:     "with status",
+			finder:   NewWithStatus(nil, config, &models.UIConfig{}, map[string]models.WorktreeState{"/home/user/worktrees/feature": models.WorktreeStatusModified}),
+			expected: []string{"  [main] main", "M feature"},
+		},
+		{
+			name:     "without status",
+			finder:   NewWithUI(nil, config, &models.UIConfig{}),
+			expected: []string{"  [main] main", "  feature"},
+		},
+		{
+			name:     "invalid template falls back to the built-in format",
+			finder:   NewWithUI(nil, &models.FinderConfig{DisplayTemplate: "{{.Branch"}, &models.UIConfig{}),
+			expected: []string{"[main] main (/home/user/project)", "feature (/home/user/worktrees/feature)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := tt.finder.formatWorktreeForDisplay(worktrees)
+			for i, want := range tt.expected {
+				if got := formatter(i); got != want {
+					t.Errorf("formatWorktreeForDisplay(%d) = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
 // Benchmark tests
 func BenchmarkTruncateHash(b *testing.B) {
 	hash := "a1b2c3d4e5f6789012345678901234567890abcd"
@@ -665,3 +751,67 @@ func BenchmarkGenerateBranchPreview(b *testing.B) {
 		finder.generateBranchPreview(branch, 20)
 	}
 }
+
+func TestSelectWorktree_SortBy(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Path: "/wt/zeta", Branch: "zeta"},
+		{Path: "/wt/alpha", Branch: "alpha"},
+		{Path: "/wt/main", Branch: "main", IsMain: true},
+	}
+
+	tests := []struct {
+		name      string
+		sortBy    string
+		wantFirst string
+		wantErr   bool
+	}{
+		{name: "Unsorted", wantFirst: "zeta"},
+		{name: "ByName", sortBy: "name", wantFirst: "alpha"},
+		{name: "Invalid", sortBy: "size", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown := stubFind(t, 0)
+			f := New(nil, &models.FinderConfig{SortBy: tt.sortBy})
+
+			got, err := f.SelectWorktree(worktrees)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("SelectWorktree() error = nil, want invalid sort key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectWorktree() error = %v", err)
+			}
+			if got.Branch != tt.wantFirst {
+				t.Errorf("picked %q, want %q", got.Branch, tt.wantFirst)
+			}
+			if len(*shown) != 1 || !strings.Contains((*shown)[0][0], tt.wantFirst) {
+				t.Errorf("first entry shown = %v, want %q", *shown, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestSortForDisplay_KeepsOriginalIndex(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Path: "/wt/b", Branch: "b"},
+		{Path: "/wt/c", Branch: "c"},
+		{Path: "/wt/a", Branch: "a"},
+	}
+	f := New(nil, &models.FinderConfig{SortBy: "name"})
+
+	sorted, order, err := f.sortForDisplay(worktrees)
+	if err != nil {
+		t.Fatalf("sortForDisplay() error = %v", err)
+	}
+	for i, wt := range sorted {
+		if worktrees[order[i]].Path != wt.Path {
+			t.Errorf("order[%d] = %d points at %s, want %s", i, order[i], worktrees[order[i]].Path, wt.Path)
+		}
+	}
+	if worktrees[0].Branch != "b" {
+		t.Error("sortForDisplay() modified its input")
+	}
+}
@@ -4,15 +4,44 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 )
 
+// DefaultMaxOutputBytes caps how much standard output a single git command
+// may produce before it is stopped. It guards against commands such as
+// 'git status' in a huge worktree exhausting memory.
+const DefaultMaxOutputBytes int64 = 64 << 20
+
+// ErrOutputTooLarge is returned, wrapped in an *Error, when a git command
+// produces more output than the configured limit.
+var ErrOutputTooLarge = errors.New("output exceeds size limit")
+
 // Git provides Git command operations.
 type Git struct {
-	workDir string
+	workDir        string
+	maxOutputBytes int64 // 0 means DefaultMaxOutputBytes, negative means unlimited
+}
+
+// Error is returned when a git command exits unsuccessfully.
+type Error struct {
+	Args   []string // arguments passed to git
+	Stderr string   // captured standard error
+	Err    error    // underlying error, set when the command was cancelled
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+	}
+	return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), e.Stderr)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
 // New creates a new Git instance.
@@ -31,6 +60,12 @@ func NewFromCwd() (*Git, error) {
 	return New(cwd), nil
 }
 
+// SetMaxOutputBytes sets the output limit for commands run by g. Zero
+// restores DefaultMaxOutputBytes and a negative value disables the limit.
+func (g *Git) SetMaxOutputBytes(n int64) {
+	g.maxOutputBytes = n
+}
+
 // RunCommand executes a git command with the provided arguments and returns the output.
 // The command is executed in the Git instance's working directory if set.
 func (g *Git) RunCommand(args ...string) (string, error) {
@@ -44,39 +79,80 @@ func (g *Git) RunWithContext(ctx context.Context, args ...string) (string, error
 
 // run executes a git command.
 func (g *Git) run(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	if g.workDir != "" {
-		cmd.Dir = g.workDir
-	}
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), stderr.String())
-	}
-
-	return stdout.String(), nil
+	return g.runWithContext(context.Background(), args...)
 }
 
 // runWithContext executes a git command with context support.
 func (g *Git) runWithContext(ctx context.Context, args ...string) (string, error) {
+	stdout, _, err := g.execute(ctx, args...)
+	return stdout, err
+}
+
+// runReport executes a git command and returns what it wrote to stderr,
+// for commands such as 'worktree prune --verbose' that report progress there.
+func (g *Git) runReport(args ...string) (string, error) {
+	_, stderr, err := g.execute(context.Background(), args...)
+	return stderr, err
+}
+
+// execute runs a git command and returns its stdout and stderr.
+func (g *Git) execute(ctx context.Context, args ...string) (string, string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
 	if g.workDir != "" {
 		cmd.Dir = g.workDir
 	}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	stdout := &limitedBuffer{limit: g.outputLimit()}
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	err := cmd.Run()
+	// Stopping the copy makes git fail on the closed pipe, so check the
+	// limit before interpreting the exit status.
+	if stdout.exceeded {
+		return "", "", &Error{Args: args, Err: fmt.Errorf("%w (%d bytes)", ErrOutputTooLarge, stdout.limit)}
+	}
+	if err != nil {
 		if ctx.Err() != nil {
-			return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), ctx.Err())
+			return "", "", &Error{Args: args, Err: ctx.Err()}
 		}
-		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), stderr.String())
+		return "", "", &Error{Args: args, Stderr: stderr.String()}
 	}
 
-	return stdout.String(), nil
+	return stdout.String(), stderr.String(), nil
+}
+
+// outputLimit returns the effective output limit, or -1 for none.
+func (g *Git) outputLimit() int64 {
+	switch {
+	case g.maxOutputBytes == 0:
+		return DefaultMaxOutputBytes
+	case g.maxOutputBytes < 0:
+		return -1
+	default:
+		return g.maxOutputBytes
+	}
+}
+
+// limitedBuffer collects output and fails writes once more than limit bytes
+// have been written. A negative limit disables the check. The buffer is a
+// named field rather than embedded so that io.Copy cannot bypass Write
+// through bytes.Buffer's ReadFrom.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int64
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit >= 0 && int64(b.buf.Len())+int64(len(p)) > b.limit {
+		b.exceeded = true
+		return 0, ErrOutputTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
 }
@@ -8,9 +8,12 @@ import (
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
-// ListBranches returns a list of all branches.
+// ListBranches returns a list of all branches. With includeRemote,
+// remote-tracking branches are included with IsRemote set and named
+// "<remote>/<branch>"; symbolic refs such as origin/HEAD are skipped.
 func (g *Git) ListBranches(includeRemote bool) ([]models.Branch, error) {
-	args := []string{"branch", "-v", "--format=%(refname:short)|%(HEAD)|%(committerdate:iso)|%(objectname)|%(subject)|%(authorname)"}
+	// The subject comes last because it may itself contain the separator.
+	args := []string{"branch", "--format=%(refname)|%(HEAD)|%(symref)|%(committerdate:iso)|%(objectname)|%(authorname)|%(subject)"}
 	if includeRemote {
 		args = append(args, "-a")
 	}
@@ -28,21 +31,33 @@ func (g *Git) ListBranches(includeRemote bool) ([]models.Branch, error) {
 			continue
 		}
 
-		parts := strings.Split(line, "|")
-		if len(parts) < 6 {
+		parts := strings.SplitN(line, "|", 7)
+		if len(parts) < 7 {
 			continue
 		}
 
-		name := parts[0]
+		refname := parts[0]
 		isCurrent := parts[1] == "*"
-		dateStr := parts[2]
-		hash := parts[3]
-		message := parts[4]
+		symref := parts[2]
+		dateStr := parts[3]
+		hash := parts[4]
 		author := parts[5]
-
-		isRemote := strings.HasPrefix(name, "remotes/")
-		if isRemote {
-			name = strings.TrimPrefix(name, "remotes/")
+		message := parts[6]
+
+		var name string
+		var isRemote bool
+		switch {
+		case strings.HasPrefix(refname, "refs/heads/"):
+			name = strings.TrimPrefix(refname, "refs/heads/")
+		case strings.HasPrefix(refname, "refs/remotes/"):
+			if symref != "" {
+				continue
+			}
+			name = strings.TrimPrefix(refname, "refs/remotes/")
+			isRemote = true
+		default:
+			// e.g. "(HEAD detached at ...)"
+			continue
 		}
 
 		date, _ := time.Parse("2006-01-02 15:04:05 -0700", dateStr)
@@ -80,6 +95,138 @@ func (g *Git) DeleteBranch(branch string, force bool) error {
 	return nil
 }
 
+// RenameBranch renames a local branch.
+func (g *Git) RenameBranch(oldName, newName string) error {
+	if _, err := g.run("branch", "-m", oldName, newName); err != nil {
+		return fmt.Errorf("failed to rename branch %s to %s: %w", oldName, newName, err)
+	}
+
+	return nil
+}
+
+// MergedBranches returns the set of local branches that are fully merged
+// into into, as listed by 'git branch --merged'.
+func (g *Git) MergedBranches(into string) (map[string]bool, error) {
+	output, err := g.run("branch", "--merged", into, "--format=%(refname:short)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches merged into %s: %w", into, err)
+	}
+
+	merged := make(map[string]bool)
+	for line := range strings.Lines(output) {
+		if name := strings.TrimSpace(line); name != "" {
+			merged[name] = true
+		}
+	}
+	return merged, nil
+}
+
+// BranchHasOwnCommits reports whether branch has advanced since it was
+// created, i.e. it is not a fresh branch still sitting on its starting
+// point. The branch reflog records the starting point; without one, a
+// branch pointing at base's commit is treated as having no commits.
+func (g *Git) BranchHasOwnCommits(branch, base string) (bool, error) {
+	tip, err := g.run("rev-parse", "--verify", "--quiet", "refs/heads/"+branch+"^{commit}")
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve branch %s: %w", branch, err)
+	}
+	tip = strings.TrimSpace(tip)
+
+	output, err := g.run("reflog", "show", "--format=%H", "refs/heads/"+branch)
+	if err != nil {
+		return false, fmt.Errorf("failed to read reflog of %s: %w", branch, err)
+	}
+	if entries := strings.Fields(output); len(entries) > 0 {
+		return entries[len(entries)-1] != tip, nil
+	}
+
+	baseTip, err := g.run("rev-parse", "--verify", "--quiet", base+"^{commit}")
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", base, err)
+	}
+	return strings.TrimSpace(baseTip) != tip, nil
+}
+
+// DefaultBranch returns the branch origin/HEAD points to, or main or master
+// when the remote HEAD is unknown and one of them exists locally.
+func (g *Git) DefaultBranch() (string, error) {
+	if output, err := g.run("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if branch, ok := strings.CutPrefix(strings.TrimSpace(output), "origin/"); ok && branch != "" {
+			return branch, nil
+		}
+	}
+
+	for _, candidate := range []string{"main", "master"} {
+		if _, err := g.run("rev-parse", "--verify", "--quiet", "refs/heads/"+candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the default branch")
+}
+
+// Upstream returns the remote and remote branch behind ref. ref may be a
+// remote-tracking ref such as origin/main or a local branch with an
+// upstream; for anything else remote and branch are empty.
+func (g *Git) Upstream(ref string) (remote, branch string, err error) {
+	if _, err := g.run("rev-parse", "--verify", "--quiet", "refs/heads/"+ref); err == nil {
+		output, err := g.run("for-each-ref", "--format=%(upstream:remotename)|%(upstream:remoteref)", "refs/heads/"+ref)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read upstream of %s: %w", ref, err)
+		}
+		remote, remoteRef, _ := strings.Cut(strings.TrimSpace(output), "|")
+		// "." is the remote of a branch tracking another local branch.
+		if remote == "" || remote == "." {
+			return "", "", nil
+		}
+		return remote, strings.TrimPrefix(remoteRef, "refs/heads/"), nil
+	}
+
+	name := strings.TrimPrefix(ref, "refs/remotes/")
+	if _, err := g.run("rev-parse", "--verify", "--quiet", "refs/remotes/"+name); err != nil {
+		return "", "", nil
+	}
+	output, err := g.run("remote")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	// Remote names may contain slashes, so prefer the longest match.
+	for line := range strings.Lines(output) {
+		candidate := strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(name, candidate+"/"); ok && rest != "" && len(candidate) > len(remote) {
+			remote, branch = candidate, rest
+		}
+	}
+	return remote, branch, nil
+}
+
+// FetchBranch fetches branch from remote, updating its remote-tracking ref.
+func (g *Git) FetchBranch(remote, branch string) error {
+	if _, err := g.run("fetch", remote, branch); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w", branch, remote, err)
+	}
+	return nil
+}
+
+// IsCommitish reports whether ref names a commit, e.g. a branch, tag or
+// commit hash.
+func (g *Git) IsCommitish(ref string) bool {
+	_, err := g.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// IsAncestor reports whether ancestor is reachable from descendant.
+func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
+	commit, err := g.run("rev-parse", "--verify", "--quiet", ancestor+"^{commit}")
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", ancestor, err)
+	}
+	base, err := g.run("merge-base", ancestor, descendant)
+	if err != nil {
+		return false, fmt.Errorf("failed to find merge base of %s and %s: %w", ancestor, descendant, err)
+	}
+	return strings.TrimSpace(base) == strings.TrimSpace(commit), nil
+}
+
 // getCurrentBranch returns the current branch name for a specific worktree.
 func (g *Git) getCurrentBranch(worktreePath string) string {
 	oldWorkDir := g.workDir
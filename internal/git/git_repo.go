@@ -109,3 +109,23 @@ func (g *Git) getRootDir() (string, error) {
 	}
 	return strings.TrimSpace(output), nil
 }
+
+// Diff returns the uncommitted changes, staged and unstaged, of the worktree
+// at path as 'git diff HEAD' prints them, or the --stat summary when stat is
+// set. args are passed on to git diff after the gwq-chosen options.
+func (g *Git) Diff(path string, stat bool, args ...string) (string, error) {
+	output, err := g.run(diffArgs(path, stat, args)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s: %w", path, err)
+	}
+	return output, nil
+}
+
+// diffArgs assembles the git arguments of Diff.
+func diffArgs(path string, stat bool, extra []string) []string {
+	args := []string{"-C", path, "diff", "HEAD"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	return append(args, extra...)
+}
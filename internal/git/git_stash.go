@@ -0,0 +1,46 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StashConflictError is returned by StashApply when the stash was applied
+// but left merge conflicts in the worktree.
+type StashConflictError struct {
+	Ref   string   // stash that was applied
+	Files []string // paths with unresolved conflicts
+}
+
+func (e *StashConflictError) Error() string {
+	return fmt.Sprintf("applying %s produced conflicts in: %s", e.Ref, strings.Join(e.Files, ", "))
+}
+
+// StashApply applies the stash ref (e.g. "stash@{0}") to the worktree at
+// worktreePath without dropping it. If the apply stops with merge conflicts,
+// the conflicted files are left in place and a *StashConflictError is returned.
+func (g *Git) StashApply(worktreePath, ref string) error {
+	if ref == "" {
+		ref = "stash@{0}"
+	}
+
+	_, applyErr := g.run("-C", worktreePath, "stash", "apply", ref)
+	if applyErr == nil {
+		return nil
+	}
+
+	output, err := g.run("-C", worktreePath, "diff", "--name-only", "--diff-filter=U")
+	if err == nil {
+		var files []string
+		for _, line := range strings.Split(output, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+		if len(files) > 0 {
+			return &StashConflictError{Ref: ref, Files: files}
+		}
+	}
+
+	return fmt.Errorf("failed to apply %s: %w", ref, applyErr)
+}
@@ -1,10 +1,13 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -212,7 +215,7 @@ func TestAddWorktree(t *testing.T) {
 
 		// Add worktree for existing branch
 		worktreePath := filepath.Join(t.TempDir(), "existing-wt")
-		err := g.AddWorktree(worktreePath, "existing-branch", false)
+		err := g.AddWorktree(worktreePath, "existing-branch", false, false)
 		if err != nil {
 			t.Fatalf("AddWorktree() error = %v", err)
 		}
@@ -226,7 +229,7 @@ func TestAddWorktree(t *testing.T) {
 	t.Run("NewBranch", func(t *testing.T) {
 		// Add worktree with new branch
 		worktreePath := filepath.Join(t.TempDir(), "new-wt")
-		err := g.AddWorktree(worktreePath, "new-branch", true)
+		err := g.AddWorktree(worktreePath, "new-branch", true, false)
 		if err != nil {
 			t.Fatalf("AddWorktree() with new branch error = %v", err)
 		}
@@ -260,6 +263,122 @@ func TestAddWorktree(t *testing.T) {
 			t.Error("New branch worktree not found")
 		}
 	})
+
+	t.Run("NoCheckout", func(t *testing.T) {
+		for _, fromBase := range []bool{false, true} {
+			worktreePath := filepath.Join(t.TempDir(), "sparse-wt")
+			branch := fmt.Sprintf("sparse-%t", fromBase)
+			var err error
+			if fromBase {
+				err = g.AddWorktreeFromBase(worktreePath, branch, "main", true)
+			} else {
+				err = g.AddWorktree(worktreePath, branch, true, true)
+			}
+			if err != nil {
+				t.Fatalf("AddWorktree(fromBase=%t) error = %v", fromBase, err)
+			}
+
+			// The worktree is registered but has no files yet.
+			if _, err := os.Stat(filepath.Join(worktreePath, ".git")); err != nil {
+				t.Errorf("fromBase=%t: worktree not registered: %v", fromBase, err)
+			}
+			if _, err := os.Stat(filepath.Join(worktreePath, "README.md")); !os.IsNotExist(err) {
+				t.Errorf("fromBase=%t: README.md was checked out (stat error = %v)", fromBase, err)
+			}
+		}
+	})
+}
+
+func TestStashApply(t *testing.T) {
+	writeFile := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	t.Run("Clean", func(t *testing.T) {
+		repo := NewTestRepository(t)
+		g := New(repo.Path)
+
+		writeFile(t, filepath.Join(repo.Path, "README.md"), "# Stashed change\n")
+		if err := repo.run("stash"); err != nil {
+			t.Fatalf("Failed to stash: %v", err)
+		}
+
+		worktreePath := filepath.Join(t.TempDir(), "stash-wt")
+		if err := g.AddWorktree(worktreePath, "from-stash", true, false); err != nil {
+			t.Fatalf("AddWorktree() error = %v", err)
+		}
+
+		if err := g.StashApply(worktreePath, ""); err != nil {
+			t.Fatalf("StashApply() error = %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(worktreePath, "README.md"))
+		if err != nil {
+			t.Fatalf("Failed to read README.md: %v", err)
+		}
+		if string(data) != "# Stashed change\n" {
+			t.Errorf("README.md = %q, want stashed content", data)
+		}
+
+		// The stash is kept so it can be applied elsewhere or dropped later.
+		if out, err := g.RunCommand("stash", "list"); err != nil || strings.TrimSpace(out) == "" {
+			t.Errorf("Expected stash to be kept, got %q (err %v)", out, err)
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		repo := NewTestRepository(t)
+		g := New(repo.Path)
+
+		writeFile(t, filepath.Join(repo.Path, "README.md"), "# Stashed change\n")
+		if err := repo.run("stash"); err != nil {
+			t.Fatalf("Failed to stash: %v", err)
+		}
+
+		// Diverge the target branch on the same line.
+		repo.CreateBranch(t, "diverged")
+		writeFile(t, filepath.Join(repo.Path, "README.md"), "# Diverged\n")
+		if err := repo.run("commit", "-am", "Diverge"); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		if err := repo.run("checkout", "main"); err != nil {
+			t.Fatalf("Failed to checkout main: %v", err)
+		}
+
+		worktreePath := filepath.Join(t.TempDir(), "conflict-wt")
+		if err := g.AddWorktree(worktreePath, "diverged", false, false); err != nil {
+			t.Fatalf("AddWorktree() error = %v", err)
+		}
+
+		err := g.StashApply(worktreePath, "stash@{0}")
+		var conflictErr *StashConflictError
+		if !errors.As(err, &conflictErr) {
+			t.Fatalf("StashApply() error = %v, want *StashConflictError", err)
+		}
+		if len(conflictErr.Files) != 1 || conflictErr.Files[0] != "README.md" {
+			t.Errorf("Conflict files = %v, want [README.md]", conflictErr.Files)
+		}
+		if _, err := os.Stat(worktreePath); err != nil {
+			t.Errorf("Worktree should be kept after a conflict: %v", err)
+		}
+	})
+
+	t.Run("MissingStash", func(t *testing.T) {
+		repo := NewTestRepository(t)
+		g := New(repo.Path)
+
+		err := g.StashApply(repo.Path, "stash@{3}")
+		if err == nil {
+			t.Fatal("StashApply() with missing stash should fail")
+		}
+		var conflictErr *StashConflictError
+		if errors.As(err, &conflictErr) {
+			t.Errorf("Missing stash reported as conflict: %v", err)
+		}
+	})
 }
 
 func TestRemoveWorktree(t *testing.T) {
@@ -286,6 +405,43 @@ func TestRemoveWorktree(t *testing.T) {
 	}
 }
 
+func TestPruneWorktreesVerbose(t *testing.T) {
+	repo := NewTestRepository(t)
+	g := New(repo.Path)
+
+	repo.CreateBranch(t, "to-prune")
+	worktreePath := filepath.Join(t.TempDir(), "prune-wt")
+	repo.CreateWorktree(t, worktreePath, "to-prune")
+	if err := os.RemoveAll(worktreePath); err != nil {
+		t.Fatalf("Failed to remove worktree directory: %v", err)
+	}
+
+	report, err := g.PruneWorktreesVerbose(true)
+	if err != nil {
+		t.Fatalf("PruneWorktreesVerbose(true) error = %v", err)
+	}
+	if !strings.Contains(report, "Removing worktrees/prune-wt") {
+		t.Errorf("dry-run report = %q, want it to name worktrees/prune-wt", report)
+	}
+
+	// The dry run must leave the entry in place for the real prune.
+	report, err = g.PruneWorktreesVerbose(false)
+	if err != nil {
+		t.Fatalf("PruneWorktreesVerbose(false) error = %v", err)
+	}
+	if !strings.Contains(report, "Removing worktrees/prune-wt") {
+		t.Errorf("report = %q, want it to name worktrees/prune-wt", report)
+	}
+
+	report, err = g.PruneWorktreesVerbose(true)
+	if err != nil {
+		t.Fatalf("PruneWorktreesVerbose(true) error = %v", err)
+	}
+	if report != "" {
+		t.Errorf("report after prune = %q, want empty", report)
+	}
+}
+
 func TestPruneWorktrees(t *testing.T) {
 	repo := NewTestRepository(t)
 	g := New(repo.Path)
@@ -378,6 +534,208 @@ func TestListBranches(t *testing.T) {
 			t.Error("No current branch found")
 		}
 	})
+
+	t.Run("WithRemote", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"commit", "--allow-empty", "-m", "subject with | separator"},
+			{"update-ref", "refs/remotes/origin/main", "HEAD"},
+			{"update-ref", "refs/remotes/origin/feature/remote-only", "HEAD"},
+			{"symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main"},
+		} {
+			if err := repo.run(args...); err != nil {
+				t.Fatalf("git %v: %v", args, err)
+			}
+		}
+
+		branchList, err := g.ListBranches(true)
+		if err != nil {
+			t.Fatalf("ListBranches(true) error = %v", err)
+		}
+
+		byName := make(map[string]models.Branch)
+		for _, b := range branchList {
+			byName[b.Name] = b
+		}
+		if _, ok := byName["origin/HEAD"]; ok {
+			t.Error("symbolic ref origin/HEAD should be skipped")
+		}
+		remote, ok := byName["origin/feature/remote-only"]
+		if !ok || !remote.IsRemote || remote.IsCurrent {
+			t.Errorf("origin/feature/remote-only = %+v, ok %v; want a remote branch", remote, ok)
+		}
+		if b := byName["feature/test"]; b.IsRemote {
+			t.Error("local branch feature/test marked as remote")
+		}
+		if b := byName["origin/main"]; b.LastCommit.Message != "subject with | separator" || b.LastCommit.Author == "" {
+			t.Errorf("origin/main commit = %+v", b.LastCommit)
+		}
+	})
+}
+
+func TestMergedBranches(t *testing.T) {
+	repo := NewTestRepository(t)
+	g := New(repo.Path)
+
+	// feature/done points at main's commit; feature/wip has an extra commit.
+	if err := repo.run("branch", "feature/done"); err != nil {
+		t.Fatal(err)
+	}
+	repo.CreateBranch(t, "feature/wip")
+	if err := repo.run("commit", "--allow-empty", "-m", "wip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.run("checkout", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := g.MergedBranches("main")
+	if err != nil {
+		t.Fatalf("MergedBranches() error = %v", err)
+	}
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "main", want: true},
+		{branch: "feature/done", want: true},
+		{branch: "feature/wip", want: false},
+		{branch: "feature", want: false},
+	}
+	for _, tt := range tests {
+		if got := merged[tt.branch]; got != tt.want {
+			t.Errorf("MergedBranches(main)[%q] = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+
+	if _, err := g.MergedBranches("no-such-branch"); err == nil {
+		t.Error("MergedBranches() into an unknown branch error = nil, want an error")
+	}
+}
+
+func TestBranchHasOwnCommits(t *testing.T) {
+	repo := NewTestRepository(t)
+	g := New(repo.Path)
+
+	// feature/fresh never moves; feature/ff gets a commit that main then
+	// fast-forwards to, so both end up merged into main.
+	if err := repo.run("branch", "feature/fresh"); err != nil {
+		t.Fatal(err)
+	}
+	repo.CreateBranch(t, "feature/ff")
+	if err := repo.run("commit", "--allow-empty", "-m", "ff"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.run("checkout", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.run("merge", "--ff-only", "feature/ff"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.run("-c", "core.logAllRefUpdates=false", "branch", "feature/nolog"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "feature/fresh", want: false},
+		{branch: "feature/ff", want: true},
+		{branch: "feature/nolog", want: false},
+	}
+	for _, tt := range tests {
+		got, err := g.BranchHasOwnCommits(tt.branch, "main")
+		if err != nil {
+			t.Fatalf("BranchHasOwnCommits(%q) error = %v", tt.branch, err)
+		}
+		if got != tt.want {
+			t.Errorf("BranchHasOwnCommits(%q, main) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+
+	if _, err := g.BranchHasOwnCommits("no-such-branch", "main"); err == nil {
+		t.Error("BranchHasOwnCommits() of an unknown branch error = nil, want an error")
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	repo := NewTestRepository(t)
+	g := New(repo.Path)
+
+	got, err := g.DefaultBranch()
+	if err != nil || got != "main" {
+		t.Errorf("DefaultBranch() = %q, %v, want main without a remote", got, err)
+	}
+
+	// origin/HEAD takes precedence over local branch names.
+	if err := repo.run("update-ref", "refs/remotes/origin/trunk", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.run("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = g.DefaultBranch()
+	if err != nil || got != "trunk" {
+		t.Errorf("DefaultBranch() = %q, %v, want trunk from origin/HEAD", got, err)
+	}
+}
+
+func TestUpstreamAndFetchBranch(t *testing.T) {
+	origin := NewTestRepository(t)
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "--quiet", origin.Path, clonePath).CombinedOutput(); err != nil {
+		t.Fatalf("git clone failed: %v: %s", err, out)
+	}
+	clone := &TestRepository{Path: clonePath}
+	g := New(clonePath)
+
+	tests := []struct {
+		ref        string
+		wantRemote string
+		wantBranch string
+	}{
+		{ref: "main", wantRemote: "origin", wantBranch: "main"},
+		{ref: "origin/main", wantRemote: "origin", wantBranch: "main"},
+		{ref: "refs/remotes/origin/main", wantRemote: "origin", wantBranch: "main"},
+		{ref: "missing"},
+	}
+	for _, tt := range tests {
+		remote, branch, err := g.Upstream(tt.ref)
+		if err != nil || remote != tt.wantRemote || branch != tt.wantBranch {
+			t.Errorf("Upstream(%q) = %q, %q, %v, want %q, %q", tt.ref, remote, branch, err, tt.wantRemote, tt.wantBranch)
+		}
+	}
+
+	// A local branch without an upstream has none.
+	if err := clone.run("branch", "topic"); err != nil {
+		t.Fatal(err)
+	}
+	if remote, _, err := g.Upstream("topic"); err != nil || remote != "" {
+		t.Errorf("Upstream(topic) = %q, %v, want no upstream", remote, err)
+	}
+
+	// A new commit on origin reaches origin/main only after fetching.
+	if err := origin.run("commit", "--allow-empty", "-m", "upstream change"); err != nil {
+		t.Fatal(err)
+	}
+	if behind, err := g.IsAncestor("main", "origin/main"); err != nil || !behind {
+		t.Errorf("IsAncestor(main, origin/main) = %v, %v, want true", behind, err)
+	}
+	if err := g.FetchBranch("origin", "main"); err != nil {
+		t.Fatalf("FetchBranch() error = %v", err)
+	}
+	fetched, _ := g.RunCommand("rev-parse", "origin/main")
+	want, _ := New(origin.Path).RunCommand("rev-parse", "main")
+	if fetched != want {
+		t.Errorf("origin/main = %q after fetch, want %q", fetched, want)
+	}
+	if ahead, err := g.IsAncestor("origin/main", "main"); err != nil || ahead {
+		t.Errorf("IsAncestor(origin/main, main) = %v, %v, want false", ahead, err)
+	}
+
+	if err := g.FetchBranch("origin", "no-such-branch"); err == nil {
+		t.Error("FetchBranch() of a missing branch succeeded")
+	}
 }
 
 func TestGetRepositoryName(t *testing.T) {
@@ -450,6 +808,58 @@ func TestGetRecentCommits(t *testing.T) {
 	}
 }
 
+func TestDiffArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		stat  bool
+		extra []string
+		want  []string
+	}{
+		{name: "patch", want: []string{"-C", "/wt", "diff", "HEAD"}},
+		{name: "stat", stat: true, want: []string{"-C", "/wt", "diff", "HEAD", "--stat"}},
+		{name: "extra args follow", stat: true, extra: []string{"-w", "--", "docs"}, want: []string{"-C", "/wt", "diff", "HEAD", "--stat", "-w", "--", "docs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffArgs("/wt", tt.stat, tt.extra); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	repo := NewTestRepository(t)
+	g := New("")
+
+	out, err := g.Diff(repo.Path, false)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if out != "" {
+		t.Errorf("Diff() of a clean worktree = %q, want empty", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo.Path, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = g.Diff(repo.Path, false)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !strings.Contains(out, "+# Changed") {
+		t.Errorf("Diff() = %q, want the added line", out)
+	}
+
+	out, err = g.Diff(repo.Path, true)
+	if err != nil {
+		t.Fatalf("Diff(stat) error = %v", err)
+	}
+	if !strings.Contains(out, "README.md") || !strings.Contains(out, "1 file changed") {
+		t.Errorf("Diff(stat) = %q, want a diffstat", out)
+	}
+}
+
 func TestGetCurrentBranch(t *testing.T) {
 	repo := NewTestRepository(t)
 	g := New(repo.Path)
@@ -516,6 +926,69 @@ func TestRunCommand(t *testing.T) {
 	}
 }
 
+// useFakeGit puts a shell script named git first on PATH. The script
+// writes size bytes to standard output and exits successfully.
+func useFakeGit(t *testing.T, size int) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nhead -c %d /dev/zero | tr '\\0' 'M'\n", size)
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake git: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunWithContext_OutputLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		size    int
+		wantErr bool
+	}{
+		{name: "within limit", limit: 1024, size: 1024},
+		{name: "beyond limit", limit: 1024, size: 1 << 20, wantErr: true},
+		{name: "unlimited", limit: -1, size: 1 << 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeGit(t, tt.size)
+			g := New(t.TempDir())
+			g.SetMaxOutputBytes(tt.limit)
+
+			output, err := g.RunWithContext(context.Background(), "status", "--porcelain")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("RunWithContext() error = %v", err)
+				}
+				if len(output) != tt.size {
+					t.Errorf("RunWithContext() returned %d bytes, want %d", len(output), tt.size)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrOutputTooLarge) {
+				t.Fatalf("RunWithContext() error = %v, want ErrOutputTooLarge", err)
+			}
+			var gitErr *Error
+			if !errors.As(err, &gitErr) || gitErr.Args[0] != "status" {
+				t.Errorf("RunWithContext() error = %#v, want *Error for git status", err)
+			}
+		})
+	}
+}
+
+func TestOutputLimitDefault(t *testing.T) {
+	g := New("")
+	if got := g.outputLimit(); got != DefaultMaxOutputBytes {
+		t.Errorf("outputLimit() = %d, want %d", got, DefaultMaxOutputBytes)
+	}
+}
+
 func TestGetMainRepositoryPath(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -625,3 +1098,30 @@ func containsWorktreeWithPath(worktrees []models.Worktree, path string) bool {
 	}
 	return false
 }
+
+func TestIsCommitish(t *testing.T) {
+	repo := NewTestRepository(t)
+	if err := repo.run("tag", "v1.0.0"); err != nil {
+		t.Fatalf("Failed to tag: %v", err)
+	}
+	g := New(repo.Path)
+	head, err := g.RunCommand("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{ref: "main", want: true},
+		{ref: "v1.0.0", want: true},
+		{ref: strings.TrimSpace(head), want: true},
+		{ref: "feature/missing", want: false},
+	}
+	for _, tt := range tests {
+		if got := g.IsCommitish(tt.ref); got != tt.want {
+			t.Errorf("IsCommitish(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
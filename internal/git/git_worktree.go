@@ -79,9 +79,14 @@ func (g *Git) ListWorktrees() ([]models.Worktree, error) {
 	return worktrees, nil
 }
 
-// AddWorktree creates a new worktree.
-func (g *Git) AddWorktree(path, branch string, createBranch bool) error {
+// AddWorktree creates a new worktree. With noCheckout the worktree is
+// registered but its files are not checked out, e.g. to set up a sparse
+// checkout first.
+func (g *Git) AddWorktree(path, branch string, createBranch, noCheckout bool) error {
 	args := []string{"worktree", "add"}
+	if noCheckout {
+		args = append(args, "--no-checkout")
+	}
 
 	if createBranch {
 		args = append(args, "-b", branch, path)
@@ -96,9 +101,14 @@ func (g *Git) AddWorktree(path, branch string, createBranch bool) error {
 	return nil
 }
 
-// AddWorktreeFromBase creates a new worktree with a branch from a specific base branch.
-func (g *Git) AddWorktreeFromBase(path, branch, baseBranch string) error {
-	args := []string{"worktree", "add", "-b", branch, path}
+// AddWorktreeFromBase creates a new worktree with a branch from a specific
+// base branch. noCheckout is as for AddWorktree.
+func (g *Git) AddWorktreeFromBase(path, branch, baseBranch string, noCheckout bool) error {
+	args := []string{"worktree", "add"}
+	if noCheckout {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, "-b", branch, path)
 
 	if baseBranch != "" {
 		args = append(args, baseBranch)
@@ -126,6 +136,26 @@ func (g *Git) RemoveWorktree(path string, force bool) error {
 	return nil
 }
 
+// IsWorktreeDirty reports whether the worktree at path has modified or
+// untracked files, which makes git refuse to remove it without --force.
+func (g *Git) IsWorktreeDirty(path string) (bool, error) {
+	output, err := g.run("-C", path, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	return strings.TrimSpace(output) != "", nil
+}
+
+// RepairWorktrees repairs worktree administrative files, e.g. after a
+// worktree directory has been moved. paths lists the new worktree locations.
+func (g *Git) RepairWorktrees(paths ...string) error {
+	args := append([]string{"worktree", "repair"}, paths...)
+	if _, err := g.run(args...); err != nil {
+		return fmt.Errorf("failed to repair worktrees: %w", err)
+	}
+	return nil
+}
+
 // PruneWorktrees removes worktree information for deleted directories.
 func (g *Git) PruneWorktrees() error {
 	if _, err := g.run("worktree", "prune"); err != nil {
@@ -133,3 +163,18 @@ func (g *Git) PruneWorktrees() error {
 	}
 	return nil
 }
+
+// PruneWorktreesVerbose runs 'git worktree prune --verbose' and returns its
+// report, one "Removing worktrees/<name>: <reason>" line per entry. With
+// dryRun nothing is removed.
+func (g *Git) PruneWorktreesVerbose(dryRun bool) (string, error) {
+	args := []string{"worktree", "prune", "--verbose"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	report, err := g.runReport(args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,204 @@
+// Package github resolves GitHub pull requests for 'gwq add --from-pr'.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// Host is the only host pull requests can be resolved for.
+const Host = "github.com"
+
+// DefaultAPIURL is the GitHub REST API endpoint.
+const DefaultAPIURL = "https://api.github.com"
+
+// ErrNoCredentials is returned when neither the gh CLI nor an API token is
+// available.
+var ErrNoCredentials = errors.New("install the gh CLI or set GH_TOKEN, GITHUB_TOKEN or github.token to check out pull requests")
+
+// PullRequest is the head of a GitHub pull request.
+type PullRequest struct {
+	Number  int
+	HeadRef string // Branch name in the head repository
+	HeadSHA string // Commit the pull request points at
+}
+
+// Fetcher looks up a pull request in owner/repo.
+type Fetcher interface {
+	PullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error)
+}
+
+// apiPullRequest is the subset of the REST API pull request object gwq uses.
+type apiPullRequest struct {
+	Number int `json:"number"`
+	Head   struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+func (p *apiPullRequest) pullRequest(number int) (*PullRequest, error) {
+	if p.Head.SHA == "" {
+		return nil, fmt.Errorf("pull request #%d has no head commit", number)
+	}
+	return &PullRequest{Number: number, HeadRef: p.Head.Ref, HeadSHA: p.Head.SHA}, nil
+}
+
+// GHFetcher resolves pull requests with 'gh api', which reuses the gh CLI's
+// authentication.
+type GHFetcher struct {
+	// Run executes gh with args and returns its stdout. Nil runs the gh
+	// binary.
+	Run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// PullRequest implements Fetcher.
+func (f *GHFetcher) PullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	run := f.Run
+	if run == nil {
+		run = runGH
+	}
+	out, err := run(ctx, "api", fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up pull request #%d: %w", number, err)
+	}
+	var p apiPullRequest
+	if err := json.Unmarshal(out, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request #%d: %w", number, err)
+	}
+	return p.pullRequest(number)
+}
+
+func runGH(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// APIFetcher resolves pull requests with the GitHub REST API.
+type APIFetcher struct {
+	Client  *http.Client
+	BaseURL string
+	Token   string
+}
+
+// NewAPIFetcher creates an APIFetcher for api.github.com.
+func NewAPIFetcher(token string) *APIFetcher {
+	return &APIFetcher{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		BaseURL: DefaultAPIURL,
+		Token:   token,
+	}
+}
+
+// PullRequest implements Fetcher.
+func (f *APIFetcher) PullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", strings.TrimSuffix(f.BaseURL, "/"), owner, repo, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if f.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.Token)
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up pull request #%d: %w", number, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("pull request #%d not found in %s/%s", number, owner, repo)
+	default:
+		return nil, fmt.Errorf("failed to look up pull request #%d: %s", number, resp.Status)
+	}
+
+	var p apiPullRequest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request #%d: %w", number, err)
+	}
+	return p.pullRequest(number)
+}
+
+// NewFetcher picks the gh CLI when it is installed and otherwise the REST API
+// with token. It returns ErrNoCredentials when neither is available.
+func NewFetcher(token string, lookPath func(string) (string, error)) (Fetcher, error) {
+	if lookPath == nil {
+		lookPath = exec.LookPath
+	}
+	if _, err := lookPath("gh"); err == nil {
+		return &GHFetcher{}, nil
+	}
+	if token != "" {
+		return NewAPIFetcher(token), nil
+	}
+	return nil, ErrNoCredentials
+}
+
+// Token returns the API token from GH_TOKEN or GITHUB_TOKEN, falling back to
+// the configured one.
+func Token(configured string) string {
+	for _, key := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return configured
+}
+
+// BranchName returns the local branch a pull request is checked out to.
+func BranchName(number int) string {
+	return fmt.Sprintf("pr/%d", number)
+}
+
+// HeadRefspec returns the refspec that fetches a pull request's head, which
+// also works for pull requests opened from forks.
+func HeadRefspec(number int) string {
+	return fmt.Sprintf("pull/%d/head", number)
+}
+
+// CheckHost reports an error unless info describes a repository hosted on
+// github.com.
+func CheckHost(info *models.RepositoryInfo) error {
+	if info == nil || !strings.EqualFold(info.Host, Host) {
+		host := ""
+		if info != nil {
+			host = info.Host
+		}
+		return fmt.Errorf("pull requests can only be checked out from repositories on %s, not %q", Host, host)
+	}
+	return nil
+}
+
+// Resolve looks up pull request number in the repository described by info.
+func Resolve(ctx context.Context, f Fetcher, info *models.RepositoryInfo, number int) (*PullRequest, error) {
+	if number <= 0 {
+		return nil, fmt.Errorf("invalid pull request number %d", number)
+	}
+	if err := CheckHost(info); err != nil {
+		return nil, err
+	}
+	return f.PullRequest(ctx, info.Owner, info.Repository, number)
+}
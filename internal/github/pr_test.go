@@ -0,0 +1,168 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// mockFetcher returns canned pull requests and records the last lookup.
+type mockFetcher struct {
+	prs    map[int]*PullRequest
+	called string
+}
+
+func (m *mockFetcher) PullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	m.called = owner + "/" + repo
+	if pr, ok := m.prs[number]; ok {
+		return pr, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestResolve(t *testing.T) {
+	fetcher := &mockFetcher{prs: map[int]*PullRequest{
+		42: {Number: 42, HeadRef: "feature/login", HeadSHA: "abc123"},
+	}}
+	github := &models.RepositoryInfo{Host: "github.com", Owner: "d-kuro", Repository: "gwq"}
+
+	tests := []struct {
+		name    string
+		info    *models.RepositoryInfo
+		number  int
+		wantSHA string
+		wantErr string
+	}{
+		{name: "Found", info: github, number: 42, wantSHA: "abc123"},
+		{name: "NotFound", info: github, number: 7, wantErr: "not found"},
+		{name: "InvalidNumber", info: github, number: 0, wantErr: "invalid pull request number"},
+		{name: "OtherHost", info: &models.RepositoryInfo{Host: "gitlab.com", Owner: "o", Repository: "r"}, number: 42, wantErr: "gitlab.com"},
+		{name: "NoInfo", info: nil, number: 42, wantErr: "github.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, err := Resolve(context.Background(), fetcher, tt.info, tt.number)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if pr.HeadSHA != tt.wantSHA {
+				t.Errorf("HeadSHA = %q, want %q", pr.HeadSHA, tt.wantSHA)
+			}
+			if fetcher.called != "d-kuro/gwq" {
+				t.Errorf("fetcher called with %q, want d-kuro/gwq", fetcher.called)
+			}
+		})
+	}
+}
+
+func TestGHFetcher(t *testing.T) {
+	var gotArgs []string
+	f := &GHFetcher{Run: func(ctx context.Context, args ...string) ([]byte, error) {
+		gotArgs = args
+		return []byte(`{"number":5,"head":{"ref":"fix","sha":"deadbeef"}}`), nil
+	}}
+
+	pr, err := f.PullRequest(context.Background(), "o", "r", 5)
+	if err != nil {
+		t.Fatalf("PullRequest() error = %v", err)
+	}
+	if strings.Join(gotArgs, " ") != "api repos/o/r/pulls/5" {
+		t.Errorf("gh args = %v", gotArgs)
+	}
+	if pr.HeadRef != "fix" || pr.HeadSHA != "deadbeef" {
+		t.Errorf("PullRequest() = %+v", pr)
+	}
+
+	f.Run = func(ctx context.Context, args ...string) ([]byte, error) {
+		return []byte(`{"number":5,"head":{}}`), nil
+	}
+	if _, err := f.PullRequest(context.Background(), "o", "r", 5); err == nil {
+		t.Error("PullRequest() without a head commit expected an error")
+	}
+}
+
+func TestAPIFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/repos/o/r/pulls/9" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"number":9,"head":{"ref":"topic","sha":"cafe"}}`))
+	}))
+	defer srv.Close()
+
+	f := NewAPIFetcher("secret")
+	f.BaseURL = srv.URL
+
+	pr, err := f.PullRequest(context.Background(), "o", "r", 9)
+	if err != nil {
+		t.Fatalf("PullRequest() error = %v", err)
+	}
+	if pr.HeadSHA != "cafe" {
+		t.Errorf("HeadSHA = %q, want cafe", pr.HeadSHA)
+	}
+
+	if _, err := f.PullRequest(context.Background(), "o", "r", 10); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("PullRequest() of a missing PR error = %v", err)
+	}
+
+	f.Token = "wrong"
+	if _, err := f.PullRequest(context.Background(), "o", "r", 9); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("PullRequest() with a bad token error = %v", err)
+	}
+}
+
+func TestNewFetcher(t *testing.T) {
+	withGH := func(string) (string, error) { return "/usr/bin/gh", nil }
+	withoutGH := func(string) (string, error) { return "", errors.New("not found") }
+
+	if f, err := NewFetcher("", withGH); err != nil {
+		t.Errorf("NewFetcher() with gh error = %v", err)
+	} else if _, ok := f.(*GHFetcher); !ok {
+		t.Errorf("NewFetcher() with gh = %T, want *GHFetcher", f)
+	}
+
+	if f, err := NewFetcher("token", withoutGH); err != nil {
+		t.Errorf("NewFetcher() with token error = %v", err)
+	} else if _, ok := f.(*APIFetcher); !ok {
+		t.Errorf("NewFetcher() with token = %T, want *APIFetcher", f)
+	}
+
+	if _, err := NewFetcher("", withoutGH); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("NewFetcher() without gh or token error = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	if got := Token("configured"); got != "configured" {
+		t.Errorf("Token() = %q, want configured", got)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "from-github-token")
+	if got := Token("configured"); got != "from-github-token" {
+		t.Errorf("Token() = %q, want from-github-token", got)
+	}
+
+	t.Setenv("GH_TOKEN", "from-gh-token")
+	if got := Token("configured"); got != "from-gh-token" {
+		t.Errorf("Token() = %q, want from-gh-token", got)
+	}
+}
@@ -0,0 +1,74 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/command"
+)
+
+// LsofLister lists processes with `lsof -a -d cwd` (macOS).
+type LsofLister struct {
+	Executor command.CommandExecutor
+}
+
+// List runs lsof in field output mode and parses the result.
+func (l *LsofLister) List(ctx context.Context) ([]Process, error) {
+	output, err := l.Executor.ExecuteWithOutput(ctx, "lsof", "-a", "-d", "cwd", "-F", "pcnR")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run lsof: %w", err)
+	}
+	return parseLsof(output), nil
+}
+
+// parseLsof parses `lsof -F pcnR` output, where each process starts with a
+// "p<pid>" line followed by "R<ppid>", "c<command>" and, per open file,
+// "n<name>".
+func parseLsof(output string) []Process {
+	var processes []Process
+	var current *Process
+
+	flush := func() {
+		if current != nil && current.Cwd != "" {
+			processes = append(processes, *current)
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		field, value := line[0], line[1:]
+		switch field {
+		case 'p':
+			flush()
+			pid, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			current = &Process{PID: pid}
+		case 'R':
+			if current != nil {
+				current.PPID, _ = strconv.Atoi(value)
+			}
+		case 'c':
+			if current != nil {
+				current.Command = filepath.Base(value)
+			}
+		case 'n':
+			if current != nil && current.Cwd == "" {
+				current.Cwd = value
+			}
+		}
+	}
+	flush()
+
+	return processes
+}
@@ -0,0 +1,94 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProcLister lists processes by reading a procfs mount (Linux).
+type ProcLister struct {
+	Root string // procfs mount point, normally /proc
+}
+
+// List reads every numeric entry under Root. Processes that exit during the
+// scan or whose cwd is not readable (other users) are skipped.
+func (l *ProcLister) List(ctx context.Context) ([]Process, error) {
+	entries, err := os.ReadDir(l.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", l.Root, err)
+	}
+
+	var processes []Process
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid <= 0 {
+			continue
+		}
+
+		dir := filepath.Join(l.Root, entry.Name())
+		cwd, err := os.Readlink(filepath.Join(dir, "cwd"))
+		if err != nil {
+			continue
+		}
+
+		command := procCommand(dir)
+		if command == "" {
+			continue
+		}
+
+		processes = append(processes, Process{PID: pid, PPID: procParent(dir), Command: command, Cwd: cwd})
+	}
+
+	return processes, nil
+}
+
+// procParent returns the parent PID from the stat file, or 0 when it cannot
+// be read. The command name in stat may contain spaces and parentheses, so
+// fields are counted from the last ')'.
+func procParent(dir string) int {
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return 0
+	}
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0
+	}
+	// Fields after the command: state, ppid, ...
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0
+	}
+	return ppid
+}
+
+// procCommand returns the basename of argv[0], falling back to comm for
+// kernel threads and processes with an empty command line.
+func procCommand(dir string) string {
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		argv0, _, _ := bytes.Cut(cmdline, []byte{0})
+		if len(argv0) > 0 {
+			// Some programs rewrite argv into a single space-separated string.
+			name, _, _ := strings.Cut(string(argv0), " ")
+			return filepath.Base(name)
+		}
+	}
+
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		return strings.TrimSpace(string(comm))
+	}
+	return ""
+}
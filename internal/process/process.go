@@ -0,0 +1,129 @@
+// Package process discovers running processes whose working directory is
+// inside a worktree and classifies known AI coding agents.
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// TypeAIAgent is the ProcessInfo.Type assigned to known AI coding tools.
+const TypeAIAgent = "ai_agent"
+
+// DefaultAgentNames lists the command names recognized as AI agents.
+var DefaultAgentNames = []string{"claude", "cursor", "aider", "copilot"}
+
+// Process is a running process and its current working directory.
+type Process struct {
+	PID     int
+	PPID    int    // parent PID, 0 when unknown
+	Command string // basename of argv[0]
+	Cwd     string
+}
+
+// Lister enumerates running processes. Implementations are platform specific.
+type Lister interface {
+	List(ctx context.Context) ([]Process, error)
+}
+
+// NewLister returns the Lister for the current platform. On platforms without
+// support it returns a Lister that reports no processes.
+func NewLister(executor command.CommandExecutor) Lister {
+	switch runtime.GOOS {
+	case "linux":
+		return &ProcLister{Root: "/proc"}
+	case "darwin":
+		return &LsofLister{Executor: executor}
+	default:
+		return noopLister{}
+	}
+}
+
+type noopLister struct{}
+
+func (noopLister) List(context.Context) ([]Process, error) { return nil, nil }
+
+// Classify returns TypeAIAgent when command matches one of agentNames, either
+// exactly or as a dash-separated prefix (e.g. "cursor-agent"). Matching is
+// case-insensitive. Other commands yield an empty type.
+func Classify(command string, agentNames []string) string {
+	name := strings.ToLower(filepath.Base(command))
+	for _, agent := range agentNames {
+		agent = strings.ToLower(agent)
+		if agent == "" {
+			continue
+		}
+		if name == agent || strings.HasPrefix(name, agent+"-") {
+			return TypeAIAgent
+		}
+	}
+	return ""
+}
+
+// Ancestors returns the PIDs of pid's parent, its parent and so on, as far as
+// processes records them. The walk stops at a PID missing from processes.
+func Ancestors(processes []Process, pid int) []int {
+	parents := make(map[int]int, len(processes))
+	for _, p := range processes {
+		parents[p.PID] = p.PPID
+	}
+
+	var ancestors []int
+	seen := map[int]bool{pid: true}
+	for ppid := parents[pid]; ppid > 0 && !seen[ppid]; ppid = parents[ppid] {
+		seen[ppid] = true
+		ancestors = append(ancestors, ppid)
+	}
+	return ancestors
+}
+
+// InPath returns the processes whose working directory is dir or below it,
+// converted to ProcessInfo and classified against agentNames. The calling
+// process is excluded. Results are ordered by PID.
+func InPath(processes []Process, dir string, agentNames []string) []models.ProcessInfo {
+	dir = resolvePath(dir)
+	self := os.Getpid()
+
+	var result []models.ProcessInfo
+	for _, p := range processes {
+		if p.PID == self || p.Cwd == "" {
+			continue
+		}
+		if !within(dir, p.Cwd) {
+			continue
+		}
+		result = append(result, models.ProcessInfo{
+			PID:     p.PID,
+			Command: p.Command,
+			Type:    Classify(p.Command, agentNames),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].PID < result[j].PID })
+	return result
+}
+
+// within reports whether path is dir or a descendant of it.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// resolvePath resolves symlinks so paths compare equal to kernel-reported cwds
+// (e.g. /tmp vs /private/tmp on macOS).
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
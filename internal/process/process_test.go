@@ -0,0 +1,174 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "claude", command: "claude", want: TypeAIAgent},
+		{name: "cursor agent", command: "cursor-agent", want: TypeAIAgent},
+		{name: "aider full path", command: "/usr/local/bin/aider", want: TypeAIAgent},
+		{name: "copilot uppercase", command: "Copilot", want: TypeAIAgent},
+		{name: "unrelated", command: "vim", want: ""},
+		{name: "agent name as substring", command: "claudette", want: ""},
+		{name: "empty", command: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.command, DefaultAgentNames); got != tt.want {
+				t.Errorf("Classify(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInPath(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "repo", "feature")
+	sibling := filepath.Join(root, "repo", "feature-2")
+	for _, dir := range []string{filepath.Join(worktree, "src"), sibling} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+	worktree = resolvePath(worktree)
+	sibling = resolvePath(sibling)
+
+	processes := []Process{
+		{PID: 30, Command: "vim", Cwd: filepath.Join(worktree, "src")},
+		{PID: 10, Command: "claude", Cwd: worktree},
+		{PID: 20, Command: "claude", Cwd: sibling},
+		{PID: 40, Command: "bash", Cwd: ""},
+		{PID: os.Getpid(), Command: "gwq", Cwd: worktree},
+	}
+
+	got := InPath(processes, worktree, DefaultAgentNames)
+	want := []models.ProcessInfo{
+		{PID: 10, Command: "claude", Type: TypeAIAgent},
+		{PID: 30, Command: "vim", Type: ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("InPath() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("InPath()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	output := "p101\nR1\ncclaude\nn/Users/me/worktrees/feature\n" +
+		"pbad\ncignored\nn/tmp\n" +
+		"p202\ncvim\n" +
+		"p303\nczsh\nn/Users/me\n"
+
+	got := parseLsof(output)
+	want := []Process{
+		{PID: 101, PPID: 1, Command: "claude", Cwd: "/Users/me/worktrees/feature"},
+		{PID: 303, Command: "zsh", Cwd: "/Users/me"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseLsof() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseLsof()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestProcLister(t *testing.T) {
+	root := t.TempDir()
+	cwd := t.TempDir()
+
+	writeProc := func(pid, cmdline, comm string, withCwd bool) {
+		t.Helper()
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644); err != nil {
+			t.Fatalf("Failed to write cmdline: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write comm: %v", err)
+		}
+		if withCwd {
+			if err := os.Symlink(cwd, filepath.Join(dir, "cwd")); err != nil {
+				t.Fatalf("Failed to create cwd link: %v", err)
+			}
+		}
+	}
+
+	writeProc("100", "/usr/bin/claude\x00--resume\x00", "node", true)
+	writeProc("200", "", "kworker", true)
+	writeProc("300", "aider", "aider", false) // cwd not readable
+	// The command name in stat may itself contain ") ".
+	if err := os.WriteFile(filepath.Join(root, "100", "stat"), []byte("100 (node) S 7) S 42 100 100 0 -1\n"), 0644); err != nil {
+		t.Fatalf("Failed to write stat: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "self"), 0755); err != nil {
+		t.Fatalf("Failed to create self: %v", err)
+	}
+
+	got, err := (&ProcLister{Root: root}).List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := map[int]Process{
+		100: {PID: 100, PPID: 42, Command: "claude", Cwd: cwd},
+		200: {PID: 200, Command: "kworker", Cwd: cwd},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", got, want)
+	}
+	for _, p := range got {
+		if want[p.PID] != p {
+			t.Errorf("List() process %d = %+v, want %+v", p.PID, p, want[p.PID])
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	processes := []Process{
+		{PID: 1, PPID: 0},
+		{PID: 10, PPID: 1},
+		{PID: 20, PPID: 10},
+		{PID: 30, PPID: 20},
+		{PID: 40, PPID: 99}, // parent not listed
+		{PID: 50, PPID: 51},
+		{PID: 51, PPID: 50}, // cycle from PID reuse
+	}
+
+	tests := []struct {
+		name string
+		pid  int
+		want []int
+	}{
+		{name: "chain to init", pid: 30, want: []int{20, 10, 1}},
+		{name: "unknown parent", pid: 40, want: []int{99}},
+		{name: "cycle", pid: 50, want: []int{51}},
+		{name: "not listed", pid: 1000, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ancestors(processes, tt.pid); !slices.Equal(got, tt.want) {
+				t.Errorf("Ancestors(%d) = %v, want %v", tt.pid, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 type Builder struct {
 	headers []string
 	rows    [][]string
+	dimmed  map[int]bool // indexes into rows rendered faint
 	style   Style
 	output  io.Writer
 }
@@ -122,6 +123,16 @@ func (b *Builder) Row(columns ...string) *Builder {
 	return b
 }
 
+// DimRow adds a data row that is rendered faint, for rows of less interest.
+// The styling is dropped when the output is not a terminal.
+func (b *Builder) DimRow(columns ...string) *Builder {
+	if b.dimmed == nil {
+		b.dimmed = make(map[int]bool)
+	}
+	b.dimmed[len(b.rows)] = true
+	return b.Row(columns...)
+}
+
 // Rows adds multiple data rows to the table
 func (b *Builder) Rows(rows [][]string) *Builder {
 	for _, row := range rows {
@@ -136,9 +147,13 @@ func (b *Builder) Build() string {
 		Border(b.style.Border).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			// Apply padding to all cells for better readability
-			return lipgloss.NewStyle().
+			style := lipgloss.NewStyle().
 				PaddingLeft(b.style.PaddingLeft).
 				PaddingRight(b.style.PaddingRight)
+			if b.dimmed[row] {
+				style = style.Faint(true)
+			}
+			return style
 		})
 
 	// Set width if specified
@@ -168,15 +183,17 @@ func (b *Builder) Build() string {
 	return styledTable
 }
 
-// Print writes the table to the configured output writer
+// Print writes the table to the configured output writer, dropping styling
+// the writer cannot display
 func (b *Builder) Print() error {
-	_, err := fmt.Fprint(b.output, b.Build())
+	_, err := lipgloss.Fprint(b.output, b.Build())
 	return err
 }
 
-// Println writes the table followed by a newline to the configured output writer
+// Println writes the table followed by a newline to the configured output
+// writer, dropping styling the writer cannot display
 func (b *Builder) Println() error {
-	_, err := fmt.Fprintln(b.output, b.Build())
+	_, err := lipgloss.Fprintln(b.output, b.Build())
 	return err
 }
 
@@ -228,6 +245,7 @@ func (b *Builder) SetPadding(left, right int) *Builder {
 func (b *Builder) Clear() *Builder {
 	b.headers = nil
 	b.rows = nil
+	b.dimmed = nil
 	return b
 }
 
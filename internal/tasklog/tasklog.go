@@ -0,0 +1,167 @@
+// Package tasklog records the lifecycle of agent tasks started with
+// 'gwq tmux run' as JSON lines, giving a durable history of what ran in which
+// worktree.
+package tasklog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultFileName is the log file name inside the gwq config directory.
+const DefaultFileName = "tasks.jsonl"
+
+// Event is a task lifecycle event.
+type Event string
+
+const (
+	EventCreated   Event = "created"         // the task was accepted, before its session exists
+	EventStarted   Event = "session_started" // its tmux session is running
+	EventCompleted Event = "completed"       // the command exited with status 0
+	EventFailed    Event = "failed"          // the command exited non-zero or could not start
+)
+
+// Record is one line of the task log.
+type Record struct {
+	Time       time.Time `json:"time"`
+	Event      Event     `json:"event"`
+	TaskID     string    `json:"task_id"`
+	Worktree   string    `json:"worktree,omitempty"`
+	Session    string    `json:"session,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	BaseCommit string    `json:"base_commit,omitempty"` // HEAD when the session started
+	// DurationSeconds is the time from session start to completion.
+	DurationSeconds float64  `json:"duration_seconds,omitempty"`
+	ExitCode        *int     `json:"exit_code,omitempty"`
+	FilesChanged    []string `json:"files_changed,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Logger appends records to a JSONL file.
+type Logger struct {
+	path string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// New creates a Logger writing to path. The file and its directory are
+// created on the first write.
+func New(path string) *Logger {
+	return &Logger{path: path, now: time.Now}
+}
+
+// Path returns the log file path.
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Append writes r as one line, setting Time when it is zero. Each record is
+// written with a single append so concurrent tasks do not interleave.
+func (l *Logger) Append(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = l.now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode task record: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create task log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open task log: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write task log: %w", err)
+	}
+	return f.Close()
+}
+
+// Records returns every record in the log. A missing file yields no records
+// and lines that cannot be decoded are skipped.
+func (l *Logger) Records() ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read task log: %w", err)
+	}
+	return records, nil
+}
+
+// Last returns the most recent record for taskID with the given event.
+func (l *Logger) Last(taskID string, event Event) (Record, bool, error) {
+	records, err := l.Records()
+	if err != nil {
+		return Record{}, false, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].TaskID == taskID && records[i].Event == event {
+			return records[i], true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// Finish appends the completed or failed record for taskID. Worktree, session,
+// command and duration are taken from the task's session_started record when
+// there is one.
+func (l *Logger) Finish(taskID string, exitCode int, filesChanged []string) (Record, error) {
+	r := Record{
+		Time:         l.now(),
+		Event:        EventCompleted,
+		TaskID:       taskID,
+		ExitCode:     &exitCode,
+		FilesChanged: filesChanged,
+	}
+	if exitCode != 0 {
+		r.Event = EventFailed
+	}
+
+	started, ok, err := l.Last(taskID, EventStarted)
+	if err != nil {
+		return Record{}, err
+	}
+	if ok {
+		r.Worktree = started.Worktree
+		r.Session = started.Session
+		r.Command = started.Command
+		r.BaseCommit = started.BaseCommit
+		r.DurationSeconds = r.Time.Sub(started.Time).Seconds()
+	}
+
+	return r, l.Append(r)
+}
@@ -0,0 +1,120 @@
+package tasklog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a clock that advances by step on every call.
+func fakeClock(start time.Time, step time.Duration) func() time.Time {
+	now := start
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestLogger_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		exitCode  int
+		files     []string
+		wantEvent Event
+	}{
+		{name: "Completed", exitCode: 0, files: []string{"main.go"}, wantEvent: EventCompleted},
+		{name: "Failed", exitCode: 2, wantEvent: EventFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := New(filepath.Join(t.TempDir(), "state", DefaultFileName))
+			logger.now = fakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 30*time.Second)
+
+			// A fake agent: created, started in a session, then finished.
+			if err := logger.Append(Record{Event: EventCreated, TaskID: "t1", Worktree: "/wt", Command: "agent"}); err != nil {
+				t.Fatalf("Append(created) error = %v", err)
+			}
+			if err := logger.Append(Record{Event: EventStarted, TaskID: "t1", Worktree: "/wt", Session: "gwq-run-t1", Command: "agent", BaseCommit: "abc"}); err != nil {
+				t.Fatalf("Append(started) error = %v", err)
+			}
+			// An unrelated task must not be mistaken for t1.
+			if err := logger.Append(Record{Event: EventStarted, TaskID: "t2", Worktree: "/other"}); err != nil {
+				t.Fatalf("Append(t2) error = %v", err)
+			}
+
+			finished, err := logger.Finish("t1", tt.exitCode, tt.files)
+			if err != nil {
+				t.Fatalf("Finish() error = %v", err)
+			}
+
+			records, err := logger.Records()
+			if err != nil {
+				t.Fatalf("Records() error = %v", err)
+			}
+			var events []Event
+			for _, r := range records {
+				events = append(events, r.Event)
+			}
+			if want := []Event{EventCreated, EventStarted, EventStarted, tt.wantEvent}; !slices.Equal(events, want) {
+				t.Fatalf("events = %v, want %v", events, want)
+			}
+
+			last := records[len(records)-1]
+			if last.TaskID != "t1" || last.Worktree != "/wt" || last.Session != "gwq-run-t1" || last.BaseCommit != "abc" {
+				t.Errorf("finish record = %+v, want fields of t1's session_started record", last)
+			}
+			if last.ExitCode == nil || *last.ExitCode != tt.exitCode {
+				t.Errorf("ExitCode = %v, want %d", last.ExitCode, tt.exitCode)
+			}
+			if last.DurationSeconds != 60 {
+				t.Errorf("DurationSeconds = %v, want 60", last.DurationSeconds)
+			}
+			if !slices.Equal(last.FilesChanged, tt.files) {
+				t.Errorf("FilesChanged = %v, want %v", last.FilesChanged, tt.files)
+			}
+			if finished.Event != tt.wantEvent {
+				t.Errorf("Finish() event = %q, want %q", finished.Event, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestLogger_FinishWithoutStart(t *testing.T) {
+	logger := New(filepath.Join(t.TempDir(), DefaultFileName))
+
+	r, err := logger.Finish("missing", 1, nil)
+	if err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if r.Event != EventFailed || r.DurationSeconds != 0 || r.Worktree != "" {
+		t.Errorf("Finish() = %+v, want a bare failed record", r)
+	}
+}
+
+func TestLogger_RecordsSkipsInvalidLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	content := `{"event":"created","task_id":"a"}
+not json
+{"event":"completed","task_id":"a"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := New(path).Records()
+	if err != nil {
+		t.Fatalf("Records() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("Records() returned %d records, want 2", len(records))
+	}
+
+	missing, err := New(filepath.Join(t.TempDir(), "none.jsonl")).Records()
+	if err != nil || missing != nil {
+		t.Errorf("Records() on missing file = %v, %v; want nil, nil", missing, err)
+	}
+}
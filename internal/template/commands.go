@@ -42,3 +42,10 @@ func renderOne(src string, data *TemplateData) RenderedCommand {
 
 	return RenderedCommand{Source: src, Rendered: buf.String()}
 }
+
+// Render executes a single template string with the given data, with the
+// same "missingkey=error" behavior as RenderCommands.
+func Render(src string, data *TemplateData) (string, error) {
+	rc := renderOne(src, data)
+	return rc.Rendered, rc.Err
+}
@@ -0,0 +1,81 @@
+package template
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// DefaultStatusIcons are the symbols {{.StatusIcon}} and statusIcon emit for
+// each worktree state unless finder.status_icons overrides them.
+var DefaultStatusIcons = map[models.WorktreeState]string{
+	models.WorktreeStatusClean:    "✓",
+	models.WorktreeStatusModified: "*",
+	models.WorktreeStatusStaged:   "+",
+	models.WorktreeStatusConflict: "!",
+	models.WorktreeStatusStale:    "~",
+	models.WorktreeStatusUnknown:  "?",
+}
+
+// DisplayProcessor renders a user-supplied template for display purposes,
+// such as the repository column of 'gwq status'. Unlike Processor it does
+// not sanitize the branch or produce a path.
+type DisplayProcessor struct {
+	template *template.Template
+	icons    map[models.WorktreeState]string
+}
+
+// NewDisplay parses templateStr as a display template. Unknown fields are
+// reported when the template is rendered. Besides the TemplateData fields,
+// the template can call statusIcon to map a state to its icon.
+func NewDisplay(templateStr string) (*DisplayProcessor, error) {
+	p := &DisplayProcessor{icons: maps.Clone(DefaultStatusIcons)}
+	tmpl, err := template.New("display").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"statusIcon": p.StatusIcon}).
+		Parse(templateStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	p.template = tmpl
+	return p, nil
+}
+
+// SetStatusIcons overrides the icons of the states in icons, keyed by state
+// name. Keys that are not valid states are rejected.
+func (p *DisplayProcessor) SetStatusIcons(icons map[string]string) error {
+	for name, icon := range icons {
+		state, err := models.ParseWorktreeState(name)
+		if err != nil {
+			return err
+		}
+		p.icons[state] = icon
+	}
+	return nil
+}
+
+// StatusIcon returns the icon of state, or a space when the state is not
+// known, so columns keep their alignment.
+func (p *DisplayProcessor) StatusIcon(state models.WorktreeState) string {
+	if icon, ok := p.icons[state]; ok && icon != "" {
+		return icon
+	}
+	return " "
+}
+
+// Render executes the template with data. StatusIcon is derived from
+// data.Status when it is not set.
+func (p *DisplayProcessor) Render(data *TemplateData) (string, error) {
+	d := *data
+	if d.StatusIcon == "" {
+		d.StatusIcon = p.StatusIcon(d.Status)
+	}
+	var buf strings.Builder
+	if err := p.template.Execute(&buf, &d); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -5,11 +5,12 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 
-	"github.com/d-kuro/gwq/internal/url"
 	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
 )
 
 // TemplateData contains the data available for template processing.
@@ -20,6 +21,11 @@ type TemplateData struct {
 	Branch     string // e.g., "feature/new-ui"
 	Hash       string // Short hash of the repository URL + branch
 	Path       string // Absolute worktree path (empty while rendering naming.template)
+
+	// Only set for display templates such as finder.display_template.
+	IsMain     bool                 // Whether the worktree is the main worktree
+	Status     models.WorktreeState // Worktree state, empty when not collected
+	StatusIcon string               // Icon of Status, or a space when unavailable
 }
 
 // Processor handles template processing for worktree path generation.
@@ -42,7 +48,7 @@ func New(templateStr string, sanitizeChars map[string]string) (*Processor, error
 }
 
 // GeneratePath generates a worktree path using the configured template.
-func (p *Processor) GeneratePath(baseDir string, repoInfo *url.RepositoryInfo, branch string) (string, error) {
+func (p *Processor) GeneratePath(baseDir string, repoInfo *models.RepositoryInfo, branch string) (string, error) {
 	// Sanitize branch name only
 	sanitizedBranch := p.sanitizeBranch(branch)
 
@@ -72,10 +78,17 @@ func (p *Processor) GeneratePath(baseDir string, repoInfo *url.RepositoryInfo, b
 
 // sanitizeBranch applies character sanitization rules to branch name only.
 func (p *Processor) sanitizeBranch(branch string) string {
+	return SanitizeBranch(branch, p.sanitizeChars)
+}
+
+// SanitizeBranch turns a branch name into the form used in worktree paths:
+// the configured sanitizeChars replacements first, then the default
+// filesystem sanitization.
+func SanitizeBranch(branch string, sanitizeChars map[string]string) string {
 	sanitized := branch
 
 	// Apply custom sanitize characters to branch name first
-	for old, new := range p.sanitizeChars {
+	for old, new := range sanitizeChars {
 		sanitized = strings.ReplaceAll(sanitized, old, new)
 	}
 
@@ -97,3 +110,24 @@ func generateShortHash(input string) string {
 func ShortHash(input string) string {
 	return generateShortHash(input)
 }
+
+// branchSuffix matches a naming template whose last element is the branch.
+var branchSuffix = regexp.MustCompile(`\{\{-?\s*\.Branch\s*-?\}\}\s*$`)
+
+// DirMatchesBranch reports whether the worktree directory at path is where
+// naming puts branch, i.e. whether path ends with the sanitized branch. It
+// returns true when this cannot be told: for a detached HEAD, when the
+// naming template does not end with {{.Branch}}, or when path is outside
+// baseDir, like worktrees moved to a custom location.
+func DirMatchesBranch(naming models.NamingConfig, baseDir, path, branch string) bool {
+	if branch == "" || branch == "HEAD" || !branchSuffix.MatchString(naming.Template) {
+		return true
+	}
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return true
+	}
+	dir := filepath.ToSlash(SanitizeBranch(branch, naming.SanitizeChars))
+	rel = filepath.ToSlash(rel)
+	return rel == dir || strings.HasSuffix(rel, "/"+dir)
+}
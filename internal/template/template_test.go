@@ -4,7 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/d-kuro/gwq/internal/url"
+	"github.com/d-kuro/gwq/pkg/models"
 )
 
 func TestProcessor_GeneratePath(t *testing.T) {
@@ -13,7 +13,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 		template      string
 		sanitizeChars map[string]string
 		baseDir       string
-		repoInfo      *url.RepositoryInfo
+		repoInfo      *models.RepositoryInfo
 		branch        string
 		expected      string
 		expectError   bool
@@ -22,7 +22,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 			name:     "default template",
 			template: "{{.Host}}/{{.Owner}}/{{.Repository}}/{{.Branch}}",
 			baseDir:  "/tmp/worktrees",
-			repoInfo: &url.RepositoryInfo{
+			repoInfo: &models.RepositoryInfo{
 				Host:       "github.com",
 				Owner:      "user1",
 				Repository: "myapp",
@@ -35,7 +35,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 			name:     "template with .git",
 			template: "{{.Host}}/{{.Owner}}/{{.Repository}}/.git/{{.Branch}}",
 			baseDir:  "/tmp/worktrees",
-			repoInfo: &url.RepositoryInfo{
+			repoInfo: &models.RepositoryInfo{
 				Host:       "github.com",
 				Owner:      "user1",
 				Repository: "myapp",
@@ -52,7 +52,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 				":": "-",
 			},
 			baseDir: "/tmp/worktrees",
-			repoInfo: &url.RepositoryInfo{
+			repoInfo: &models.RepositoryInfo{
 				Host:       "github.com",
 				Owner:      "user1",
 				Repository: "myapp",
@@ -65,7 +65,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 			name:     "template with hash",
 			template: "{{.Repository}}-{{.Hash}}",
 			baseDir:  "/tmp/worktrees",
-			repoInfo: &url.RepositoryInfo{
+			repoInfo: &models.RepositoryInfo{
 				Host:       "github.com",
 				Owner:      "user1",
 				Repository: "myapp",
@@ -78,7 +78,7 @@ func TestProcessor_GeneratePath(t *testing.T) {
 			name:        "invalid template",
 			template:    "{{.Invalid}}",
 			baseDir:     "/tmp/worktrees",
-			repoInfo:    &url.RepositoryInfo{},
+			repoInfo:    &models.RepositoryInfo{},
 			branch:      "main",
 			expectError: true,
 		},
@@ -190,7 +190,7 @@ func TestProcessor_GeneratePath_BranchOnlySanitization(t *testing.T) {
 		t.Fatalf("Failed to create processor: %v", err)
 	}
 
-	repoInfo := &url.RepositoryInfo{
+	repoInfo := &models.RepositoryInfo{
 		Host:       "github.com",
 		Owner:      "user1",
 		Repository: "myapp",
@@ -208,3 +208,125 @@ func TestProcessor_GeneratePath_BranchOnlySanitization(t *testing.T) {
 		t.Errorf("GeneratePath() = %s, want %s", result, expected)
 	}
 }
+
+func TestDisplayProcessor_Render(t *testing.T) {
+	data := &TemplateData{
+		Host:       "github.com",
+		Owner:      "user1",
+		Repository: "myapp",
+		Branch:     "feature/new-ui",
+		Path:       "/tmp/worktrees/github.com/user1/myapp/feature-new-ui",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		parseErr bool
+		execErr  bool
+	}{
+		{name: "owner and repository", template: "{{.Owner}}/{{.Repository}}", expected: "user1/myapp"},
+		{name: "branch is not sanitized", template: "{{.Repository}}@{{.Branch}}", expected: "myapp@feature/new-ui"},
+		{name: "parse error", template: "{{.Repository", parseErr: true},
+		{name: "unknown field", template: "{{.Missing}}", execErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewDisplay(tt.template)
+			if (err != nil) != tt.parseErr {
+				t.Fatalf("NewDisplay() error = %v, parseErr %v", err, tt.parseErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := p.Render(data)
+			if (err != nil) != tt.execErr {
+				t.Fatalf("Render() error = %v, execErr %v", err, tt.execErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Render() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisplayProcessor_StatusIcon(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		icons    map[string]string
+		status   models.WorktreeState
+		expected string
+	}{
+		{name: "default icon", template: "{{.StatusIcon}} {{.Branch}}", status: models.WorktreeStatusModified, expected: "* main"},
+		{name: "status unavailable", template: "{{.StatusIcon}} {{.Branch}}", expected: "  main"},
+		{name: "configured icon", template: "{{.StatusIcon}}", icons: map[string]string{"modified": "M"}, status: models.WorktreeStatusModified, expected: "M"},
+		{name: "other states keep defaults", template: "{{.StatusIcon}}", icons: map[string]string{"modified": "M"}, status: models.WorktreeStatusClean, expected: "✓"},
+		{name: "function", template: `{{statusIcon .Status}}{{statusIcon "conflict"}}`, status: models.WorktreeStatusStaged, expected: "+!"},
+		{name: "status", template: "{{.Status}}", status: models.WorktreeStatusStale, expected: "Stale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewDisplay(tt.template)
+			if err != nil {
+				t.Fatalf("NewDisplay() error = %v", err)
+			}
+			if err := p.SetStatusIcons(tt.icons); err != nil {
+				t.Fatalf("SetStatusIcons() error = %v", err)
+			}
+			got, err := p.Render(&TemplateData{Branch: "main", Status: tt.status})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Render() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisplayProcessor_SetStatusIconsRejectsUnknownState(t *testing.T) {
+	p, err := NewDisplay("{{.StatusIcon}}")
+	if err != nil {
+		t.Fatalf("NewDisplay() error = %v", err)
+	}
+	if err := p.SetStatusIcons(map[string]string{"dirty": "D"}); err == nil {
+		t.Error("SetStatusIcons() should reject an unknown state")
+	}
+}
+
+func TestDirMatchesBranch(t *testing.T) {
+	naming := models.NamingConfig{
+		Template:      "{{.Host}}/{{.Owner}}/{{.Repository}}/{{.Branch}}",
+		SanitizeChars: map[string]string{"/": "-"},
+	}
+	base := filepath.FromSlash("/wt")
+	repo := filepath.FromSlash("/wt/github.com/user/app/")
+
+	tests := []struct {
+		name   string
+		naming models.NamingConfig
+		path   string
+		branch string
+		want   bool
+	}{
+		{name: "sanitized branch", naming: naming, path: repo + "feature-x", branch: "feature/x", want: true},
+		{name: "renamed branch", naming: naming, path: repo + "feature-x", branch: "feature/y", want: false},
+		{name: "suffix of another name", naming: naming, path: repo + "feature-x", branch: "x", want: false},
+		{name: "default filesystem sanitizing", naming: models.NamingConfig{Template: naming.Template}, path: repo + "feature-x", branch: "feature/x", want: true},
+		{name: "detached HEAD", naming: naming, path: repo + "feature-x", branch: "HEAD", want: true},
+		{name: "template without trailing branch", naming: models.NamingConfig{Template: "{{.Repository}}-{{.Hash}}"}, path: repo + "feature-x", branch: "main", want: true},
+		{name: "spaced branch action", naming: models.NamingConfig{Template: "{{.Repository}}/{{ .Branch }}"}, path: repo + "other", branch: "main", want: false},
+		{name: "outside base dir", naming: naming, path: filepath.FromSlash("/src/review"), branch: "feature/x", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DirMatchesBranch(tt.naming, base, tt.path, tt.branch); got != tt.want {
+				t.Errorf("DirMatchesBranch(%q, %q) = %v, want %v", tt.path, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package tmux
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SessionEventType describes a change in the set of live sessions.
+type SessionEventType string
+
+const (
+	// SessionDied is emitted when a session is no longer running, e.g.
+	// because its command finished with auto-cleanup or it was killed.
+	SessionDied SessionEventType = "died"
+	// SessionAppeared is emitted when a session was started after the
+	// health check began.
+	SessionAppeared SessionEventType = "appeared"
+)
+
+// SessionEvent reports that Session died or appeared. For a died session,
+// Session is the last state seen while it was alive.
+type SessionEvent struct {
+	Type    SessionEventType
+	Session *Session
+}
+
+// StartHealthCheck polls tmux every interval for the gwq sessions that are
+// alive and sends an event on the returned channel for each session that
+// died or appeared since the previous poll. The sessions alive when it is
+// called are the baseline and produce no events. Failed polls are skipped,
+// so a transient tmux error does not report every session as dead.
+//
+// Polling stops and the channel is closed when ctx is cancelled.
+func (sm *SessionManager) StartHealthCheck(ctx context.Context, interval time.Duration) <-chan SessionEvent {
+	events := make(chan SessionEvent)
+	known := sm.liveSessions()
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := sm.liveSessions()
+			if current == nil {
+				continue
+			}
+			if known == nil {
+				// The baseline could not be taken; this poll becomes it.
+				known = current
+				continue
+			}
+			for _, event := range diffSessions(known, current) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			known = current
+		}
+	}()
+
+	return events
+}
+
+// liveSessions returns the live gwq sessions keyed by session name, or nil
+// if tmux could not be queried.
+func (sm *SessionManager) liveSessions() map[string]*Session {
+	sessions, err := sm.ListSessions()
+	if err != nil {
+		return nil
+	}
+	byName := make(map[string]*Session, len(sessions))
+	for _, session := range sessions {
+		byName[session.SessionName] = session
+	}
+	return byName
+}
+
+// diffSessions returns the events turning previous into current: deaths
+// first, then appearances, each ordered by session name.
+func diffSessions(previous, current map[string]*Session) []SessionEvent {
+	var died, appeared []SessionEvent
+	for name, session := range previous {
+		if _, ok := current[name]; !ok {
+			died = append(died, SessionEvent{Type: SessionDied, Session: session})
+		}
+	}
+	for name, session := range current {
+		if _, ok := previous[name]; !ok {
+			appeared = append(appeared, SessionEvent{Type: SessionAppeared, Session: session})
+		}
+	}
+
+	byName := func(a, b SessionEvent) int {
+		return strings.Compare(a.Session.SessionName, b.Session.SessionName)
+	}
+	slices.SortFunc(died, byName)
+	slices.SortFunc(appeared, byName)
+	return append(died, appeared...)
+}
@@ -0,0 +1,111 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedTmux lets a test change the live sessions while the health check
+// polls them from its goroutine.
+type lockedTmux struct {
+	*mockTmux
+	mu sync.Mutex
+}
+
+func (m *lockedTmux) ListSessionsDetailed() ([]*SessionInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mockTmux.ListSessionsDetailed()
+}
+
+func (m *lockedTmux) set(sessions []string, listErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions = slices.Clone(sessions)
+	m.listErr = listErr
+}
+
+func nextEvent(t *testing.T, events <-chan SessionEvent) SessionEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("event channel closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a session event")
+	}
+	return SessionEvent{}
+}
+
+func TestStartHealthCheck(t *testing.T) {
+	const (
+		build = "gwq-run-build-20240101120000"
+		test  = "gwq-run-test-20240101120100"
+	)
+	mock := &lockedTmux{mockTmux: &mockTmux{sessions: []string{build}}}
+	sm, _ := newTestManager(t, mock.mockTmux)
+	sm.tmuxCmd = mock
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	events := sm.StartHealthCheck(ctx, 10*time.Millisecond)
+
+	mock.set([]string{build, test}, nil)
+	if event := nextEvent(t, events); event.Type != SessionAppeared || event.Session.SessionName != test {
+		t.Errorf("event = %s %s, want appeared %s", event.Type, event.Session.SessionName, test)
+	}
+
+	// A failed poll must not report the sessions as dead.
+	mock.set(nil, errors.New("no server running"))
+	time.Sleep(50 * time.Millisecond)
+
+	mock.set([]string{test}, nil)
+	event := nextEvent(t, events)
+	if event.Type != SessionDied || event.Session.SessionName != build {
+		t.Errorf("event = %s %s, want died %s", event.Type, event.Session.SessionName, build)
+	}
+	if event.Session.Identifier != "build" {
+		t.Errorf("died session identifier = %q, want build", event.Session.Identifier)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestStartHealthCheck_StopsOnCancel(t *testing.T) {
+	sm, _ := newTestManager(t, &mockTmux{})
+	ctx, cancel := context.WithCancel(t.Context())
+	events := sm.StartHealthCheck(ctx, time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("unexpected event after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestDiffSessions(t *testing.T) {
+	session := func(name string) *Session { return &Session{SessionName: name} }
+	previous := map[string]*Session{"a": session("a"), "b": session("b"), "c": session("c")}
+	current := map[string]*Session{"b": session("b"), "e": session("e"), "d": session("d")}
+
+	var got []string
+	for _, event := range diffSessions(previous, current) {
+		got = append(got, string(event.Type)+" "+event.Session.SessionName)
+	}
+	want := []string{"died a", "died c", "appeared d", "appeared e"}
+	if !slices.Equal(got, want) {
+		t.Errorf("diffSessions() = %q, want %q", got, want)
+	}
+}
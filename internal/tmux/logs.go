@@ -0,0 +1,85 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/utils"
+)
+
+// sessionLogExt is the extension of captured session output files.
+const sessionLogExt = ".log"
+
+// exitCodeExt is the extension of exit code sentinel files.
+const exitCodeExt = ".exit"
+
+// MetadataExitFile is the session metadata key of the sentinel file the
+// session's command writes its exit code to when it finishes.
+const MetadataExitFile = "exit_file"
+
+// DefaultSessionLogDir returns the directory session output is captured to.
+func DefaultSessionLogDir() string {
+	return filepath.Join(filepath.Dir(DefaultSessionStorePath()), "tmux-logs")
+}
+
+// SessionLogPath returns the file the output of sessionName is captured to
+// under dir.
+func SessionLogPath(dir, sessionName string) string {
+	return filepath.Join(dir, utils.SanitizeForFilesystem(sessionName)+sessionLogExt)
+}
+
+// FindSessionLogs returns the captured logs under dir whose session name
+// contains pattern (case-insensitively), sorted by name. They remain after
+// their session has ended. A missing dir yields no logs.
+func FindSessionLogs(dir, pattern string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	pattern = strings.ToLower(pattern)
+	var logs []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), sessionLogExt)
+		if !ok || e.IsDir() || !strings.Contains(strings.ToLower(name), pattern) {
+			continue
+		}
+		logs = append(logs, filepath.Join(dir, e.Name()))
+	}
+	slices.Sort(logs)
+	return logs, nil
+}
+
+// ExitCodePath returns the exit code sentinel file of the task id under dir.
+func ExitCodePath(dir, id string) string {
+	return filepath.Join(dir, utils.SanitizeForFilesystem(id)+exitCodeExt)
+}
+
+// ReadExitCode reads the exit code from the sentinel file at path, written
+// by the shell as "<code>\n" when the command finishes. done is false while
+// the file is missing or still being written, i.e. lacks its final newline.
+func ReadExitCode(path string) (code int, done bool, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	text, complete := strings.CutSuffix(string(content), "\n")
+	if !complete {
+		return 0, false, nil
+	}
+	code, err = strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid exit code in %s: %q", path, text)
+	}
+	return code, true, nil
+}
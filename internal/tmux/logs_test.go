@@ -0,0 +1,109 @@
+package tmux
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSessionLogPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		want    string
+	}{
+		{name: "plain", session: "gwq-run-build-20250101000000", want: "gwq-run-build-20250101000000.log"},
+		{name: "slash in identifier", session: "gwq-run-feature/auth-20250101000000", want: "gwq-run-feature-auth-20250101000000.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := SessionLogPath("/logs", tt.session), filepath.Join("/logs", tt.want); got != want {
+				t.Errorf("SessionLogPath() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFindSessionLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"gwq-run-build-20250101000000.log",
+		"gwq-run-build-20250102000000.log",
+		"gwq-run-Test-20250101000000.log",
+		"gwq-run-build-notes.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "build", want: []string{"gwq-run-build-20250101000000.log", "gwq-run-build-20250102000000.log"}},
+		{pattern: "test", want: []string{"gwq-run-Test-20250101000000.log"}},
+		{pattern: "deploy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := FindSessionLogs(dir, tt.pattern)
+			if err != nil {
+				t.Fatalf("FindSessionLogs() error = %v", err)
+			}
+			var want []string
+			for _, name := range tt.want {
+				want = append(want, filepath.Join(dir, name))
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("FindSessionLogs() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if got, err := FindSessionLogs(filepath.Join(dir, "missing"), "build"); err != nil || got != nil {
+		t.Errorf("FindSessionLogs(missing dir) = %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestReadExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  *string
+		wantCode int
+		wantDone bool
+		wantErr  bool
+	}{
+		{name: "missing file"},
+		{name: "empty file", content: ptr("")},
+		{name: "partially written", content: ptr("12")},
+		{name: "success", content: ptr("0\n"), wantDone: true},
+		{name: "failure", content: ptr("127\n"), wantCode: 127, wantDone: true},
+		{name: "surrounding spaces", content: ptr(" 3 \n"), wantCode: 3, wantDone: true},
+		{name: "garbage", content: ptr("done\n"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ExitCodePath(t.TempDir(), "abc123")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			code, done, err := ReadExitCode(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadExitCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if code != tt.wantCode || done != tt.wantDone {
+				t.Errorf("ReadExitCode() = %d, %t, want %d, %t", code, done, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -3,6 +3,9 @@ package tmux
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -13,6 +16,10 @@ import (
 type SessionManager struct {
 	config  *SessionConfig
 	tmuxCmd TmuxInterface
+	store   SessionStore
+	// logDir receives the captured output of new sessions; empty disables
+	// capturing.
+	logDir string
 }
 
 func NewSessionManager(config *SessionConfig) *SessionManager {
@@ -23,16 +30,62 @@ func NewSessionManager(config *SessionConfig) *SessionManager {
 	return &SessionManager{
 		config:  config,
 		tmuxCmd: NewTmuxCommand(config.TmuxCommand),
+		store:   NewFileSessionStore(DefaultSessionStorePath()),
+		logDir:  DefaultSessionLogDir(),
 	}
 }
 
+// Metadata keys recording the unsanitized context and identifier of a session
+// whose tmux name had to be sanitized.
+const (
+	MetadataContext    = "context"
+	MetadataIdentifier = "identifier"
+)
+
+// tmuxNameReplacer replaces characters tmux interprets in target names
+// (session:window.pane), which make a session impossible to address reliably.
+var tmuxNameReplacer = strings.NewReplacer(".", "_", ":", "_")
+
+// SanitizeSessionNamePart makes s safe to embed in a tmux session name.
+func SanitizeSessionNamePart(s string) string {
+	return tmuxNameReplacer.Replace(s)
+}
+
+// sessionTimeLayout is the start time embedded in session names.
+const sessionTimeLayout = "20060102150405"
+
+// buildSessionName returns the tmux name of a session:
+// gwq-{context}-{identifier}-{start time}.
+func buildSessionName(context, identifier string, start time.Time) string {
+	return fmt.Sprintf("gwq-%s-%s-%s", SanitizeSessionNamePart(context), SanitizeSessionNamePart(identifier), start.Format(sessionTimeLayout))
+}
+
 func (sm *SessionManager) CreateSession(ctx context.Context, opts SessionOptions) (*Session, error) {
-	sessionName := fmt.Sprintf("gwq-%s-%s-%s", opts.Context, opts.Identifier, time.Now().Format("20060102150405"))
+	context := SanitizeSessionNamePart(opts.Context)
+	identifier := SanitizeSessionNamePart(opts.Identifier)
+	sessionName := buildSessionName(opts.Context, opts.Identifier, time.Now())
+
+	metadata := opts.Metadata
+	if context != opts.Context || identifier != opts.Identifier {
+		// Keep the readable names; ListSessions restores them.
+		metadata = maps.Clone(opts.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[MetadataContext] = opts.Context
+		metadata[MetadataIdentifier] = opts.Identifier
+	}
 
 	// Create session with or without command
 	if opts.Command != "" {
+		command := opts.Command
+		if sm.logDir != "" {
+			// Hold the command until its output is captured so the start of
+			// it is not lost.
+			command = fmt.Sprintf("%s wait-for %s; %s", shellQuote(sm.tmuxBinary()), shellQuote(sessionName), command)
+		}
 		// Create session with command - when command finishes, session will automatically terminate
-		if err := sm.tmuxCmd.NewSessionWithCommandContext(ctx, sessionName, opts.WorkingDir, opts.Command); err != nil {
+		if err := sm.tmuxCmd.NewSessionWithCommandContext(ctx, sessionName, opts.WorkingDir, command); err != nil {
 			return nil, fmt.Errorf("failed to create tmux session with command: %w", err)
 		}
 	} else {
@@ -47,6 +100,24 @@ func (sm *SessionManager) CreateSession(ctx context.Context, opts SessionOptions
 		return nil, fmt.Errorf("failed to set history limit: %w", err)
 	}
 
+	if err := sm.applyLayout(sessionName, opts); err != nil {
+		_ = sm.tmuxCmd.KillSession(sessionName)
+		return nil, err
+	}
+
+	// Output capture is best-effort like the metadata below.
+	if sm.logDir != "" {
+		if err := os.MkdirAll(sm.logDir, 0755); err == nil {
+			_ = sm.tmuxCmd.PipePane(sessionName, SessionLogPath(sm.logDir, sessionName))
+		}
+		if opts.Command != "" {
+			if err := sm.tmuxCmd.SignalChannel(sessionName); err != nil {
+				_ = sm.tmuxCmd.KillSession(sessionName)
+				return nil, fmt.Errorf("failed to start command: %w", err)
+			}
+		}
+	}
+
 	session := &Session{
 		ID:          utils.GenerateID(),
 		SessionName: sessionName,
@@ -56,18 +127,60 @@ func (sm *SessionManager) CreateSession(ctx context.Context, opts SessionOptions
 		Command:     opts.Command,
 		StartTime:   time.Now(),
 		HistorySize: sm.config.HistoryLimit,
-		Metadata:    opts.Metadata,
+		Metadata:    metadata,
+	}
+
+	// Session metadata is best-effort: the tmux session is usable without it.
+	if sm.store != nil {
+		_ = sm.store.Put(session)
 	}
 
 	return session, nil
 }
 
+// shellQuote quotes s as a single POSIX shell word. Session names keep
+// whatever the context and identifier contain apart from '.' and ':', so
+// they may hold spaces, quotes or '$'.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// tmuxBinary returns the tmux command run inside sessions.
+func (sm *SessionManager) tmuxBinary() string {
+	if sm.config.TmuxCommand == "" {
+		return "tmux"
+	}
+	return sm.config.TmuxCommand
+}
+
+// applyLayout creates the extra panes and windows requested in opts.
+func (sm *SessionManager) applyLayout(sessionName string, opts SessionOptions) error {
+	for _, command := range opts.SplitCommands {
+		if err := sm.tmuxCmd.SplitWindow(sessionName, opts.WorkingDir, command); err != nil {
+			return fmt.Errorf("failed to split window: %w", err)
+		}
+	}
+	if opts.Layout != "" {
+		if err := sm.tmuxCmd.SelectLayout(sessionName, opts.Layout); err != nil {
+			return fmt.Errorf("failed to select layout %q: %w", opts.Layout, err)
+		}
+	}
+	for _, w := range opts.Windows {
+		if err := sm.tmuxCmd.NewWindowWithCommand(sessionName, w.Name, opts.WorkingDir, w.Command); err != nil {
+			return fmt.Errorf("failed to create window: %w", err)
+		}
+	}
+	return nil
+}
+
 func (sm *SessionManager) ListSessions() ([]*Session, error) {
 	tmuxSessions, err := sm.tmuxCmd.ListSessionsDetailed()
 	if err != nil {
 		return nil, err
 	}
 
+	stored := sm.storedSessions()
+
 	var sessions []*Session
 	for _, tmuxSession := range tmuxSessions {
 		// Only show gwq-managed sessions
@@ -77,6 +190,15 @@ func (sm *SessionManager) ListSessions() ([]*Session, error) {
 
 		session := sm.parseSessionFromTmux(tmuxSession)
 		if session != nil {
+			if meta, ok := stored[session.SessionName]; ok && meta.Metadata != nil {
+				session.Metadata = meta.Metadata
+				if v, ok := meta.Metadata[MetadataContext]; ok {
+					session.Context = v
+				}
+				if v, ok := meta.Metadata[MetadataIdentifier]; ok {
+					session.Identifier = v
+				}
+			}
 			sessions = append(sessions, session)
 		}
 	}
@@ -84,6 +206,120 @@ func (sm *SessionManager) ListSessions() ([]*Session, error) {
 	return sessions, nil
 }
 
+// storedSessions returns stored session metadata keyed by session name.
+// Store errors yield an empty map so listing never depends on the store.
+func (sm *SessionManager) storedSessions() map[string]*Session {
+	byName := make(map[string]*Session)
+	if sm.store == nil {
+		return byName
+	}
+
+	stored, err := sm.store.List()
+	if err != nil {
+		return byName
+	}
+	for _, session := range stored {
+		byName[session.SessionName] = session
+	}
+	return byName
+}
+
+// RelocateSessions rewrites stored session metadata that refers to oldPath
+// (or a path below it) to refer to newPath instead, e.g. after a worktree has
+// been moved. Live sessions for the stored entries are looked up through
+// ListSessions; the running tmux panes themselves are not changed. It returns
+// the number of entries updated.
+func (sm *SessionManager) RelocateSessions(oldPath, newPath string) (int, error) {
+	if sm.store == nil {
+		return 0, nil
+	}
+
+	live, err := sm.ListSessions()
+	if err != nil {
+		return 0, err
+	}
+	stored := sm.storedSessions()
+
+	updated := 0
+	for _, session := range live {
+		entry, ok := stored[session.SessionName]
+		if !ok {
+			continue
+		}
+
+		changed := false
+		if p, ok := relocatePath(entry.WorkingDir, oldPath, newPath); ok {
+			entry.WorkingDir = p
+			changed = true
+		}
+		for key, value := range entry.Metadata {
+			if p, ok := relocatePath(value, oldPath, newPath); ok {
+				entry.Metadata[key] = p
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := sm.store.Put(entry); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// relocatePath maps path from under oldRoot to under newRoot.
+func relocatePath(path, oldRoot, newRoot string) (string, bool) {
+	if path == oldRoot {
+		return newRoot, true
+	}
+	if rest, ok := strings.CutPrefix(path, oldRoot+string(filepath.Separator)); ok {
+		return filepath.Join(newRoot, rest), true
+	}
+	return "", false
+}
+
+// Prune removes stored metadata for sessions that no longer exist in tmux,
+// e.g. sessions killed outside gwq. It returns the number of entries removed.
+func (sm *SessionManager) Prune() (int, error) {
+	if sm.store == nil {
+		return 0, nil
+	}
+
+	stored, err := sm.store.List()
+	if err != nil {
+		return 0, err
+	}
+	if len(stored) == 0 {
+		return 0, nil
+	}
+
+	live, err := sm.tmuxCmd.ListSessions()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	alive := make(map[string]bool, len(live))
+	for _, name := range live {
+		alive[name] = true
+	}
+
+	var dead []string
+	for _, session := range stored {
+		if !alive[session.SessionName] {
+			dead = append(dead, session.SessionName)
+		}
+	}
+	if len(dead) == 0 {
+		return 0, nil
+	}
+
+	if err := sm.store.Delete(dead...); err != nil {
+		return 0, err
+	}
+	return len(dead), nil
+}
+
 func (sm *SessionManager) parseSessionFromTmux(info *SessionInfo) *Session {
 	// Parse session name format: gwq-{context}-{identifier}-{timestamp}
 	re := regexp.MustCompile(`^gwq-([^-]+)-(.+)-(\d{14})$`)
@@ -96,7 +332,7 @@ func (sm *SessionManager) parseSessionFromTmux(info *SessionInfo) *Session {
 	identifier := matches[2]
 	timestamp := matches[3]
 
-	startTime, err := time.Parse("20060102150405", timestamp)
+	startTime, err := time.Parse(sessionTimeLayout, timestamp)
 	if err != nil {
 		startTime = time.Now()
 	}
@@ -110,15 +346,16 @@ func (sm *SessionManager) parseSessionFromTmux(info *SessionInfo) *Session {
 	}
 
 	return &Session{
-		ID:          utils.GenerateShortID(),
-		SessionName: info.Name,
-		Context:     context,
-		Identifier:  identifier,
-		WorkingDir:  info.WorkingDir,
-		Command:     command,
-		StartTime:   startTime,
-		HistorySize: sm.config.HistoryLimit,
-		Metadata:    map[string]string{},
+		ID:           utils.GenerateShortID(),
+		SessionName:  info.Name,
+		Context:      context,
+		Identifier:   identifier,
+		WorkingDir:   info.WorkingDir,
+		Command:      command,
+		StartTime:    startTime,
+		LastActivity: info.ActivityTime(),
+		HistorySize:  sm.config.HistoryLimit,
+		Metadata:     map[string]string{},
 	}
 }
 
@@ -157,9 +394,61 @@ func (sm *SessionManager) KillSessionDirect(session *Session) error {
 		}
 	}
 
+	if sm.store != nil {
+		_ = sm.store.Delete(session.SessionName)
+	}
+
 	return nil
 }
 
+// RenameSession gives session the identifier newIdentifier. Its tmux name
+// is rebuilt like in CreateSession, keeping the context and start time, and
+// the stored metadata and captured output log follow the new name. It
+// returns the new session name.
+func (sm *SessionManager) RenameSession(session *Session, newIdentifier string) (string, error) {
+	oldName := session.SessionName
+	newName := buildSessionName(session.Context, newIdentifier, session.StartTime)
+	if newName == oldName {
+		return newName, nil
+	}
+	if sm.tmuxCmd.HasSession(newName) {
+		return "", fmt.Errorf("tmux session %s already exists", newName)
+	}
+	if err := sm.tmuxCmd.RenameSession(oldName, newName); err != nil {
+		return "", fmt.Errorf("failed to rename tmux session: %w", err)
+	}
+
+	// The log is held open by the pane's pipe, so renaming it keeps the
+	// capture going. Like metadata, it is best-effort.
+	if sm.logDir != "" {
+		_ = os.Rename(SessionLogPath(sm.logDir, oldName), SessionLogPath(sm.logDir, newName))
+	}
+
+	if sm.store == nil {
+		return newName, nil
+	}
+	entry, ok := sm.storedSessions()[oldName]
+	if !ok {
+		copied := *session
+		entry = &copied
+	}
+	entry.SessionName = newName
+	entry.Identifier = newIdentifier
+	entry.Metadata = maps.Clone(entry.Metadata)
+	if SanitizeSessionNamePart(newIdentifier) != newIdentifier {
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string)
+		}
+		entry.Metadata[MetadataIdentifier] = newIdentifier
+	} else {
+		delete(entry.Metadata, MetadataIdentifier)
+	}
+	_ = sm.store.Delete(oldName)
+	_ = sm.store.Put(entry)
+
+	return newName, nil
+}
+
 func (sm *SessionManager) AttachSession(id string) error {
 	session, err := sm.GetSession(id)
 	if err != nil {
@@ -174,9 +463,32 @@ func (sm *SessionManager) AttachSessionDirect(session *Session) error {
 		return fmt.Errorf("tmux session %s no longer exists", session.SessionName)
 	}
 
+	// Attaching from inside tmux would nest sessions; switch instead.
+	if os.Getenv("TMUX") != "" {
+		return sm.tmuxCmd.SwitchClient(session.SessionName)
+	}
 	return sm.tmuxCmd.AttachSession(session.SessionName)
 }
 
+// MostRecentlyActive returns the sessions with the latest LastActivity. More
+// than one session is returned when several share that time, and none when
+// no session has a known activity time.
+func MostRecentlyActive(sessions []*Session) []*Session {
+	var latest time.Time
+	var result []*Session
+	for _, session := range sessions {
+		switch {
+		case session.LastActivity.IsZero():
+		case session.LastActivity.After(latest):
+			latest = session.LastActivity
+			result = []*Session{session}
+		case session.LastActivity.Equal(latest):
+			result = append(result, session)
+		}
+	}
+	return result
+}
+
 // HasSession checks if a session exists
 func (sm *SessionManager) HasSession(sessionName string) bool {
 	return sm.tmuxCmd.HasSession(sessionName)
@@ -0,0 +1,654 @@
+package tmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockTmux is a TmuxInterface backed by a fixed list of live session names.
+type mockTmux struct {
+	sessions []string
+	activity map[string]string // session name -> #{session_activity}
+	listErr  error
+	killed   []string
+	attached string
+	switched string
+	layout   []string // split-window, select-layout and new-window calls
+	splitErr error
+	piped    map[string]string // session name -> log file
+	commands map[string]string // session name -> command
+	signaled []string
+}
+
+func (m *mockTmux) NewSession(name, workDir string) error { return nil }
+func (m *mockTmux) NewSessionContext(ctx context.Context, name, workDir string) error {
+	m.sessions = append(m.sessions, name)
+	return nil
+}
+func (m *mockTmux) NewSessionWithCommandContext(ctx context.Context, name, workDir, command string) error {
+	if m.commands == nil {
+		m.commands = make(map[string]string)
+	}
+	m.commands[name] = command
+	m.sessions = append(m.sessions, name)
+	return nil
+}
+func (m *mockTmux) SetOption(sessionName, option string, value any) error { return nil }
+func (m *mockTmux) SetOptionContext(ctx context.Context, sessionName, option string, value any) error {
+	return nil
+}
+func (m *mockTmux) ListSessions() ([]string, error) { return m.sessions, m.listErr }
+func (m *mockTmux) ListSessionsDetailed() ([]*SessionInfo, error) {
+	infos := make([]*SessionInfo, 0, len(m.sessions))
+	for _, name := range m.sessions {
+		infos = append(infos, &SessionInfo{Name: name, Activity: m.activity[name], CurrentCommand: "sleep", WorkingDir: "/tmp"})
+	}
+	return infos, m.listErr
+}
+func (m *mockTmux) KillSession(sessionName string) error {
+	m.killed = append(m.killed, sessionName)
+	m.sessions = slices.DeleteFunc(m.sessions, func(s string) bool { return s == sessionName })
+	return nil
+}
+func (m *mockTmux) AttachSession(sessionName string) error {
+	m.attached = sessionName
+	return nil
+}
+func (m *mockTmux) SwitchClient(sessionName string) error {
+	m.switched = sessionName
+	return nil
+}
+func (m *mockTmux) HasSession(sessionName string) bool {
+	return slices.Contains(m.sessions, sessionName)
+}
+
+func (m *mockTmux) SplitWindow(sessionName, workDir, command string) error {
+	m.layout = append(m.layout, fmt.Sprintf("split %s %s %q", sessionName, workDir, command))
+	return m.splitErr
+}
+func (m *mockTmux) SelectLayout(sessionName, layout string) error {
+	m.layout = append(m.layout, fmt.Sprintf("layout %s %s", sessionName, layout))
+	return nil
+}
+func (m *mockTmux) NewWindowWithCommand(sessionName, windowName, workDir, command string) error {
+	m.layout = append(m.layout, fmt.Sprintf("window %s %s %s %q", sessionName, windowName, workDir, command))
+	return nil
+}
+func (m *mockTmux) NewWindow(sessionName, workDir string) error {
+	return m.NewWindowCommand(sessionName, workDir, "")
+}
+func (m *mockTmux) NewWindowCommand(sessionName, workDir, command string) error {
+	m.layout = append(m.layout, fmt.Sprintf("open-window %s %s %q", sessionName, workDir, command))
+	return nil
+}
+
+func (m *mockTmux) PipePane(sessionName, logFile string) error {
+	if m.piped == nil {
+		m.piped = make(map[string]string)
+	}
+	m.piped[sessionName] = logFile
+	return nil
+}
+
+func (m *mockTmux) SignalChannel(channel string) error {
+	m.signaled = append(m.signaled, channel)
+	return nil
+}
+
+func (m *mockTmux) RenameSession(oldName, newName string) error {
+	i := slices.Index(m.sessions, oldName)
+	if i < 0 {
+		return errors.New("can't find session: " + oldName)
+	}
+	m.sessions[i] = newName
+	return nil
+}
+
+func (m *mockTmux) CapturePane(sessionName string, startLine int) (string, error) {
+	return "", nil
+}
+
+func newTestManager(t *testing.T, tmuxCmd *mockTmux) (*SessionManager, *FileSessionStore) {
+	t.Helper()
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "tmux-sessions.json"))
+	return &SessionManager{config: DefaultSessionConfig(), tmuxCmd: tmuxCmd, store: store}, store
+}
+
+func storedNames(t *testing.T, store SessionStore) []string {
+	t.Helper()
+	sessions, err := store.List()
+	if err != nil {
+		t.Fatalf("store.List() error = %v", err)
+	}
+	var names []string
+	for _, s := range sessions {
+		names = append(names, s.SessionName)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestSessionManagerPrune(t *testing.T) {
+	tmuxCmd := &mockTmux{sessions: []string{"gwq-run-live-20250101000000", "other-session"}}
+	sm, store := newTestManager(t, tmuxCmd)
+
+	for _, name := range []string{
+		"gwq-run-live-20250101000000",
+		"gwq-run-dead-20250101000000",
+		"gwq-run-gone-20250101000000",
+	} {
+		if err := store.Put(&Session{SessionName: name}); err != nil {
+			t.Fatalf("store.Put() error = %v", err)
+		}
+	}
+
+	removed, err := sm.Prune()
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Prune() removed %d, want 2", removed)
+	}
+	if got, want := storedNames(t, store), []string{"gwq-run-live-20250101000000"}; !slices.Equal(got, want) {
+		t.Errorf("remaining entries = %v, want %v", got, want)
+	}
+
+	// A second prune has nothing left to do.
+	if removed, err := sm.Prune(); err != nil || removed != 0 {
+		t.Errorf("second Prune() = %d, %v; want 0, nil", removed, err)
+	}
+}
+
+func TestFileSessionStore_SaveReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tmux-sessions.json")
+	store := NewFileSessionStore(path)
+
+	for _, name := range []string{"gwq-run-a-20250101000000", "gwq-run-b-20250101000000"} {
+		if err := store.Put(&Session{SessionName: name}); err != nil {
+			t.Fatalf("store.Put() error = %v", err)
+		}
+	}
+
+	if got, want := storedNames(t, store), []string{"gwq-run-a-20250101000000", "gwq-run-b-20250101000000"}; !slices.Equal(got, want) {
+		t.Errorf("stored entries = %v, want %v", got, want)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "tmux-sessions.json" {
+		t.Errorf("store dir = %v, want only the store file without temp files", entries)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("store file mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestSessionManagerPrune_ListError(t *testing.T) {
+	tmuxCmd := &mockTmux{listErr: errors.New("tmux not found")}
+	sm, store := newTestManager(t, tmuxCmd)
+	if err := store.Put(&Session{SessionName: "gwq-run-x-20250101000000"}); err != nil {
+		t.Fatalf("store.Put() error = %v", err)
+	}
+
+	if _, err := sm.Prune(); err == nil {
+		t.Fatal("Prune() expected error when tmux listing fails")
+	}
+	if got := storedNames(t, store); len(got) != 1 {
+		t.Errorf("entries should be kept when tmux listing fails, got %v", got)
+	}
+}
+
+func TestSessionManager_StoreLifecycle(t *testing.T) {
+	tmuxCmd := &mockTmux{}
+	sm, store := newTestManager(t, tmuxCmd)
+
+	session, err := sm.CreateSession(context.Background(), SessionOptions{
+		Context:    "run",
+		Identifier: "build",
+		WorkingDir: "/tmp",
+		Command:    "make",
+		Metadata:   map[string]string{"worktree": "/tmp"},
+	})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if got := storedNames(t, store); !slices.Equal(got, []string{session.SessionName}) {
+		t.Fatalf("stored entries = %v, want [%s]", got, session.SessionName)
+	}
+
+	sessions, err := sm.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Metadata["worktree"] != "/tmp" {
+		t.Errorf("ListSessions() did not restore stored metadata: %+v", sessions)
+	}
+
+	if err := sm.KillSessionDirect(session); err != nil {
+		t.Fatalf("KillSessionDirect() error = %v", err)
+	}
+	if got := storedNames(t, store); len(got) != 0 {
+		t.Errorf("stored entries after kill = %v, want none", got)
+	}
+}
+
+func TestSessionManagerRelocateSessions(t *testing.T) {
+	tmuxCmd := &mockTmux{sessions: []string{"gwq-run-a-20250101000000", "gwq-run-b-20250101000000"}}
+	sm, store := newTestManager(t, tmuxCmd)
+
+	entries := []*Session{
+		{SessionName: "gwq-run-a-20250101000000", WorkingDir: "/wt/feature/sub", Metadata: map[string]string{"worktree": "/wt/feature"}},
+		{SessionName: "gwq-run-b-20250101000000", WorkingDir: "/wt/feature-other"},
+		{SessionName: "gwq-run-dead-20250101000000", WorkingDir: "/wt/feature"},
+	}
+	for _, e := range entries {
+		if err := store.Put(e); err != nil {
+			t.Fatalf("store.Put() error = %v", err)
+		}
+	}
+
+	updated, err := sm.RelocateSessions("/wt/feature", "/moved")
+	if err != nil {
+		t.Fatalf("RelocateSessions() error = %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("RelocateSessions() updated %d, want 1", updated)
+	}
+
+	stored := sm.storedSessions()
+	if got := stored["gwq-run-a-20250101000000"]; got.WorkingDir != "/moved/sub" || got.Metadata["worktree"] != "/moved" {
+		t.Errorf("relocated entry = %+v", got)
+	}
+	if got := stored["gwq-run-b-20250101000000"].WorkingDir; got != "/wt/feature-other" {
+		t.Errorf("sibling path should be unchanged, got %s", got)
+	}
+	if got := stored["gwq-run-dead-20250101000000"].WorkingDir; got != "/wt/feature" {
+		t.Errorf("dead session should be left for prune, got %s", got)
+	}
+}
+
+func TestSessionManager_CreateSessionSanitizesName(t *testing.T) {
+	tests := []struct {
+		name       string
+		context    string
+		identifier string
+	}{
+		{name: "Dots", context: "run", identifier: "release-1.2.3-task"},
+		{name: "Colons", context: "run", identifier: "feature:login-abc"},
+		{name: "Both", context: "a.b", identifier: "v1.0:hotfix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxCmd := &mockTmux{}
+			sm, _ := newTestManager(t, tmuxCmd)
+
+			session, err := sm.CreateSession(context.Background(), SessionOptions{
+				Context:    tt.context,
+				Identifier: tt.identifier,
+				WorkingDir: "/tmp",
+				Metadata:   map[string]string{"worktree": "/tmp"},
+			})
+			if err != nil {
+				t.Fatalf("CreateSession() error = %v", err)
+			}
+			if strings.ContainsAny(session.SessionName, ".:") {
+				t.Errorf("SessionName %q contains tmux-unsafe characters", session.SessionName)
+			}
+			if !sm.HasSession(session.SessionName) {
+				t.Errorf("HasSession(%q) = false", session.SessionName)
+			}
+			if session.Identifier != tt.identifier || session.Metadata[MetadataIdentifier] != tt.identifier {
+				t.Errorf("identifier = %q, metadata %v; want %q kept", session.Identifier, session.Metadata, tt.identifier)
+			}
+
+			sessions, err := sm.ListSessions()
+			if err != nil {
+				t.Fatalf("ListSessions() error = %v", err)
+			}
+			if len(sessions) != 1 {
+				t.Fatalf("ListSessions() = %d sessions, want 1", len(sessions))
+			}
+			got := sessions[0]
+			if got.Context != tt.context || got.Identifier != tt.identifier {
+				t.Errorf("ListSessions() = %s/%s, want %s/%s", got.Context, got.Identifier, tt.context, tt.identifier)
+			}
+			if got.Metadata["worktree"] != "/tmp" {
+				t.Errorf("caller metadata lost: %v", got.Metadata)
+			}
+
+			if err := sm.KillSessionDirect(session); err != nil {
+				t.Fatalf("KillSessionDirect() error = %v", err)
+			}
+			if !slices.Equal(tmuxCmd.killed, []string{session.SessionName}) {
+				t.Errorf("killed = %v, want [%s]", tmuxCmd.killed, session.SessionName)
+			}
+		})
+	}
+}
+
+func TestSessionManager_CreateSessionKeepsSafeNames(t *testing.T) {
+	sm, _ := newTestManager(t, &mockTmux{})
+	metadata := map[string]string{"worktree": "/tmp"}
+
+	session, err := sm.CreateSession(context.Background(), SessionOptions{
+		Context:    "run",
+		Identifier: "build",
+		WorkingDir: "/tmp",
+		Metadata:   metadata,
+	})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if !strings.HasPrefix(session.SessionName, "gwq-run-build-") {
+		t.Errorf("SessionName = %q, want gwq-run-build-*", session.SessionName)
+	}
+	if _, ok := session.Metadata[MetadataIdentifier]; ok {
+		t.Errorf("metadata for a safe name should not record a mapping: %v", session.Metadata)
+	}
+	if _, ok := metadata[MetadataIdentifier]; ok {
+		t.Error("caller's metadata map was modified")
+	}
+}
+
+func TestSessionInfoActivityTime(t *testing.T) {
+	if got := (&SessionInfo{Activity: "1760000000"}).ActivityTime(); !got.Equal(time.Unix(1760000000, 0)) {
+		t.Errorf("ActivityTime() = %v", got)
+	}
+	for _, v := range []string{"", "abc", "0"} {
+		if got := (&SessionInfo{Activity: v}).ActivityTime(); !got.IsZero() {
+			t.Errorf("ActivityTime(%q) = %v, want zero", v, got)
+		}
+	}
+}
+
+func TestMostRecentlyActive(t *testing.T) {
+	tmuxCmd := &mockTmux{
+		sessions: []string{
+			"gwq-run-old-20250101000000",
+			"gwq-run-newest-20250101000000",
+			"gwq-run-middle-20250101000000",
+			"gwq-run-unknown-20250101000000",
+		},
+		activity: map[string]string{
+			"gwq-run-old-20250101000000":    "1760000000",
+			"gwq-run-newest-20250101000000": "1760000300",
+			"gwq-run-middle-20250101000000": "1760000100",
+		},
+	}
+	sm, _ := newTestManager(t, tmuxCmd)
+
+	sessions, err := sm.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	got := MostRecentlyActive(sessions)
+	if len(got) != 1 || got[0].Identifier != "newest" {
+		t.Fatalf("MostRecentlyActive() = %+v, want the newest session", got)
+	}
+
+	// Sessions active in the same second are ambiguous.
+	tmuxCmd.activity["gwq-run-middle-20250101000000"] = "1760000300"
+	sessions, err = sm.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if got := MostRecentlyActive(sessions); len(got) != 2 {
+		t.Errorf("MostRecentlyActive() with a tie = %d sessions, want 2", len(got))
+	}
+
+	if got := MostRecentlyActive([]*Session{{SessionName: "x"}}); len(got) != 0 {
+		t.Errorf("MostRecentlyActive() without activity = %+v, want none", got)
+	}
+}
+
+func TestSessionManager_AttachSessionDirectSwitchesInsideTmux(t *testing.T) {
+	name := "gwq-run-a-20250101000000"
+
+	t.Setenv("TMUX", "")
+	tmuxCmd := &mockTmux{sessions: []string{name}}
+	sm, _ := newTestManager(t, tmuxCmd)
+	if err := sm.AttachSessionDirect(&Session{SessionName: name}); err != nil {
+		t.Fatalf("AttachSessionDirect() error = %v", err)
+	}
+	if tmuxCmd.attached != name || tmuxCmd.switched != "" {
+		t.Errorf("outside tmux: attached %q, switched %q", tmuxCmd.attached, tmuxCmd.switched)
+	}
+
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	tmuxCmd = &mockTmux{sessions: []string{name}}
+	sm, _ = newTestManager(t, tmuxCmd)
+	if err := sm.AttachSessionDirect(&Session{SessionName: name}); err != nil {
+		t.Fatalf("AttachSessionDirect() error = %v", err)
+	}
+	if tmuxCmd.switched != name || tmuxCmd.attached != "" {
+		t.Errorf("inside tmux: attached %q, switched %q", tmuxCmd.attached, tmuxCmd.switched)
+	}
+}
+
+func TestSessionManager_CreateSessionLayout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SessionOptions
+		want func(session string) []string
+	}{
+		{
+			name: "single pane by default",
+			opts: SessionOptions{Context: "run", Identifier: "build", WorkingDir: "/wt", Command: "make"},
+			want: func(string) []string { return nil },
+		},
+		{
+			name: "splits, layout and windows in order",
+			opts: SessionOptions{
+				Context:       "run",
+				Identifier:    "build",
+				WorkingDir:    "/wt",
+				Command:       "make",
+				SplitCommands: []string{"tail -f build.log", ""},
+				Layout:        "main-vertical",
+				Windows:       []WindowSpec{{Name: "verify", Command: "make test"}, {}},
+			},
+			want: func(s string) []string {
+				return []string{
+					fmt.Sprintf("split %s /wt %q", s, "tail -f build.log"),
+					fmt.Sprintf("split %s /wt %q", s, ""),
+					fmt.Sprintf("layout %s main-vertical", s),
+					fmt.Sprintf("window %s verify /wt %q", s, "make test"),
+					fmt.Sprintf("window %s  /wt %q", s, ""),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxCmd := &mockTmux{}
+			sm, _ := newTestManager(t, tmuxCmd)
+
+			session, err := sm.CreateSession(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("CreateSession() error = %v", err)
+			}
+			if want := tt.want(session.SessionName); !slices.Equal(tmuxCmd.layout, want) {
+				t.Errorf("layout calls = %q, want %q", tmuxCmd.layout, want)
+			}
+		})
+	}
+}
+
+func TestSessionManager_CreateSessionLayoutError(t *testing.T) {
+	tmuxCmd := &mockTmux{splitErr: errors.New("no space for new pane")}
+	sm, store := newTestManager(t, tmuxCmd)
+
+	_, err := sm.CreateSession(context.Background(), SessionOptions{
+		Context:       "run",
+		Identifier:    "build",
+		SplitCommands: []string{"tail -f build.log"},
+		Layout:        "tiled",
+	})
+	if err == nil {
+		t.Fatal("CreateSession() expected error when splitting fails")
+	}
+	if len(tmuxCmd.killed) != 1 || len(tmuxCmd.sessions) != 0 {
+		t.Errorf("half-built session should be killed: killed = %v, live = %v", tmuxCmd.killed, tmuxCmd.sessions)
+	}
+	if len(tmuxCmd.layout) != 1 {
+		t.Errorf("layout calls = %q, want only the failed split", tmuxCmd.layout)
+	}
+	if got := storedNames(t, store); len(got) != 0 {
+		t.Errorf("stored sessions = %v, want none", got)
+	}
+}
+
+func TestSessionManager_CreateSessionQuotesWaitChannel(t *testing.T) {
+	tmuxCmd := &mockTmux{}
+	sm, _ := newTestManager(t, tmuxCmd)
+	sm.logDir = filepath.Join(t.TempDir(), "tmux-logs")
+
+	session, err := sm.CreateSession(context.Background(), SessionOptions{Context: "run", Identifier: "it's my $HOME; (x)", Command: "make"})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	// The shell must see the binary and the channel as single words, with
+	// nothing expanded.
+	command := tmuxCmd.commands[session.SessionName]
+	prefix, ok := strings.CutSuffix(command, "; make")
+	if !ok {
+		t.Fatalf("session command = %q, want it to end with the user command", command)
+	}
+	out, err := exec.Command("sh", "-c", `printf '%s\n' `+strings.TrimPrefix(prefix, "'tmux' wait-for ")).Output()
+	if err != nil {
+		t.Fatalf("sh failed on %q: %v", prefix, err)
+	}
+	if got := strings.TrimSuffix(string(out), "\n"); got != session.SessionName {
+		t.Errorf("shell saw channel %q, want %q", got, session.SessionName)
+	}
+}
+
+func TestSessionManager_CreateSessionCapturesOutput(t *testing.T) {
+	tmuxCmd := &mockTmux{}
+	sm, _ := newTestManager(t, tmuxCmd)
+	sm.logDir = filepath.Join(t.TempDir(), "tmux-logs")
+
+	session, err := sm.CreateSession(context.Background(), SessionOptions{Context: "run", Identifier: "build", Command: "make"})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	if got, want := tmuxCmd.piped[session.SessionName], SessionLogPath(sm.logDir, session.SessionName); got != want {
+		t.Errorf("piped to %q, want %q", got, want)
+	}
+	if info, err := os.Stat(sm.logDir); err != nil || !info.IsDir() {
+		t.Errorf("log directory not created: %v", err)
+	}
+
+	// The command waits until capturing has started.
+	wantCommand := "'tmux' wait-for '" + session.SessionName + "'; make"
+	if got := tmuxCmd.commands[session.SessionName]; got != wantCommand {
+		t.Errorf("session command = %q, want %q", got, wantCommand)
+	}
+	if !slices.Equal(tmuxCmd.signaled, []string{session.SessionName}) {
+		t.Errorf("signaled channels = %v, want [%s]", tmuxCmd.signaled, session.SessionName)
+	}
+	if session.Command != "make" {
+		t.Errorf("session.Command = %q, want the unwrapped command", session.Command)
+	}
+}
+
+func TestSessionManager_RenameSession(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldName := "gwq-run-make-20250102030405"
+
+	tests := []struct {
+		name          string
+		newIdentifier string
+		wantName      string
+		wantMeta      map[string]string
+	}{
+		{
+			name:          "plain identifier",
+			newIdentifier: "test-suite",
+			wantName:      "gwq-run-test-suite-20250102030405",
+			wantMeta:      map[string]string{"worktree": "/wt"},
+		},
+		{
+			name:          "identifier needing sanitizing",
+			newIdentifier: "v1.2:rc",
+			wantName:      "gwq-run-v1_2_rc-20250102030405",
+			wantMeta:      map[string]string{"worktree": "/wt", MetadataIdentifier: "v1.2:rc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxCmd := &mockTmux{sessions: []string{oldName}}
+			sm, store := newTestManager(t, tmuxCmd)
+			sm.logDir = t.TempDir()
+			if err := os.WriteFile(SessionLogPath(sm.logDir, oldName), []byte("output\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			stored := &Session{SessionName: oldName, Context: "run", Identifier: "make", StartTime: start, Metadata: map[string]string{"worktree": "/wt"}}
+			if err := store.Put(stored); err != nil {
+				t.Fatal(err)
+			}
+
+			session := &Session{SessionName: oldName, Context: "run", Identifier: "make", StartTime: start}
+			got, err := sm.RenameSession(session, tt.newIdentifier)
+			if err != nil {
+				t.Fatalf("RenameSession() error = %v", err)
+			}
+			if got != tt.wantName {
+				t.Errorf("RenameSession() = %q, want %q", got, tt.wantName)
+			}
+			if !slices.Equal(tmuxCmd.sessions, []string{tt.wantName}) {
+				t.Errorf("tmux sessions = %v, want [%s]", tmuxCmd.sessions, tt.wantName)
+			}
+			if names := storedNames(t, store); !slices.Equal(names, []string{tt.wantName}) {
+				t.Errorf("stored sessions = %v, want [%s]", names, tt.wantName)
+			}
+			entries, _ := store.List()
+			if entries[0].Identifier != tt.newIdentifier || !maps.Equal(entries[0].Metadata, tt.wantMeta) {
+				t.Errorf("stored entry = %q %v, want %q %v", entries[0].Identifier, entries[0].Metadata, tt.newIdentifier, tt.wantMeta)
+			}
+			if _, err := os.Stat(SessionLogPath(sm.logDir, tt.wantName)); err != nil {
+				t.Errorf("log not renamed: %v", err)
+			}
+
+			// The listing restores the readable identifier.
+			listed, err := sm.ListSessions()
+			if err != nil || len(listed) != 1 || listed[0].Identifier != tt.newIdentifier {
+				t.Errorf("ListSessions() = %v, %v; want identifier %q", listed, err, tt.newIdentifier)
+			}
+		})
+	}
+}
+
+func TestSessionManager_RenameSessionConflict(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tmuxCmd := &mockTmux{sessions: []string{"gwq-run-make-20250102030405", "gwq-run-test-20250102030405"}}
+	sm, _ := newTestManager(t, tmuxCmd)
+
+	session := &Session{SessionName: "gwq-run-make-20250102030405", Context: "run", Identifier: "make", StartTime: start}
+	if _, err := sm.RenameSession(session, "test"); err == nil {
+		t.Fatal("RenameSession() expected error when the new name is taken")
+	}
+	if tmuxCmd.sessions[0] != "gwq-run-make-20250102030405" {
+		t.Errorf("session renamed despite conflict: %v", tmuxCmd.sessions)
+	}
+}
@@ -0,0 +1,151 @@
+package tmux
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/table"
+	"github.com/d-kuro/gwq/internal/utils"
+)
+
+// SessionRenderer writes a list of sessions in one output format.
+type SessionRenderer interface {
+	Render(w io.Writer, sessions []*Session) error
+}
+
+// RenderOptions carries the display settings renderers may use.
+type RenderOptions struct {
+	TildeHome bool             // Abbreviate the home directory as ~
+	Now       func() time.Time // Clock for durations; nil means time.Now
+}
+
+func (o RenderOptions) now() time.Time {
+	if o.Now != nil {
+		return o.Now()
+	}
+	return time.Now()
+}
+
+// sessionRenderers maps --format names to renderer constructors. A new
+// format only needs an entry here.
+var sessionRenderers = map[string]func(RenderOptions) SessionRenderer{
+	"table": func(o RenderOptions) SessionRenderer { return &TableRenderer{Options: o} },
+	"json":  func(RenderOptions) SessionRenderer { return &JSONRenderer{} },
+	"csv":   func(o RenderOptions) SessionRenderer { return &CSVRenderer{Options: o} },
+}
+
+// SessionFormats returns the supported output formats in sorted order.
+func SessionFormats() []string {
+	return slices.Sorted(maps.Keys(sessionRenderers))
+}
+
+// NewSessionRenderer returns the renderer for format.
+func NewSessionRenderer(format string, opts RenderOptions) (SessionRenderer, error) {
+	newRenderer, ok := sessionRenderers[format]
+	if !ok {
+		return nil, fmt.Errorf("invalid format %q: must be one of %v", format, SessionFormats())
+	}
+	return newRenderer(opts), nil
+}
+
+// TableRenderer writes a human-readable table.
+type TableRenderer struct {
+	Options RenderOptions
+}
+
+// Render implements SessionRenderer.
+func (r *TableRenderer) Render(w io.Writer, sessions []*Session) error {
+	if len(sessions) == 0 {
+		_, err := fmt.Fprintln(w, "No tmux sessions found")
+		return err
+	}
+
+	now := r.Options.now()
+	t := table.New().SetOutput(w).Headers("SESSION", "DURATION", "WORKING_DIR")
+	for _, session := range sessions {
+		t.Row(
+			session.Context+"/"+session.Identifier,
+			FormatSessionAge(now.Sub(session.StartTime)),
+			r.workingDir(session.WorkingDir),
+		)
+	}
+	return t.Println()
+}
+
+// workingDir shortens dir to fit the table column.
+func (r *TableRenderer) workingDir(dir string) string {
+	if r.Options.TildeHome {
+		dir = utils.TildePath(dir)
+	}
+	if len(dir) > 30 {
+		return "..." + dir[len(dir)-27:]
+	}
+	return dir
+}
+
+// JSONRenderer writes the sessions as an indented JSON array.
+type JSONRenderer struct{}
+
+// Render implements SessionRenderer.
+func (r *JSONRenderer) Render(w io.Writer, sessions []*Session) error {
+	if sessions == nil {
+		sessions = []*Session{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sessions)
+}
+
+// CSVRenderer writes one CSV row per session with a header row. started is
+// RFC 3339 and duration is a Go duration rounded to seconds.
+type CSVRenderer struct {
+	Options RenderOptions
+}
+
+// Render implements SessionRenderer.
+func (r *CSVRenderer) Render(w io.Writer, sessions []*Session) error {
+	now := r.Options.now()
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"session_name", "context", "identifier", "command", "started", "duration"})
+	for _, session := range sessions {
+		_ = cw.Write([]string{
+			session.SessionName,
+			session.Context,
+			session.Identifier,
+			session.Command,
+			session.StartTime.Format(time.RFC3339),
+			now.Sub(session.StartTime).Round(time.Second).String(),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// FormatSessionAge describes how long a session has been running in
+// coarse units, e.g. "just now", "5 mins" or "2 days".
+func FormatSessionAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age.Minutes()), "min")
+	case age < 24*time.Hour:
+		return plural(int(age.Hours()), "hour")
+	case age < 7*24*time.Hour:
+		return plural(int(age.Hours()/24), "day")
+	default:
+		return plural(int(age.Hours()/24/7), "week")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,132 @@
+package tmux
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRenderSessions() ([]*Session, RenderOptions) {
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	sessions := []*Session{
+		{SessionName: "gwq-run-build-20240102150405", Context: "run", Identifier: "build", Command: `make "all", test`, WorkingDir: "/src/app", StartTime: start},
+		{SessionName: "gwq-claude-review-20240102150405", Context: "claude", Identifier: "review", Command: "claude", WorkingDir: "/home/user/worktrees/github.com/user/app/feature", StartTime: start},
+	}
+	return sessions, RenderOptions{Now: func() time.Time { return start.Add(90 * time.Minute) }}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	sessions, opts := testRenderSessions()
+	for _, input := range [][]*Session{sessions, nil} {
+		r, err := NewSessionRenderer("json", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := r.Render(&buf, input); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []*Session
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+		}
+		if got == nil || len(got) != len(input) {
+			t.Fatalf("decoded %d sessions (nil: %t), want %d", len(got), got == nil, len(input))
+		}
+		for i := range got {
+			if got[i].SessionName != input[i].SessionName || !got[i].StartTime.Equal(input[i].StartTime) {
+				t.Errorf("session %d = %+v, want %+v", i, got[i], input[i])
+			}
+		}
+	}
+}
+
+func TestCSVRenderer(t *testing.T) {
+	sessions, opts := testRenderSessions()
+	r, err := NewSessionRenderer("csv", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.Render(&buf, sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{
+		{"session_name", "context", "identifier", "command", "started", "duration"},
+		{"gwq-run-build-20240102150405", "run", "build", `make "all", test`, "2024-01-02T15:04:05Z", "1h30m0s"},
+		{"gwq-claude-review-20240102150405", "claude", "review", "claude", "2024-01-02T15:04:05Z", "1h30m0s"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(records[i], want[i]) {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	sessions, opts := testRenderSessions()
+	r, err := NewSessionRenderer("table", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Render(&buf, sessions); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"SESSION", "run/build", "claude/review", "1 hour", "/src/app", "...github.com/user/app/feature"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := r.Render(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "No tmux sessions found\n" {
+		t.Errorf("empty table output = %q", got)
+	}
+}
+
+func TestNewSessionRenderer_UnknownFormat(t *testing.T) {
+	if _, err := NewSessionRenderer("yaml", RenderOptions{}); err == nil {
+		t.Error("NewSessionRenderer(yaml) error = nil, want error")
+	}
+}
+
+func TestFormatSessionAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 min"},
+		{45 * time.Minute, "45 mins"},
+		{time.Hour, "1 hour"},
+		{5 * time.Hour, "5 hours"},
+		{24 * time.Hour, "1 day"},
+		{3 * 24 * time.Hour, "3 days"},
+		{7 * 24 * time.Hour, "1 week"},
+		{30 * 24 * time.Hour, "4 weeks"},
+	}
+	for _, tt := range tests {
+		if got := FormatSessionAge(tt.age); got != tt.want {
+			t.Errorf("FormatSessionAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
@@ -5,15 +5,18 @@ import (
 )
 
 type Session struct {
-	ID          string            `json:"id"`
-	SessionName string            `json:"session_name"`
-	Context     string            `json:"context"`
-	Identifier  string            `json:"identifier"`
-	WorkingDir  string            `json:"working_dir"`
-	Command     string            `json:"command"`
-	StartTime   time.Time         `json:"start_time"`
-	HistorySize int               `json:"history_size"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
+	ID          string    `json:"id"`
+	SessionName string    `json:"session_name"`
+	Context     string    `json:"context"`
+	Identifier  string    `json:"identifier"`
+	WorkingDir  string    `json:"working_dir"`
+	Command     string    `json:"command"`
+	StartTime   time.Time `json:"start_time"`
+	// LastActivity is the last time tmux saw activity in the session. It is
+	// only known for sessions returned by ListSessions.
+	LastActivity time.Time         `json:"last_activity,omitzero"`
+	HistorySize  int               `json:"history_size"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
 }
 
 type SessionOptions struct {
@@ -22,6 +25,22 @@ type SessionOptions struct {
 	WorkingDir string
 	Command    string
 	Metadata   map[string]string
+
+	// SplitCommands adds a pane to the first window for each command, in
+	// order, e.g. to tail a log next to the main command. An empty command
+	// starts a shell. The main command's pane stays selected.
+	SplitCommands []string
+	// Layout is the tmux layout (e.g. "tiled", "main-vertical") applied to
+	// the first window after splitting. Empty keeps tmux's default splits.
+	Layout string
+	// Windows adds further windows after the first one, in order.
+	Windows []WindowSpec
+}
+
+// WindowSpec describes an extra window of a session.
+type WindowSpec struct {
+	Name    string // Window name; empty lets tmux name it after the command
+	Command string // Command run in the window; empty starts a shell
 }
 
 type SessionConfig struct {
@@ -0,0 +1,138 @@
+package tmux
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SessionStore persists metadata for gwq-created sessions, such as the
+// original command and caller-supplied metadata, which tmux does not keep.
+// Entries are keyed by tmux session name.
+type SessionStore interface {
+	List() ([]*Session, error)
+	Put(session *Session) error
+	Delete(sessionNames ...string) error
+}
+
+// FileSessionStore is a SessionStore backed by a JSON file.
+type FileSessionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSessionStore creates a store that reads and writes path.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+// DefaultSessionStorePath returns the session metadata location under the gwq config dir.
+func DefaultSessionStorePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "gwq", "tmux-sessions.json")
+}
+
+// List returns all stored sessions.
+func (s *FileSessionStore) List() ([]*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(entries))
+	for _, session := range entries {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// Put adds or replaces the entry for session.SessionName.
+func (s *FileSessionStore) Put(session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[session.SessionName] = session
+	return s.save(entries)
+}
+
+// Delete removes the entries for the given session names.
+func (s *FileSessionStore) Delete(sessionNames ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	for _, name := range sessionNames {
+		delete(entries, name)
+	}
+	return s.save(entries)
+}
+
+func (s *FileSessionStore) load() (map[string]*Session, error) {
+	entries := make(map[string]*Session)
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("failed to read session store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse session store: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *FileSessionStore) save(entries map[string]*Session) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create session store directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session store: %w", err)
+	}
+
+	// Write to a temp file and rename it into place, so that a crash or a
+	// concurrent reader never sees a truncated store.
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create session store temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write session store: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write session store: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write session store: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace session store: %w", err)
+	}
+	return nil
+}
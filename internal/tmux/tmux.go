@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/utils"
 )
 
 // TmuxInterface defines the contract for tmux operations
@@ -20,7 +25,17 @@ type TmuxInterface interface {
 	ListSessionsDetailed() ([]*SessionInfo, error)
 	KillSession(sessionName string) error
 	AttachSession(sessionName string) error
+	SwitchClient(sessionName string) error
 	HasSession(sessionName string) bool
+	SplitWindow(sessionName, workDir, command string) error
+	SelectLayout(sessionName, layout string) error
+	NewWindowWithCommand(sessionName, windowName, workDir, command string) error
+	NewWindow(sessionName, workDir string) error
+	NewWindowCommand(sessionName, workDir, command string) error
+	PipePane(sessionName, logFile string) error
+	SignalChannel(channel string) error
+	RenameSession(oldName, newName string) error
+	CapturePane(sessionName string, startLine int) (string, error)
 }
 
 // SessionManagerInterface defines the contract for session management
@@ -32,7 +47,11 @@ type SessionManagerInterface interface {
 	KillSessionDirect(session *Session) error
 	AttachSession(id string) error
 	AttachSessionDirect(session *Session) error
+	RenameSession(session *Session, newIdentifier string) (string, error)
 	HasSession(sessionName string) bool
+	Prune() (int, error)
+	RelocateSessions(oldPath, newPath string) (int, error)
+	StartHealthCheck(ctx context.Context, interval time.Duration) <-chan SessionEvent
 }
 
 type TmuxCommand struct {
@@ -149,6 +168,16 @@ type SessionInfo struct {
 	WorkingDir     string
 }
 
+// ActivityTime returns the session's last activity, reported by tmux as Unix
+// seconds. It is zero when the value cannot be parsed.
+func (s *SessionInfo) ActivityTime() time.Time {
+	sec, err := strconv.ParseInt(s.Activity, 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
 func (t *TmuxCommand) KillSession(sessionName string) error {
 	args := []string{"kill-session", "-t", sessionName}
 	return t.runCommand(args...)
@@ -163,12 +192,128 @@ func (t *TmuxCommand) AttachSession(sessionName string) error {
 	return cmd.Run()
 }
 
+// SwitchClient moves the current tmux client to sessionName. It is used
+// instead of AttachSession when already running inside tmux.
+func (t *TmuxCommand) SwitchClient(sessionName string) error {
+	args := []string{"switch-client", "-t", sessionName}
+	return t.runCommand(args...)
+}
+
 func (t *TmuxCommand) HasSession(sessionName string) bool {
 	args := []string{"has-session", "-t", sessionName}
 	err := t.runCommand(args...)
 	return err == nil
 }
 
+// SplitWindow adds a pane running command to the current window of
+// sessionName without selecting it. An empty command starts a shell.
+func (t *TmuxCommand) SplitWindow(sessionName, workDir, command string) error {
+	return t.runCommand(splitWindowArgs(sessionName, workDir, command)...)
+}
+
+func splitWindowArgs(sessionName, workDir, command string) []string {
+	args := []string{"split-window", "-d", "-t", sessionName}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	return args
+}
+
+// SelectLayout arranges the panes of the current window of sessionName.
+func (t *TmuxCommand) SelectLayout(sessionName, layout string) error {
+	return t.runCommand("select-layout", "-t", sessionName, layout)
+}
+
+// NewWindowWithCommand adds a window running command to sessionName without
+// selecting it. An empty command starts a shell.
+func (t *TmuxCommand) NewWindowWithCommand(sessionName, windowName, workDir, command string) error {
+	return t.runCommand(newWindowArgs(sessionName, windowName, workDir, command)...)
+}
+
+func newWindowArgs(sessionName, windowName, workDir, command string) []string {
+	// The trailing colon targets the session, so the window gets the next
+	// free index instead of replacing the current one.
+	args := []string{"new-window", "-d", "-t", sessionName + ":"}
+	if windowName != "" {
+		args = append(args, "-n", windowName)
+	}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	return args
+}
+
+// NewWindow opens a shell in workDir in a new window of sessionName and makes
+// it the session's current window.
+func (t *TmuxCommand) NewWindow(sessionName, workDir string) error {
+	return t.NewWindowCommand(sessionName, workDir, "")
+}
+
+// NewWindowCommand is NewWindow running command instead of a shell.
+func (t *TmuxCommand) NewWindowCommand(sessionName, workDir, command string) error {
+	return t.runCommand(openWindowArgs(sessionName, workDir, command)...)
+}
+
+func openWindowArgs(sessionName, workDir, command string) []string {
+	args := []string{"new-window", "-t", sessionName + ":"}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	if command != "" {
+		args = append(args, command)
+	}
+	return args
+}
+
+// PipePane appends everything the current pane of sessionName prints to
+// logFile. The pipe lives as long as the pane, so the log outlasts the
+// session.
+func (t *TmuxCommand) PipePane(sessionName, logFile string) error {
+	return t.runCommand(pipePaneArgs(sessionName, logFile)...)
+}
+
+func pipePaneArgs(sessionName, logFile string) []string {
+	// -o only opens a pipe when none is open, so repeated calls never
+	// duplicate output.
+	return []string{"pipe-pane", "-o", "-t", sessionName, fmt.Sprintf(`cat >> "%s"`, utils.EscapeForShell(logFile))}
+}
+
+func (t *TmuxCommand) RenameSession(oldName, newName string) error {
+	args := []string{"rename-session", "-t", oldName, newName}
+	return t.runCommand(args...)
+}
+
+// HistoryStart is the CapturePane start line for the oldest line of the
+// pane's scrollback.
+const HistoryStart = math.MinInt
+
+// CapturePane returns the text of the current pane of sessionName from
+// startLine to the last visible line. Line 0 is the first visible line and
+// negative lines reach back into the scrollback; HistoryStart captures all
+// of it.
+func (t *TmuxCommand) CapturePane(sessionName string, startLine int) (string, error) {
+	return t.runCommandOutput(capturePaneArgs(sessionName, startLine)...)
+}
+
+func capturePaneArgs(sessionName string, startLine int) []string {
+	start := strconv.Itoa(startLine)
+	if startLine == HistoryStart {
+		start = "-"
+	}
+	return []string{"capture-pane", "-p", "-t", sessionName, "-S", start}
+}
+
+// SignalChannel wakes up clients blocked in 'tmux wait-for channel'.
+func (t *TmuxCommand) SignalChannel(channel string) error {
+	return t.runCommand("wait-for", "-S", channel)
+}
+
 func (t *TmuxCommand) runCommand(args ...string) error {
 	cmd := exec.Command(t.command, args...)
 	var stderr bytes.Buffer
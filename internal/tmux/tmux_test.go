@@ -0,0 +1,99 @@
+package tmux
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitWindowArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		workDir string
+		command string
+		want    []string
+	}{
+		{name: "shell", want: []string{"split-window", "-d", "-t", "s"}},
+		{name: "command in dir", workDir: "/wt", command: "tail -f log", want: []string{"split-window", "-d", "-t", "s", "-c", "/wt", "tail -f log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitWindowArgs("s", tt.workDir, tt.command); !slices.Equal(got, tt.want) {
+				t.Errorf("splitWindowArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWindowArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		windowName string
+		workDir    string
+		command    string
+		want       []string
+	}{
+		{name: "shell", want: []string{"new-window", "-d", "-t", "s:"}},
+		{
+			name:       "named command in dir",
+			windowName: "verify",
+			workDir:    "/wt",
+			command:    "make test",
+			want:       []string{"new-window", "-d", "-t", "s:", "-n", "verify", "-c", "/wt", "make test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newWindowArgs("s", tt.windowName, tt.workDir, tt.command); !slices.Equal(got, tt.want) {
+				t.Errorf("newWindowArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenWindowArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		workDir string
+		command string
+		want    []string
+	}{
+		{name: "shell", workDir: "/wt", want: []string{"new-window", "-t", "s:", "-c", "/wt"}},
+		{name: "command", workDir: "/wt", command: "nvim", want: []string{"new-window", "-t", "s:", "-c", "/wt", "nvim"}},
+		{name: "no dir", want: []string{"new-window", "-t", "s:"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := openWindowArgs("s", tt.workDir, tt.command); !slices.Equal(got, tt.want) {
+				t.Errorf("openWindowArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipePaneArgs(t *testing.T) {
+	got := pipePaneArgs("gwq-run-build-1", `/home/me/my "logs"/build.log`)
+	want := []string{"pipe-pane", "-o", "-t", "gwq-run-build-1", `cat >> "/home/me/my \"logs\"/build.log"`}
+	if !slices.Equal(got, want) {
+		t.Errorf("pipePaneArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestCapturePaneArgs(t *testing.T) {
+	tests := []struct {
+		start int
+		want  []string
+	}{
+		{start: HistoryStart, want: []string{"capture-pane", "-p", "-t", "s", "-S", "-"}},
+		{start: 0, want: []string{"capture-pane", "-p", "-t", "s", "-S", "0"}},
+		{start: -100, want: []string{"capture-pane", "-p", "-t", "s", "-S", "-100"}},
+	}
+
+	for _, tt := range tests {
+		if got := capturePaneArgs("s", tt.start); !slices.Equal(got, tt.want) {
+			t.Errorf("capturePaneArgs(%d) = %q, want %q", tt.start, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// Terminal control sequences used by TerminalRefresher.
+const (
+	clearScreen = "\033[2J\033[H"
+	hideCursor  = "\033[?25l"
+	showCursor  = "\033[?25h"
+)
+
+// TerminalRefresher redraws a full-screen view in place, like watch(1).
+// Each frame is rendered into a buffer first and written together with the
+// clear sequence, so a slow render never leaves the screen half drawn.
+type TerminalRefresher struct {
+	w    io.Writer
+	mu   sync.Mutex
+	last []byte
+}
+
+// NewTerminalRefresher creates a TerminalRefresher writing to w, or to
+// os.Stdout when w is nil.
+func NewTerminalRefresher(w io.Writer) *TerminalRefresher {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &TerminalRefresher{w: w}
+}
+
+// Start hides the cursor for the duration of the refresh loop.
+func (r *TerminalRefresher) Start() {
+	_, _ = io.WriteString(r.w, hideCursor)
+}
+
+// Stop restores the cursor.
+func (r *TerminalRefresher) Stop() {
+	_, _ = io.WriteString(r.w, showCursor)
+}
+
+// Render clears the screen and draws the frame produced by render. When
+// render fails nothing is written and the previous frame stays on screen.
+func (r *TerminalRefresher) Render(render func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = buf.Bytes()
+	return r.write()
+}
+
+// Redraw clears the screen and draws the last frame again, e.g. after the
+// terminal was resized.
+func (r *TerminalRefresher) Redraw() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.write()
+}
+
+func (r *TerminalRefresher) write() error {
+	_, err := r.w.Write(append([]byte(clearScreen), r.last...))
+	return err
+}
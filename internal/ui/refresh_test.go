@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestTerminalRefresher(t *testing.T) {
+	var out bytes.Buffer
+	r := NewTerminalRefresher(&out)
+
+	r.Start()
+	if err := r.Render(func(w io.Writer) error {
+		_, err := io.WriteString(w, "frame 1\n")
+		return err
+	}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if err := r.Render(func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("boom")
+	}); err == nil {
+		t.Fatal("Render() error = nil, want the render error")
+	}
+	if err := r.Redraw(); err != nil {
+		t.Fatalf("Redraw() error = %v", err)
+	}
+	r.Stop()
+
+	want := hideCursor + clearScreen + "frame 1\n" + clearScreen + "frame 1\n" + showCursor
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package ui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyResize relays terminal resize signals to c. Call signal.Stop(c) to
+// stop relaying.
+func NotifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
@@ -0,0 +1,6 @@
+package ui
+
+import "os"
+
+// NotifyResize is a no-op on Windows, which has no resize signal.
+func NotifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/git"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// SortKeys are the keys accepted by SortWorktrees.
+var SortKeys = []string{"name", "path", "activity", "commit-date"}
+
+// timeLoaders load the timestamp each date sort key orders by. They are
+// variables so tests can avoid real repositories.
+var timeLoaders = map[string]func(path string) time.Time{
+	"activity":    activityTime,
+	"commit-date": commitTime,
+}
+
+// ValidateSortKey returns an error unless key is one of SortKeys.
+func ValidateSortKey(key string) error {
+	if !slices.Contains(SortKeys, strings.ToLower(key)) {
+		return fmt.Errorf("invalid sort key %q: must be one of %s", key, strings.Join(SortKeys, ", "))
+	}
+	return nil
+}
+
+// SortWorktrees sorts worktrees in place by one of SortKeys: name (branch,
+// alphabetical), path, activity (last checkout, commit or reset in the
+// worktree, most recent first) or commit-date (HEAD commit, most recent
+// first). Ties are broken by path so the order is deterministic. Dates are
+// only read from git for the date keys, once per worktree.
+func SortWorktrees(worktrees []models.Worktree, by string) error {
+	by = strings.ToLower(by)
+	if err := ValidateSortKey(by); err != nil {
+		return err
+	}
+
+	var compare func(a, b models.Worktree) int
+	switch by {
+	case "name":
+		compare = func(a, b models.Worktree) int { return cmp.Compare(a.Branch, b.Branch) }
+	case "path":
+		compare = func(a, b models.Worktree) int { return cmp.Compare(a.Path, b.Path) }
+	default:
+		load := timeLoaders[by]
+		times := make(map[string]time.Time, len(worktrees))
+		for _, wt := range worktrees {
+			times[wt.Path] = load(wt.Path)
+		}
+		compare = func(a, b models.Worktree) int { return times[b.Path].Compare(times[a.Path]) }
+	}
+
+	slices.SortStableFunc(worktrees, func(a, b models.Worktree) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Path, b.Path))
+	})
+	return nil
+}
+
+// commitTime returns the committer date of HEAD in the worktree at path, or
+// the zero time when it cannot be read.
+func commitTime(path string) time.Time {
+	output, err := git.New(path).RunCommand("log", "-1", "--format=%cI", "HEAD")
+	if err != nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(output))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// activityTime returns when HEAD last moved in the worktree at path, taken
+// from its HEAD reflog, falling back to the HEAD commit date.
+func activityTime(path string) time.Time {
+	g := git.New(path)
+	if output, err := g.RunCommand("rev-parse", "--git-path", "logs/HEAD"); err == nil {
+		logPath := strings.TrimSpace(output)
+		if !filepath.IsAbs(logPath) {
+			logPath = filepath.Join(path, logPath)
+		}
+		if info, err := os.Stat(logPath); err == nil {
+			return info.ModTime()
+		}
+	}
+	return commitTime(path)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestSortWorktrees(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC) }
+	times := map[string]time.Time{"/a": day(1), "/b": day(3), "/c": day(3), "/d": day(2)}
+	for key := range timeLoaders {
+		original := timeLoaders[key]
+		timeLoaders[key] = func(path string) time.Time { return times[path] }
+		t.Cleanup(func() { timeLoaders[key] = original })
+	}
+
+	worktrees := []models.Worktree{
+		{Path: "/d", Branch: "feature"},
+		{Path: "/c", Branch: "main"},
+		{Path: "/b", Branch: "feature"},
+		{Path: "/a", Branch: "bugfix"},
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		// Equal branch names fall back to path.
+		{by: "name", want: []string{"/a", "/b", "/d", "/c"}},
+		{by: "NAME", want: []string{"/a", "/b", "/d", "/c"}},
+		{by: "path", want: []string{"/a", "/b", "/c", "/d"}},
+		// Most recent first; /b and /c share a date.
+		{by: "activity", want: []string{"/b", "/c", "/d", "/a"}},
+		{by: "commit-date", want: []string{"/b", "/c", "/d", "/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := slices.Clone(worktrees)
+			if err := SortWorktrees(sorted, tt.by); err != nil {
+				t.Fatalf("SortWorktrees() error = %v", err)
+			}
+			var got []string
+			for _, wt := range sorted {
+				got = append(got, wt.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortWorktrees(%q) = %v, want %v", tt.by, got, tt.want)
+			}
+		})
+	}
+
+	if err := SortWorktrees(slices.Clone(worktrees), "size"); err == nil {
+		t.Error("SortWorktrees() accepted an unknown key")
+	}
+}
+
+func TestWorktreeTimes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", dir},
+		{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-q", "--allow-empty", "-m", "init", "--date=2020-01-02T03:04:05Z"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-02T03:04:05Z")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	if got, want := commitTime(dir), time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("commitTime() = %v, want %v", got, want)
+	}
+	// The reflog was written just now, not at the commit date.
+	if got := activityTime(dir); time.Since(got) > time.Hour {
+		t.Errorf("activityTime() = %v, want the time of the last HEAD update", got)
+	}
+	if got := commitTime(filepath.Join(dir, "missing")); !got.IsZero() {
+		t.Errorf("commitTime() outside a repository = %v, want zero", got)
+	}
+}
@@ -4,7 +4,9 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/d-kuro/gwq/internal/table"
@@ -38,14 +40,33 @@ func New(config *models.UIConfig) *Printer {
 
 // PrintWorktrees displays worktrees in a formatted table.
 func (p *Printer) PrintWorktrees(worktrees []models.Worktree, verbose bool) {
+	p.FprintWorktrees(os.Stdout, worktrees, verbose)
+}
+
+// FprintWorktrees writes worktrees to w in a formatted table.
+func (p *Printer) FprintWorktrees(w io.Writer, worktrees []models.Worktree, verbose bool) {
 	if len(worktrees) == 0 {
-		fmt.Println("No worktrees found")
+		_, _ = fmt.Fprintln(w, "No worktrees found")
 		return
 	}
 
+	showBehind := hasCommitsBehind(worktrees)
+	headers := func(names ...string) []string {
+		if showBehind {
+			names = append(names, "BEHIND")
+		}
+		return names
+	}
+	row := func(wt models.Worktree, cells ...string) []string {
+		if showBehind {
+			cells = append(cells, formatCommitsBehind(wt.CommitsBehind))
+		}
+		return cells
+	}
+
 	var t *table.Builder
 	if verbose {
-		t = table.New().Headers("BRANCH", "PATH", "COMMIT", "CREATED", "TYPE")
+		t = table.New().Headers(headers("BRANCH", "PATH", "COMMIT", "CREATED", "TYPE")...)
 		for _, wt := range worktrees {
 			wtType := models.WorktreeTypeWorktree
 			if wt.IsMain {
@@ -59,21 +80,24 @@ func (p *Printer) PrintWorktrees(worktrees []models.Worktree, verbose bool) {
 			} else {
 				branchWithMarker = "  " + wt.Branch // Two spaces to match "● " width
 			}
+			if wt.BranchDirMismatch {
+				branchWithMarker += " [dir mismatch]"
+			}
 
 			path := wt.Path
 			if p.useTildeHome {
 				path = utils.TildePath(path)
 			}
-			t.Row(
+			t.Row(row(wt,
 				branchWithMarker,
 				path,
 				p.truncateHash(wt.CommitHash),
 				p.formatTime(wt.CreatedAt),
 				wtType,
-			)
+			)...)
 		}
 	} else {
-		t = table.New().Headers("BRANCH", "PATH")
+		t = table.New().Headers(headers("BRANCH", "PATH")...)
 		for _, wt := range worktrees {
 			// Apply marker with consistent spacing
 			var branchWithMarker string
@@ -87,13 +111,33 @@ func (p *Printer) PrintWorktrees(worktrees []models.Worktree, verbose bool) {
 			if p.useTildeHome {
 				path = utils.TildePath(path)
 			}
-			t.Row(branchWithMarker, path)
+			t.Row(row(wt, branchWithMarker, path)...)
 		}
 	}
 
-	if err := t.Println(); err != nil {
-		fmt.Printf("Error printing table: %v\n", err)
+	if err := t.SetOutput(w).Println(); err != nil {
+		_, _ = fmt.Fprintf(w, "Error printing table: %v\n", err)
+	}
+}
+
+// hasCommitsBehind reports whether any worktree carries a behind count, in
+// which case the table gets a BEHIND column.
+func hasCommitsBehind(worktrees []models.Worktree) bool {
+	for _, wt := range worktrees {
+		if wt.CommitsBehind != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// formatCommitsBehind renders a behind count, or "-" for a branch without
+// an upstream.
+func formatCommitsBehind(n *int) string {
+	if n == nil {
+		return "-"
 	}
+	return strconv.Itoa(*n)
 }
 
 // PrintWorktreesJSON displays worktrees in JSON format.
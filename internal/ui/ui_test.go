@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -157,6 +158,50 @@ func TestPrintWorktreesEmpty(t *testing.T) {
 	}
 }
 
+func TestFprintWorktreesCommitsBehind(t *testing.T) {
+	three := 3
+	tests := []struct {
+		name      string
+		worktrees []models.Worktree
+		want      []string
+		wantNo    []string
+	}{
+		{
+			name: "counts and no upstream",
+			worktrees: []models.Worktree{
+				{Path: "/wt/main", Branch: "main", CommitsBehind: &three},
+				{Path: "/wt/local", Branch: "local"},
+			},
+			want: []string{"BEHIND", "3", "-"},
+		},
+		{
+			name:      "not requested",
+			worktrees: []models.Worktree{{Path: "/wt/main", Branch: "main"}},
+			wantNo:    []string{"BEHIND"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, verbose := range []bool{false, true} {
+				var buf bytes.Buffer
+				New(&models.UIConfig{}).FprintWorktrees(&buf, tt.worktrees, verbose)
+				output := buf.String()
+				for _, s := range tt.want {
+					if !strings.Contains(output, s) {
+						t.Errorf("verbose=%v: output %q should contain %q", verbose, output, s)
+					}
+				}
+				for _, s := range tt.wantNo {
+					if strings.Contains(output, s) {
+						t.Errorf("verbose=%v: output %q should not contain %q", verbose, output, s)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestPrintWorktreesJSON(t *testing.T) {
 	// Capture stdout
 	oldStdout := os.Stdout
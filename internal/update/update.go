@@ -0,0 +1,351 @@
+// Package update downloads gwq releases from GitHub and replaces the running
+// executable with them.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+)
+
+// DefaultReleaseURL is the GitHub API endpoint for the latest gwq release.
+const DefaultReleaseURL = "https://api.github.com/repos/d-kuro/gwq/releases/latest"
+
+// maxDownloadBytes caps any single download so a misbehaving server cannot
+// exhaust memory.
+const maxDownloadBytes = 128 << 20
+
+// ErrNotWritable is returned when the executable cannot be replaced by the
+// current user, typically because a package manager owns it.
+var ErrNotWritable = errors.New("executable is not writable")
+
+// Release is a published gwq release.
+type Release struct {
+	Version string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Updater fetches releases and installs them.
+type Updater struct {
+	Client     *http.Client
+	ReleaseURL string
+	FS         filesystem.FileSystemInterface
+}
+
+// New creates an Updater for the official GitHub releases.
+func New() *Updater {
+	return &Updater{
+		Client:     &http.Client{Timeout: 5 * time.Minute},
+		ReleaseURL: DefaultReleaseURL,
+		FS:         filesystem.NewStandardFileSystem(),
+	}
+}
+
+// LatestRelease returns the most recent release.
+func (u *Updater) LatestRelease(ctx context.Context) (*Release, error) {
+	data, err := u.get(ctx, u.ReleaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.Version == "" {
+		return nil, fmt.Errorf("failed to parse release: missing tag name")
+	}
+	return &release, nil
+}
+
+// DownloadBinary downloads the release archive for goos/goarch, verifies it
+// against the release checksums and returns the gwq executable inside it.
+func (u *Updater) DownloadBinary(ctx context.Context, release *Release, goos, goarch string) ([]byte, error) {
+	name := ArchiveName(goos, goarch)
+	archive, ok := release.asset(func(n string) bool { return n == name })
+	if !ok {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s", release.Version, goos, goarch)
+	}
+	checksums, ok := release.asset(func(n string) bool { return strings.HasSuffix(n, "checksums.txt") })
+	if !ok {
+		return nil, fmt.Errorf("release %s has no checksums file", release.Version)
+	}
+
+	sums, err := u.get(ctx, checksums.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksums.Name, err)
+	}
+	data, err := u.get(ctx, archive.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", archive.Name, err)
+	}
+	if err := VerifyChecksum(data, sums, name); err != nil {
+		return nil, err
+	}
+	return extractBinary(data, name)
+}
+
+// CheckWritable reports ErrNotWritable when a new executable cannot be
+// written next to exePath.
+func (u *Updater) CheckWritable(exePath string) error {
+	tmp := stagingPath(exePath)
+	if err := u.FS.WriteFile(tmp, nil, 0o600); err != nil {
+		return writeError(exePath, err)
+	}
+	_ = u.FS.Remove(tmp)
+	return nil
+}
+
+// moveAsideRunning reports whether the running executable must be renamed
+// out of the way before it can be replaced. Windows cannot overwrite a
+// running executable, but it can rename it. Tests set it to exercise that
+// path on other platforms.
+var moveAsideRunning = runtime.GOOS == "windows"
+
+// Replace atomically replaces the executable at exePath with binary by
+// writing it alongside and renaming it into place. If the new binary cannot
+// be moved into place, the previous executable is restored.
+func (u *Updater) Replace(exePath string, binary []byte) error {
+	tmp := stagingPath(exePath)
+	if err := u.FS.WriteFile(tmp, binary, 0o755); err != nil {
+		return writeError(exePath, err)
+	}
+
+	old := ""
+	if moveAsideRunning {
+		old = exePath + ".old"
+		_ = u.FS.Remove(old)
+		if err := u.FS.Rename(exePath, old); err != nil {
+			_ = u.FS.Remove(tmp)
+			return writeError(exePath, err)
+		}
+	}
+
+	if err := u.FS.Rename(tmp, exePath); err != nil {
+		_ = u.FS.Remove(tmp)
+		if old != "" {
+			if restoreErr := u.FS.Rename(old, exePath); restoreErr != nil {
+				return fmt.Errorf("%w; restoring the previous executable from %s also failed: %v", writeError(exePath, err), old, restoreErr)
+			}
+		}
+		return writeError(exePath, err)
+	}
+	return nil
+}
+
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadBytes {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxDownloadBytes)
+	}
+	return data, nil
+}
+
+func (r *Release) asset(match func(name string) bool) (Asset, bool) {
+	for _, a := range r.Assets {
+		if match(a.Name) {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ArchiveName returns the release archive name for goos/goarch, following
+// the name_template in .goreleaser.yaml.
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("gwq_%s%s_%s.%s", strings.ToUpper(goos[:1]), goos[1:], arch, ext)
+}
+
+// VerifyChecksum checks data against the SHA-256 listed for name in a
+// checksums file ("<hex>  <name>" per line).
+func VerifyChecksum(data, checksums []byte, name string) error {
+	for line := range strings.Lines(string(checksums)) {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, fields[0]) {
+			return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, fields[0])
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// CompareVersions compares two release versions such as "v1.2.3" or
+// "0.4.0-rc.1", returning -1, 0 or 1. A pre-release sorts before the
+// release it precedes; pre-release labels are compared as strings.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.numbers {
+		if va.numbers[i] != vb.numbers[i] {
+			if va.numbers[i] < vb.numbers[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0, nil
+	case va.pre == "":
+		return 1, nil
+	case vb.pre == "":
+		return -1, nil
+	case va.pre < vb.pre:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+type releaseVersion struct {
+	numbers [3]int
+	pre     string
+}
+
+func parseVersion(s string) (releaseVersion, error) {
+	var v releaseVersion
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	core, _, _ = strings.Cut(core, "+")
+	core, v.pre, _ = strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
+
+// extractBinary returns the gwq executable from a release archive.
+func extractBinary(archive []byte, archiveName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return extractFromZip(archive)
+	}
+	return extractFromTarGz(archive)
+}
+
+func isBinaryName(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	return base == "gwq" || base == "gwq.exe"
+}
+
+func extractFromTarGz(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive does not contain gwq")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && isBinaryName(hdr.Name) {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadBytes))
+		}
+	}
+}
+
+func extractFromZip(archive []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !isBinaryName(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadBytes))
+	}
+	return nil, fmt.Errorf("archive does not contain gwq")
+}
+
+// stagingPath is where the new executable is written before it is renamed
+// over exePath; it must be in the same directory for the rename to be atomic.
+func stagingPath(exePath string) string {
+	return filepath.Join(filepath.Dir(exePath), "."+filepath.Base(exePath)+".update")
+}
+
+func writeError(exePath string, err error) error {
+	if errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("%w: %s: %v", ErrNotWritable, exePath, err)
+	}
+	return fmt.Errorf("failed to replace %s: %w", exePath, err)
+}
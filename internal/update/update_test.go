@@ -0,0 +1,323 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "v0.1.0", b: "v0.1.0", want: 0},
+		{a: "0.1.0", b: "v0.1.0", want: 0},
+		{a: "v0.1.0", b: "v0.2.0", want: -1},
+		{a: "v1.10.0", b: "v1.9.3", want: 1},
+		{a: "v1.2.3", b: "v1.2.4", want: -1},
+		{a: "v1.2.3-rc.1", b: "v1.2.3", want: -1},
+		{a: "v1.2.3", b: "v1.2.3-rc.1", want: 1},
+		{a: "v1.2.3-rc.1", b: "v1.2.3-rc.2", want: -1},
+		{a: "v1.2.3+build.5", b: "v1.2.3", want: 0},
+		{a: "dev", b: "v1.0.0", wantErr: true},
+		{a: "v1.0", b: "v1.0.0", wantErr: true},
+		{a: "v1.0.0", b: "abc1234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompareVersions(%q, %q) error = %v, wantErr %v", tt.a, tt.b, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		goos, goarch, want string
+	}{
+		{goos: "linux", goarch: "amd64", want: "gwq_Linux_x86_64.tar.gz"},
+		{goos: "darwin", goarch: "arm64", want: "gwq_Darwin_arm64.tar.gz"},
+		{goos: "linux", goarch: "386", want: "gwq_Linux_i386.tar.gz"},
+		{goos: "windows", goarch: "amd64", want: "gwq_Windows_x86_64.zip"},
+	}
+	for _, tt := range tests {
+		if got := ArchiveName(tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("ArchiveName(%q, %q) = %q, want %q", tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive contents")
+	checksums := []byte(fmt.Sprintf("%s  gwq_Darwin_arm64.tar.gz\n%s  gwq_Linux_x86_64.tar.gz\n",
+		sha256Hex([]byte("other")), sha256Hex(data)))
+
+	tests := []struct {
+		name    string
+		data    []byte
+		file    string
+		wantErr string
+	}{
+		{name: "match", data: data, file: "gwq_Linux_x86_64.tar.gz"},
+		{name: "mismatch", data: []byte("tampered"), file: "gwq_Linux_x86_64.tar.gz", wantErr: "checksum mismatch"},
+		{name: "not listed", data: data, file: "gwq_Windows_x86_64.zip", wantErr: "no checksum listed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyChecksum(tt.data, checksums, tt.file)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyChecksum() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyChecksum() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func tarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newReleaseServer serves a latest-release document and its assets.
+func newReleaseServer(t *testing.T, version string, assets map[string][]byte) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	release := Release{Version: version}
+	for name, data := range assets {
+		release.Assets = append(release.Assets, Asset{Name: name, URL: srv.URL + "/download/" + name})
+		mux.HandleFunc("/download/"+name, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(data)
+		})
+	}
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(release)
+	})
+	return srv
+}
+
+func newTestUpdater(srv *httptest.Server) *Updater {
+	return &Updater{
+		Client:     srv.Client(),
+		ReleaseURL: srv.URL + "/latest",
+		FS:         filesystem.NewStandardFileSystem(),
+	}
+}
+
+func TestDownloadBinary(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new gwq\n")
+	tgz := tarGz(t, map[string][]byte{"README.md": []byte("docs"), "gwq": binary})
+	zipped := zipArchive(t, map[string][]byte{"gwq.exe": binary})
+	checksums := []byte(fmt.Sprintf("%s  gwq_Linux_x86_64.tar.gz\n%s  gwq_Windows_x86_64.zip\n%s  gwq_Darwin_arm64.tar.gz\n",
+		sha256Hex(tgz), sha256Hex(zipped), sha256Hex(tgz)))
+
+	srv := newReleaseServer(t, "v1.2.0", map[string][]byte{
+		"gwq_Linux_x86_64.tar.gz":  tgz,
+		"gwq_Windows_x86_64.zip":   zipped,
+		"gwq_1.2.0_checksums.txt":  checksums,
+		"gwq_Darwin_arm64.tar.gz":  []byte("not what the checksum says"),
+		"gwq_Darwin_x86_64.tar.gz": tgz,
+	})
+	u := newTestUpdater(srv)
+	ctx := context.Background()
+
+	release, err := u.LatestRelease(ctx)
+	if err != nil {
+		t.Fatalf("LatestRelease() error = %v", err)
+	}
+	if release.Version != "v1.2.0" {
+		t.Errorf("Version = %q, want v1.2.0", release.Version)
+	}
+
+	for _, platform := range [][2]string{{"linux", "amd64"}, {"windows", "amd64"}} {
+		got, err := u.DownloadBinary(ctx, release, platform[0], platform[1])
+		if err != nil {
+			t.Fatalf("DownloadBinary(%s) error = %v", platform, err)
+		}
+		if !bytes.Equal(got, binary) {
+			t.Errorf("DownloadBinary(%s) = %q, want %q", platform, got, binary)
+		}
+	}
+
+	if _, err := u.DownloadBinary(ctx, release, "darwin", "arm64"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("DownloadBinary(darwin/arm64) error = %v, want checksum mismatch", err)
+	}
+	if _, err := u.DownloadBinary(ctx, release, "darwin", "amd64"); err == nil || !strings.Contains(err.Error(), "no checksum listed") {
+		t.Errorf("DownloadBinary(darwin/amd64) error = %v, want a missing checksum", err)
+	}
+	if _, err := u.DownloadBinary(ctx, release, "freebsd", "amd64"); err == nil {
+		t.Error("DownloadBinary(freebsd/amd64) error = nil, want missing archive")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "gwq")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &Updater{FS: filesystem.NewStandardFileSystem()}
+	if err := u.CheckWritable(exe); err != nil {
+		t.Fatalf("CheckWritable() error = %v", err)
+	}
+	if err := u.Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+
+	got, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Errorf("executable = %q, want %q", got, "new")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the executable", len(entries))
+	}
+}
+
+// readOnlyFS rejects writes the way a root-owned install directory would.
+type readOnlyFS struct {
+	*filesystem.StandardFileSystem
+}
+
+func (readOnlyFS) WriteFile(name string, _ []byte, _ os.FileMode) error {
+	return &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+}
+
+func TestReplace_NotWritable(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "gwq")
+	u := &Updater{FS: readOnlyFS{filesystem.NewStandardFileSystem()}}
+
+	if err := u.CheckWritable(exe); !errors.Is(err, ErrNotWritable) {
+		t.Errorf("CheckWritable() error = %v, want ErrNotWritable", err)
+	}
+	if err := u.Replace(exe, []byte("new")); !errors.Is(err, ErrNotWritable) {
+		t.Errorf("Replace() error = %v, want ErrNotWritable", err)
+	}
+}
+
+// failingRenameFS fails renames from the staging file, as when antivirus
+// software holds the freshly written binary open.
+type failingRenameFS struct {
+	*filesystem.StandardFileSystem
+}
+
+func (f failingRenameFS) Rename(oldpath, newpath string) error {
+	if strings.HasSuffix(oldpath, ".update") {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrPermission}
+	}
+	return f.StandardFileSystem.Rename(oldpath, newpath)
+}
+
+func TestReplace_MoveAsideRestoresOnFailure(t *testing.T) {
+	orig := moveAsideRunning
+	moveAsideRunning = true
+	t.Cleanup(func() { moveAsideRunning = orig })
+
+	exe := filepath.Join(t.TempDir(), "gwq")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &Updater{FS: failingRenameFS{filesystem.NewStandardFileSystem()}}
+	if err := u.Replace(exe, []byte("new")); err == nil {
+		t.Fatal("Replace() error = nil, want the rename failure")
+	}
+
+	got, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatalf("executable is missing after a failed replace: %v", err)
+	}
+	if string(got) != "old" {
+		t.Errorf("executable = %q, want the previous binary restored", got)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the executable", len(entries))
+	}
+
+	// Without a failure the previous binary is kept aside as .old.
+	u.FS = filesystem.NewStandardFileSystem()
+	if err := u.Replace(exe, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "new" {
+		t.Errorf("executable = %q, want %q", got, "new")
+	}
+	if got, _ := os.ReadFile(exe + ".old"); string(got) != "old" {
+		t.Errorf("%s = %q, want the previous binary", exe+".old", got)
+	}
+}
@@ -0,0 +1,76 @@
+package url
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// urlRewrite is a git url.<base>.insteadOf rule: URLs starting with Prefix
+// are rewritten to start with Base instead. pushInsteadOf rules only affect
+// push URLs and never the repository identity, so they are not read.
+type urlRewrite struct {
+	Base   string
+	Prefix string
+}
+
+// urlRewrites returns the rewrite rules from the user's git config. They are
+// read once per process; tests replace this to supply fixed rules.
+var urlRewrites = sync.OnceValue(readURLRewrites)
+
+// readURLRewrites reads insteadOf rules with 'git config --get-regexp'.
+// Without git or without rules it returns nil.
+func readURLRewrites() []urlRewrite {
+	output, err := exec.Command("git", "config", "--get-regexp", `^url\..*\.insteadof$`).Output()
+	if err != nil {
+		return nil // exit status 1 just means no rules are configured
+	}
+	return parseURLRewrites(string(output))
+}
+
+// parseURLRewrites parses 'git config --get-regexp' output, one
+// "url.<base>.insteadof <prefix>" entry per line. The base may itself
+// contain dots, so it is cut from the known key prefix and suffix.
+func parseURLRewrites(output string) []urlRewrite {
+	var rules []urlRewrite
+	for line := range strings.SplitSeq(output, "\n") {
+		key, prefix, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || prefix == "" {
+			continue
+		}
+
+		// git lowercases the variable name but keeps the subsection as written.
+		lower := strings.ToLower(key)
+		if !strings.HasPrefix(lower, "url.") || !strings.HasSuffix(lower, ".insteadof") || strings.HasSuffix(lower, ".pushinsteadof") {
+			continue
+		}
+		rules = append(rules, urlRewrite{Base: key[len("url.") : len(key)-len(".insteadof")], Prefix: prefix})
+	}
+	return rules
+}
+
+// applyURLRewrites rewrites repoURL with the rule whose prefix is the
+// longest match, as git does. repoURL is returned unchanged when no rule
+// matches, or when rules claim the longest prefix for different bases: such
+// a conflict has no well-defined answer, so the URL is kept as written.
+func applyURLRewrites(repoURL string, rules []urlRewrite) string {
+	var best *urlRewrite
+	conflict := false
+	for i := range rules {
+		rule := &rules[i]
+		if !strings.HasPrefix(repoURL, rule.Prefix) {
+			continue
+		}
+		switch {
+		case best == nil || len(rule.Prefix) > len(best.Prefix):
+			best = rule
+			conflict = false
+		case len(rule.Prefix) == len(best.Prefix) && rule.Base != best.Base:
+			conflict = true
+		}
+	}
+	if best == nil || conflict {
+		return repoURL
+	}
+	return best.Base + strings.TrimPrefix(repoURL, best.Prefix)
+}
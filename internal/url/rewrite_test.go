@@ -0,0 +1,165 @@
+package url
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/git"
+)
+
+// TestMain isolates the package's tests from the developer's git config, so
+// personal insteadOf rules cannot change parsing results.
+func TestMain(m *testing.M) {
+	_ = os.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	_ = os.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	os.Exit(m.Run())
+}
+
+// useGitConfig points git at a global config file with the given content
+// and makes urlRewrites re-read it.
+func useGitConfig(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write git config: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", path)
+	t.Chdir(t.TempDir()) // keep repository-local config out of the lookup
+
+	orig := urlRewrites
+	urlRewrites = readURLRewrites
+	t.Cleanup(func() { urlRewrites = orig })
+}
+
+func TestParseURLRewrites(t *testing.T) {
+	output := "url.git@github.com:.insteadof gh:\n" +
+		"url.https://gitlab.example.com/.pushinsteadof work:\n" +
+		"url.ssh://git@host.v1.example/.insteadOf v1:\n" +
+		"malformed\n"
+
+	want := []urlRewrite{
+		{Base: "git@github.com:", Prefix: "gh:"},
+		{Base: "ssh://git@host.v1.example/", Prefix: "v1:"},
+	}
+	if got := parseURLRewrites(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseURLRewrites() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyURLRewrites(t *testing.T) {
+	rules := []urlRewrite{
+		{Base: "https://github.com/", Prefix: "gh:"},
+		{Base: "https://github.com/myorg/", Prefix: "gh:myorg/"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no match", input: "https://github.com/user/repo.git", want: "https://github.com/user/repo.git"},
+		{name: "short alias", input: "gh:user/repo", want: "https://github.com/user/repo"},
+		{name: "longest prefix wins", input: "gh:myorg/repo", want: "https://github.com/myorg/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyURLRewrites(tt.input, rules); got != tt.want {
+				t.Errorf("applyURLRewrites(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyURLRewrites_Conflict(t *testing.T) {
+	rules := []urlRewrite{
+		{Base: "https://github.com/", Prefix: "gh:"},
+		{Base: "git@github.com:", Prefix: "gh:"},
+		{Base: "https://github.com/myorg/", Prefix: "gh:myorg/"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "conflicting insteadOf rules keep the URL", input: "gh:user/repo", want: "gh:user/repo"},
+		{name: "longer rule is not affected", input: "gh:myorg/repo", want: "https://github.com/myorg/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyURLRewrites(tt.input, rules); got != tt.want {
+				t.Errorf("applyURLRewrites(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseRepositoryURL_RemoteWithInsteadOf resolves a repository whose
+// origin is rewritten by git itself, the way Manager.List does. The URL git
+// reports must not be rewritten a second time.
+func TestParseRepositoryURL_RemoteWithInsteadOf(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	useGitConfig(t, "[url \"git@github.com:\"]\n\tinsteadOf = gh:\n"+
+		"[url \"https://mirror.example.com/\"]\n\tinsteadOf = git@github.com:\n"+
+		"[url \"https://push.example.com/\"]\n\tpushInsteadOf = git@github.com:\n")
+
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", repo},
+		{"-C", repo, "remote", "add", "origin", "gh:user/repo.git"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	remote, err := git.New(repo).GetRepositoryURL()
+	if err != nil {
+		t.Fatalf("GetRepositoryURL() error = %v", err)
+	}
+	if remote != "git@github.com:user/repo.git" {
+		t.Errorf("GetRepositoryURL() = %q, want the rewritten URL", remote)
+	}
+
+	info, err := ParseRepositoryURL(remote)
+	if err != nil {
+		t.Fatalf("ParseRepositoryURL() error = %v", err)
+	}
+	if info.FullPath != filepath.Join("github.com", "user", "repo") {
+		t.Errorf("FullPath = %q, want github.com/user/repo", info.FullPath)
+	}
+}
+
+func TestParseConfiguredRepositoryURL_InsteadOf(t *testing.T) {
+	useGitConfig(t, "[url \"https://github.com/\"]\n\tinsteadOf = gh:\n"+
+		"[url \"https://push.example.com/\"]\n\tpushInsteadOf = https://github.com/\n")
+
+	info, err := ParseConfiguredRepositoryURL("gh:user/repo.git")
+	if err != nil {
+		t.Fatalf("ParseConfiguredRepositoryURL() error = %v", err)
+	}
+	if info.Host != "github.com" || info.Owner != "user" || info.Repository != "repo" {
+		t.Errorf("ParseConfiguredRepositoryURL() = %+v, want github.com/user/repo", info)
+	}
+
+	// URLs reported by git are already rewritten and parsed as they are.
+	if info, err := ParseRepositoryURL("gh:user/repo.git"); err == nil && info.Host == "github.com" {
+		t.Errorf("ParseRepositoryURL(gh:user/repo.git) = %+v, want the alias left unrewritten", info)
+	}
+}
+
+func TestReadURLRewrites_NoConfig(t *testing.T) {
+	useGitConfig(t, "")
+
+	if got := readURLRewrites(); got != nil {
+		t.Errorf("readURLRewrites() = %+v, want nil", got)
+	}
+}
@@ -8,18 +8,16 @@ import (
 	"strings"
 
 	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
 )
 
-// RepositoryInfo contains parsed repository information.
-type RepositoryInfo struct {
-	Host       string // e.g., "github.com"
-	Owner      string // e.g., "user1"
-	Repository string // e.g., "myapp"
-	FullPath   string // e.g., "github.com/user1/myapp"
-}
-
 // ParseRepositoryURL parses a git repository URL and extracts host, owner, and repository name.
-func ParseRepositoryURL(repoURL string) (*RepositoryInfo, error) {
+// For nested namespaces such as GitLab subgroups, Owner holds every path
+// segment before the repository joined with "/", e.g. "group/subgroup".
+// repoURL is taken as git reports it, e.g. from 'git remote get-url', which
+// has already applied insteadOf rules; use ParseConfiguredRepositoryURL for
+// URLs as the user wrote them.
+func ParseRepositoryURL(repoURL string) (*models.RepositoryInfo, error) {
 	// Handle different URL formats
 	repoURL = normalizeURL(repoURL)
 
@@ -39,7 +37,9 @@ func ParseRepositoryURL(repoURL string) (*RepositoryInfo, error) {
 		return nil, fmt.Errorf("invalid repository path: %s", parsedURL.Path)
 	}
 
-	owner := pathParts[0]
+	// The last segment is the repository; everything before it is the
+	// owner, which may be nested (GitLab subgroups, Azure DevOps projects).
+	owner := strings.Join(pathParts[:len(pathParts)-1], "/")
 	repository := pathParts[len(pathParts)-1]
 
 	// Remove .git suffix if present
@@ -47,7 +47,7 @@ func ParseRepositoryURL(repoURL string) (*RepositoryInfo, error) {
 
 	fullPath := filepath.Join(host, owner, repository)
 
-	return &RepositoryInfo{
+	return &models.RepositoryInfo{
 		Host:       host,
 		Owner:      owner,
 		Repository: repository,
@@ -56,14 +56,30 @@ func ParseRepositoryURL(repoURL string) (*RepositoryInfo, error) {
 }
 
 // GenerateWorktreePath creates a worktree path based on repository info and branch name.
-func GenerateWorktreePath(baseDir string, repoInfo *RepositoryInfo, branch string) string {
+func GenerateWorktreePath(baseDir string, repoInfo *models.RepositoryInfo, branch string) string {
 	// Sanitize branch name for filesystem
 	safeBranch := sanitizeBranchName(branch)
 	return filepath.Join(baseDir, repoInfo.FullPath, safeBranch)
 }
 
-// normalizeURL converts various git URL formats to a standard HTTP(S) format for parsing.
+// ParseConfiguredRepositoryURL parses a repository URL as the user wrote it,
+// on the command line or in git config, applying the user's git insteadOf
+// rewrites first as git does. Short aliases such as gh:user/repo thus
+// resolve to their host.
+func ParseConfiguredRepositoryURL(repoURL string) (*models.RepositoryInfo, error) {
+	return ParseRepositoryURL(applyURLRewrites(repoURL, urlRewrites()))
+}
+
+// normalizeURL converts various git URL formats to a standard HTTP(S) format
+// for parsing.
 func normalizeURL(repoURL string) string {
+	if azureURL, ok := normalizeAzureDevOpsURL(repoURL); ok {
+		return azureURL
+	}
+	if bitbucketURL, ok := normalizeBitbucketServerURL(repoURL); ok {
+		return bitbucketURL
+	}
+
 	// Convert SSH format to HTTPS format for easier parsing
 	if strings.HasPrefix(repoURL, "git@") {
 		// git@github.com:user/repo.git -> https://github.com/user/repo.git
@@ -73,9 +89,13 @@ func normalizeURL(repoURL string) string {
 		}
 	} else if strings.HasPrefix(repoURL, "ssh://git@") {
 		// ssh://git@github.com:user/repo.git -> https://github.com/user/repo.git
+		// ssh://git@host:7999/user/repo.git -> https://host/user/repo.git
 		repoURL = strings.TrimPrefix(repoURL, "ssh://")
 		if host, path, found := strings.Cut(repoURL, ":"); found {
 			host = strings.TrimPrefix(host, "git@")
+			if port, rest, ok := strings.Cut(path, "/"); ok && isPort(port) {
+				path = rest // the SSH port is not part of the repository identity
+			}
 			repoURL = fmt.Sprintf("https://%s/%s", host, path)
 		}
 	} else if isSCPLikeURL(repoURL) {
@@ -93,6 +113,80 @@ func normalizeURL(repoURL string) string {
 	return repoURL
 }
 
+// azureDevOpsHost is the host of Azure DevOps Services repositories.
+const azureDevOpsHost = "dev.azure.com"
+
+// normalizeAzureDevOpsURL converts Azure DevOps clone URLs to the canonical
+// https://dev.azure.com/<org>/<project>/<repo> form. It accepts
+//
+//	https://[user@]dev.azure.com/<org>/<project>/_git/<repo>
+//	git@ssh.dev.azure.com:v3/<org>/<project>/<repo>
+//	ssh://git@ssh.dev.azure.com/v3/<org>/<project>/<repo>
+//
+// with or without a trailing .git, and reports false for any other URL.
+func normalizeAzureDevOpsURL(repoURL string) (string, bool) {
+	var parts []string
+	if path, ok := strings.CutPrefix(repoURL, "git@ssh."+azureDevOpsHost+":v3/"); ok {
+		parts = strings.Split(path, "/")
+	} else if path, ok := strings.CutPrefix(repoURL, "ssh://git@ssh."+azureDevOpsHost+"/v3/"); ok {
+		parts = strings.Split(path, "/")
+	} else {
+		parsed, err := url.Parse(repoURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() != azureDevOpsHost {
+			return "", false
+		}
+		// <org>/<project>/_git/<repo>
+		segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		if len(segments) != 4 || segments[2] != "_git" {
+			return "", false
+		}
+		parts = []string{segments[0], segments[1], segments[3]}
+	}
+
+	if len(parts) != 3 {
+		return "", false
+	}
+	parts[2] = strings.TrimSuffix(parts[2], ".git")
+	for _, part := range parts {
+		if part == "" {
+			return "", false
+		}
+	}
+	return "https://" + azureDevOpsHost + "/" + strings.Join(parts, "/"), true
+}
+
+// normalizeBitbucketServerURL converts Bitbucket Server (Data Center) HTTP
+// clone URLs of the form /scm/<project>/<repo> to
+// https://<host>/<project>/<repo>. Credentials and the port are dropped, so
+// the result matches the SSH form of the same repository. Only a leading scm
+// segment counts, so a GitLab group or repository named "scm" elsewhere in
+// the path is left alone.
+func normalizeBitbucketServerURL(repoURL string) (string, bool) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() == "" {
+		return "", false
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) != 3 || segments[0] != "scm" || segments[1] == "" || strings.TrimSuffix(segments[2], ".git") == "" {
+		return "", false
+	}
+	return fmt.Sprintf("https://%s/%s/%s", parsed.Hostname(), segments[1], strings.TrimSuffix(segments[2], ".git")), true
+}
+
+// isPort reports whether s is a non-empty string of digits.
+func isPort(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // isSCPLikeURL checks if a URL string uses SCP-like syntax (host:path)
 // without a git@ prefix. This handles SSH config aliases like "workgit:org/repo.git".
 //
@@ -137,7 +231,7 @@ func sanitizeBranchName(branch string) string {
 }
 
 // ParseWorktreePath extracts repository info and branch from a worktree path.
-func ParseWorktreePath(worktreePath, baseDir string) (*RepositoryInfo, string, error) {
+func ParseWorktreePath(worktreePath, baseDir string) (*models.RepositoryInfo, string, error) {
 	// Remove base directory from path
 	relPath, err := filepath.Rel(baseDir, worktreePath)
 	if err != nil {
@@ -155,7 +249,7 @@ func ParseWorktreePath(worktreePath, baseDir string) (*RepositoryInfo, string, e
 	repository := parts[2]
 	branch := strings.Join(parts[3:], "/") // Branch might contain slashes (converted to -)
 
-	repoInfo := &RepositoryInfo{
+	repoInfo := &models.RepositoryInfo{
 		Host:       host,
 		Owner:      owner,
 		Repository: repository,
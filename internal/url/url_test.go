@@ -1,6 +1,7 @@
 package url
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -38,28 +39,28 @@ func TestParseRepositoryURL(t *testing.T) {
 			name:      "gitlab nested group - 3 levels",
 			input:     "https://gitlab.com/org/team/repo",
 			wantHost:  "gitlab.com",
-			wantOwner: "org",
+			wantOwner: "org/team",
 			wantRepo:  "repo",
 		},
 		{
 			name:      "gitlab nested group - 4 levels",
 			input:     "https://gitlab.com/org/team/suborg/repo",
 			wantHost:  "gitlab.com",
-			wantOwner: "org",
+			wantOwner: "org/team/suborg",
 			wantRepo:  "repo",
 		},
 		{
 			name:      "gitlab nested group with .git suffix",
 			input:     "https://gitlab.com/org/team/suborg/repo.git",
 			wantHost:  "gitlab.com",
-			wantOwner: "org",
+			wantOwner: "org/team/suborg",
 			wantRepo:  "repo",
 		},
 		{
 			name:      "gitlab nested group ssh format",
 			input:     "git@gitlab.com:org/team/suborg/repo.git",
 			wantHost:  "gitlab.com",
-			wantOwner: "org",
+			wantOwner: "org/team/suborg",
 			wantRepo:  "repo",
 		},
 		{
@@ -80,7 +81,7 @@ func TestParseRepositoryURL(t *testing.T) {
 			name:      "SSH config alias with nested path",
 			input:     "myhost:org/team/repo.git",
 			wantHost:  "myhost",
-			wantOwner: "org",
+			wantOwner: "org/team",
 			wantRepo:  "repo",
 		},
 		{
@@ -269,3 +270,156 @@ func TestNormalizeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeAzureDevOpsURL(t *testing.T) {
+	const canonical = "https://dev.azure.com/myorg/myproject/myrepo"
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{name: "https", input: "https://dev.azure.com/myorg/myproject/_git/myrepo", want: canonical, wantOK: true},
+		{name: "https with .git", input: "https://dev.azure.com/myorg/myproject/_git/myrepo.git", want: canonical, wantOK: true},
+		{name: "https with user", input: "https://myorg@dev.azure.com/myorg/myproject/_git/myrepo", want: canonical, wantOK: true},
+		{name: "ssh", input: "git@ssh.dev.azure.com:v3/myorg/myproject/myrepo", want: canonical, wantOK: true},
+		{name: "ssh with .git", input: "git@ssh.dev.azure.com:v3/myorg/myproject/myrepo.git", want: canonical, wantOK: true},
+		{name: "ssh scheme", input: "ssh://git@ssh.dev.azure.com/v3/myorg/myproject/myrepo", want: canonical, wantOK: true},
+		{name: "https without _git", input: "https://dev.azure.com/myorg/myproject/myrepo"},
+		{name: "ssh missing project", input: "git@ssh.dev.azure.com:v3/myorg/myrepo"},
+		{name: "other host", input: "https://github.com/myorg/myproject/_git/myrepo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := normalizeAzureDevOpsURL(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("normalizeAzureDevOpsURL(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryURL_AzureDevOps(t *testing.T) {
+	inputs := []string{
+		"https://dev.azure.com/myorg/myproject/_git/myrepo",
+		"https://dev.azure.com/myorg/myproject/_git/myrepo.git",
+		"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo",
+		"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo.git",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			info, err := ParseRepositoryURL(input)
+			if err != nil {
+				t.Fatalf("ParseRepositoryURL() error = %v", err)
+			}
+			if info.Host != "dev.azure.com" || info.Owner != "myorg/myproject" || info.Repository != "myrepo" {
+				t.Errorf("ParseRepositoryURL() = %+v, want dev.azure.com, myorg/myproject, myrepo", info)
+			}
+			if want := filepath.Join("dev.azure.com", "myorg", "myproject", "myrepo"); info.FullPath != want {
+				t.Errorf("FullPath = %q, want %q", info.FullPath, want)
+			}
+		})
+	}
+}
+
+func TestGenerateWorktreePath_Nesting(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "github owner/repo",
+			input: "https://github.com/user/repo.git",
+			want:  filepath.Join("/base", "github.com", "user", "repo", "feature-x"),
+		},
+		{
+			name:  "gitlab subgroup",
+			input: "https://gitlab.com/group/subgroup/project.git",
+			want:  filepath.Join("/base", "gitlab.com", "group", "subgroup", "project", "feature-x"),
+		},
+		{
+			name:  "gitlab two subgroups over ssh",
+			input: "git@gitlab.com:group/subgroup/team/project.git",
+			want:  filepath.Join("/base", "gitlab.com", "group", "subgroup", "team", "project", "feature-x"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseRepositoryURL(tt.input)
+			if err != nil {
+				t.Fatalf("ParseRepositoryURL() error = %v", err)
+			}
+			if got := GenerateWorktreePath("/base", info, "feature/x"); got != tt.want {
+				t.Errorf("GenerateWorktreePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBitbucketServerURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{name: "https scm", input: "https://bitbucket.example.com/scm/proj/repo.git", want: "https://bitbucket.example.com/proj/repo", wantOK: true},
+		{name: "https scm without .git", input: "https://bitbucket.example.com/scm/proj/repo", want: "https://bitbucket.example.com/proj/repo", wantOK: true},
+		{name: "https scm with port", input: "https://bitbucket.example.com:8443/scm/proj/repo.git", want: "https://bitbucket.example.com/proj/repo", wantOK: true},
+		{name: "http scm with credentials", input: "http://user@bitbucket.example.com:7990/scm/proj/repo.git", want: "https://bitbucket.example.com/proj/repo", wantOK: true},
+		{name: "scm not the first segment", input: "https://gitlab.example.com/group/scm/repo.git"},
+		{name: "repository named scm", input: "https://gitlab.example.com/group/sub/scm.git"},
+		{name: "no scm segment", input: "https://bitbucket.org/owner/repo.git"},
+		{name: "scm without repository", input: "https://bitbucket.example.com/scm/proj"},
+		{name: "ssh is left to normalizeURL", input: "ssh://git@bitbucket.example.com:7999/proj/repo.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := normalizeBitbucketServerURL(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("normalizeBitbucketServerURL(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseRepositoryURL_Bitbucket(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantHost  string
+		wantOwner string
+		wantRepo  string
+	}{
+		{name: "server https", input: "https://bitbucket.example.com/scm/proj/repo.git", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server https without .git", input: "https://bitbucket.example.com/scm/proj/repo", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server https with port", input: "https://bitbucket.example.com:8443/scm/proj/repo.git", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server http with credentials", input: "http://user@bitbucket.example.com:7990/scm/proj/repo.git", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server ssh with port", input: "ssh://git@bitbucket.example.com:7999/proj/repo.git", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server ssh without .git", input: "ssh://git@bitbucket.example.com:7999/proj/repo", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "server ssh without port", input: "ssh://git@bitbucket.example.com/proj/repo.git", wantHost: "bitbucket.example.com", wantOwner: "proj", wantRepo: "repo"},
+		{name: "cloud https", input: "https://bitbucket.org/owner/repo.git", wantHost: "bitbucket.org", wantOwner: "owner", wantRepo: "repo"},
+		{name: "cloud https with user", input: "https://owner@bitbucket.org/owner/repo.git", wantHost: "bitbucket.org", wantOwner: "owner", wantRepo: "repo"},
+		{name: "cloud ssh", input: "git@bitbucket.org:owner/repo.git", wantHost: "bitbucket.org", wantOwner: "owner", wantRepo: "repo"},
+		{name: "gitlab subgroup named scm", input: "https://gitlab.example.com/group/scm/repo.git", wantHost: "gitlab.example.com", wantOwner: "group/scm", wantRepo: "repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseRepositoryURL(tt.input)
+			if err != nil {
+				t.Fatalf("ParseRepositoryURL() error = %v", err)
+			}
+			if info.Host != tt.wantHost || info.Owner != tt.wantOwner || info.Repository != tt.wantRepo {
+				t.Errorf("ParseRepositoryURL(%q) = %s/%s/%s, want %s/%s/%s",
+					tt.input, info.Host, info.Owner, info.Repository, tt.wantHost, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
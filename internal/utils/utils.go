@@ -5,9 +5,14 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
@@ -170,6 +175,18 @@ func MatchPath(pattern, path string) bool {
 	return matched
 }
 
+// ValidatePattern reports whether pattern is usable with MatchPath. Patterns
+// without glob characters are always valid since they are matched exactly.
+func ValidatePattern(pattern string) error {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return nil
+	}
+	if !doublestar.ValidatePattern(pattern) {
+		return fmt.Errorf("invalid glob pattern %q", pattern)
+	}
+	return nil
+}
+
 // SanitizeForFilesystem converts strings to filesystem-safe names by replacing problematic characters.
 func SanitizeForFilesystem(input string) string {
 	// Replace problematic characters
@@ -202,3 +219,90 @@ func EscapeForShell(s string) string {
 	s = strings.ReplaceAll(s, "`", "\\`") // Escape backticks (command substitution)
 	return s
 }
+
+// calendarDurationRegex matches whole-number day, week, and month durations
+// like "7d", "2w", or "3mo".
+var calendarDurationRegex = regexp.MustCompile(`^(-?\d+)(d|w|mo)$`)
+
+// calendarUnits maps calendar suffixes to their length. A month is 30 days.
+var calendarUnits = map[string]time.Duration{
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+}
+
+// ParseDuration parses a non-negative duration. In addition to the formats
+// accepted by time.ParseDuration (e.g. "90m", "2h30m"), it accepts whole
+// numbers of days ("30d"), weeks ("2w"), and months ("3mo", 30 days each).
+// "m" keeps its standard meaning of minutes. Zero is allowed.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration string")
+	}
+
+	var d time.Duration
+	if matches := calendarDurationRegex.FindStringSubmatch(s); matches != nil {
+		n, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		unit := calendarUnits[matches[2]]
+		if limit := math.MaxInt64 / int64(unit); int64(n) > limit || int64(n) < -limit {
+			return 0, fmt.Errorf("invalid duration %q: too large", s)
+		}
+		d = time.Duration(n) * unit
+	} else {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: use a Go duration (e.g. 12h) or a number followed by d, w, or mo", s)
+		}
+		d = parsed
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("duration must not be negative: %s", s)
+	}
+	return d, nil
+}
+
+// signalNames maps signal names without the SIG prefix to signals available
+// on every supported platform.
+var signalNames = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"ABRT": syscall.SIGABRT,
+	"KILL": syscall.SIGKILL,
+	"PIPE": syscall.SIGPIPE,
+	"ALRM": syscall.SIGALRM,
+	"TERM": syscall.SIGTERM,
+}
+
+// ParseSignal parses a signal given by number ("15") or by name with or
+// without the SIG prefix ("SIGTERM", "term"). Other signals can be given by
+// number.
+func ParseSignal(name string) (syscall.Signal, error) {
+	s := strings.ToUpper(strings.TrimSpace(name))
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 || n > 64 {
+			return 0, fmt.Errorf("invalid signal number %d", n)
+		}
+		return syscall.Signal(n), nil
+	}
+	if sig, ok := signalNames[strings.TrimPrefix(s, "SIG")]; ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal %q", name)
+}
+
+// SignalName returns the SIG-prefixed name of sig, or its number for signals
+// ParseSignal only accepts numerically.
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return "SIG" + name
+		}
+	}
+	return fmt.Sprintf("signal %d", int(sig))
+}
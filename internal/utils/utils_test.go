@@ -3,7 +3,9 @@ package utils
 import (
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestTildePath(t *testing.T) {
@@ -265,6 +267,28 @@ func TestMatchPath(t *testing.T) {
 	}
 }
 
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "/home/user/src/repo"},
+		{pattern: "**/github.com/owner/*"},
+		{pattern: "~/src/[ab]*"},
+		{pattern: "**/owner/[abc", wantErr: true},
+		{pattern: "**/{a,b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := ValidatePattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestEscapeForShell(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -322,3 +346,86 @@ func TestEscapeForShell(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "days", input: "30d", want: 30 * 24 * time.Hour},
+		{name: "weeks", input: "2w", want: 14 * 24 * time.Hour},
+		{name: "months", input: "3mo", want: 90 * 24 * time.Hour},
+		{name: "minutes keep Go meaning", input: "30m", want: 30 * time.Minute},
+		{name: "compound Go duration", input: "2h30m", want: 2*time.Hour + 30*time.Minute},
+		{name: "zero days", input: "0d", want: 0},
+		{name: "zero seconds", input: "0s", want: 0},
+		{name: "surrounding whitespace", input: " 7d ", want: 7 * 24 * time.Hour},
+		{name: "empty", input: "", wantErr: true},
+		{name: "negative days", input: "-1d", wantErr: true},
+		{name: "negative Go duration", input: "-5m", wantErr: true},
+		{name: "unknown suffix", input: "5y", wantErr: true},
+		{name: "fractional days", input: "1.5d", wantErr: true},
+		{name: "missing number", input: "d", wantErr: true},
+		{name: "bare number", input: "10", wantErr: true},
+		{name: "largest months", input: "3558mo", want: 3558 * 30 * 24 * time.Hour},
+		{name: "months overflow", input: "99999999mo", wantErr: true},
+		{name: "negative overflow wraps positive", input: "-99999999999999mo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    syscall.Signal
+		wantErr bool
+	}{
+		{input: "15", want: syscall.SIGTERM},
+		{input: "9", want: syscall.SIGKILL},
+		{input: "SIGTERM", want: syscall.SIGTERM},
+		{input: "TERM", want: syscall.SIGTERM},
+		{input: "term", want: syscall.SIGTERM},
+		{input: "sigint", want: syscall.SIGINT},
+		{input: " HUP ", want: syscall.SIGHUP},
+		{input: "KILL", want: syscall.SIGKILL},
+		{input: "0", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "65", wantErr: true},
+		{input: "SIGFOO", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSignal(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSignal(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSignal(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignalName(t *testing.T) {
+	if got := SignalName(syscall.SIGTERM); got != "SIGTERM" {
+		t.Errorf("SignalName(SIGTERM) = %q", got)
+	}
+	if got := SignalName(syscall.Signal(42)); got != "signal 42" {
+		t.Errorf("SignalName(42) = %q", got)
+	}
+}
@@ -0,0 +1,324 @@
+package worktree
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// ArchiveExt is the file extension of worktree archives.
+const ArchiveExt = ".tar.gz"
+
+// archiveDateLayout is the date format in archive file names.
+const archiveDateLayout = "2006-01-02"
+
+// archiveNamePattern matches "<escaped branch>-<date>.tar.gz".
+var archiveNamePattern = regexp.MustCompile(`^(.+)-\d{4}-\d{2}-\d{2}` + regexp.QuoteMeta(ArchiveExt) + `$`)
+
+// ArchiveFileName returns the archive file name for branch archived at t. The
+// branch is path-escaped so that slashes survive in a single file name and
+// ArchiveBranch can recover it.
+func ArchiveFileName(branch string, t time.Time) string {
+	return url.PathEscape(branch) + "-" + t.Format(archiveDateLayout) + ArchiveExt
+}
+
+// ArchiveBranch infers the branch name from an archive file name created by
+// ArchiveFileName.
+func ArchiveBranch(archivePath string) (string, error) {
+	m := archiveNamePattern.FindStringSubmatch(filepath.Base(archivePath))
+	if m == nil {
+		return "", fmt.Errorf("cannot infer branch from archive name %q: expected <branch>-YYYY-MM-DD%s", filepath.Base(archivePath), ArchiveExt)
+	}
+	branch, err := url.PathUnescape(m[1])
+	if err != nil {
+		return "", fmt.Errorf("cannot infer branch from archive name %q: %w", filepath.Base(archivePath), err)
+	}
+	return branch, nil
+}
+
+// Archive writes the worktree directory to <outputDir>/<branch>-<date>.tar.gz
+// and then removes the worktree with force, since everything in it has been
+// saved. It returns the archive path. An existing archive is never
+// overwritten.
+func (m *Manager) Archive(wt models.Worktree, outputDir string, now time.Time) (string, error) {
+	if wt.IsMain {
+		return "", fmt.Errorf("cannot archive the main worktree")
+	}
+	if wt.Branch == "" || wt.Branch == "HEAD" {
+		return "", fmt.Errorf("worktree %s has no branch checked out", wt.Path)
+	}
+
+	dir, err := utils.ExpandPath(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path: %w", err)
+	}
+	// The worktree is deleted afterwards, and the archive with it.
+	if inside, err := isWithinDir(dir, wt.Path); err != nil {
+		return "", err
+	} else if inside {
+		return "", fmt.Errorf("archive directory %s is inside the worktree %s, which archiving removes", dir, wt.Path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	archivePath := filepath.Join(dir, ArchiveFileName(wt.Branch, now))
+	if err := CreateArchive(wt.Path, archivePath); err != nil {
+		return "", err
+	}
+
+	if err := m.Remove(wt.Path, true); err != nil {
+		return archivePath, fmt.Errorf("archived to %s but failed to remove worktree: %w", archivePath, err)
+	}
+	// Anything git left behind has been archived.
+	if err := os.RemoveAll(wt.Path); err != nil {
+		return archivePath, fmt.Errorf("archived to %s but failed to remove %s: %w", archivePath, wt.Path, err)
+	}
+	return archivePath, nil
+}
+
+// isWithinDir reports whether path is dir or below it, after making both
+// absolute and resolving symlinks. path need not exist yet: its deepest
+// existing ancestor is resolved instead.
+func isWithinDir(path, dir string) (bool, error) {
+	resolvedDir, err := resolveExistingPath(dir)
+	if err != nil {
+		return false, err
+	}
+	resolvedPath, err := resolveExistingPath(path)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(resolvedDir, resolvedPath)
+	if err != nil {
+		return false, nil
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))), nil
+}
+
+// resolveExistingPath returns the absolute form of path with symlinks
+// resolved in its deepest existing ancestor.
+func resolveExistingPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	var missing []string
+	for p := abs; ; p = filepath.Dir(p) {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if filepath.Dir(p) == p {
+			return abs, nil
+		}
+		missing = append([]string{filepath.Base(p)}, missing...)
+	}
+}
+
+// Restore creates a worktree for the branch inferred from the archive name,
+// at customPath or the generated path, and extracts the archive into it so
+// uncommitted and untracked files come back. It returns the worktree path and
+// branch.
+func (m *Manager) Restore(archivePath, customPath string) (string, string, error) {
+	branch, err := ArchiveBranch(archivePath)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", "", fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	path, err := m.Add(branch, customPath, false)
+	if err != nil {
+		return "", "", err
+	}
+	if err := ExtractArchive(archivePath, path); err != nil {
+		return path, branch, fmt.Errorf("created worktree at %s but failed to extract archive: %w", path, err)
+	}
+	return path, branch, nil
+}
+
+// CreateArchive writes the contents of dir as a gzip-compressed tar file to
+// dst, which must not exist. The worktree's .git file is skipped: it points
+// at administrative files that removal deletes. A failed archive is removed.
+func CreateArchive(dir, dst string) (err error) {
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("archive already exists: %s", dst)
+		}
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to write archive: %w", cerr)
+		}
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return writeTarEntry(tw, path, filepath.ToSlash(rel), d)
+	}); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", dir, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// writeTarEntry adds one file, directory or symlink to tw.
+func writeTarEntry(tw *tar.Writer, path, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+// ExtractArchive extracts a gzip-compressed tar file created by CreateArchive
+// into dir, overwriting existing files. Extraction goes through os.Root, so
+// entries cannot escape dir, not even through symlinks in the archive.
+func ExtractArchive(src, dir string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+	defer func() { _ = root.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if err := extractTarEntry(root, tr, hdr); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+// extractTarEntry writes one archive entry below root.
+func extractTarEntry(root *os.Root, tr *tar.Reader, hdr *tar.Header) error {
+	name := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("entry escapes the worktree")
+	}
+	if name == ".git" {
+		return nil
+	}
+	mode := os.FileMode(hdr.Mode).Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return root.MkdirAll(name, mode|0700)
+	case tar.TypeSymlink:
+		if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		if err := root.RemoveAll(name); err != nil {
+			return err
+		}
+		return root.Symlink(hdr.Linkname, name)
+	case tar.TypeReg:
+		if err := root.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		// Replace rather than truncate so a symlink at name is not followed.
+		if err := root.RemoveAll(name); err != nil {
+			return err
+		}
+		out, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,267 @@
+package worktree
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestArchiveFileName_RoundTrip(t *testing.T) {
+	date := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{branch: "main", want: "main-2026-10-15.tar.gz"},
+		{branch: "feature/login", want: "feature%2Flogin-2026-10-15.tar.gz"},
+		{branch: "release-2024-01-01", want: "release-2024-01-01-2026-10-15.tar.gz"},
+		{branch: "100%done", want: "100%25done-2026-10-15.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			name := ArchiveFileName(tt.branch, date)
+			if name != tt.want {
+				t.Errorf("ArchiveFileName(%q) = %q, want %q", tt.branch, name, tt.want)
+			}
+			got, err := ArchiveBranch(filepath.Join("/archives", name))
+			if err != nil {
+				t.Fatalf("ArchiveBranch(%q) error = %v", name, err)
+			}
+			if got != tt.branch {
+				t.Errorf("ArchiveBranch(%q) = %q, want %q", name, got, tt.branch)
+			}
+		})
+	}
+}
+
+func TestArchiveBranch_Invalid(t *testing.T) {
+	for _, name := range []string{"main.tar.gz", "main-2026-10-15.zip", "-2026-10-15.tar.gz", "bad%zz-2026-10-15.tar.gz"} {
+		if _, err := ArchiveBranch(name); err == nil {
+			t.Errorf("ArchiveBranch(%q) expected an error", name)
+		}
+	}
+}
+
+func TestCreateAndExtractArchive(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "README.md"), "hello", 0644)
+	writeFile(t, filepath.Join(src, "bin", "run.sh"), "#!/bin/sh\n", 0755)
+	writeFile(t, filepath.Join(src, ".git"), "gitdir: /repo/.git/worktrees/x\n", 0644)
+	if err := os.Mkdir(filepath.Join(src, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("README.md", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "x-2026-10-15.tar.gz")
+	if err := CreateArchive(src, archive); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	if err := CreateArchive(src, archive); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("CreateArchive() over an existing archive error = %v, want 'already exists'", err)
+	}
+
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dst, ".git"), "gitdir: /repo/.git/worktrees/y\n", 0644)
+	writeFile(t, filepath.Join(dst, "README.md"), "checked out", 0644)
+	if err := ExtractArchive(archive, dst); err != nil {
+		t.Fatalf("ExtractArchive() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(dst, "README.md")); got != "hello" {
+		t.Errorf("README.md = %q, want %q", got, "hello")
+	}
+	if got := readFile(t, filepath.Join(dst, ".git")); !strings.Contains(got, "worktrees/y") {
+		t.Errorf(".git was overwritten from the archive: %q", got)
+	}
+	info, err := os.Stat(filepath.Join(dst, "bin", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("run.sh lost its executable bit: %v", info.Mode())
+	}
+	if info, err := os.Stat(filepath.Join(dst, "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty directory was not restored: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "README.md" {
+		t.Errorf("link = %q, %v; want README.md", target, err)
+	}
+}
+
+func TestExtractArchive_RejectsEscapingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tar.Header
+	}{
+		{
+			name:    "ParentTraversal",
+			entries: []tar.Header{{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644}},
+		},
+		{
+			name:    "AbsolutePath",
+			entries: []tar.Header{{Name: "/tmp/evil", Typeflag: tar.TypeReg, Mode: 0644}},
+		},
+		{
+			name: "ThroughSymlink",
+			entries: []tar.Header{
+				{Name: "out", Typeflag: tar.TypeSymlink, Linkname: "..", Mode: 0777},
+				{Name: "out/evil", Typeflag: tar.TypeReg, Mode: 0644},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dst := filepath.Join(parent, "wt")
+			if err := os.Mkdir(dst, 0755); err != nil {
+				t.Fatal(err)
+			}
+			archive := filepath.Join(t.TempDir(), "evil-2026-10-15.tar.gz")
+			writeTarGz(t, archive, tt.entries)
+
+			if err := ExtractArchive(archive, dst); err == nil {
+				t.Error("ExtractArchive() expected an error")
+			}
+			if _, err := os.Stat(filepath.Join(parent, "evil")); !os.IsNotExist(err) {
+				t.Errorf("file was written outside the worktree: %v", err)
+			}
+		})
+	}
+}
+
+func TestManagerArchive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	wtPath := filepath.Join(t.TempDir(), "feature-login")
+	writeFile(t, filepath.Join(wtPath, "notes.txt"), "wip", 0644)
+	outputDir := filepath.Join(t.TempDir(), "archives")
+
+	wt := models.Worktree{Path: wtPath, Branch: "feature/login"}
+	git := &mockGit{worktrees: []models.Worktree{wt}}
+	m := New(git, &models.Config{})
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.Local)
+
+	archive, err := m.Archive(wt, outputDir, now)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if want := filepath.Join(outputDir, "feature%2Flogin-2026-10-15.tar.gz"); archive != want {
+		t.Errorf("Archive() = %q, want %q", archive, want)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Errorf("archive was not written: %v", err)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree directory still exists: %v", err)
+	}
+	if len(git.worktrees) != 0 {
+		t.Errorf("worktree was not removed from git: %+v", git.worktrees)
+	}
+
+	restoreTo := t.TempDir()
+	git.repoPath = t.TempDir()
+	path, branch, err := m.Restore(archive, restoreTo)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if path != restoreTo || branch != "feature/login" {
+		t.Errorf("Restore() = %q, %q; want %q, feature/login", path, branch, restoreTo)
+	}
+	if len(git.worktrees) != 1 || git.worktrees[0].Branch != "feature/login" {
+		t.Errorf("Restore() did not add a worktree for the branch: %+v", git.worktrees)
+	}
+	if got := readFile(t, filepath.Join(restoreTo, "notes.txt")); got != "wip" {
+		t.Errorf("notes.txt = %q, want %q", got, "wip")
+	}
+}
+
+func TestManagerArchive_Refuses(t *testing.T) {
+	m := New(&mockGit{}, &models.Config{})
+	out := t.TempDir()
+
+	if _, err := m.Archive(models.Worktree{Path: t.TempDir(), Branch: "main", IsMain: true}, out, time.Now()); err == nil {
+		t.Error("Archive() of the main worktree expected an error")
+	}
+	if _, err := m.Archive(models.Worktree{Path: t.TempDir(), Branch: "HEAD"}, out, time.Now()); err == nil {
+		t.Error("Archive() of a detached worktree expected an error")
+	}
+}
+
+func TestManagerArchive_RefusesOutputInsideWorktree(t *testing.T) {
+	wtPath := filepath.Join(t.TempDir(), "feature-login")
+	writeFile(t, filepath.Join(wtPath, "notes.txt"), "wip", 0644)
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(wtPath, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, outputDir := range []string{wtPath, filepath.Join(wtPath, "archives", "new"), link} {
+		wt := models.Worktree{Path: wtPath, Branch: "feature/login"}
+		git := &mockGit{worktrees: []models.Worktree{wt}}
+		m := New(git, &models.Config{})
+
+		if _, err := m.Archive(wt, outputDir, time.Now()); err == nil {
+			t.Errorf("Archive(%s) expected an error for an output directory inside the worktree", outputDir)
+		}
+		if len(git.worktrees) != 1 {
+			t.Errorf("Archive(%s) removed the worktree", outputDir)
+		}
+		if got := readFile(t, filepath.Join(wtPath, "notes.txt")); got != "wip" {
+			t.Errorf("notes.txt = %q after Archive(%s)", got, outputDir)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "archives")); !os.IsNotExist(err) {
+			t.Errorf("Archive(%s) created directories in the worktree", outputDir)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeTarGz(t *testing.T, path string, entries []tar.Header) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range entries {
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,115 @@
+package worktree
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// BranchCandidates returns the branches a worktree can be created from: local
+// branches, then remote-tracking branches that have no local branch of the
+// same name. The current branch comes first and each group is ordered by most
+// recent commit.
+func (m *Manager) BranchCandidates() ([]models.Branch, error) {
+	branches, err := m.git.ListBranches(true)
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[string]bool)
+	for _, b := range branches {
+		if !b.IsRemote {
+			local[b.Name] = true
+		}
+	}
+
+	candidates := slices.DeleteFunc(slices.Clone(branches), func(b models.Branch) bool {
+		return b.IsRemote && local[LocalBranchName(b)]
+	})
+	slices.SortStableFunc(candidates, func(a, b models.Branch) int {
+		if a.IsCurrent != b.IsCurrent {
+			if a.IsCurrent {
+				return -1
+			}
+			return 1
+		}
+		if a.IsRemote != b.IsRemote {
+			if b.IsRemote {
+				return -1
+			}
+			return 1
+		}
+		return b.LastCommit.Date.Compare(a.LastCommit.Date)
+	})
+	return candidates, nil
+}
+
+// LocalBranchName returns the local name for b: remote-tracking branches lose
+// their "<remote>/" prefix, so that checking the name out creates a local
+// branch tracking the remote one.
+func LocalBranchName(b models.Branch) string {
+	if !b.IsRemote {
+		return b.Name
+	}
+	if _, name, ok := strings.Cut(b.Name, "/"); ok {
+		return name
+	}
+	return b.Name
+}
+
+// BranchExists reports whether a worktree can check out name without creating
+// a branch: it is a local branch, or a remote-tracking branch git will create
+// a tracking branch for.
+func (m *Manager) BranchExists(name string) (bool, error) {
+	candidates, err := m.BranchCandidates()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(candidates, func(b models.Branch) bool {
+		return LocalBranchName(b) == name
+	}), nil
+}
+
+// BranchSource says where the branch of a new worktree comes from.
+type BranchSource struct {
+	Existing bool   // A local branch, or another ref such as a tag, is checked out as given
+	Remote   string // Otherwise the remote-tracking branch to track, e.g. origin/feature
+}
+
+// ResolveBranchSource finds where a worktree for name would get its branch:
+// an existing local branch, else a remote-tracking branch of the same name
+// (origin's if several remotes have one), else any other ref of that name.
+// If there is none of these, a new branch is created.
+func (m *Manager) ResolveBranchSource(name string) (BranchSource, error) {
+	branches, err := m.git.ListBranches(true)
+	if err != nil {
+		return BranchSource{}, err
+	}
+
+	var source BranchSource
+	for _, b := range branches {
+		switch {
+		case !b.IsRemote && b.Name == name:
+			return BranchSource{Existing: true}, nil
+		case b.IsRemote && LocalBranchName(b) == name:
+			if source.Remote == "" || strings.HasPrefix(b.Name, "origin/") {
+				source.Remote = b.Name
+			}
+		}
+	}
+	if source.Remote == "" && m.git.IsCommitish(name) {
+		source.Existing = true
+	}
+	return source, nil
+}
+
+// AddOptions returns the options that create a worktree from s: checking
+// out the existing ref, or creating the branch from the remote-tracking
+// one (git sets it as upstream) or from HEAD.
+func (s BranchSource) AddOptions() AddOptions {
+	if s.Existing {
+		return AddOptions{}
+	}
+	return AddOptions{CreateBranch: true, Base: s.Remote}
+}
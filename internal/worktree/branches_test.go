@@ -0,0 +1,142 @@
+package worktree
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestManagerBranchCandidates(t *testing.T) {
+	day := func(n int) models.CommitInfo {
+		return models.CommitInfo{Date: time.Date(2026, 10, n, 0, 0, 0, 0, time.UTC)}
+	}
+	git := &mockGit{branches: []models.Branch{
+		{Name: "feature/old", LastCommit: day(1)},
+		{Name: "main", IsCurrent: true, LastCommit: day(2)},
+		{Name: "feature/new", LastCommit: day(5)},
+		{Name: "origin/main", IsRemote: true, LastCommit: day(2)},
+		{Name: "origin/feature/old", IsRemote: true, LastCommit: day(1)},
+		{Name: "origin/feature/remote-a", IsRemote: true, LastCommit: day(3)},
+		{Name: "upstream/feature/remote-b", IsRemote: true, LastCommit: day(4)},
+	}}
+	m := New(git, &models.Config{})
+
+	got, err := m.BranchCandidates()
+	if err != nil {
+		t.Fatalf("BranchCandidates() error = %v", err)
+	}
+
+	want := []struct {
+		name      string
+		isCurrent bool
+		isRemote  bool
+	}{
+		{name: "main", isCurrent: true},
+		{name: "feature/new"},
+		{name: "feature/old"},
+		{name: "upstream/feature/remote-b", isRemote: true},
+		{name: "origin/feature/remote-a", isRemote: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("BranchCandidates() = %+v, want %d branches", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].IsCurrent != w.isCurrent || got[i].IsRemote != w.isRemote {
+			t.Errorf("BranchCandidates()[%d] = %+v, want %+v", i, got[i], w)
+		}
+		if got[i].LastCommit.Date.IsZero() {
+			t.Errorf("BranchCandidates()[%d] lost its last commit", i)
+		}
+	}
+}
+
+func TestLocalBranchName(t *testing.T) {
+	tests := []struct {
+		branch models.Branch
+		want   string
+	}{
+		{branch: models.Branch{Name: "feature/x"}, want: "feature/x"},
+		{branch: models.Branch{Name: "origin/feature/x", IsRemote: true}, want: "feature/x"},
+		{branch: models.Branch{Name: "upstream/main", IsRemote: true}, want: "main"},
+	}
+	for _, tt := range tests {
+		if got := LocalBranchName(tt.branch); got != tt.want {
+			t.Errorf("LocalBranchName(%q) = %q, want %q", tt.branch.Name, got, tt.want)
+		}
+	}
+}
+
+func TestManagerBranchExists(t *testing.T) {
+	m := New(&mockGit{branches: []models.Branch{
+		{Name: "main"},
+		{Name: "origin/feature/remote", IsRemote: true},
+	}}, &models.Config{})
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "main", want: true},
+		{name: "feature/remote", want: true},
+		{name: "origin/feature/remote", want: false},
+		{name: "feature/new", want: false},
+	}
+	for _, tt := range tests {
+		got, err := m.BranchExists(tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("BranchExists(%q) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestManagerResolveBranchSource(t *testing.T) {
+	branches := []models.Branch{
+		{Name: "main"},
+		{Name: "upstream/feature/shared", IsRemote: true},
+		{Name: "origin/feature/shared", IsRemote: true},
+		{Name: "fork/feature/fork-only", IsRemote: true},
+		{Name: "origin/main", IsRemote: true},
+	}
+
+	tests := []struct {
+		name       string
+		branch     string
+		want       BranchSource
+		wantCall   string
+		wantBranch string
+	}{
+		{name: "local branch", branch: "main", want: BranchSource{Existing: true}},
+		{name: "remote exists", branch: "feature/shared", want: BranchSource{Remote: "origin/feature/shared"}, wantCall: "add feature/shared from origin/feature/shared"},
+		{name: "remote on another remote", branch: "feature/fork-only", want: BranchSource{Remote: "fork/feature/fork-only"}, wantCall: "add feature/fork-only from fork/feature/fork-only"},
+		{name: "remote absent", branch: "feature/new", want: BranchSource{}, wantCall: "add -b feature/new"},
+		{name: "tag", branch: "v1.0.0", want: BranchSource{Existing: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			git := &mockGit{branches: branches, commitish: map[string]bool{"main": true, "v1.0.0": true}}
+			m := New(git, &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir()}})
+
+			source, err := m.ResolveBranchSource(tt.branch)
+			if err != nil {
+				t.Fatalf("ResolveBranchSource() error = %v", err)
+			}
+			if source != tt.want {
+				t.Fatalf("ResolveBranchSource(%q) = %+v, want %+v", tt.branch, source, tt.want)
+			}
+
+			if _, err := m.AddWithOptions(tt.branch, "", source.AddOptions()); err != nil {
+				t.Fatalf("AddWithOptions() error = %v", err)
+			}
+			var want []string
+			if tt.wantCall != "" {
+				want = []string{tt.wantCall}
+			}
+			if !slices.Equal(git.calls, want) {
+				t.Errorf("git calls = %q, want %q", git.calls, want)
+			}
+		})
+	}
+}
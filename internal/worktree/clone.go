@@ -0,0 +1,80 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/internal/url"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// Clone clones repoURL into the standard worktree layout under the
+// configured base directory and checks out branch, or the remote's default
+// branch when branch is empty. The clone becomes the repository's main
+// worktree. git's progress output is written to progress. It returns the
+// path of the new checkout.
+func Clone(ctx context.Context, executor command.CommandExecutor, config *models.Config, repoURL, branch string, progress io.Writer) (string, error) {
+	repoInfo, err := url.ParseConfiguredRepositoryURL(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse repository URL: %w", err)
+	}
+
+	if branch == "" {
+		branch, err = remoteDefaultBranch(ctx, executor, repoURL)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	baseDir, err := utils.ExpandPath(config.Worktree.BaseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand base directory path: %w", err)
+	}
+	if baseDir == "" {
+		return "", fmt.Errorf("base directory not configured")
+	}
+
+	path := namedWorktreePath(config.Naming, baseDir, repoInfo, branch)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("path already exists: %s", path)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to check path: %w", err)
+	}
+
+	// "--" keeps a URL starting with "-" from being parsed as an option.
+	if err := executor.ExecuteWithStreams(ctx, nil, progress, progress, "git", "clone", "--no-checkout", "--", repoURL, path); err != nil {
+		return "", fmt.Errorf("failed to clone %s: %w", repoURL, err)
+	}
+
+	if _, err := executor.ExecuteInDirWithOutput(ctx, path, "git", "checkout", branch); err != nil {
+		return path, fmt.Errorf("cloned to %s but failed to check out %s: %w", path, branch, err)
+	}
+
+	return path, nil
+}
+
+// remoteDefaultBranch asks the remote which branch its HEAD points to.
+func remoteDefaultBranch(ctx context.Context, executor command.CommandExecutor, repoURL string) (string, error) {
+	output, err := executor.ExecuteWithOutput(ctx, "git", "ls-remote", "--symref", "--", repoURL, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to query default branch: %w", err)
+	}
+
+	// The symref line looks like "ref: refs/heads/main\tHEAD".
+	for line := range strings.SplitSeq(output, "\n") {
+		ref, ok := strings.CutPrefix(line, "ref: ")
+		if !ok {
+			continue
+		}
+		ref, _, _ = strings.Cut(ref, "\t")
+		if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok && branch != "" {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the default branch of %s; pass a branch explicitly", repoURL)
+}
@@ -0,0 +1,129 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// cloneExecutor is a fake command.CommandExecutor that records git
+// invocations and answers ls-remote with a fixed symref.
+type cloneExecutor struct {
+	command.CommandExecutor // unused methods panic
+
+	lsRemoteOutput string
+	cloneErr       error
+	checkoutErr    error
+	calls          []string // "dir: name args..."
+}
+
+func (e *cloneExecutor) record(dir, name string, args []string) {
+	e.calls = append(e.calls, strings.TrimSpace(dir+": "+name+" "+strings.Join(args, " ")))
+}
+
+func (e *cloneExecutor) ExecuteWithOutput(_ context.Context, name string, args ...string) (string, error) {
+	e.record("", name, args)
+	return e.lsRemoteOutput, nil
+}
+
+func (e *cloneExecutor) ExecuteWithStreams(_ context.Context, _ io.Reader, _, _ io.Writer, name string, args ...string) error {
+	e.record("", name, args)
+	return e.cloneErr
+}
+
+func (e *cloneExecutor) ExecuteInDirWithOutput(_ context.Context, dir, name string, args ...string) (string, error) {
+	e.record(dir, name, args)
+	return "", e.checkoutErr
+}
+
+func TestClone(t *testing.T) {
+	const repoURL = "https://github.com/user/repo.git"
+	baseDir := t.TempDir()
+	existing := filepath.Join(baseDir, "github.com", "user", "repo", "taken")
+	if err := os.MkdirAll(existing, 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		branch    string
+		executor  *cloneExecutor
+		wantPath  string
+		wantCalls []string
+		wantErr   string
+	}{
+		{
+			name:     "default branch from remote HEAD",
+			executor: &cloneExecutor{lsRemoteOutput: "ref: refs/heads/develop\tHEAD\nabc123\tHEAD\n"},
+			wantPath: filepath.Join(baseDir, "github.com", "user", "repo", "develop"),
+			wantCalls: []string{
+				": git ls-remote --symref -- " + repoURL + " HEAD",
+				": git clone --no-checkout -- " + repoURL + " " + filepath.Join(baseDir, "github.com", "user", "repo", "develop"),
+				filepath.Join(baseDir, "github.com", "user", "repo", "develop") + ": git checkout develop",
+			},
+		},
+		{
+			name:     "explicit branch skips the remote query",
+			branch:   "feature/auth",
+			executor: &cloneExecutor{},
+			wantPath: filepath.Join(baseDir, "github.com", "user", "repo", "feature-auth"),
+			wantCalls: []string{
+				": git clone --no-checkout -- " + repoURL + " " + filepath.Join(baseDir, "github.com", "user", "repo", "feature-auth"),
+				filepath.Join(baseDir, "github.com", "user", "repo", "feature-auth") + ": git checkout feature/auth",
+			},
+		},
+		{
+			name:     "remote without symref",
+			executor: &cloneExecutor{lsRemoteOutput: "abc123\tHEAD\n"},
+			wantErr:  "could not determine the default branch",
+		},
+		{
+			name:     "existing destination",
+			branch:   "taken",
+			executor: &cloneExecutor{},
+			wantErr:  "path already exists",
+		},
+		{
+			name:     "clone failure",
+			branch:   "main",
+			executor: &cloneExecutor{cloneErr: errors.New("repository not found")},
+			wantErr:  "failed to clone",
+		},
+		{
+			name:     "checkout failure keeps the clone path",
+			branch:   "missing",
+			executor: &cloneExecutor{checkoutErr: errors.New("pathspec 'missing' did not match")},
+			wantPath: filepath.Join(baseDir, "github.com", "user", "repo", "missing"),
+			wantErr:  "failed to check out missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{Worktree: models.WorktreeConfig{BaseDir: baseDir}}
+			path, err := Clone(context.Background(), tt.executor, config, repoURL, tt.branch, io.Discard)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Clone() error = %v, want containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Clone() error = %v", err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("Clone() path = %q, want %q", path, tt.wantPath)
+			}
+			if tt.wantCalls != nil && !slices.Equal(tt.executor.calls, tt.wantCalls) {
+				t.Errorf("git calls = %q, want %q", tt.executor.calls, tt.wantCalls)
+			}
+		})
+	}
+}
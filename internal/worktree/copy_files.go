@@ -5,49 +5,155 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/internal/utils"
 )
 
 // CopyFilesWithGlob copies files from srcRoot to dstRoot, supporting glob patterns and preserving directory structure.
 // Errors are returned for each failed copy, but copying continues for all files.
+//
+// Patterns are resolved by ResolveCopySources; each matched file is written to
+// the same relative path under dstRoot, creating parent directories as needed.
 func CopyFilesWithGlob(fs filesystem.FileSystemInterface, srcRoot, dstRoot string, patterns []string) []error {
-	var errs []error
-	for _, pattern := range patterns {
-		patternErrs := copyFilesForPattern(fs, srcRoot, dstRoot, pattern)
-		errs = append(errs, patternErrs...)
+	relPaths, errs := ResolveCopySources(fs, srcRoot, patterns)
+	for _, relPath := range relPaths {
+		if err := copySingleFile(fs, srcRoot, dstRoot, filepath.Join(srcRoot, filepath.FromSlash(relPath))); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	return errs
 }
 
-// copyFilesForPattern processes a single glob pattern and copies matching files.
-func copyFilesForPattern(fs filesystem.FileSystemInterface, srcRoot, dstRoot, pattern string) []error {
+// CopyBetweenWorktrees copies the files matching patterns (see
+// ResolveCopySources) from the worktree at srcRoot to the same relative
+// paths in the worktree at dstRoot, overwriting existing files. It returns
+// the copied paths, relative to the roots, and an error for each file that
+// could not be resolved or copied; copying continues past failures.
+func CopyBetweenWorktrees(fs filesystem.FileSystemInterface, srcRoot, dstRoot string, patterns []string, opts filesystem.CopyOptions) ([]string, []error) {
+	relPaths, errs := ResolveCopySources(fs, srcRoot, patterns)
+	var copied []string
+	for _, relPath := range relPaths {
+		src := filepath.Join(srcRoot, filepath.FromSlash(relPath))
+		dst := filepath.Join(dstRoot, filepath.FromSlash(relPath))
+		if err := fs.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			errs = append(errs, fmt.Errorf("create directory for %q: %w", dst, err))
+			continue
+		}
+		if err := fs.CopyWithOptions(src, dst, opts); err != nil {
+			errs = append(errs, fmt.Errorf("copy %q: %w", relPath, err))
+			continue
+		}
+		copied = append(copied, relPath)
+	}
+	return copied, errs
+}
+
+// ResolveCopySources expands copy_files patterns against srcRoot and returns
+// the matched regular files as slash-separated paths relative to srcRoot.
+//
+// Patterns use doublestar syntax and are always relative to srcRoot: "*"
+// matches within a single directory, "**" matches any number of directories,
+// and a pattern without metacharacters names a single file. A leading "./" is
+// ignored. Absolute patterns and patterns that escape srcRoot with ".." are
+// rejected. Matched directories are skipped; use "dir/**" to copy a tree.
+// The result is sorted and free of duplicates, so overlapping patterns copy
+// each file once and in a stable order.
+//
+// Patterns prefixed with "!" exclude matching files from the result,
+// wherever they appear in the list: ["!config/secrets.json", "config/*"]
+// copies config/* except secrets.json. Excludes match file paths, so use
+// "!dir/**" to skip a whole tree. A list of only excludes copies nothing.
+func ResolveCopySources(fs filesystem.FileSystemInterface, srcRoot string, patterns []string) ([]string, []error) {
 	var errs []error
+	seen := make(map[string]bool)
+	var relPaths []string
 
-	// matches are relative paths from srcRoot
-	matches, err := doublestar.Glob(os.DirFS(srcRoot), pattern)
-	if err != nil {
-		return []error{fmt.Errorf("invalid glob pattern %q: %w", pattern, err)}
+	var includes, excludes []string
+	for _, pattern := range patterns {
+		negated, ok := strings.CutPrefix(pattern, "!")
+		if !ok {
+			includes = append(includes, pattern)
+			continue
+		}
+		normalized, err := normalizeCopyPattern(negated)
+		if err == nil {
+			err = utils.ValidatePattern(normalized)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("exclude %q: %w", pattern, err))
+			continue
+		}
+		excludes = append(excludes, normalized)
 	}
 
-	for _, relPath := range matches {
-		srcPath := filepath.Join(srcRoot, relPath)
-		info, err := fs.Stat(srcPath)
+	for _, pattern := range includes {
+		normalized, err := normalizeCopyPattern(pattern)
 		if err != nil {
-			errs = append(errs, fmt.Errorf("stat %q: %w", srcPath, err))
+			errs = append(errs, err)
 			continue
 		}
-		if info.IsDir() {
+
+		// matches are relative paths from srcRoot
+		matches, err := doublestar.Glob(os.DirFS(srcRoot), normalized)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid glob pattern %q: %w", pattern, err))
 			continue
 		}
 
-		if err := copySingleFile(fs, srcRoot, dstRoot, srcPath); err != nil {
-			errs = append(errs, err)
+		for _, relPath := range matches {
+			if seen[relPath] {
+				continue
+			}
+			if slices.ContainsFunc(excludes, func(exclude string) bool { return utils.MatchPath(exclude, relPath) }) {
+				seen[relPath] = true
+				continue
+			}
+
+			srcPath := filepath.Join(srcRoot, filepath.FromSlash(relPath))
+			info, err := fs.Stat(srcPath)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("stat %q: %w", srcPath, err))
+				continue
+			}
+			if info.IsDir() {
+				continue
+			}
+
+			seen[relPath] = true
+			relPaths = append(relPaths, relPath)
 		}
 	}
 
-	return errs
+	slices.Sort(relPaths)
+	return relPaths, errs
+}
+
+// normalizeCopyPattern converts a copy_files pattern to the slash-separated,
+// root-relative form expected by doublestar.
+func normalizeCopyPattern(pattern string) (string, error) {
+	if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, "/") {
+		return "", fmt.Errorf("invalid glob pattern %q: must be relative to the repository root", pattern)
+	}
+
+	normalized := filepath.ToSlash(pattern)
+	for strings.HasPrefix(normalized, "./") {
+		normalized = strings.TrimPrefix(normalized, "./")
+	}
+
+	for _, segment := range strings.Split(normalized, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("invalid glob pattern %q: must not reference parent directories", pattern)
+		}
+	}
+
+	if normalized == "" {
+		return "", fmt.Errorf("invalid glob pattern %q: empty pattern", pattern)
+	}
+	return normalized, nil
 }
 
 // copySingleFile copies a single file from srcPath to the corresponding path under dstRoot.
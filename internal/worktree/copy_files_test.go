@@ -3,6 +3,7 @@ package worktree
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/d-kuro/gwq/internal/filesystem"
@@ -49,6 +50,26 @@ func TestCopyFilesWithGlob(t *testing.T) {
 			},
 			notExpected: []string{"other/ignore.txt"},
 		},
+		{
+			name: "include with exclude",
+			dirs: []string{"config"},
+			files: map[string]string{
+				"config/app.json":     "app",
+				"config/secrets.json": "secret",
+			},
+			patterns:    []string{"!config/secrets.json", "config/*"},
+			expected:    []string{"config/app.json"},
+			notExpected: []string{"config/secrets.json"},
+		},
+		{
+			name: "only excludes",
+			dirs: []string{"config"},
+			files: map[string]string{
+				"config/app.json": "app",
+			},
+			patterns:    []string{"!config/*"},
+			notExpected: []string{"config/app.json"},
+		},
 		{
 			name: "double star with suffix filter",
 			dirs: []string{"templates/layouts", "templates/partials/common", "src"},
@@ -67,6 +88,38 @@ func TestCopyFilesWithGlob(t *testing.T) {
 			},
 			notExpected: []string{"templates/README.md", "src/main.go"},
 		},
+		{
+			name: "single star does not recurse",
+			dirs: []string{"config/nested"},
+			files: map[string]string{
+				"config/app.json":        "app",
+				"config/nested/db.json":  "db",
+				"config/nested/app.yaml": "yaml",
+			},
+			patterns:    []string{"config/*.json"},
+			expected:    []string{"config/app.json"},
+			notExpected: []string{"config/nested/db.json", "config/nested/app.yaml"},
+		},
+		{
+			name: "literal nested file path",
+			dirs: []string{"deploy/local", "deploy/prod"},
+			files: map[string]string{
+				"deploy/local/.env": "local",
+				"deploy/prod/.env":  "prod",
+			},
+			patterns:    []string{"./deploy/local/.env"},
+			expected:    []string{"deploy/local/.env"},
+			notExpected: []string{"deploy/prod/.env", ".env"},
+		},
+		{
+			name: "directory match is skipped",
+			dirs: []string{"config"},
+			files: map[string]string{
+				"config/app.json": "app",
+			},
+			patterns:    []string{"config"},
+			notExpected: []string{"config/app.json"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,11 +147,16 @@ func TestCopyFilesWithGlob(t *testing.T) {
 				t.Errorf("expected no errors, got %v", errs)
 			}
 
-			// Check expected files exist
+			// Check expected files exist at the same relative path with the same content
 			for _, rel := range tt.expected {
 				path := filepath.Join(dstDir, rel)
-				if _, err := os.Stat(path); err != nil {
+				content, err := os.ReadFile(path)
+				if err != nil {
 					t.Errorf("expected %s to be copied, err: %v", rel, err)
+					continue
+				}
+				if string(content) != tt.files[rel] {
+					t.Errorf("%s content = %q, want %q", rel, content, tt.files[rel])
 				}
 			}
 
@@ -112,3 +170,148 @@ func TestCopyFilesWithGlob(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveCopySources(t *testing.T) {
+	srcDir := t.TempDir()
+	for _, rel := range []string{"b.txt", "a.txt", "sub/c.txt", "sub/deep/d.txt"} {
+		path := filepath.Join(srcDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir for %s: %v", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+
+	fs := filesystem.NewStandardFileSystem()
+
+	t.Run("sorted and deduplicated", func(t *testing.T) {
+		got, errs := ResolveCopySources(fs, srcDir, []string{"**/*.txt", "*.txt", "sub/c.txt"})
+		if len(errs) != 0 {
+			t.Fatalf("expected no errors, got %v", errs)
+		}
+		want := []string{"a.txt", "b.txt", "sub/c.txt", "sub/deep/d.txt"}
+		if !slices.Equal(got, want) {
+			t.Errorf("ResolveCopySources() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rejects patterns outside the root", func(t *testing.T) {
+		got, errs := ResolveCopySources(fs, srcDir, []string{"/etc/passwd", "../secret", "sub/../../x", "a.txt"})
+		if len(errs) != 3 {
+			t.Errorf("expected 3 errors, got %v", errs)
+		}
+		if !slices.Equal(got, []string{"a.txt"}) {
+			t.Errorf("ResolveCopySources() = %v, want [a.txt]", got)
+		}
+	})
+
+	t.Run("excludes apply regardless of position", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			patterns []string
+			want     []string
+		}{
+			{
+				name:     "exclude after include",
+				patterns: []string{"**/*.txt", "!sub/c.txt"},
+				want:     []string{"a.txt", "b.txt", "sub/deep/d.txt"},
+			},
+			{
+				name:     "exclude before include",
+				patterns: []string{"!sub/c.txt", "**/*.txt"},
+				want:     []string{"a.txt", "b.txt", "sub/deep/d.txt"},
+			},
+			{
+				name:     "glob exclude",
+				patterns: []string{"**/*.txt", "!sub/**"},
+				want:     []string{"a.txt", "b.txt"},
+			},
+			{
+				name:     "exclude wins over explicit include",
+				patterns: []string{"a.txt", "sub/c.txt", "!*.txt"},
+				want:     []string{"sub/c.txt"},
+			},
+			{
+				name:     "leading ./ in exclude",
+				patterns: []string{"*.txt", "!./b.txt"},
+				want:     []string{"a.txt"},
+			},
+			{
+				name:     "only excludes copy nothing",
+				patterns: []string{"!a.txt", "!sub/**"},
+				want:     nil,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, errs := ResolveCopySources(fs, srcDir, tt.patterns)
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("ResolveCopySources(%v) = %v, want %v", tt.patterns, got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("rejects invalid excludes", func(t *testing.T) {
+		got, errs := ResolveCopySources(fs, srcDir, []string{"*.txt", "!../x", "!/abs", "![", "!"})
+		if len(errs) != 4 {
+			t.Errorf("expected 4 errors, got %v", errs)
+		}
+		if !slices.Equal(got, []string{"a.txt", "b.txt"}) {
+			t.Errorf("ResolveCopySources() = %v, want [a.txt b.txt]", got)
+		}
+	})
+}
+
+func TestCopyBetweenWorktrees(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		"main.go":              "package main\n",
+		"internal/cmd/a.go":    "package cmd\n",
+		"internal/cmd/a.txt":   "notes\n",
+		"internal/util/b.go":   "package util\n",
+		"internal/util/b_x.go": "package util\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Existing destination files are overwritten.
+	if err := os.WriteFile(filepath.Join(dst, "main.go"), []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	copied, errs := CopyBetweenWorktrees(filesystem.NewStandardFileSystem(), src, dst,
+		[]string{"main.go", "internal/**/*.go", "!internal/util/b_x.go"}, filesystem.CopyOptions{})
+	if len(errs) > 0 {
+		t.Fatalf("CopyBetweenWorktrees() errors = %v", errs)
+	}
+	want := []string{"internal/cmd/a.go", "internal/util/b.go", "main.go"}
+	if !slices.Equal(copied, want) {
+		t.Errorf("copied = %v, want %v", copied, want)
+	}
+	for _, rel := range want {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(rel)))
+		if err != nil || string(data) != files[rel] {
+			t.Errorf("%s = %q, %v; want %q", rel, data, err, files[rel])
+		}
+	}
+	for _, rel := range []string{"internal/cmd/a.txt", "internal/util/b_x.go"} {
+		if _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(rel))); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied", rel)
+		}
+	}
+
+	if _, errs := CopyBetweenWorktrees(filesystem.NewStandardFileSystem(), src, dst, []string{"../escape"}, filesystem.CopyOptions{}); len(errs) != 1 {
+		t.Errorf("expected one error for an escaping pattern, got %v", errs)
+	}
+}
@@ -0,0 +1,8 @@
+package worktree
+
+// Diff returns the uncommitted changes of the worktree at path, or their
+// --stat summary, with args passed on to git diff. The result is empty when
+// the worktree has no changes.
+func (m *Manager) Diff(path string, stat bool, args ...string) (string, error) {
+	return m.git.Diff(path, stat, args...)
+}
@@ -0,0 +1,28 @@
+package worktree
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestManagerDiff(t *testing.T) {
+	mock := &mockGit{diffs: map[string]string{"/wt/feature": " a.go | 2 +-\n"}}
+	m := New(mock, nil)
+
+	out, err := m.Diff("/wt/feature", true, "-w")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if out != " a.go | 2 +-\n" {
+		t.Errorf("Diff() = %q", out)
+	}
+	if want := []string{"diff /wt/feature stat=true -w"}; !reflect.DeepEqual(mock.calls, want) {
+		t.Errorf("calls = %v, want %v", mock.calls, want)
+	}
+
+	mock.diffError = errors.New("boom")
+	if _, err := m.Diff("/wt/feature", false); err == nil {
+		t.Error("Diff() should return the git error")
+	}
+}
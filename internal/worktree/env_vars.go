@@ -0,0 +1,85 @@
+package worktree
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/internal/template"
+)
+
+// DotenvFile is the file env_vars are written to when auto_dotenv is set.
+const DotenvFile = ".env"
+
+// gwqEnv returns the GWQ_* variables every setup and teardown command gets:
+// the worktree path, its branch, and the main repository's root and
+// directory name.
+func gwqEnv(branch, worktreePath, repoRoot string) []string {
+	return []string{
+		"GWQ_WORKTREE_PATH=" + worktreePath,
+		"GWQ_BRANCH=" + branch,
+		"GWQ_REPO_ROOT=" + repoRoot,
+		"GWQ_REPO_NAME=" + filepath.Base(repoRoot),
+	}
+}
+
+// renderEnvVars renders each env_vars value as a template and returns the
+// variables as sorted "NAME=value" entries. Names are upper-cased because
+// config keys are case-insensitive and reach us lower-cased. Variables whose
+// value fails to render are skipped and reported in errs.
+func renderEnvVars(vars map[string]string, data *template.TemplateData) (env []string, errs []error) {
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		value, err := template.Render(vars[name], data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("env var %s: %w", name, err))
+			continue
+		}
+		env = append(env, strings.ToUpper(name)+"="+value)
+	}
+	return env, errs
+}
+
+// writeDotenv appends env entries to the .env file in dir, creating it if
+// needed. An existing .env (e.g. one copied by copy_files) is kept, so the
+// appended definitions come last.
+func writeDotenv(fs filesystem.FileSystemInterface, dir string, env []string) error {
+	if len(env) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	path := filepath.Join(dir, DotenvFile)
+	existing, err := fs.ReadFile(path)
+	switch {
+	case err == nil:
+		b.Write(existing)
+		if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+			b.WriteByte('\n')
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, entry := range env {
+		name, value, _ := strings.Cut(entry, "=")
+		b.WriteString(name + "=" + dotenvValue(value) + "\n")
+	}
+
+	if err := fs.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// dotenvValue quotes value when it would not survive unquoted in a .env file.
+func dotenvValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"'#$\\`") {
+		return strconv.Quote(value)
+	}
+	return value
+}
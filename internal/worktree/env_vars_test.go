@@ -0,0 +1,65 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/internal/template"
+)
+
+func TestRenderEnvVars(t *testing.T) {
+	data := &template.TemplateData{Branch: "feature/auth", Repository: "app", Path: "/wt/feature-auth"}
+	vars := map[string]string{
+		"db_name":   "{{.Repository}}_{{.Branch}}",
+		"work_dir":  "{{.Path}}",
+		"bad_value": "{{.Missing}}",
+	}
+
+	env, errs := renderEnvVars(vars, data)
+
+	want := []string{"DB_NAME=app_feature/auth", "WORK_DIR=/wt/feature-auth"}
+	if len(env) != len(want) {
+		t.Fatalf("renderEnvVars() = %v, want %v", env, want)
+	}
+	for i := range want {
+		if env[i] != want[i] {
+			t.Errorf("env[%d] = %q, want %q", i, env[i], want[i])
+		}
+	}
+	if len(errs) != 1 {
+		t.Errorf("renderEnvVars() errs = %v, want one error for bad_value", errs)
+	}
+}
+
+func TestWriteDotenv(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DotenvFile)
+	if err := os.WriteFile(path, []byte("EXISTING=1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := []string{"PLAIN=value", "SPACED=hello world", "EMPTY="}
+	if err := writeDotenv(filesystem.NewStandardFileSystem(), dir, env); err != nil {
+		t.Fatalf("writeDotenv() error = %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "EXISTING=1\nPLAIN=value\nSPACED=\"hello world\"\nEMPTY=\"\"\n"
+	if string(got) != want {
+		t.Errorf("%s = %q, want %q", DotenvFile, got, want)
+	}
+}
+
+func TestRunSetupCommandsWithEnv_UnsupportedExecutor(t *testing.T) {
+	results := RunSetupCommandsWithEnv(t.Context(), &fakeExecutor{}, t.TempDir(), "", []string{"A=1"}, []string{"true"})
+
+	if len(results) != 1 || !errors.Is(results[0].Err, errEnvUnsupported) {
+		t.Errorf("results = %+v, want errEnvUnsupported", results)
+	}
+}
@@ -0,0 +1,169 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/internal/utils"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// Move relocates the worktree directory to newPath and repairs git's worktree
+// metadata. It returns the expanded destination path. newPath must not exist
+// or must be an empty directory.
+func (m *Manager) Move(wt models.Worktree, newPath string) (string, error) {
+	if wt.IsMain {
+		return "", fmt.Errorf("cannot move the main worktree")
+	}
+	if strings.TrimSpace(newPath) == "" {
+		return "", fmt.Errorf("destination path must not be empty")
+	}
+
+	dst, err := utils.ExpandPath(newPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path: %w", err)
+	}
+	if dst == filepath.Clean(wt.Path) {
+		return "", fmt.Errorf("worktree is already at %s", dst)
+	}
+
+	if info, err := os.Lstat(dst); err == nil && !info.IsDir() {
+		return "", fmt.Errorf("target path already exists: %s", dst)
+	}
+	if err := m.ValidateWorktreePath(dst); err != nil {
+		return "", err
+	}
+	// An empty destination directory is replaced; os.Rename refuses to.
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to remove empty directory: %w", err)
+	}
+
+	if err := m.moveWorktreeDir(wt.Path, dst); err != nil {
+		return "", err
+	}
+
+	if err := m.git.RepairWorktrees(dst); err != nil {
+		return dst, fmt.Errorf("worktree moved but git metadata was not updated (run 'git worktree repair %s'): %w", dst, err)
+	}
+
+	return dst, nil
+}
+
+// moveWorktreeDir moves a worktree directory, creating the parent of dst
+// when auto_mkdir is enabled.
+func (m *Manager) moveWorktreeDir(src, dst string) error {
+	fs := filesystem.NewStandardFileSystem()
+
+	if m.config.Worktree.AutoMkdir {
+		if err := fs.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	if err := MoveDir(fs, src, dst); err != nil {
+		return fmt.Errorf("failed to move worktree: %w", err)
+	}
+	return nil
+}
+
+// MoveDir moves the directory src to dst. When a plain rename is impossible
+// because src and dst are on different devices, the tree is copied to dst
+// and src is removed afterwards. A failed copy removes the partial dst and
+// leaves src untouched.
+func MoveDir(fs filesystem.FileSystemInterface, src, dst string) error {
+	err := fs.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := copyTree(fs, src, dst); err != nil {
+		_ = fs.RemoveAll(dst)
+		return fmt.Errorf("copy %q to %q: %w", src, dst, err)
+	}
+
+	if err := fs.RemoveAll(src); err != nil {
+		return fmt.Errorf("copied to %q but failed to remove %q: %w", dst, src, err)
+	}
+	return nil
+}
+
+// copyTree recursively copies the directory src to dst, preserving file
+// modes and symbolic links.
+func copyTree(fs filesystem.FileSystemInterface, src, dst string) error {
+	info, err := fs.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	entries, err := fs.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			// FileSystemInterface has no symlink support; links are recreated
+			// as-is so relative targets keep working at the new location.
+			target, err := os.Readlink(srcPath)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(target, dstPath); err != nil {
+				return err
+			}
+		case entry.IsDir():
+			if err := copyTree(fs, srcPath, dstPath); err != nil {
+				return err
+			}
+		default:
+			if err := copyFileMode(fs, srcPath, dstPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyFileMode copies a regular file, preserving its permission bits.
+func copyFileMode(fs filesystem.FileSystemInterface, src, dst string) (retErr error) {
+	info, err := fs.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	srcFile, err := fs.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := srcFile.Close(); closeErr != nil && retErr == nil {
+			retErr = closeErr
+		}
+	}()
+
+	dstFile, err := fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := dstFile.Close(); closeErr != nil && retErr == nil {
+			retErr = closeErr
+		}
+	}()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
@@ -0,0 +1,203 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/d-kuro/gwq/internal/filesystem"
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+// crossDeviceFS behaves like the standard filesystem except that Rename
+// always fails with EXDEV, as it does across mount points.
+type crossDeviceFS struct {
+	*filesystem.StandardFileSystem
+	renameCalls int
+}
+
+func (fs *crossDeviceFS) Rename(oldpath, newpath string) error {
+	fs.renameCalls++
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+}
+
+// failingRenameFS fails Rename with an error other than EXDEV.
+type failingRenameFS struct {
+	*filesystem.StandardFileSystem
+}
+
+func (fs *failingRenameFS) Rename(oldpath, newpath string) error {
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EACCES}
+}
+
+func createTree(t *testing.T, root string) {
+	t.Helper()
+	files := map[string]string{
+		".git":             "gitdir: /repo/.git/worktrees/feature",
+		"README.md":        "readme",
+		"src/main.go":      "package main",
+		"scripts/build.sh": "#!/bin/sh",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(root, "scripts/build.sh"), 0755); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+	if err := os.Symlink("src/main.go", filepath.Join(root, "link")); err != nil {
+		t.Fatalf("failed to symlink: %v", err)
+	}
+}
+
+func TestMoveDir_CrossDeviceFallback(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "feature")
+	dst := filepath.Join(t.TempDir(), "moved")
+	createTree(t, src)
+
+	fs := &crossDeviceFS{StandardFileSystem: filesystem.NewStandardFileSystem()}
+	if err := MoveDir(fs, src, dst); err != nil {
+		t.Fatalf("MoveDir() error = %v", err)
+	}
+
+	if fs.renameCalls != 1 {
+		t.Errorf("expected one rename attempt, got %d", fs.renameCalls)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("expected source to be removed, err = %v", err)
+	}
+
+	for rel, want := range map[string]string{
+		".git":        "gitdir: /repo/.git/worktrees/feature",
+		"src/main.go": "package main",
+		"link":        "package main",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, rel))
+		if err != nil {
+			t.Errorf("expected %s at destination: %v", rel, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s content = %q, want %q", rel, got, want)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(dst, "scripts/build.sh"))
+	if err != nil {
+		t.Fatalf("expected build.sh at destination: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("build.sh mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "src/main.go" {
+		t.Errorf("link target = %q, %v; want src/main.go", target, err)
+	}
+}
+
+func TestMoveDir_OtherRenameErrorIsReturned(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "feature")
+	dst := filepath.Join(t.TempDir(), "moved")
+	createTree(t, src)
+
+	fs := &failingRenameFS{StandardFileSystem: filesystem.NewStandardFileSystem()}
+	if err := MoveDir(fs, src, dst); err == nil {
+		t.Fatal("MoveDir() expected error, got nil")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("source should be untouched: %v", err)
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("destination should not exist, err = %v", err)
+	}
+}
+
+func TestManagerMove(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "feature")
+	createTree(t, src)
+	dst := filepath.Join(t.TempDir(), "nested", "moved")
+
+	mockG := &mockGit{}
+	m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{AutoMkdir: true}})
+
+	got, err := m.Move(models.Worktree{Path: src, Branch: "feature"}, dst)
+	if err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if got != dst {
+		t.Errorf("Move() = %s, want %s", got, dst)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "README.md")); err != nil {
+		t.Errorf("expected worktree at destination: %v", err)
+	}
+	if len(mockG.repairedPaths) != 1 || mockG.repairedPaths[0] != dst {
+		t.Errorf("repaired paths = %v, want [%s]", mockG.repairedPaths, dst)
+	}
+
+	if _, err := m.Move(models.Worktree{Path: dst, Branch: "feature"}, dst); err == nil {
+		t.Error("Move() onto an existing path should fail")
+	}
+}
+
+func TestManagerMove_Validation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	nonEmpty := t.TempDir()
+	if err := os.WriteFile(filepath.Join(nonEmpty, "file"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		isMain  bool
+		newPath string
+		want    string // expected destination; empty means an error is expected
+	}{
+		{name: "main worktree is rejected", isMain: true, newPath: filepath.Join(t.TempDir(), "moved")},
+		{name: "empty destination is rejected", newPath: ""},
+		{name: "blank destination is rejected", newPath: "  "},
+		{name: "non-empty directory is rejected", newPath: nonEmpty},
+		{name: "tilde is expanded", newPath: "~/moved", want: filepath.Join(home, "moved")},
+		{name: "empty directory is accepted", newPath: filepath.Join(home, "empty"), want: filepath.Join(home, "empty")},
+	}
+
+	if err := os.Mkdir(filepath.Join(home, "empty"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(t.TempDir(), "feature")
+			createTree(t, src)
+
+			m := New(&mockGit{}, &models.Config{})
+			got, err := m.Move(models.Worktree{Path: src, Branch: "feature", IsMain: tt.isMain}, tt.newPath)
+
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("Move(%q) succeeded, want error", tt.newPath)
+				}
+				if _, statErr := os.Stat(filepath.Join(src, "README.md")); statErr != nil {
+					t.Errorf("source should be untouched after a rejected move: %v", statErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Move(%q) error = %v", tt.newPath, err)
+			}
+			if got != tt.want {
+				t.Errorf("Move(%q) = %s, want %s", tt.newPath, got, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(got, "README.md")); err != nil {
+				t.Errorf("expected worktree at destination: %v", err)
+			}
+		})
+	}
+}
@@ -14,7 +14,8 @@ import (
 )
 
 // runPostWorktreeSetup runs file copy and setup commands for the new worktree.
-// branch is used as the raw value for {{.Branch}} in templated setup commands.
+// branch is used as the raw value for {{.Branch}} in templated setup commands
+// and env_vars values, and for GWQ_BRANCH in the commands' environment.
 func (m *Manager) runPostWorktreeSetup(branch, worktreePath string) {
 	m.runPostWorktreeSetupWithExecutor(context.Background(), command.NewStandardExecutor(), branch, worktreePath)
 }
@@ -37,11 +38,23 @@ func (m *Manager) runPostWorktreeSetupWithExecutor(ctx context.Context, executor
 		return nil
 	}
 
-	for _, err := range CopyFilesWithGlob(filesystem.NewStandardFileSystem(), repoRoot, worktreePath, repoSetting.CopyFiles) {
+	fs := filesystem.NewStandardFileSystem()
+	for _, err := range CopyFilesWithGlob(fs, repoRoot, worktreePath, repoSetting.CopyFiles) {
 		fmt.Fprintf(os.Stderr, "[gwq] file copy error: %v\n", err)
 	}
 
 	data := buildSetupTemplateData(m.git, branch, worktreePath)
+
+	env, envErrs := renderEnvVars(repoSetting.EnvVars, data)
+	for _, err := range envErrs {
+		fmt.Fprintf(os.Stderr, "[gwq] env var template error: %v\n", err)
+	}
+	if repoSetting.AutoDotenv {
+		if err := writeDotenv(fs, worktreePath, env); err != nil {
+			fmt.Fprintf(os.Stderr, "[gwq] .env write error: %v\n", err)
+		}
+	}
+
 	rendered := template.RenderCommands(repoSetting.SetupCommands, data)
 
 	toRun := make([]string, 0, len(rendered))
@@ -53,7 +66,9 @@ func (m *Manager) runPostWorktreeSetupWithExecutor(ctx context.Context, executor
 		toRun = append(toRun, rc.Rendered)
 	}
 
-	results := RunSetupCommands(ctx, executor, worktreePath, toRun)
+	// env_vars come last so they can override the GWQ_* variables.
+	env = append(gwqEnv(branch, worktreePath, repoRoot), env...)
+	results := RunSetupCommandsWithEnv(ctx, executor, worktreePath, m.config.Worktree.SetupShell, env, toRun)
 	for _, r := range results {
 		if r.Output != "" {
 			fmt.Fprintf(os.Stderr, "[gwq] setup command output: %s\n", r.Output)
@@ -3,16 +3,20 @@ package worktree
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/d-kuro/gwq/internal/command"
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
 // recordingExecutor records every Execute call so we can assert the exact
 // rendered command string passed to `sh -c`.
 type recordingExecutor struct {
-	fakeExecutor
+	envExecutor
 }
 
 func newRecordingExecutor() *recordingExecutor {
@@ -158,3 +162,138 @@ func TestRunPostWorktreeSetup_NoMatchingRepoSetting(t *testing.T) {
 		t.Errorf("expected no executor calls, got %d", len(exec.calls))
 	}
 }
+
+// envExecutor is a fakeExecutor that also accepts command options, recording
+// the environment each command was given.
+type envExecutor struct {
+	fakeExecutor
+	envs [][]string
+}
+
+func (e *envExecutor) ExecuteWithOptions(ctx context.Context, name string, args []string, opts *command.CommandOptions) error {
+	_, err := e.ExecuteWithOptionsAndOutput(ctx, name, args, opts)
+	return err
+}
+
+func (e *envExecutor) ExecuteWithOptionsAndOutput(ctx context.Context, name string, args []string, opts *command.CommandOptions) (string, error) {
+	e.envs = append(e.envs, opts.Environment)
+	return e.ExecuteInDirWithOutput(ctx, opts.WorkingDir, name, args...)
+}
+
+func TestRunPostWorktreeSetup_EnvVars(t *testing.T) {
+	git := &mockGit{
+		repoPath: "/mock/repo/path",
+		repoURL:  "https://github.com/test-user/test-repo.git",
+	}
+	worktreePath := t.TempDir()
+	setting := models.RepositorySetting{
+		Repository:    "/mock/repo/path",
+		SetupCommands: []string{"npm install", "direct:make setup"},
+		EnvVars: map[string]string{
+			"app_branch": "{{.Branch}}",
+			"app_repo":   "{{.Repository}}",
+			"broken":     "{{.NoSuchVar}}",
+		},
+		AutoDotenv: true,
+	}
+	m := buildManagerWithRepoSetting(git, setting)
+
+	exec := &envExecutor{}
+	results := m.runPostWorktreeSetupWithExecutor(context.Background(), exec, "feature/x", worktreePath)
+
+	if len(results) != 2 || len(exec.envs) != 2 {
+		t.Fatalf("got %d results and %d env calls, want 2 each", len(results), len(exec.envs))
+	}
+	for i, env := range exec.envs {
+		if !slices.Contains(env, "APP_BRANCH=feature/x") || !slices.Contains(env, "APP_REPO=test-repo") {
+			t.Errorf("call %d env is missing the rendered env_vars", i)
+		}
+		if slices.ContainsFunc(env, func(e string) bool { return strings.HasPrefix(e, "BROKEN=") }) {
+			t.Errorf("call %d env contains a variable whose template failed", i)
+		}
+		if exec.calls[i].dir != worktreePath {
+			t.Errorf("call %d dir = %q, want %q", i, exec.calls[i].dir, worktreePath)
+		}
+	}
+
+	dotenv, err := os.ReadFile(filepath.Join(worktreePath, DotenvFile))
+	if err != nil {
+		t.Fatalf("auto_dotenv did not write %s: %v", DotenvFile, err)
+	}
+	if want := "APP_BRANCH=feature/x\nAPP_REPO=test-repo\n"; string(dotenv) != want {
+		t.Errorf("%s = %q, want %q", DotenvFile, dotenv, want)
+	}
+}
+
+func TestRunPostWorktreeSetup_EnvVarsWithoutDotenv(t *testing.T) {
+	git := &mockGit{repoPath: "/mock/repo/path"}
+	worktreePath := t.TempDir()
+	setting := models.RepositorySetting{
+		Repository:    "/mock/repo/path",
+		SetupCommands: []string{"true"},
+		EnvVars:       map[string]string{"mode": "dev"},
+	}
+	m := buildManagerWithRepoSetting(git, setting)
+
+	exec := &envExecutor{}
+	m.runPostWorktreeSetupWithExecutor(context.Background(), exec, "br", worktreePath)
+
+	if len(exec.envs) != 1 || !slices.Contains(exec.envs[0], "MODE=dev") {
+		t.Errorf("envs = %v, want MODE=dev passed to the command", exec.envs)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, DotenvFile)); !os.IsNotExist(err) {
+		t.Errorf("%s should not be written without auto_dotenv (stat error = %v)", DotenvFile, err)
+	}
+}
+
+func TestRunPostWorktreeSetup_GwqEnv(t *testing.T) {
+	git := &mockGit{repoPath: "/src/myproject"}
+	setting := models.RepositorySetting{
+		Repository:    "/src/myproject",
+		SetupCommands: []string{"echo Created $GWQ_BRANCH", "direct:make setup"},
+	}
+	m := buildManagerWithRepoSetting(git, setting)
+
+	exec := &envExecutor{}
+	m.runPostWorktreeSetupWithExecutor(context.Background(), exec, "feature/x", "/wt/feature-x")
+
+	want := []string{
+		"GWQ_WORKTREE_PATH=/wt/feature-x",
+		"GWQ_BRANCH=feature/x",
+		"GWQ_REPO_ROOT=/src/myproject",
+		"GWQ_REPO_NAME=myproject",
+	}
+	if len(exec.envs) != 2 {
+		t.Fatalf("got %d env calls, want 2", len(exec.envs))
+	}
+	for i, env := range exec.envs {
+		for _, w := range want {
+			if !slices.Contains(env, w) {
+				t.Errorf("call %d env is missing %s", i, w)
+			}
+		}
+	}
+}
+
+func TestRunPostWorktreeSetup_EnvVarsOverrideGwqEnv(t *testing.T) {
+	git := &mockGit{repoPath: "/src/myproject"}
+	setting := models.RepositorySetting{
+		Repository:    "/src/myproject",
+		SetupCommands: []string{"true"},
+		EnvVars:       map[string]string{"gwq_repo_name": "custom"},
+	}
+	m := buildManagerWithRepoSetting(git, setting)
+
+	exec := &envExecutor{}
+	m.runPostWorktreeSetupWithExecutor(context.Background(), exec, "br", "/wt/br")
+
+	if len(exec.envs) != 1 {
+		t.Fatalf("got %d env calls, want 1", len(exec.envs))
+	}
+	env := exec.envs[0]
+	builtin := slices.Index(env, "GWQ_REPO_NAME=myproject")
+	override := slices.Index(env, "GWQ_REPO_NAME=custom")
+	if builtin < 0 || override < builtin {
+		t.Errorf("env_vars should come after the GWQ_* variables, got %v", env)
+	}
+}
@@ -2,7 +2,11 @@ package worktree
 
 import (
 	"context"
+	"errors"
+	"os"
 	"strings"
+
+	"github.com/d-kuro/gwq/internal/command"
 )
 
 // Executor is the minimal contract needed to run a setup command.
@@ -11,6 +15,10 @@ type Executor interface {
 	ExecuteInDirWithOutput(ctx context.Context, dir, name string, args ...string) (string, error)
 }
 
+// errEnvUnsupported is returned for commands that need extra environment
+// variables when the executor cannot pass them.
+var errEnvUnsupported = errors.New("executor cannot set environment variables")
+
 // SetupResult is the outcome of running one setup command. Each field is
 // self-contained so callers do not need to correlate parallel output/error
 // slices by index.
@@ -20,17 +28,72 @@ type SetupResult struct {
 	Err     error
 }
 
+// DefaultSetupShell runs setup commands when worktree.setup_shell is unset.
+const DefaultSetupShell = "sh"
+
+// DirectCommandPrefix marks a setup command that is run without a shell.
+// The rest of the command is split on whitespace into the program and its
+// arguments; no quoting, expansion or operators are interpreted.
+const DirectCommandPrefix = "direct:"
+
 // RunSetupCommands runs each non-empty command string via `sh -c` in the
 // given directory. It returns one SetupResult per command actually executed
 // (empty or whitespace-only commands are skipped silently).
 func RunSetupCommands(ctx context.Context, executor Executor, dir string, commands []string) []SetupResult {
+	return RunSetupCommandsWithShell(ctx, executor, dir, DefaultSetupShell, commands)
+}
+
+// RunSetupCommandsWithShell is RunSetupCommands with a configurable shell.
+// shell may include arguments (e.g. "bash -eo pipefail"); each command is
+// appended after "-c". An empty shell means DefaultSetupShell. Commands
+// starting with DirectCommandPrefix bypass the shell.
+func RunSetupCommandsWithShell(ctx context.Context, executor Executor, dir, shell string, commands []string) []SetupResult {
+	return RunSetupCommandsWithEnv(ctx, executor, dir, shell, nil, commands)
+}
+
+// RunSetupCommandsWithEnv is RunSetupCommandsWithShell with extra
+// environment variables ("NAME=value") added to the process environment.
+// With a non-empty env the executor must also implement
+// command.AdvancedCommandExecutor, as command.NewStandardExecutor() does.
+func RunSetupCommandsWithEnv(ctx context.Context, executor Executor, dir, shell string, env []string, commands []string) []SetupResult {
+	shellArgs := strings.Fields(shell)
+	if len(shellArgs) == 0 {
+		shellArgs = []string{DefaultSetupShell}
+	}
+
+	run := func(name string, args ...string) (string, error) {
+		if len(env) == 0 {
+			return executor.ExecuteInDirWithOutput(ctx, dir, name, args...)
+		}
+		advanced, ok := executor.(command.AdvancedCommandExecutor)
+		if !ok {
+			return "", errEnvUnsupported
+		}
+		return advanced.ExecuteWithOptionsAndOutput(ctx, name, args, &command.CommandOptions{
+			WorkingDir:  dir,
+			Environment: append(os.Environ(), env...),
+		})
+	}
+
 	results := make([]SetupResult, 0, len(commands))
 	for _, cmd := range commands {
 		trimmed := strings.TrimSpace(cmd)
 		if trimmed == "" {
 			continue
 		}
-		output, err := executor.ExecuteInDirWithOutput(ctx, dir, "sh", "-c", trimmed)
+
+		var output string
+		var err error
+		if direct, ok := strings.CutPrefix(trimmed, DirectCommandPrefix); ok {
+			fields := strings.Fields(direct)
+			if len(fields) == 0 {
+				continue
+			}
+			output, err = run(fields[0], fields[1:]...)
+		} else {
+			args := append(append([]string{}, shellArgs[1:]...), "-c", trimmed)
+			output, err = run(shellArgs[0], args...)
+		}
 		results = append(results, SetupResult{Command: trimmed, Output: output, Err: err})
 	}
 	return results
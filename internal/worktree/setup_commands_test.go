@@ -3,8 +3,12 @@ package worktree
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/d-kuro/gwq/internal/command"
 )
 
 // capturedCall records one invocation of the fake Executor.
@@ -133,3 +137,62 @@ func TestRunSetupCommands_IndexAlignment(t *testing.T) {
 		t.Errorf("result 2 = %+v; want {Command: c, Output: o3, Err: nil}", results[2])
 	}
 }
+
+func TestRunSetupCommandsWithShell_Invocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		shell    string
+		command  string
+		wantName string
+		wantArgs []string
+	}{
+		{name: "default shell", command: "npm ci && npm run build", wantName: "sh", wantArgs: []string{"-c", "npm ci && npm run build"}},
+		{name: "configured shell", shell: "bash", command: "a | b", wantName: "bash", wantArgs: []string{"-c", "a | b"}},
+		{name: "shell with arguments", shell: "bash -eo pipefail", command: "a | b", wantName: "bash", wantArgs: []string{"-eo", "pipefail", "-c", "a | b"}},
+		{name: "direct", shell: "bash", command: "direct: make  setup V=1", wantName: "make", wantArgs: []string{"setup", "V=1"}},
+		{name: "direct keeps operators literal", command: "direct:echo a && b", wantName: "echo", wantArgs: []string{"a", "&&", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExecutor{}
+			results := RunSetupCommandsWithShell(context.Background(), exec, "/dir", tt.shell, []string{tt.command})
+
+			if len(exec.calls) != 1 || len(results) != 1 {
+				t.Fatalf("got %d calls and %d results, want 1 each", len(exec.calls), len(results))
+			}
+			call := exec.calls[0]
+			if call.name != tt.wantName || !reflect.DeepEqual(call.args, tt.wantArgs) {
+				t.Errorf("ran %q %v; want %q %v", call.name, call.args, tt.wantName, tt.wantArgs)
+			}
+			if results[0].Command != tt.command {
+				t.Errorf("Command = %q; want %q", results[0].Command, tt.command)
+			}
+		})
+	}
+}
+
+func TestRunSetupCommandsWithShell_SkipEmptyDirect(t *testing.T) {
+	exec := &fakeExecutor{}
+	results := RunSetupCommandsWithShell(context.Background(), exec, "/dir", "", []string{"direct:", "direct:   "})
+	if len(results) != 0 || len(exec.calls) != 0 {
+		t.Errorf("got %d results and %d calls; want none", len(results), len(exec.calls))
+	}
+}
+
+func TestRunSetupCommandsWithShell_Operators(t *testing.T) {
+	dir := t.TempDir()
+	results := RunSetupCommandsWithShell(context.Background(), command.NewStandardExecutor(), dir, "sh",
+		[]string{"touch first && touch second", "echo piped | cat > third"})
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%q failed: %v", r.Command, r.Err)
+		}
+	}
+	for _, name := range []string{"first", "second", "third"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s was not created: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/d-kuro/gwq/internal/command"
+	"github.com/d-kuro/gwq/internal/template"
+)
+
+// runTeardown runs the matching repository's teardown_commands in the
+// worktree before it is removed. Failures are logged and never block the
+// removal. branch is used as the raw value for {{.Branch}}; when empty it is
+// looked up from the worktree list.
+func (m *Manager) runTeardown(branch, worktreePath string) []SetupResult {
+	executor := m.executor
+	if executor == nil {
+		executor = command.NewStandardExecutor()
+	}
+	return m.runTeardownWithExecutor(context.Background(), executor, branch, worktreePath)
+}
+
+// runTeardownWithExecutor is the test seam for runTeardown.
+func (m *Manager) runTeardownWithExecutor(ctx context.Context, executor Executor, branch, worktreePath string) []SetupResult {
+	if len(m.config.RepositorySettings) == 0 {
+		return nil
+	}
+
+	repoRoot, err := m.git.GetMainRepositoryPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[gwq] warning: failed to get repository path: %v\n", err)
+		return nil
+	}
+
+	repoSetting := findRepoSetting(m.config.RepositorySettings, repoRoot)
+	if repoSetting == nil || len(repoSetting.TeardownCommands) == 0 {
+		return nil
+	}
+
+	// An orphaned worktree (see FindOrphaned) has no directory to run in.
+	if info, err := os.Stat(worktreePath); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	if branch == "" {
+		branch = m.branchAt(worktreePath)
+	}
+	data := buildSetupTemplateData(m.git, branch, worktreePath)
+
+	env, envErrs := renderEnvVars(repoSetting.EnvVars, data)
+	for _, err := range envErrs {
+		fmt.Fprintf(os.Stderr, "[gwq] env var template error: %v\n", err)
+	}
+
+	toRun := make([]string, 0, len(repoSetting.TeardownCommands))
+	for _, rc := range template.RenderCommands(repoSetting.TeardownCommands, data) {
+		if rc.Err != nil {
+			fmt.Fprintf(os.Stderr, "[gwq] teardown command template error: %v\n", rc.Err)
+			continue
+		}
+		toRun = append(toRun, rc.Rendered)
+	}
+
+	env = append(gwqEnv(branch, worktreePath, repoRoot), env...)
+	results := RunSetupCommandsWithEnv(ctx, executor, worktreePath, m.config.Worktree.SetupShell, env, toRun)
+	for _, r := range results {
+		if r.Output != "" {
+			fmt.Fprintf(os.Stderr, "[gwq] teardown command output: %s\n", r.Output)
+		}
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "[gwq] teardown command error: %s: %v\n", r.Command, r.Err)
+		}
+	}
+
+	return results
+}
+
+// branchAt returns the branch checked out in the worktree at path, or "" if
+// it cannot be determined.
+func (m *Manager) branchAt(path string) string {
+	worktrees, err := m.git.ListWorktrees()
+	if err != nil {
+		return ""
+	}
+	for _, wt := range worktrees {
+		if wt.Path == path {
+			return wt.Branch
+		}
+	}
+	return ""
+}
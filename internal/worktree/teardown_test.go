@@ -0,0 +1,145 @@
+package worktree
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/d-kuro/gwq/pkg/models"
+)
+
+func TestRemove_RunsTeardownCommands(t *testing.T) {
+	wtPath := t.TempDir()
+	git := &mockGit{
+		repoPath:  "/mock/repo/path",
+		repoURL:   "https://github.com/test-user/test-repo.git",
+		worktrees: []models.Worktree{{Path: wtPath, Branch: "feature/x"}},
+	}
+	exec := newRecordingExecutor()
+	m := buildManagerWithRepoSetting(git, models.RepositorySetting{
+		Repository:       "/mock/repo/path",
+		TeardownCommands: []string{"docker compose down", "echo bye {{.Branch}}"},
+	})
+	m.executor = exec
+
+	if err := m.RemoveWithBranch(wtPath, "feature/x", false, false, false); err != nil {
+		t.Fatalf("RemoveWithBranch() error = %v", err)
+	}
+
+	want := []string{"docker compose down", "echo bye feature/x"}
+	if got := exec.rendered(); !slices.Equal(got, want) {
+		t.Errorf("teardown commands = %v, want %v", got, want)
+	}
+	for i, c := range exec.calls {
+		if c.dir != wtPath {
+			t.Errorf("teardown ran in %q, want %q", c.dir, wtPath)
+		}
+		if !slices.Contains(exec.envs[i], "GWQ_BRANCH=feature/x") || !slices.Contains(exec.envs[i], "GWQ_WORKTREE_PATH="+wtPath) {
+			t.Errorf("teardown call %d env is missing the GWQ_* variables", i)
+		}
+	}
+	if len(git.worktrees) != 0 {
+		t.Errorf("worktree was not removed: %+v", git.worktrees)
+	}
+}
+
+func TestRemove_TeardownFailureDoesNotBlockRemoval(t *testing.T) {
+	wtPath := t.TempDir()
+	git := &mockGit{
+		repoPath:  "/mock/repo/path",
+		repoURL:   "https://github.com/test-user/test-repo.git",
+		worktrees: []models.Worktree{{Path: wtPath, Branch: "feature/x"}},
+	}
+	exec := newRecordingExecutor()
+	exec.errs = []error{errors.New("exit status 1")}
+	m := buildManagerWithRepoSetting(git, models.RepositorySetting{
+		Repository:       "/mock/repo/path",
+		TeardownCommands: []string{"false", "echo {{.Branch}}"},
+	})
+	m.executor = exec
+
+	if err := m.Remove(wtPath, false); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	want := []string{"false", "echo feature/x"}
+	if got := exec.rendered(); !slices.Equal(got, want) {
+		t.Errorf("teardown commands = %v, want %v", got, want)
+	}
+	if len(git.worktrees) != 0 {
+		t.Errorf("worktree was not removed: %+v", git.worktrees)
+	}
+}
+
+func TestRemove_DirtyWorktreeSkipsTeardown(t *testing.T) {
+	wtPath := t.TempDir()
+	git := &mockGit{
+		repoPath:  "/mock/repo/path",
+		repoURL:   "https://github.com/test-user/test-repo.git",
+		worktrees: []models.Worktree{{Path: wtPath, Branch: "feature/x"}},
+		dirty:     map[string]bool{wtPath: true},
+	}
+	exec := newRecordingExecutor()
+	m := buildManagerWithRepoSetting(git, models.RepositorySetting{
+		Repository:       "/mock/repo/path",
+		TeardownCommands: []string{"docker compose down"},
+	})
+	m.executor = exec
+
+	if err := m.RemoveWithBranch(wtPath, "feature/x", false, true, false); err == nil {
+		t.Fatal("RemoveWithBranch() error = nil, want refusal for a dirty worktree")
+	}
+	if got := exec.rendered(); len(got) != 0 {
+		t.Errorf("teardown ran for a refused removal: %v", got)
+	}
+	if len(git.worktrees) != 1 {
+		t.Errorf("dirty worktree was removed: %+v", git.worktrees)
+	}
+
+	if err := m.Remove(wtPath, true); err != nil {
+		t.Fatalf("Remove(force) error = %v", err)
+	}
+	if got := exec.rendered(); !slices.Equal(got, []string{"docker compose down"}) {
+		t.Errorf("teardown commands = %v, want the forced removal to run them", got)
+	}
+}
+
+func TestRunTeardown_Skips(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting models.RepositorySetting
+		path    string
+	}{
+		{
+			name:    "no teardown commands",
+			setting: models.RepositorySetting{Repository: "/mock/repo/path", SetupCommands: []string{"make"}},
+			path:    t.TempDir(),
+		},
+		{
+			name:    "other repository",
+			setting: models.RepositorySetting{Repository: "/other/repo", TeardownCommands: []string{"make clean"}},
+			path:    t.TempDir(),
+		},
+		{
+			name:    "directory already deleted",
+			setting: models.RepositorySetting{Repository: "/mock/repo/path", TeardownCommands: []string{"make clean"}},
+			path:    filepath.Join(t.TempDir(), "gone"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			git := &mockGit{repoPath: "/mock/repo/path"}
+			m := buildManagerWithRepoSetting(git, tt.setting)
+			exec := newRecordingExecutor()
+
+			if results := m.runTeardownWithExecutor(context.Background(), exec, "br", tt.path); results != nil {
+				t.Errorf("expected no results, got %+v", results)
+			}
+			if len(exec.calls) != 0 {
+				t.Errorf("expected no commands to run, got %+v", exec.calls)
+			}
+		})
+	}
+}
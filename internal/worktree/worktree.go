@@ -2,6 +2,7 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,24 +14,47 @@ import (
 	"github.com/d-kuro/gwq/pkg/models"
 )
 
+// ErrNoWorktreeFound is returned when no worktree matches a lookup.
+var ErrNoWorktreeFound = errors.New("no worktree found")
+
+// ErrReservedPath is returned when a worktree path is the filesystem root,
+// the home directory or the main worktree.
+var ErrReservedPath = errors.New("refusing to use reserved path for a worktree")
+
 // GitInterface defines the git operations used by Manager.
 type GitInterface interface {
 	ListWorktrees() ([]models.Worktree, error)
-	AddWorktree(path, branch string, createBranch bool) error
-	AddWorktreeFromBase(path, branch, baseBranch string) error
+	AddWorktree(path, branch string, createBranch, noCheckout bool) error
+	AddWorktreeFromBase(path, branch, baseBranch string, noCheckout bool) error
 	RemoveWorktree(path string, force bool) error
+	IsWorktreeDirty(path string) (bool, error)
 	DeleteBranch(branch string, force bool) error
+	ListBranches(includeRemote bool) ([]models.Branch, error)
+	RenameBranch(oldName, newName string) error
 	PruneWorktrees() error
+	PruneWorktreesVerbose(dryRun bool) (string, error)
+	RepairWorktrees(paths ...string) error
 	GetRepositoryName() (string, error)
 	GetRecentCommits(path string, limit int) ([]models.CommitInfo, error)
 	GetRepositoryURL() (string, error)
 	GetMainRepositoryPath() (string, error)
+	MergedBranches(into string) (map[string]bool, error)
+	BranchHasOwnCommits(branch, base string) (bool, error)
+	DefaultBranch() (string, error)
+	Upstream(ref string) (remote, branch string, err error)
+	FetchBranch(remote, branch string) error
+	IsAncestor(ancestor, descendant string) (bool, error)
+	IsCommitish(ref string) bool
+	Diff(path string, stat bool, args ...string) (string, error)
 }
 
 // Manager handles worktree operations.
 type Manager struct {
 	git    GitInterface
 	config *models.Config
+
+	// executor runs teardown commands; nil means command.NewStandardExecutor.
+	executor Executor
 }
 
 // New creates a new worktree Manager.
@@ -41,44 +65,104 @@ func New(g GitInterface, config *models.Config) *Manager {
 	}
 }
 
+// AddOptions controls how AddWithOptions creates a worktree.
+type AddOptions struct {
+	// CreateBranch creates the branch instead of checking out an existing one.
+	CreateBranch bool
+	// Base is the ref a created branch starts from; empty means HEAD.
+	Base string
+	// NoCheckout registers the worktree without checking out its files.
+	// Setup from repository_settings is skipped, as there is no tree to set
+	// up.
+	NoCheckout bool
+}
+
 // Add creates a new worktree and returns the path of the created worktree.
 func (m *Manager) Add(branch string, customPath string, createBranch bool) (string, error) {
+	return m.AddWithOptions(branch, customPath, AddOptions{CreateBranch: createBranch})
+}
+
+// AddFromBase creates a new worktree with a branch from a specific base branch
+// and returns the path of the created worktree.
+func (m *Manager) AddFromBase(branch string, baseBranch string, customPath string) (string, error) {
+	return m.AddWithOptions(branch, customPath, AddOptions{CreateBranch: true, Base: baseBranch})
+}
+
+// AddWithOptions creates a new worktree as described by opts and returns its
+// path.
+func (m *Manager) AddWithOptions(branch string, customPath string, opts AddOptions) (string, error) {
 	path, err := m.preparePath(customPath, branch)
 	if err != nil {
 		return "", err
 	}
 
-	if err := m.git.AddWorktree(path, branch, createBranch); err != nil {
+	if opts.Base != "" {
+		err = m.git.AddWorktreeFromBase(path, branch, opts.Base, opts.NoCheckout)
+	} else {
+		err = m.git.AddWorktree(path, branch, opts.CreateBranch, opts.NoCheckout)
+	}
+	if err != nil {
 		return "", err
 	}
 
-	m.runPostWorktreeSetup(branch, path)
+	if !opts.NoCheckout {
+		m.runPostWorktreeSetup(branch, path)
+	}
 	return path, nil
 }
 
-// AddFromBase creates a new worktree with a branch from a specific base branch
-// and returns the path of the created worktree.
-func (m *Manager) AddFromBase(branch string, baseBranch string, customPath string) (string, error) {
-	path, err := m.preparePath(customPath, branch)
+// PullBase fetches base from its remote so that a branch created from it
+// starts at the latest commit, and returns the ref to branch from. A
+// remote-tracking base such as origin/main is refreshed in place. A local
+// branch is replaced by its fetched upstream, unless it has commits of its
+// own. On error base is returned along with it, so callers can warn and
+// carry on from the local state.
+func (m *Manager) PullBase(base string) (string, error) {
+	remote, branch, err := m.git.Upstream(base)
 	if err != nil {
-		return "", err
+		return base, err
+	}
+	if remote == "" {
+		return base, fmt.Errorf("%s is not a remote-tracking branch and has no upstream", base)
 	}
 
-	if err := m.git.AddWorktreeFromBase(path, branch, baseBranch); err != nil {
-		return "", err
+	if err := m.git.FetchBranch(remote, branch); err != nil {
+		return base, err
 	}
 
-	m.runPostWorktreeSetup(branch, path)
-	return path, nil
+	tracking := remote + "/" + branch
+	if base == tracking || base == "refs/remotes/"+tracking {
+		return base, nil
+	}
+	behind, err := m.git.IsAncestor(base, tracking)
+	if err != nil {
+		return base, err
+	}
+	if !behind {
+		return base, fmt.Errorf("%s has commits that are not in %s", base, tracking)
+	}
+	return tracking, nil
 }
 
-// Remove deletes a worktree.
+// Remove deletes a worktree after running any configured teardown commands.
+// A worktree that git would refuse to remove is rejected before teardown runs.
 func (m *Manager) Remove(path string, force bool) error {
+	if err := m.checkRemovable(path, force); err != nil {
+		return err
+	}
+	m.runTeardown("", path)
 	return m.git.RemoveWorktree(path, force)
 }
 
-// RemoveWithBranch deletes a worktree and optionally its branch.
+// RemoveWithBranch deletes a worktree and optionally its branch. Configured
+// teardown commands run in the worktree first, once the worktree is known to
+// be removable.
 func (m *Manager) RemoveWithBranch(path string, branch string, forceWorktree bool, deleteBranch bool, forceBranch bool) error {
+	if err := m.checkRemovable(path, forceWorktree); err != nil {
+		return err
+	}
+	m.runTeardown(branch, path)
+
 	// First remove the worktree
 	if err := m.git.RemoveWorktree(path, forceWorktree); err != nil {
 		return err
@@ -95,9 +179,175 @@ func (m *Manager) RemoveWithBranch(path string, branch string, forceWorktree boo
 	return nil
 }
 
+// checkRemovable returns an error when git would refuse to remove the
+// worktree at path, so teardown side effects never precede a failed removal.
+func (m *Manager) checkRemovable(path string, force bool) error {
+	if force {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		// A missing directory is left for git to report.
+		return nil
+	}
+	dirty, err := m.git.IsWorktreeDirty(path)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("worktree %s contains modified or untracked files, use --force to delete it", path)
+	}
+	return nil
+}
+
+// Rename renames the branch checked out in wt to newBranch. With moveDir it
+// also moves the worktree directory to the path generated for newBranch and
+// repairs git's worktree metadata. It returns the resulting worktree path.
+// The target path must not exist; if the move fails the branch rename is
+// rolled back. An existing newBranch is rejected by git before anything
+// changes.
+func (m *Manager) Rename(wt models.Worktree, newBranch string, moveDir bool) (string, error) {
+	if wt.IsMain {
+		return "", fmt.Errorf("cannot rename the main worktree")
+	}
+	if wt.Branch == "" || wt.Branch == "HEAD" {
+		return "", fmt.Errorf("worktree %s has no branch checked out", wt.Path)
+	}
+	if newBranch == wt.Branch {
+		return "", fmt.Errorf("worktree is already on branch %s", newBranch)
+	}
+
+	if !moveDir {
+		if err := m.git.RenameBranch(wt.Branch, newBranch); err != nil {
+			return "", err
+		}
+		return wt.Path, nil
+	}
+
+	generatedPath, err := m.generateWorktreePath(newBranch)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate worktree path: %w", err)
+	}
+	newPath, err := utils.ExpandPath(generatedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path: %w", err)
+	}
+
+	if _, err := os.Lstat(newPath); err == nil {
+		return "", fmt.Errorf("target path already exists: %s", newPath)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to check path: %w", err)
+	}
+
+	if err := m.git.RenameBranch(wt.Branch, newBranch); err != nil {
+		return "", err
+	}
+
+	if err := m.moveWorktreeDir(wt.Path, newPath); err != nil {
+		if rbErr := m.git.RenameBranch(newBranch, wt.Branch); rbErr != nil {
+			return "", fmt.Errorf("%w (rolling back branch rename also failed: %v)", err, rbErr)
+		}
+		return "", err
+	}
+
+	if err := m.git.RepairWorktrees(newPath); err != nil {
+		return newPath, fmt.Errorf("worktree moved but git metadata was not updated (run 'git worktree repair %s'): %w", newPath, err)
+	}
+
+	return newPath, nil
+}
+
 // List returns all worktrees.
 func (m *Manager) List() ([]models.Worktree, error) {
-	return m.git.ListWorktrees()
+	worktrees, err := m.git.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+
+	// All worktrees share the repository's origin; without one (or with an
+	// unparseable URL) RepositoryInfo is left nil.
+	if repoURL, err := m.git.GetRepositoryURL(); err == nil {
+		if repoInfo, err := url.ParseRepositoryURL(repoURL); err == nil {
+			for i := range worktrees {
+				worktrees[i].RepositoryInfo = repoInfo
+			}
+		}
+	}
+	if m.config != nil {
+		if baseDir, err := utils.ExpandPath(m.config.Worktree.BaseDir); err == nil {
+			for i := range worktrees {
+				wt := &worktrees[i]
+				wt.BranchDirMismatch = !wt.IsMain && !template.DirMatchesBranch(m.config.Naming, baseDir, wt.Path, wt.Branch)
+			}
+		}
+	}
+	return worktrees, nil
+}
+
+// FindOrphaned returns the worktrees git still tracks whose directory no
+// longer exists on disk. The main worktree is never reported.
+func (m *Manager) FindOrphaned() ([]models.Worktree, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var orphaned []models.Worktree
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			continue
+		}
+		if _, err := os.Stat(wt.Path); errors.Is(err, os.ErrNotExist) {
+			orphaned = append(orphaned, wt)
+		}
+	}
+	return orphaned, nil
+}
+
+// ListMergedWorktrees returns the worktrees whose branch is merged into
+// baseBranch, or into the default branch when baseBranch is empty. The main
+// worktree, detached HEADs, worktrees of the base branch itself and fresh
+// branches without commits of their own are never included.
+func (m *Manager) ListMergedWorktrees(baseBranch string) ([]models.Worktree, error) {
+	if baseBranch == "" {
+		branch, err := m.git.DefaultBranch()
+		if err != nil {
+			return nil, err
+		}
+		baseBranch = branch
+	}
+
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	mergedBranches, err := m.git.MergedBranches(baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []models.Worktree
+	for _, wt := range worktrees {
+		if wt.IsMain || isDetached(wt) || wt.Branch == baseBranch || !mergedBranches[wt.Branch] {
+			continue
+		}
+		// A branch that never moved past its starting point is contained in
+		// the base trivially; it is new work, not merged work.
+		ok, err := m.git.BranchHasOwnCommits(wt.Branch, baseBranch)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			merged = append(merged, wt)
+		}
+	}
+	return merged, nil
+}
+
+// isDetached reports whether wt has no branch checked out. git reports
+// detached worktrees without a branch, which ListWorktrees resolves to "HEAD".
+func isDetached(wt models.Worktree) bool {
+	return wt.Branch == "" || wt.Branch == "HEAD"
 }
 
 // Prune removes worktree information for deleted directories.
@@ -105,6 +355,45 @@ func (m *Manager) Prune() error {
 	return m.git.PruneWorktrees()
 }
 
+// PruneEntry is a stale administrative entry removed by prune.
+type PruneEntry struct {
+	Path   string // Entry relative to the git directory, e.g. "worktrees/feature"
+	Reason string // Why git considers it stale
+}
+
+// PruneDryRun reports the entries Prune would remove, without removing them.
+func (m *Manager) PruneDryRun() ([]PruneEntry, error) {
+	report, err := m.git.PruneWorktreesVerbose(true)
+	if err != nil {
+		return nil, err
+	}
+	return parsePruneReport(report), nil
+}
+
+// PruneVerbose is Prune, returning the entries that were removed.
+func (m *Manager) PruneVerbose() ([]PruneEntry, error) {
+	report, err := m.git.PruneWorktreesVerbose(false)
+	if err != nil {
+		return nil, err
+	}
+	return parsePruneReport(report), nil
+}
+
+// parsePruneReport extracts the entries from 'git worktree prune --verbose'
+// output. Lines other than "Removing <path>: <reason>" are ignored.
+func parsePruneReport(report string) []PruneEntry {
+	var entries []PruneEntry
+	for line := range strings.Lines(report) {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Removing ")
+		if !ok {
+			continue
+		}
+		path, reason, _ := strings.Cut(rest, ": ")
+		entries = append(entries, PruneEntry{Path: path, Reason: reason})
+	}
+	return entries
+}
+
 // GetWorktreePath returns the path for a worktree by pattern matching.
 func (m *Manager) GetWorktreePath(pattern string) (string, error) {
 	worktrees, err := m.List()
@@ -120,7 +409,7 @@ func (m *Manager) GetWorktreePath(pattern string) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("no worktree found matching pattern: %s", pattern)
+	return "", fmt.Errorf("%w matching pattern: %s", ErrNoWorktreeFound, pattern)
 }
 
 // GetMatchingWorktrees returns all worktrees matching the given pattern.
@@ -142,8 +431,48 @@ func (m *Manager) GetMatchingWorktrees(pattern string) ([]models.Worktree, error
 	return matches, nil
 }
 
+// GetWorktreeByExactBranch returns the worktree that has branch checked
+// out. Unlike GetMatchingWorktrees it never matches substrings, so "feat"
+// does not resolve to "feature". A worktree whose branch name equals branch
+// wins; otherwise branch may be given in the sanitized form used for
+// worktree directory names (e.g. "feature-auth" for "feature/auth"), as long
+// as exactly one worktree matches that way. The boolean reports whether a
+// worktree was found.
+func (m *Manager) GetWorktreeByExactBranch(branch string) (models.Worktree, bool, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return models.Worktree{}, false, err
+	}
+
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			return wt, true, nil
+		}
+	}
+
+	sanitized := template.SanitizeBranch(branch, m.config.Naming.SanitizeChars)
+	var matches []models.Worktree
+	for _, wt := range worktrees {
+		if wt.Branch != "" && template.SanitizeBranch(wt.Branch, m.config.Naming.SanitizeChars) == sanitized {
+			matches = append(matches, wt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.Worktree{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		return models.Worktree{}, false, fmt.Errorf("branch %q matches %d worktrees after sanitization", branch, len(matches))
+	}
+}
+
 // ValidateWorktreePath checks if a path can be used for a new worktree.
 func (m *Manager) ValidateWorktreePath(path string) error {
+	if err := m.checkReservedPath(path); err != nil {
+		return err
+	}
+
 	info, err := os.Stat(path)
 	if err == nil {
 		if info.IsDir() {
@@ -181,6 +510,10 @@ func (m *Manager) preparePath(customPath, branch string) (string, error) {
 	}
 	path = expandedPath
 
+	if err := m.checkReservedPath(path); err != nil {
+		return "", err
+	}
+
 	if m.config.Worktree.AutoMkdir {
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0755); err != nil {
@@ -191,6 +524,46 @@ func (m *Manager) preparePath(customPath, branch string) (string, error) {
 	return path, nil
 }
 
+// checkReservedPath rejects paths that must never become a worktree: the
+// filesystem root, the home directory and the main worktree. Creating
+// directories there or later removing the "worktree" would touch unrelated
+// files.
+func (m *Manager) checkReservedPath(path string) error {
+	expanded, err := utils.ExpandPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to expand path: %w", err)
+	}
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if filepath.Dir(abs) == abs {
+		return fmt.Errorf("%w: %s is the filesystem root", ErrReservedPath, abs)
+	}
+	if home, err := os.UserHomeDir(); err == nil && samePath(abs, home) {
+		return fmt.Errorf("%w: %s is the home directory", ErrReservedPath, abs)
+	}
+	if m.git != nil {
+		if root, err := m.git.GetMainRepositoryPath(); err == nil && root != "" && samePath(abs, root) {
+			return fmt.Errorf("%w: %s is the main worktree", ErrReservedPath, abs)
+		}
+	}
+	return nil
+}
+
+// samePath reports whether a and b name the same location, also after
+// resolving symlinks (e.g. /var and /private/var on macOS).
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if a == b {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
 // generateWorktreePath generates a path for a new worktree using template configuration.
 func (m *Manager) generateWorktreePath(branch string) (string, error) {
 	// Get repository URL
@@ -217,26 +590,27 @@ func (m *Manager) generateWorktreePath(branch string) (string, error) {
 		}
 	}
 
-	// Use template if configured, otherwise fall back to default URL hierarchy
-	if m.config.Naming.Template != "" {
-		// Create template processor
-		processor, err := template.New(m.config.Naming.Template, m.config.Naming.SanitizeChars)
-		if err != nil {
-			// Fall back to default hierarchy if template is invalid
-			return url.GenerateWorktreePath(baseDir, repoInfo, branch), nil
-		}
+	return namedWorktreePath(m.config.Naming, baseDir, repoInfo, branch), nil
+}
 
-		// Generate path using template
-		path, err := processor.GeneratePath(baseDir, repoInfo, branch)
-		if err != nil {
-			// Fall back to default hierarchy if template execution fails
-			return url.GenerateWorktreePath(baseDir, repoInfo, branch), nil
-		}
+// namedWorktreePath returns the path for branch of the repository under
+// baseDir, using the naming template when one is configured and valid, and
+// the default URL hierarchy otherwise.
+func namedWorktreePath(naming models.NamingConfig, baseDir string, repoInfo *models.RepositoryInfo, branch string) string {
+	if naming.Template == "" {
+		return url.GenerateWorktreePath(baseDir, repoInfo, branch)
+	}
 
-		return path, nil
+	processor, err := template.New(naming.Template, naming.SanitizeChars)
+	if err != nil {
+		// Fall back to default hierarchy if template is invalid
+		return url.GenerateWorktreePath(baseDir, repoInfo, branch)
 	}
 
-	// Fall back to default URL hierarchy
-	path := url.GenerateWorktreePath(baseDir, repoInfo, branch)
-	return path, nil
+	path, err := processor.GeneratePath(baseDir, repoInfo, branch)
+	if err != nil {
+		// Fall back to default hierarchy if template execution fails
+		return url.GenerateWorktreePath(baseDir, repoInfo, branch)
+	}
+	return path
 }
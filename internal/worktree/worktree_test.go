@@ -2,8 +2,10 @@ package worktree
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -21,9 +23,29 @@ type mockGit struct {
 	removeError       error
 	listError         error
 	pruneError        error
+	pruneReport       string
+	pruneDryRuns      []bool
 	deleteBranchError error
 	recentCommits     []models.CommitInfo
 	mainRepoPathError error
+	renameBranchError error
+	repairError       error
+	renamedBranches   [][2]string
+	repairedPaths     []string
+	mergedBranches    map[string]bool
+	mergedError       error
+	mergedInto        []string
+	freshBranches     map[string]bool
+	defaultBranch     string
+	branches          []models.Branch
+	upstreams         map[string][2]string // ref -> remote, branch
+	fetchError        error
+	ancestors         map[[2]string]bool
+	calls             []string
+	diffs             map[string]string // path -> diff output
+	diffError         error
+	commitish         map[string]bool
+	dirty             map[string]bool
 }
 
 func (m *mockGit) ListWorktrees() ([]models.Worktree, error) {
@@ -33,7 +55,12 @@ func (m *mockGit) ListWorktrees() ([]models.Worktree, error) {
 	return m.worktrees, nil
 }
 
-func (m *mockGit) AddWorktree(path, branch string, createBranch bool) error {
+func (m *mockGit) AddWorktree(path, branch string, createBranch, noCheckout bool) error {
+	if noCheckout {
+		m.calls = append(m.calls, "add "+branch+" --no-checkout")
+	} else if createBranch {
+		m.calls = append(m.calls, "add -b "+branch)
+	}
 	if m.addError != nil {
 		return m.addError
 	}
@@ -58,10 +85,22 @@ func (m *mockGit) RemoveWorktree(path string, force bool) error {
 	return nil
 }
 
+func (m *mockGit) IsWorktreeDirty(path string) (bool, error) {
+	return m.dirty[path], nil
+}
+
 func (m *mockGit) PruneWorktrees() error {
 	return m.pruneError
 }
 
+func (m *mockGit) PruneWorktreesVerbose(dryRun bool) (string, error) {
+	if m.pruneError != nil {
+		return "", m.pruneError
+	}
+	m.pruneDryRuns = append(m.pruneDryRuns, dryRun)
+	return m.pruneReport, nil
+}
+
 func (m *mockGit) GetRepositoryName() (string, error) {
 	if m.repoName == "" {
 		return "test-repo", nil
@@ -90,6 +129,22 @@ func (m *mockGit) DeleteBranch(branch string, force bool) error {
 	return nil
 }
 
+func (m *mockGit) RenameBranch(oldName, newName string) error {
+	if m.renameBranchError != nil {
+		return m.renameBranchError
+	}
+	m.renamedBranches = append(m.renamedBranches, [2]string{oldName, newName})
+	return nil
+}
+
+func (m *mockGit) RepairWorktrees(paths ...string) error {
+	if m.repairError != nil {
+		return m.repairError
+	}
+	m.repairedPaths = append(m.repairedPaths, paths...)
+	return nil
+}
+
 func (m *mockGit) GetMainRepositoryPath() (string, error) {
 	if m.mainRepoPathError != nil {
 		return "", m.mainRepoPathError
@@ -100,7 +155,12 @@ func (m *mockGit) GetMainRepositoryPath() (string, error) {
 	return m.repoPath, nil
 }
 
-func (m *mockGit) AddWorktreeFromBase(path, branch, baseBranch string) error {
+func (m *mockGit) AddWorktreeFromBase(path, branch, baseBranch string, noCheckout bool) error {
+	call := "add " + branch + " from " + baseBranch
+	if noCheckout {
+		call += " --no-checkout"
+	}
+	m.calls = append(m.calls, call)
 	if m.addError != nil {
 		return m.addError
 	}
@@ -111,6 +171,59 @@ func (m *mockGit) AddWorktreeFromBase(path, branch, baseBranch string) error {
 	return nil
 }
 
+func (m *mockGit) MergedBranches(into string) (map[string]bool, error) {
+	if m.mergedError != nil {
+		return nil, m.mergedError
+	}
+	m.mergedInto = append(m.mergedInto, into)
+	return m.mergedBranches, nil
+}
+
+func (m *mockGit) BranchHasOwnCommits(branch, base string) (bool, error) {
+	return !m.freshBranches[branch], nil
+}
+
+func (m *mockGit) IsCommitish(ref string) bool { return m.commitish[ref] }
+
+func (m *mockGit) ListBranches(includeRemote bool) ([]models.Branch, error) {
+	var result []models.Branch
+	for _, b := range m.branches {
+		if includeRemote || !b.IsRemote {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockGit) DefaultBranch() (string, error) {
+	if m.defaultBranch == "" {
+		return "", errors.New("could not determine the default branch")
+	}
+	return m.defaultBranch, nil
+}
+
+func (m *mockGit) Upstream(ref string) (string, string, error) {
+	u := m.upstreams[ref]
+	return u[0], u[1], nil
+}
+
+func (m *mockGit) FetchBranch(remote, branch string) error {
+	m.calls = append(m.calls, "fetch "+remote+" "+branch)
+	return m.fetchError
+}
+
+func (m *mockGit) Diff(path string, stat bool, args ...string) (string, error) {
+	m.calls = append(m.calls, strings.TrimSpace(fmt.Sprintf("diff %s stat=%t %s", path, stat, strings.Join(args, " "))))
+	if m.diffError != nil {
+		return "", m.diffError
+	}
+	return m.diffs[path], nil
+}
+
+func (m *mockGit) IsAncestor(ancestor, descendant string) (bool, error) {
+	return m.ancestors[[2]string{ancestor, descendant}], nil
+}
+
 func TestManagerAdd(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -209,6 +322,199 @@ func TestManagerRemove(t *testing.T) {
 	}
 }
 
+func TestManagerPullBase(t *testing.T) {
+	upstreams := map[string][2]string{
+		"origin/main": {"origin", "main"},
+		"main":        {"origin", "main"},
+		"develop":     {"origin", "develop"},
+	}
+	tests := []struct {
+		name       string
+		base       string
+		fetchError error
+		ancestors  map[[2]string]bool
+		want       string
+		wantFetch  bool
+		wantErr    string
+	}{
+		{name: "RemoteTrackingRef", base: "origin/main", want: "origin/main", wantFetch: true},
+		{
+			name:      "LocalBranchBehindUpstream",
+			base:      "main",
+			ancestors: map[[2]string]bool{{"main", "origin/main"}: true},
+			want:      "origin/main",
+			wantFetch: true,
+		},
+		{name: "LocalBranchWithOwnCommits", base: "develop", want: "develop", wantFetch: true, wantErr: "commits that are not in origin/develop"},
+		{name: "NoUpstream", base: "topic", want: "topic", wantErr: "no upstream"},
+		{name: "FetchFails", base: "origin/main", fetchError: errors.New("offline"), want: "origin/main", wantFetch: true, wantErr: "offline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := &mockGit{upstreams: upstreams, fetchError: tt.fetchError, ancestors: tt.ancestors}
+			m := New(mockG, &models.Config{})
+
+			got, err := m.PullBase(tt.base)
+			if got != tt.want {
+				t.Errorf("PullBase() = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("PullBase() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("PullBase() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if fetched := len(mockG.calls) > 0; fetched != tt.wantFetch {
+				t.Errorf("fetched = %v, want %v", fetched, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestManagerPullBase_FetchThenBranch(t *testing.T) {
+	mockG := &mockGit{
+		upstreams: map[string][2]string{"main": {"origin", "main"}},
+		ancestors: map[[2]string]bool{{"main", "origin/main"}: true},
+	}
+	m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{BaseDir: t.TempDir(), AutoMkdir: true}})
+
+	base, err := m.PullBase("main")
+	if err != nil {
+		t.Fatalf("PullBase() error = %v", err)
+	}
+	if _, err := m.AddFromBase("feature/x", base, ""); err != nil {
+		t.Fatalf("AddFromBase() error = %v", err)
+	}
+
+	want := []string{"fetch origin main", "add feature/x from origin/main"}
+	if !reflect.DeepEqual(mockG.calls, want) {
+		t.Errorf("calls = %v, want %v", mockG.calls, want)
+	}
+}
+
+func TestManagerRename(t *testing.T) {
+	newManager := func(t *testing.T) (*Manager, *mockGit, string, string) {
+		t.Helper()
+		baseDir := t.TempDir()
+		oldPath := filepath.Join(baseDir, "github.com", "test-user", "test-repo", "feature-old")
+		if err := os.MkdirAll(oldPath, 0755); err != nil {
+			t.Fatalf("failed to create worktree dir: %v", err)
+		}
+		mockG := &mockGit{}
+		m := New(mockG, &models.Config{
+			Worktree: models.WorktreeConfig{BaseDir: baseDir, AutoMkdir: true},
+		})
+		newPath := filepath.Join(baseDir, "github.com", "test-user", "test-repo", "feature-new")
+		return m, mockG, oldPath, newPath
+	}
+
+	t.Run("MovesDirectoryAndRepairs", func(t *testing.T) {
+		m, mockG, oldPath, newPath := newManager(t)
+
+		got, err := m.Rename(models.Worktree{Path: oldPath, Branch: "feature/old"}, "feature/new", true)
+		if err != nil {
+			t.Fatalf("Rename() error = %v", err)
+		}
+		if got != newPath {
+			t.Errorf("Rename() = %s, want %s", got, newPath)
+		}
+		if _, err := os.Stat(newPath); err != nil {
+			t.Errorf("expected worktree at %s: %v", newPath, err)
+		}
+		if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be gone, err = %v", oldPath, err)
+		}
+		if len(mockG.renamedBranches) != 1 || mockG.renamedBranches[0] != [2]string{"feature/old", "feature/new"} {
+			t.Errorf("renamed branches = %v", mockG.renamedBranches)
+		}
+		if len(mockG.repairedPaths) != 1 || mockG.repairedPaths[0] != newPath {
+			t.Errorf("repaired paths = %v, want [%s]", mockG.repairedPaths, newPath)
+		}
+	})
+
+	t.Run("TargetExists", func(t *testing.T) {
+		m, mockG, oldPath, newPath := newManager(t)
+		if err := os.MkdirAll(newPath, 0755); err != nil {
+			t.Fatalf("failed to create target: %v", err)
+		}
+
+		_, err := m.Rename(models.Worktree{Path: oldPath, Branch: "feature/old"}, "feature/new", true)
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("Rename() error = %v, want target exists error", err)
+		}
+		if len(mockG.renamedBranches) != 0 {
+			t.Errorf("branch should not be renamed, got %v", mockG.renamedBranches)
+		}
+	})
+
+	t.Run("RollsBackBranchWhenMoveFails", func(t *testing.T) {
+		m, mockG, _, _ := newManager(t)
+
+		_, err := m.Rename(models.Worktree{Path: "/nonexistent/worktree", Branch: "feature/old"}, "feature/new", true)
+		if err == nil {
+			t.Fatal("Rename() expected error, got nil")
+		}
+		want := [][2]string{{"feature/old", "feature/new"}, {"feature/new", "feature/old"}}
+		if len(mockG.renamedBranches) != 2 || mockG.renamedBranches[0] != want[0] || mockG.renamedBranches[1] != want[1] {
+			t.Errorf("renamed branches = %v, want %v", mockG.renamedBranches, want)
+		}
+	})
+
+	t.Run("MainWorktree", func(t *testing.T) {
+		m, _, oldPath, _ := newManager(t)
+
+		if _, err := m.Rename(models.Worktree{Path: oldPath, Branch: "main", IsMain: true}, "trunk", true); err == nil {
+			t.Error("Rename() expected error for main worktree")
+		}
+	})
+
+	t.Run("DetachedHEAD", func(t *testing.T) {
+		m, mockG, oldPath, _ := newManager(t)
+
+		_, err := m.Rename(models.Worktree{Path: oldPath, Branch: "HEAD"}, "feature/new", true)
+		if err == nil || !strings.Contains(err.Error(), "no branch checked out") {
+			t.Fatalf("Rename() error = %v, want detached HEAD error", err)
+		}
+		if len(mockG.renamedBranches) != 0 {
+			t.Errorf("branch should not be renamed, got %v", mockG.renamedBranches)
+		}
+	})
+
+	t.Run("BranchExists", func(t *testing.T) {
+		m, mockG, oldPath, newPath := newManager(t)
+		mockG.renameBranchError = errors.New("a branch named 'feature/new' already exists")
+
+		if _, err := m.Rename(models.Worktree{Path: oldPath, Branch: "feature/old"}, "feature/new", true); err == nil {
+			t.Fatal("Rename() expected error when the new branch exists")
+		}
+		if _, err := os.Stat(oldPath); err != nil {
+			t.Errorf("worktree should stay at %s: %v", oldPath, err)
+		}
+		if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be created, err = %v", newPath, err)
+		}
+	})
+
+	t.Run("KeepDirectory", func(t *testing.T) {
+		m, mockG, oldPath, newPath := newManager(t)
+
+		got, err := m.Rename(models.Worktree{Path: oldPath, Branch: "feature/old"}, "feature/new", false)
+		if err != nil {
+			t.Fatalf("Rename() error = %v", err)
+		}
+		if got != oldPath {
+			t.Errorf("Rename() = %s, want unchanged %s", got, oldPath)
+		}
+		if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be created, err = %v", newPath, err)
+		}
+		if len(mockG.renamedBranches) != 1 || len(mockG.repairedPaths) != 0 {
+			t.Errorf("renamed = %v, repaired = %v; want one rename and no repair", mockG.renamedBranches, mockG.repairedPaths)
+		}
+	})
+}
+
 func TestManagerList(t *testing.T) {
 	expectedWorktrees := []models.Worktree{
 		{Path: "/path/1", Branch: "main", IsMain: true},
@@ -229,6 +535,123 @@ func TestManagerList(t *testing.T) {
 	if len(worktrees) != len(expectedWorktrees) {
 		t.Errorf("List() returned %d worktrees, want %d", len(worktrees), len(expectedWorktrees))
 	}
+	for _, wt := range worktrees {
+		if wt.RepositoryInfo == nil || wt.RepositoryInfo.FullPath != "github.com/test-user/test-repo" {
+			t.Errorf("List() RepositoryInfo for %s = %+v, want github.com/test-user/test-repo", wt.Path, wt.RepositoryInfo)
+		}
+	}
+}
+
+func TestManagerList_NoRemote(t *testing.T) {
+	mockG := &mockGit{
+		worktrees:    []models.Worktree{{Path: "/path/1", Branch: "main", IsMain: true}},
+		repoURLError: errors.New("no such remote 'origin'"),
+	}
+
+	worktrees, err := New(mockG, &models.Config{}).List()
+	if err != nil {
+		t.Fatalf("List() error = %v, want nil without a remote", err)
+	}
+	if len(worktrees) != 1 || worktrees[0].RepositoryInfo != nil {
+		t.Errorf("List() = %+v, want one worktree without RepositoryInfo", worktrees)
+	}
+}
+
+func TestManagerFindOrphaned(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing")
+	if err := os.Mkdir(existing, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mockG := &mockGit{
+		worktrees: []models.Worktree{
+			{Path: filepath.Join(root, "main-gone"), Branch: "main", IsMain: true},
+			{Path: existing, Branch: "feature/kept"},
+			{Path: filepath.Join(root, "deleted"), Branch: "feature/deleted"},
+			{Path: filepath.Join(root, "also-deleted"), Branch: "fix/old"},
+		},
+	}
+
+	orphaned, err := New(mockG, &models.Config{}).FindOrphaned()
+	if err != nil {
+		t.Fatalf("FindOrphaned() error = %v", err)
+	}
+	var branches []string
+	for _, wt := range orphaned {
+		branches = append(branches, wt.Branch)
+	}
+	if want := []string{"feature/deleted", "fix/old"}; !reflect.DeepEqual(branches, want) {
+		t.Errorf("FindOrphaned() branches = %v, want %v", branches, want)
+	}
+}
+
+func TestManagerFindOrphaned_ListError(t *testing.T) {
+	mockG := &mockGit{listError: errors.New("not a git repository")}
+
+	if _, err := New(mockG, &models.Config{}).FindOrphaned(); err == nil {
+		t.Error("FindOrphaned() error = nil, want the list error")
+	}
+}
+
+func TestManagerListMergedWorktrees(t *testing.T) {
+	newMock := func() *mockGit {
+		return &mockGit{
+			worktrees: []models.Worktree{
+				{Path: "/repo", Branch: "main", IsMain: true},
+				{Path: "/wt/done", Branch: "feature/done"},
+				{Path: "/wt/wip", Branch: "feature/wip"},
+				{Path: "/wt/detached", Branch: "HEAD"},
+				{Path: "/wt/main-copy", Branch: "main"},
+				{Path: "/wt/fix", Branch: "fix/merged"},
+				{Path: "/wt/fresh", Branch: "feature/fresh"},
+			},
+			mergedBranches: map[string]bool{"main": true, "HEAD": true, "feature/done": true, "fix/merged": true, "feature/fresh": true},
+			freshBranches:  map[string]bool{"feature/fresh": true},
+			defaultBranch:  "main",
+		}
+	}
+
+	tests := []struct {
+		name       string
+		baseBranch string
+		wantPaths  []string
+		wantInto   string
+	}{
+		{name: "default branch", wantPaths: []string{"/wt/done", "/wt/fix"}, wantInto: "main"},
+		{name: "explicit base", baseBranch: "develop", wantPaths: []string{"/wt/done", "/wt/main-copy", "/wt/fix"}, wantInto: "develop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := newMock()
+			merged, err := New(mockG, &models.Config{}).ListMergedWorktrees(tt.baseBranch)
+			if err != nil {
+				t.Fatalf("ListMergedWorktrees() error = %v", err)
+			}
+			var paths []string
+			for _, wt := range merged {
+				paths = append(paths, wt.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("ListMergedWorktrees() paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(mockG.mergedInto, []string{tt.wantInto}) {
+				t.Errorf("MergedBranches into = %v, want a single lookup into %q", mockG.mergedInto, tt.wantInto)
+			}
+		})
+	}
+}
+
+func TestManagerListMergedWorktrees_Errors(t *testing.T) {
+	mockG := &mockGit{worktrees: []models.Worktree{{Path: "/wt/a", Branch: "a"}}}
+	if _, err := New(mockG, &models.Config{}).ListMergedWorktrees(""); err == nil {
+		t.Error("ListMergedWorktrees() without a default branch error = nil, want an error")
+	}
+
+	mockG.mergedError = errors.New("bad revision")
+	if _, err := New(mockG, &models.Config{}).ListMergedWorktrees("main"); err == nil {
+		t.Error("ListMergedWorktrees() error = nil, want the MergedBranches error")
+	}
 }
 
 func TestManagerPrune(t *testing.T) {
@@ -241,6 +664,49 @@ func TestManagerPrune(t *testing.T) {
 	}
 }
 
+func TestManagerPruneDryRun(t *testing.T) {
+	mockG := &mockGit{
+		pruneReport: "Removing worktrees/feature: gitdir file points to non-existent location\n" +
+			"Removing worktrees/old-fix: gitdir file does not exist\n" +
+			"warning: unrelated message\n",
+	}
+	m := New(mockG, &models.Config{})
+
+	entries, err := m.PruneDryRun()
+	if err != nil {
+		t.Fatalf("PruneDryRun() error = %v", err)
+	}
+	want := []PruneEntry{
+		{Path: "worktrees/feature", Reason: "gitdir file points to non-existent location"},
+		{Path: "worktrees/old-fix", Reason: "gitdir file does not exist"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("PruneDryRun() = %+v, want %+v", entries, want)
+	}
+	if !reflect.DeepEqual(mockG.pruneDryRuns, []bool{true}) {
+		t.Errorf("PruneWorktreesVerbose dryRun args = %v, want [true]", mockG.pruneDryRuns)
+	}
+
+	if _, err := m.PruneVerbose(); err != nil {
+		t.Fatalf("PruneVerbose() error = %v", err)
+	}
+	if !reflect.DeepEqual(mockG.pruneDryRuns, []bool{true, false}) {
+		t.Errorf("PruneWorktreesVerbose dryRun args = %v, want [true false]", mockG.pruneDryRuns)
+	}
+}
+
+func TestManagerPruneDryRun_Empty(t *testing.T) {
+	m := New(&mockGit{}, &models.Config{})
+
+	entries, err := m.PruneDryRun()
+	if err != nil {
+		t.Fatalf("PruneDryRun() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("PruneDryRun() = %+v, want none", entries)
+	}
+}
+
 func TestManagerGetWorktreePath(t *testing.T) {
 	mockG := &mockGit{
 		worktrees: []models.Worktree{
@@ -368,6 +834,57 @@ func TestManagerGetMatchingWorktrees(t *testing.T) {
 	}
 }
 
+func TestManagerGetWorktreeByExactBranch(t *testing.T) {
+	mockG := &mockGit{
+		worktrees: []models.Worktree{
+			{Path: "/path/to/main", Branch: "main"},
+			{Path: "/path/to/feature", Branch: "feature"},
+			{Path: "/path/to/feature-auth", Branch: "feature/auth"},
+			{Path: "/path/to/fix-a", Branch: "fix/a"},
+			{Path: "/path/to/fix-a-2", Branch: "fix:a"},
+			{Path: "/path/to/detached", Branch: ""},
+		},
+	}
+	m := New(mockG, &models.Config{Naming: models.NamingConfig{SanitizeChars: map[string]string{"/": "-", ":": "-"}}})
+
+	tests := []struct {
+		name      string
+		branch    string
+		wantPath  string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "exact", branch: "feature", wantPath: "/path/to/feature", wantFound: true},
+		{name: "substring does not match", branch: "feat"},
+		{name: "prefix of a longer branch does not match", branch: "feature/au"},
+		{name: "exact with slash", branch: "feature/auth", wantPath: "/path/to/feature-auth", wantFound: true},
+		{name: "sanitized form", branch: "feature-auth", wantPath: "/path/to/feature-auth", wantFound: true},
+		{name: "ambiguous sanitized form", branch: "fix-a", wantErr: true},
+		{name: "exact beats sanitized collision", branch: "fix/a", wantPath: "/path/to/fix-a", wantFound: true},
+		{name: "no match", branch: "release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt, found, err := m.GetWorktreeByExactBranch(tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWorktreeByExactBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("GetWorktreeByExactBranch() found = %v, want %v", found, tt.wantFound)
+			}
+			if wt.Path != tt.wantPath {
+				t.Errorf("GetWorktreeByExactBranch() path = %q, want %q", wt.Path, tt.wantPath)
+			}
+		})
+	}
+
+	// The fuzzy lookup used by user-facing commands still matches substrings.
+	if matches, err := m.GetMatchingWorktrees("feat"); err != nil || len(matches) != 2 {
+		t.Errorf("GetMatchingWorktrees(feat) = %d matches, %v; want 2", len(matches), err)
+	}
+}
+
 func TestManagerValidateWorktreePath(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -432,6 +949,67 @@ func TestManagerValidateWorktreePath(t *testing.T) {
 	}
 }
 
+func TestManagerAdd_RefusesReservedPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repoRoot := t.TempDir()
+
+	tests := []struct {
+		name       string
+		customPath string
+		want       string
+	}{
+		{name: "FilesystemRoot", customPath: "/", want: "filesystem root"},
+		{name: "FilesystemRootUnclean", customPath: "/tmp/..", want: "filesystem root"},
+		{name: "HomeDirectory", customPath: home, want: "home directory"},
+		{name: "HomeTilde", customPath: "~", want: "home directory"},
+		{name: "HomeTrailingSlash", customPath: home + "/", want: "home directory"},
+		{name: "MainWorktree", customPath: repoRoot, want: "main worktree"},
+		{name: "MainWorktreeUnclean", customPath: filepath.Join(repoRoot, "sub", ".."), want: "main worktree"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockG := &mockGit{repoPath: repoRoot}
+			m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{AutoMkdir: true}})
+
+			_, err := m.Add("feature/x", tt.customPath, true)
+			if !errors.Is(err, ErrReservedPath) {
+				t.Fatalf("Add(%q) error = %v, want ErrReservedPath", tt.customPath, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Add(%q) error = %v, want it to mention %q", tt.customPath, err, tt.want)
+			}
+			if len(mockG.worktrees) != 0 {
+				t.Errorf("worktree was created at a reserved path: %+v", mockG.worktrees)
+			}
+
+			if err := m.ValidateWorktreePath(tt.customPath); !errors.Is(err, ErrReservedPath) {
+				t.Errorf("ValidateWorktreePath(%q) error = %v, want ErrReservedPath", tt.customPath, err)
+			}
+		})
+	}
+
+	t.Run("GeneratedPathAtHome", func(t *testing.T) {
+		mockG := &mockGit{repoPath: repoRoot, repoURL: "https://github.com/user/repo.git"}
+		m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{BaseDir: home, AutoMkdir: true}})
+		m.config.Naming.Template = "."
+
+		if _, err := m.Add("feature/x", "", true); !errors.Is(err, ErrReservedPath) {
+			t.Errorf("Add() with generated path at home error = %v, want ErrReservedPath", err)
+		}
+	})
+
+	t.Run("SubdirectoryAllowed", func(t *testing.T) {
+		mockG := &mockGit{repoPath: repoRoot}
+		m := New(mockG, &models.Config{Worktree: models.WorktreeConfig{AutoMkdir: true}})
+
+		if _, err := m.Add("feature/x", filepath.Join(home, "worktrees", "x"), true); err != nil {
+			t.Errorf("Add() below home error = %v", err)
+		}
+	})
+}
+
 func TestGenerateWorktreePath(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -473,6 +1051,30 @@ func TestGenerateWorktreePath(t *testing.T) {
 			wantSuffix:  "github.com/test-user/test-repo/feature-test",
 			wantBaseDir: "/per-repo-base",
 		},
+		{
+			name:     "PerRepoBaseDirAmongOthers",
+			branch:   "feature/test",
+			repoName: "myrepo",
+			repoPath: "/mock/repo/path",
+			repositorySettings: []models.RepositorySetting{
+				{Repository: "/other/repo", BaseDir: "/other/repo/.worktrees"},
+				{Repository: "/mock/repo/path", BaseDir: "/mock/repo/path/.wt"},
+			},
+			wantSuffix:  "github.com/test-user/test-repo/feature-test",
+			wantBaseDir: "/mock/repo/path/.wt",
+		},
+		{
+			name:     "OtherRepoBaseDir",
+			branch:   "feature/test",
+			repoName: "myrepo",
+			repoPath: "/other/repo",
+			repositorySettings: []models.RepositorySetting{
+				{Repository: "/other/repo", BaseDir: "/other/repo/.worktrees"},
+				{Repository: "/mock/repo/path", BaseDir: "/mock/repo/path/.wt"},
+			},
+			wantSuffix:  "github.com/test-user/test-repo/feature-test",
+			wantBaseDir: "/other/repo/.worktrees",
+		},
 		{
 			name:     "PerRepoBaseDirEmpty",
 			branch:   "feature/test",
@@ -578,6 +1180,47 @@ func TestManagerAdd_ConfigurableSetupIntegration(t *testing.T) {
 	}
 }
 
+func TestManagerAddWithOptions_NoCheckout(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "copyme.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write src file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     AddOptions
+		wantCall string
+	}{
+		{name: "existing branch", opts: AddOptions{NoCheckout: true}, wantCall: "add feature/x --no-checkout"},
+		{name: "from base", opts: AddOptions{CreateBranch: true, Base: "origin/main", NoCheckout: true}, wantCall: "add feature/x from origin/main --no-checkout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worktreeDir := t.TempDir()
+			cfg := &models.Config{
+				Worktree: models.WorktreeConfig{BaseDir: worktreeDir, AutoMkdir: true},
+				RepositorySettings: []models.RepositorySetting{
+					{Repository: repoDir, CopyFiles: []string{"copyme.txt"}},
+				},
+			}
+			mockG := &mockGit{repoPath: repoDir}
+			m := New(mockG, cfg)
+
+			path, err := m.AddWithOptions("feature/x", filepath.Join(worktreeDir, "wt"), tt.opts)
+			if err != nil {
+				t.Fatalf("AddWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(mockG.calls, []string{tt.wantCall}) {
+				t.Errorf("calls = %v, want [%s]", mockG.calls, tt.wantCall)
+			}
+			if _, err := os.Stat(filepath.Join(path, "copyme.txt")); !os.IsNotExist(err) {
+				t.Errorf("setup ran for a worktree without checkout (stat error = %v)", err)
+			}
+		})
+	}
+}
+
 func TestManagerAdd_SetupFromWorktreeContext(t *testing.T) {
 	repoDir, err := filepath.EvalSymlinks(t.TempDir())
 	if err != nil {
@@ -10,6 +10,28 @@ type Worktree struct {
 	CommitHash string    `json:"commit_hash"` // Current HEAD commit hash
 	IsMain     bool      `json:"is_main"`     // Whether this is the main worktree
 	CreatedAt  time.Time `json:"created_at"`  // Creation timestamp
+
+	// BranchDirMismatch is set when the directory is not where the naming
+	// template puts the checked-out branch, e.g. after the branch was
+	// renamed outside gwq.
+	BranchDirMismatch bool `json:"branch_dir_mismatch,omitempty"`
+
+	// CommitsBehind is how many commits the branch is behind its fetched
+	// upstream. It is nil unless requested (gwq list --commits-behind) and
+	// for branches without an upstream.
+	CommitsBehind *int `json:"commits_behind,omitempty"`
+
+	// RepositoryInfo describes the repository the worktree belongs to. It is
+	// nil when the repository has no parseable origin URL.
+	RepositoryInfo *RepositoryInfo `json:"repository_info,omitempty"`
+}
+
+// RepositoryInfo contains repository information parsed from its remote URL.
+type RepositoryInfo struct {
+	Host       string `json:"host"`       // e.g., "github.com"
+	Owner      string `json:"owner"`      // e.g., "user1"
+	Repository string `json:"repository"` // e.g., "myapp"
+	FullPath   string `json:"full_path"`  // e.g., "github.com/user1/myapp"
 }
 
 // Branch represents a Git branch with its metadata.
@@ -36,31 +58,64 @@ type CdConfig struct {
 
 // Config represents the application configuration.
 type Config struct {
-	Worktree           WorktreeConfig      `mapstructure:"worktree"`            // Worktree-related configuration
-	Cd                 CdConfig            `mapstructure:"cd"`                  // Cd command configuration
-	Finder             FinderConfig        `mapstructure:"finder"`              // Fuzzy finder configuration
-	UI                 UIConfig            `mapstructure:"ui"`                  // UI-related configuration
-	Naming             NamingConfig        `mapstructure:"naming"`              // Naming and template configuration
-	RepositorySettings []RepositorySetting `mapstructure:"repository_settings"` // Per-repository setup/copy overrides
+	Worktree           WorktreeConfig         `mapstructure:"worktree"`            // Worktree-related configuration
+	Cd                 CdConfig               `mapstructure:"cd"`                  // Cd command configuration
+	Finder             FinderConfig           `mapstructure:"finder"`              // Fuzzy finder configuration
+	UI                 UIConfig               `mapstructure:"ui"`                  // UI-related configuration
+	GitHub             GitHubConfig           `mapstructure:"github"`              // GitHub API access for 'gwq add --from-pr'
+	Status             StatusConfig           `mapstructure:"status"`              // Worktree status collection
+	ProcessDetect      ProcessDetectConfig    `mapstructure:"process_detect"`      // Process detection for 'gwq status --show-processes'
+	TaskLog            TaskLogConfig          `mapstructure:"task_log"`            // Lifecycle log of 'gwq tmux run' tasks
+	Agents             map[string]AgentConfig `mapstructure:"agents"`              // Agents for 'gwq tmux run --agent', by name
+	Naming             NamingConfig           `mapstructure:"naming"`              // Naming and template configuration
+	RepositorySettings []RepositorySetting    `mapstructure:"repository_settings"` // Per-repository setup/copy overrides
+	ActiveProfile      string                 `mapstructure:"active_profile"`      // Profile applied on top of this config
+	Profiles           map[string]Profile     `mapstructure:"profiles"`            // Named partial configs selectable with active_profile
 }
 
-// RepositorySetting defines per-repository setup commands and files to copy for worktree creation.
+// Profile is a named set of config overrides. Only the keys present in the
+// config file are applied; see config.Init.
+type Profile map[string]any
+
+// RepositorySetting defines per-repository setup commands and files to copy
+// for worktree creation, and teardown commands to run before removal.
 type RepositorySetting struct {
-	Repository    string   `mapstructure:"repository"`     // Path or pattern for repository
-	SetupCommands []string `mapstructure:"setup_commands"` // Commands to run in new worktree
-	CopyFiles     []string `mapstructure:"copy_files"`     // Files/globs to copy into new worktree
-	BaseDir       string   `mapstructure:"basedir"`        // Override global worktree.basedir for this repository
+	Repository       string            `mapstructure:"repository"`        // Path or pattern for repository
+	SetupCommands    []string          `mapstructure:"setup_commands"`    // Commands to run in new worktree
+	TeardownCommands []string          `mapstructure:"teardown_commands"` // Commands to run in a worktree before it is removed
+	CopyFiles        []string          `mapstructure:"copy_files"`        // Files/globs to copy into new worktree
+	BaseDir          string            `mapstructure:"basedir"`           // Override global worktree.basedir for this repository
+	EnvVars          map[string]string `mapstructure:"env_vars"`          // Environment for setup commands; values are templates
+	AutoDotenv       bool              `mapstructure:"auto_dotenv"`       // Also write env_vars to .env in the new worktree
 }
 
 // WorktreeConfig contains worktree-specific configuration options.
 type WorktreeConfig struct {
-	BaseDir   string `mapstructure:"basedir"`    // Base directory for creating worktrees
-	AutoMkdir bool   `mapstructure:"auto_mkdir"` // Automatically create directories
+	BaseDir    string `mapstructure:"basedir"`     // Base directory for creating worktrees
+	AutoMkdir  bool   `mapstructure:"auto_mkdir"`  // Automatically create directories
+	SetupShell string `mapstructure:"setup_shell"` // Shell for setup_commands (default: sh)
+
+	// OpenCommand opens a worktree directory for 'gwq open'; empty means the
+	// platform default (open, xdg-open or explorer).
+	OpenCommand string `mapstructure:"open_command"`
+
+	// ProtectedBranches lists branch patterns (matched with utils.MatchPath)
+	// that 'gwq add' refuses to check out without --force.
+	ProtectedBranches []string `mapstructure:"protected_branches"`
+
+	// TrackRemoteIfExists makes 'gwq add <branch>' without a local branch
+	// track a remote branch of the same name, or create a new branch when
+	// there is none.
+	TrackRemoteIfExists bool `mapstructure:"track_remote_if_exists"`
 }
 
 // FinderConfig contains fuzzy finder configuration options.
 type FinderConfig struct {
-	Preview bool `mapstructure:"preview"` // Enable preview window
+	Preview bool   `mapstructure:"preview"` // Enable preview window
+	SortBy  string `mapstructure:"sort_by"` // Worktree order: name, path, activity or commit-date
+
+	DisplayTemplate string            `mapstructure:"display_template"` // Worktree line template (empty: "<branch> (<path>)")
+	StatusIcons     map[string]string `mapstructure:"status_icons"`     // Per-state overrides of the {{.StatusIcon}} symbols
 }
 
 // UIConfig contains UI-related configuration options.
@@ -69,22 +124,66 @@ type UIConfig struct {
 	TildeHome bool `mapstructure:"tilde_home"` // Display home directory as ~
 }
 
+// GitHubConfig contains GitHub API settings.
+type GitHubConfig struct {
+	Token string `mapstructure:"token"` // API token used when the gh CLI is not installed
+}
+
+// StatusConfig contains worktree status collection settings.
+type StatusConfig struct {
+	ActivitySampleSize int `mapstructure:"activity_sample_size"` // Tracked files stat'd per worktree for the last activity (default: 100)
+}
+
+// ProcessDetectConfig contains process detection settings.
+type ProcessDetectConfig struct {
+	AgentNames []string `mapstructure:"agent_names"` // Commands classified as AI agents (default: built-in list)
+}
+
+// AgentConfig defines a coding agent started as a plain command.
+type AgentConfig struct {
+	Executable         string   `mapstructure:"executable"`          // Program to run
+	Args               []string `mapstructure:"args"`                // Argument templates over .Prompt and .Worktree
+	CompletionPatterns []string `mapstructure:"completion_patterns"` // Regexps marking a finished run in the pane output
+	SuccessPatterns    []string `mapstructure:"success_patterns"`    // Regexps marking a successful run
+	ErrorPatterns      []string `mapstructure:"error_patterns"`      // Regexps marking a failed run
+}
+
+// TaskLogConfig controls the JSONL log of tasks started with 'gwq tmux run'.
+type TaskLogConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // Append lifecycle records for each task
+	Path    string `mapstructure:"path"`    // Log file (default: <config dir>/tasks.jsonl)
+}
+
 // NamingConfig contains directory naming and template configuration options.
 type NamingConfig struct {
-	Template      string            `mapstructure:"template"`       // Directory name template
-	SanitizeChars map[string]string `mapstructure:"sanitize_chars"` // Character replacement for branch names
+	Template       string            `mapstructure:"template"`        // Directory name template
+	SanitizeChars  map[string]string `mapstructure:"sanitize_chars"`  // Character replacement for branch names
+	StatusTemplate string            `mapstructure:"status_template"` // Repository column template for status (empty: derive from path)
 }
 
 // WorktreeStatus represents the current status of a worktree.
 type WorktreeStatus struct {
 	Path          string        `json:"path"`             // Absolute path to the worktree
 	Branch        string        `json:"branch"`           // Branch name
+	CommitHash    string        `json:"commit_hash"`      // Current HEAD commit hash
+	IsMain        bool          `json:"is_main"`          // Whether this is the main worktree
 	Repository    string        `json:"repository"`       // Repository identifier
 	Status        WorktreeState `json:"status"`           // Current status (clean, modified, etc.)
 	GitStatus     GitStatus     `json:"git_status"`       // Detailed git status
 	LastActivity  time.Time     `json:"last_activity"`    // Last modification time
 	ActiveProcess []ProcessInfo `json:"active_processes"` // Running processes
 	IsCurrent     bool          `json:"is_current"`       // Whether this is the current worktree
+
+	// AgentSessions lists gwq tmux sessions running in the worktree. It is
+	// only collected by 'gwq status --agent'.
+	AgentSessions []AgentSession `json:"agent_sessions,omitempty"`
+}
+
+// AgentSession describes a gwq-managed tmux session running in a worktree.
+type AgentSession struct {
+	Agent       string    `json:"agent"`        // Command the session was started with, e.g. "claude"
+	SessionName string    `json:"session_name"` // tmux session name
+	StartTime   time.Time `json:"start_time"`   // When the session was created
 }
 
 // WorktreeState represents the overall state of a worktree.
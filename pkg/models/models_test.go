@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -124,3 +125,96 @@ func TestConfigDefaults(t *testing.T) {
 		t.Error("Default Icons should be true")
 	}
 }
+
+func TestParseWorktreeState(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    WorktreeState
+		wantErr bool
+	}{
+		{input: "clean", want: WorktreeStatusClean},
+		{input: "Modified", want: WorktreeStatusModified},
+		{input: " STAGED ", want: WorktreeStatusStaged},
+		{input: "conflict", want: WorktreeStatusConflict},
+		{input: "conflicted", want: WorktreeStatusConflict},
+		{input: "changed", want: WorktreeStatusModified},
+		{input: "up to date", want: WorktreeStatusClean},
+		{input: "inactive", want: WorktreeStatusStale},
+		{input: "unknown", want: WorktreeStatusUnknown},
+		{input: "dirty", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWorktreeState(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWorktreeState(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWorktreeState(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorktreeStateStringAndIcon(t *testing.T) {
+	tests := []struct {
+		state    WorktreeState
+		wantStr  string
+		wantIcon string
+	}{
+		{WorktreeStatusClean, "Clean", "✓"},
+		{WorktreeStatusModified, "Modified", "✎"},
+		{WorktreeStatusStaged, "Staged", "✚"},
+		{WorktreeStatusConflict, "Conflict", "⚡"},
+		{WorktreeStatusStale, "Stale", "◌"},
+		{WorktreeStatusUnknown, "Unknown", "?"},
+		{WorktreeState("custom"), "custom", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if got := tt.state.String(); got != tt.wantStr {
+				t.Errorf("String() = %q, want %q", got, tt.wantStr)
+			}
+			if got := tt.state.Icon(); got != tt.wantIcon {
+				t.Errorf("Icon() = %q, want %q", got, tt.wantIcon)
+			}
+		})
+	}
+}
+
+func TestWorktreeStateJSON(t *testing.T) {
+	data, err := json.Marshal(WorktreeStatus{Status: WorktreeStatusModified})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"status":"modified"`) {
+		t.Errorf("Marshal() = %s, want the lower-case status value", data)
+	}
+
+	tests := []struct {
+		input   string
+		want    WorktreeState
+		wantErr bool
+	}{
+		{input: `"modified"`, want: WorktreeStatusModified},
+		{input: `"Conflict"`, want: WorktreeStatusConflict},
+		{input: `"up to date"`, want: WorktreeStatusClean},
+		{input: `"dirty"`, wantErr: true},
+		{input: `3`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var s WorktreeStatus
+			err := json.Unmarshal([]byte(`{"status":`+tt.input+`}`), &s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if s.Status != tt.want {
+				t.Errorf("Unmarshal(%s) = %q, want %q", tt.input, s.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// worktreeStateAliases maps accepted spellings, including the labels shown
+// by 'gwq status', to states. Keys are lower case.
+var worktreeStateAliases = map[string]WorktreeState{
+	"clean":      WorktreeStatusClean,
+	"up to date": WorktreeStatusClean,
+	"modified":   WorktreeStatusModified,
+	"changed":    WorktreeStatusModified,
+	"staged":     WorktreeStatusStaged,
+	"conflict":   WorktreeStatusConflict,
+	"conflicted": WorktreeStatusConflict,
+	"stale":      WorktreeStatusStale,
+	"inactive":   WorktreeStatusStale,
+	"unknown":    WorktreeStatusUnknown,
+}
+
+// ParseWorktreeState parses user input into a WorktreeState. Matching is
+// case-insensitive and accepts the aliases shown by 'gwq status' ("changed",
+// "up to date", "inactive", "conflicted").
+func ParseWorktreeState(s string) (WorktreeState, error) {
+	if state, ok := worktreeStateAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return state, nil
+	}
+	return "", fmt.Errorf("invalid worktree state %q: must be one of clean, modified, staged, conflict, stale, unknown", s)
+}
+
+// String returns a capitalised label for the state, e.g. "Modified". The
+// JSON form stays the lower-case constant value.
+func (s WorktreeState) String() string {
+	switch s {
+	case WorktreeStatusClean:
+		return "Clean"
+	case WorktreeStatusModified:
+		return "Modified"
+	case WorktreeStatusStaged:
+		return "Staged"
+	case WorktreeStatusConflict:
+		return "Conflict"
+	case WorktreeStatusStale:
+		return "Stale"
+	case WorktreeStatusUnknown:
+		return "Unknown"
+	default:
+		return string(s)
+	}
+}
+
+// Icon returns a symbol for the state, or "" for unrecognised values.
+func (s WorktreeState) Icon() string {
+	switch s {
+	case WorktreeStatusClean:
+		return "✓"
+	case WorktreeStatusModified:
+		return "✎"
+	case WorktreeStatusStaged:
+		return "✚"
+	case WorktreeStatusConflict:
+		return "⚡"
+	case WorktreeStatusStale:
+		return "◌"
+	case WorktreeStatusUnknown:
+		return "?"
+	default:
+		return ""
+	}
+}
+
+// MarshalJSON encodes the state as its lower-case value rather than String.
+func (s WorktreeState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON accepts any spelling ParseWorktreeState does, so "modified",
+// "Modified" and "changed" all decode to WorktreeStatusModified.
+func (s *WorktreeState) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("worktree state must be a string: %w", err)
+	}
+	state, err := ParseWorktreeState(raw)
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}